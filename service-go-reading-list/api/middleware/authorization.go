@@ -0,0 +1,168 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/auth"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/openapi"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+// NewAuthorization returns a middleware that enforces the security
+// requirements declared for each operation in the OpenAPI definition. The
+// bearer token of the request is validated with the validator, and the
+// principal must be granted all the scopes of at least one of the
+// requirements of the operation. Requests outside the base path of the
+// definition are passed through. The requests under it that do not match any
+// operation are never passed to a route: they are answered with 401 without
+// a valid token, and with 404 otherwise.
+func NewAuthorization(spec *openapi.Spec, validator auth.TokenValidator) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		method := c.Method()
+		if method == fiber.MethodHead {
+			method = fiber.MethodGet
+		}
+		route, _, ok := spec.FindRoute(method, c.Path())
+		if !ok {
+			if !spec.HasPath(c.Path()) {
+				return c.Next()
+			}
+			if _, err := authenticate(c, validator); err != nil {
+				return err
+			}
+			return makeHttpUndeclaredRouteError(c)
+		}
+		requirements, _ := spec.SecurityRequirements(route)
+		if allowsAnonymous(requirements) {
+			return c.Next()
+		}
+
+		principal, err := authenticate(c, validator)
+		if err != nil {
+			return err
+		}
+		if !isAuthorized(principal, requirements) {
+			c.Set(fiber.HeaderWWWAuthenticate, fmt.Sprintf(`Bearer error="insufficient_scope", scope="%s"`, strings.Join(requiredScopes(requirements), " ")))
+			return fiber.NewError(http.StatusForbidden, "the bearer token does not have the required scopes")
+		}
+		utils.SetPrincipal(c, principal)
+		return c.Next()
+	}
+}
+
+// authenticate returns the principal of the bearer token of the request.
+func authenticate(c *fiber.Ctx, validator auth.TokenValidator) (*auth.Principal, error) {
+	token, ok := bearerToken(c)
+	if !ok {
+		c.Set(fiber.HeaderWWWAuthenticate, `Bearer`)
+		return nil, fiber.NewError(http.StatusUnauthorized, "a bearer token is required")
+	}
+	principal, err := validator.Validate(c.UserContext(), token)
+	if errors.Is(err, auth.ErrInvalidToken) {
+		c.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+		return nil, fiber.NewError(http.StatusUnauthorized, "the bearer token is invalid")
+	} else if err != nil {
+		logrus.Errorf("failed to validate the bearer token: %v", err)
+		return nil, fiber.NewError(http.StatusServiceUnavailable, "the authorization server is unavailable")
+	}
+	return principal, nil
+}
+
+// makeHttpUndeclaredRouteError answers the requests under the base path of
+// the definition that match none of its operations, like Fiber answers the
+// requests that match no route.
+func makeHttpUndeclaredRouteError(c *fiber.Ctx) *fiber.Error {
+	return fiber.NewError(http.StatusNotFound, fmt.Sprintf("Cannot %s %s", c.Method(), c.Path()))
+}
+
+// VerifyRouteSecurity checks that every route registered under the base path
+// of the definition is declared in it with security requirements, so that no
+// route is left unprotected by mistake.
+func VerifyRouteSecurity(routes []fiber.Route, spec *openapi.Spec) error {
+	var errs []error
+	for _, route := range routes {
+		if route.Method == fiber.MethodHead {
+			continue
+		}
+		template, ok := spec.TemplateFromFiberPath(route.Path)
+		if !ok {
+			continue
+		}
+		specRoute, ok := findRouteByTemplate(spec, route.Method, template)
+		if !ok {
+			errs = append(errs, fmt.Errorf("route %s %s is not declared in the OpenAPI definition", route.Method, route.Path))
+			continue
+		}
+		if _, ok := spec.SecurityRequirements(specRoute); !ok {
+			errs = append(errs, fmt.Errorf("route %s %s has no security requirements in the OpenAPI definition", route.Method, route.Path))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func findRouteByTemplate(spec *openapi.Spec, method, template string) (*openapi.Route, bool) {
+	for _, route := range spec.Routes() {
+		if route.Method == method && route.Path == template {
+			return route, true
+		}
+	}
+	return nil, false
+}
+
+func bearerToken(c *fiber.Ctx) (string, bool) {
+	scheme, token, ok := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// allowsAnonymous reports whether the requirements are empty or contain an
+// empty requirement, both of which make the operation public.
+func allowsAnonymous(requirements openapi3.SecurityRequirements) bool {
+	if len(requirements) == 0 {
+		return true
+	}
+	for _, requirement := range requirements {
+		if len(requirement) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// isAuthorized reports whether the principal satisfies at least one of the
+// requirements, i.e. has all the scopes of every scheme in that requirement.
+func isAuthorized(principal *auth.Principal, requirements openapi3.SecurityRequirements) bool {
+	for _, requirement := range requirements {
+		satisfied := true
+		for _, scopes := range requirement {
+			if !principal.HasScopes(scopes...) {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			return true
+		}
+	}
+	return false
+}
+
+func requiredScopes(requirements openapi3.SecurityRequirements) []string {
+	var scopes []string
+	for _, scheme := range requirements[0] {
+		scopes = append(scopes, scheme...)
+	}
+	return scopes
+}
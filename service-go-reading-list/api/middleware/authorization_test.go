@@ -0,0 +1,165 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/auth"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/openapi"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+const testSpec = `
+openapi: 3.0.1
+info:
+  title: Test
+  version: "1.0"
+servers:
+- url: /api
+paths:
+  /books:
+    get:
+      security:
+      - default:
+        - read:books
+      responses:
+        "200":
+          description: ok
+    post:
+      security:
+      - default:
+        - write:books
+      responses:
+        "201":
+          description: ok
+  /public:
+    get:
+      security: []
+      responses:
+        "200":
+          description: ok
+  /unprotected:
+    get:
+      responses:
+        "200":
+          description: ok
+components:
+  securitySchemes:
+    default:
+      type: oauth2
+      flows:
+        implicit:
+          authorizationUrl: https://example.com
+          scopes:
+            read:books: Grants read access
+            write:books: Grants write access
+`
+
+func TestAuthorization(t *testing.T) {
+	spec, err := openapi.Load([]byte(testSpec))
+	require.NoError(t, err)
+
+	// A stub introspection endpoint that knows a single reader token.
+	introspection := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := map[string]interface{}{"active": false}
+		if r.FormValue("token") == "reader-token" {
+			response = map[string]interface{}{"active": true, "sub": "alice", "scope": "read:books"}
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer introspection.Close()
+	validator := auth.NewIntrospectionValidator(auth.IntrospectionValidatorConfig{Endpoint: introspection.URL})
+
+	app := fiber.New(fiber.Config{ErrorHandler: utils.FiberErrorHandler})
+	api := app.Group("/api", NewAuthorization(spec, validator))
+	api.Get("/books", func(c *fiber.Ctx) error {
+		principal, _ := auth.PrincipalFromContext(utils.GetRequestContext(c))
+		return c.SendString(principal.Subject)
+	})
+	api.Post("/books", func(c *fiber.Ctx) error { return c.SendStatus(http.StatusCreated) })
+	api.Get("/public", func(c *fiber.Ctx) error { return c.SendStatus(http.StatusOK) })
+
+	request := func(method, path, token string) *http.Response {
+		req := httptest.NewRequest(method, path, nil)
+		if token != "" {
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Authorized", func(t *testing.T) {
+		resp := request(http.MethodGet, "/api/books", "reader-token")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("MissingToken", func(t *testing.T) {
+		resp := request(http.MethodGet, "/api/books", "")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, "Bearer", resp.Header.Get(fiber.HeaderWWWAuthenticate))
+
+		// HEAD requests are checked like the GET requests they mirror.
+		resp = request(http.MethodHead, "/api/books", "")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("InvalidToken", func(t *testing.T) {
+		resp := request(http.MethodGet, "/api/books", "revoked-token")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("InsufficientScope", func(t *testing.T) {
+		resp := request(http.MethodPost, "/api/books", "reader-token")
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get(fiber.HeaderWWWAuthenticate), `scope="write:books"`)
+	})
+
+	t.Run("MixedCase", func(t *testing.T) {
+		// The routes are matched regardless of case, and so are the
+		// operations of the definition.
+		for _, path := range []string{"/API/books", "/api/BOOKS", "/Api/Books/"} {
+			resp := request(http.MethodGet, path, "")
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, path)
+			resp = request(http.MethodGet, path, "reader-token")
+			assert.Equal(t, http.StatusOK, resp.StatusCode, path)
+			resp = request(http.MethodPost, path, "reader-token")
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode, path)
+		}
+	})
+
+	t.Run("Public", func(t *testing.T) {
+		resp := request(http.MethodGet, "/api/public", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("VerifyRouteSecurity", func(t *testing.T) {
+		assert.NoError(t, VerifyRouteSecurity(app.GetRoutes(true), spec))
+
+		api.Get("/unprotected", func(c *fiber.Ctx) error { return nil })
+		api.Delete("/books", func(c *fiber.Ctx) error { return nil })
+		err := VerifyRouteSecurity(app.GetRoutes(true), spec)
+		assert.ErrorContains(t, err, "route GET /api/unprotected has no security requirements")
+		assert.ErrorContains(t, err, "route DELETE /api/books is not declared")
+	})
+	t.Run("UndeclaredRoute", func(t *testing.T) {
+		// The routes under the base path that the definition does not declare
+		// are never reached.
+		api.Get("/hidden", func(c *fiber.Ctx) error { return c.SendStatus(http.StatusOK) })
+		resp := request(http.MethodGet, "/api/hidden", "")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		resp = request(http.MethodGet, "/api/hidden", "reader-token")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		resp = request(http.MethodGet, "/api/books/42", "revoked-token")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
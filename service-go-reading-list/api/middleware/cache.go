@@ -0,0 +1,237 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"bytes"
+	"container/list"
+	"expvar"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+// The response cache metrics are published with expvar under response_cache.
+var (
+	responseCacheHits          = new(expvar.Int)
+	responseCacheMisses        = new(expvar.Int)
+	responseCacheNotModified   = new(expvar.Int)
+	responseCacheEvictions     = new(expvar.Int)
+	responseCacheInvalidations = new(expvar.Int)
+)
+
+func init() {
+	metrics := expvar.NewMap("response_cache")
+	metrics.Set("hits", responseCacheHits)
+	metrics.Set("misses", responseCacheMisses)
+	metrics.Set("not_modified", responseCacheNotModified)
+	metrics.Set("evictions", responseCacheEvictions)
+	metrics.Set("invalidations", responseCacheInvalidations)
+	metrics.Set("hit_ratio", expvar.Func(responseCacheHitRatio))
+}
+
+type ResponseCacheConfig struct {
+	// LastModified returns when the cached resources were last changed.
+	// When it is nil, no Last-Modified header is sent and the conditional
+	// requests are not answered.
+	LastModified func() time.Time
+	// MaxEntries is the number of responses kept, the least recently used
+	// ones are evicted first. No response is kept when it is zero, but the
+	// conditional requests are still answered.
+	MaxEntries int
+	// MaxAge is how long the clients may reuse a response without
+	// revalidating it.
+	MaxAge time.Duration
+}
+
+// ResponseCache keeps the successful responses of GET requests in memory,
+// keyed by the path, the query, the Accept header and the principal of the
+// request, until Invalidate is called.
+type ResponseCache struct {
+	cfg          ResponseCacheConfig
+	cacheControl string
+	lock         sync.Mutex
+	entries      map[string]*list.Element
+	// order has the most recently used entry at the front.
+	order *list.List
+	// generation is incremented by Invalidate, so that a response produced
+	// from the data before an invalidation is not stored after it.
+	generation uint64
+}
+
+type responseCacheEntry struct {
+	key         string
+	contentType string
+	vary        string
+	body        []byte
+}
+
+func NewResponseCache(cfg ResponseCacheConfig) *ResponseCache {
+	cacheControl := "private, no-cache"
+	if cfg.MaxAge > 0 {
+		cacheControl = fmt.Sprintf("private, max-age=%d", int(cfg.MaxAge.Seconds()))
+	}
+	return &ResponseCache{
+		cfg:          cfg,
+		cacheControl: cacheControl,
+		entries:      make(map[string]*list.Element),
+		order:        list.New(),
+	}
+}
+
+// Invalidate drops all the cached responses.
+func (rc *ResponseCache) Invalidate() {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	rc.generation++
+	clear(rc.entries)
+	rc.order.Init()
+	responseCacheInvalidations.Add(1)
+}
+
+// Handle is the middleware that serves the cached responses. It sets the
+// Cache-Control and Last-Modified headers and answers the requests with an
+// If-Modified-Since header that is not older than the last change with 304.
+// Only the successful responses become 304, so that a missing resource or
+// an unsupported Accept header is still answered with its error.
+func (rc *ResponseCache) Handle(c *fiber.Ctx) error {
+	if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+		return c.Next()
+	}
+	c.Set(fiber.HeaderCacheControl, rc.cacheControl)
+	// Last-Modified has a resolution of one second, so it is only sent once
+	// the second of the last change is over. Otherwise a later change in the
+	// same second would have the same Last-Modified.
+	notModified := false
+	if rc.cfg.LastModified != nil {
+		lastModified := rc.cfg.LastModified().Truncate(time.Second)
+		if lastModified.Before(time.Now().Truncate(time.Second)) {
+			c.Set(fiber.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+			notModified = notModifiedSince(c, lastModified)
+		}
+	}
+	if rc.cfg.MaxEntries <= 0 {
+		if err := c.Next(); err != nil {
+			return err
+		}
+		return rc.sendNotModified(c, notModified)
+	}
+
+	key := responseCacheKey(c)
+	if entry, ok := rc.get(key); ok {
+		responseCacheHits.Add(1)
+		c.Set(fiber.HeaderContentType, entry.contentType)
+		if entry.vary != "" {
+			c.Set(fiber.HeaderVary, entry.vary)
+		}
+		if err := c.Status(fiber.StatusOK).Send(entry.body); err != nil {
+			return err
+		}
+		return rc.sendNotModified(c, notModified)
+	}
+	responseCacheMisses.Add(1)
+	generation := rc.currentGeneration()
+	if err := c.Next(); err != nil {
+		return err
+	}
+	response := c.Response()
+	if c.Method() == fiber.MethodGet && response.StatusCode() == fiber.StatusOK {
+		rc.put(generation, &responseCacheEntry{
+			key:         key,
+			contentType: string(response.Header.ContentType()),
+			vary:        string(response.Header.Peek(fiber.HeaderVary)),
+			body:        bytes.Clone(response.Body()),
+		})
+	}
+	return rc.sendNotModified(c, notModified)
+}
+
+// sendNotModified replaces the successful response with 304 when the
+// resource has not changed since the If-Modified-Since header.
+func (rc *ResponseCache) sendNotModified(c *fiber.Ctx, notModified bool) error {
+	if !notModified || c.Response().StatusCode() != fiber.StatusOK {
+		return nil
+	}
+	responseCacheNotModified.Add(1)
+	c.Response().ResetBody()
+	c.Status(fiber.StatusNotModified)
+	return nil
+}
+
+func (rc *ResponseCache) get(key string) (*responseCacheEntry, bool) {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	element, ok := rc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	rc.order.MoveToFront(element)
+	return element.Value.(*responseCacheEntry), true
+}
+
+func (rc *ResponseCache) put(generation uint64, entry *responseCacheEntry) {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	if generation != rc.generation {
+		return
+	}
+	if element, ok := rc.entries[entry.key]; ok {
+		element.Value = entry
+		rc.order.MoveToFront(element)
+		return
+	}
+	rc.entries[entry.key] = rc.order.PushFront(entry)
+	for rc.order.Len() > rc.cfg.MaxEntries {
+		oldest := rc.order.Back()
+		rc.order.Remove(oldest)
+		delete(rc.entries, oldest.Value.(*responseCacheEntry).key)
+		responseCacheEvictions.Add(1)
+	}
+}
+
+func (rc *ResponseCache) currentGeneration() uint64 {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	return rc.generation
+}
+
+// responseCacheKey returns the key of the response, which differs for the
+// tenants, the callers and the representations they accept.
+func responseCacheKey(c *fiber.Ctx) string {
+	subject := ""
+	if principal, ok := utils.GetPrincipal(c); ok {
+		subject = principal.Subject
+	}
+	tenant, _ := utils.GetTenant(c)
+	return strings.Join([]string{c.Path(), string(c.Request().URI().QueryString()), c.Get(fiber.HeaderAccept), subject, tenant}, "\x00")
+}
+
+// notModifiedSince reports whether the If-Modified-Since header of the
+// request is not older than lastModified. As required by RFC 9110, invalid
+// dates and dates in the future are ignored.
+func notModifiedSince(c *fiber.Ctx, lastModified time.Time) bool {
+	value := c.Get(fiber.HeaderIfModifiedSince)
+	if value == "" {
+		return false
+	}
+	since, err := http.ParseTime(value)
+	if err != nil || since.After(time.Now()) {
+		return false
+	}
+	return !lastModified.After(since)
+}
+
+func responseCacheHitRatio() any {
+	hits, misses := responseCacheHits.Value(), responseCacheMisses.Value()
+	if hits+misses == 0 {
+		return 0.0
+	}
+	return float64(hits) / float64(hits+misses)
+}
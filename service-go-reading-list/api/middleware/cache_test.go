@@ -0,0 +1,175 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCache(t *testing.T) {
+	lastModified := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	calls := 0
+	newApp := func(cache *ResponseCache, handler fiber.Handler) *fiber.App {
+		app := fiber.New()
+		app.Get("/books", cache.Handle, func(c *fiber.Ctx) error {
+			calls++
+			return handler(c)
+		})
+		return app
+	}
+	sendBooks := func(c *fiber.Ctx) error {
+		c.Vary(fiber.HeaderAccept)
+		return c.JSON(fiber.Map{"call": calls})
+	}
+	get := func(app *fiber.App, target string, headers map[string]string) (*http.Response, string) {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(body)
+	}
+	newCache := func(maxEntries int) *ResponseCache {
+		calls = 0
+		return NewResponseCache(ResponseCacheConfig{
+			LastModified: func() time.Time { return lastModified },
+			MaxEntries:   maxEntries,
+		})
+	}
+
+	t.Run("Hit", func(t *testing.T) {
+		app := newApp(newCache(10), sendBooks)
+		hits := responseCacheHits.Value()
+		get(app, "/books", nil)
+		resp, body := get(app, "/books", nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, `{"call":1}`, body)
+		assert.Equal(t, fiber.MIMEApplicationJSON, resp.Header.Get(fiber.HeaderContentType))
+		assert.Equal(t, fiber.HeaderAccept, resp.Header.Get(fiber.HeaderVary))
+		assert.Equal(t, "private, no-cache", resp.Header.Get(fiber.HeaderCacheControl))
+		assert.Equal(t, "Tue, 02 Jan 2024 15:04:05 GMT", resp.Header.Get(fiber.HeaderLastModified))
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, hits+1, responseCacheHits.Value())
+	})
+
+	t.Run("Key", func(t *testing.T) {
+		app := newApp(newCache(10), sendBooks)
+		get(app, "/books", nil)
+		_, body := get(app, "/books?limit=1", nil)
+		assert.Equal(t, `{"call":2}`, body)
+		_, body = get(app, "/books", map[string]string{fiber.HeaderAccept: fiber.MIMEApplicationXML})
+		assert.Equal(t, `{"call":3}`, body)
+	})
+
+	t.Run("Invalidate", func(t *testing.T) {
+		cache := newCache(10)
+		app := newApp(cache, sendBooks)
+		get(app, "/books", nil)
+		cache.Invalidate()
+		_, body := get(app, "/books", nil)
+		assert.Equal(t, `{"call":2}`, body)
+	})
+
+	t.Run("InvalidatedWhileHandling", func(t *testing.T) {
+		cache := newCache(10)
+		app := newApp(cache, func(c *fiber.Ctx) error {
+			cache.Invalidate()
+			return sendBooks(c)
+		})
+		get(app, "/books", nil)
+		get(app, "/books", nil)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Eviction", func(t *testing.T) {
+		app := newApp(newCache(1), sendBooks)
+		get(app, "/books?offset=0", nil)
+		get(app, "/books?offset=1", nil)
+		_, body := get(app, "/books?offset=0", nil)
+		assert.Equal(t, `{"call":3}`, body)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		app := newApp(newCache(0), sendBooks)
+		get(app, "/books", nil)
+		resp, body := get(app, "/books", nil)
+		assert.Equal(t, `{"call":2}`, body)
+		assert.Equal(t, "Tue, 02 Jan 2024 15:04:05 GMT", resp.Header.Get(fiber.HeaderLastModified))
+	})
+
+	t.Run("ErrorsNotCached", func(t *testing.T) {
+		app := newApp(newCache(10), func(c *fiber.Ctx) error {
+			return fiber.NewError(http.StatusNotFound, "not found")
+		})
+		get(app, "/books", nil)
+		resp, _ := get(app, "/books", nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("NotModified", func(t *testing.T) {
+		app := newApp(newCache(10), sendBooks)
+		resp, body := get(app, "/books", map[string]string{fiber.HeaderIfModifiedSince: "Tue, 02 Jan 2024 15:04:05 GMT"})
+		assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+		assert.Empty(t, body)
+		assert.Equal(t, "Tue, 02 Jan 2024 15:04:05 GMT", resp.Header.Get(fiber.HeaderLastModified))
+		// The response is made, so that it can be told apart from an error,
+		// and the next ones are answered from the cache.
+		assert.Equal(t, 1, calls)
+		resp, _ = get(app, "/books", map[string]string{fiber.HeaderIfModifiedSince: "Tue, 02 Jan 2024 15:04:05 GMT"})
+		assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+		assert.Equal(t, 1, calls)
+
+		for _, since := range []string{"Tue, 02 Jan 2024 15:04:04 GMT", "Fri, 01 Jan 2100 00:00:00 GMT", "yesterday"} {
+			resp, _ = get(app, "/books", map[string]string{fiber.HeaderIfModifiedSince: since})
+			assert.Equal(t, http.StatusOK, resp.StatusCode, since)
+		}
+	})
+
+	t.Run("ErrorsNotModified", func(t *testing.T) {
+		// A missing resource or an unsupported Accept header is reported
+		// rather than answered with 304.
+		for _, cache := range []*ResponseCache{newCache(10), newCache(0)} {
+			app := newApp(cache, func(c *fiber.Ctx) error {
+				return fiber.NewError(http.StatusNotAcceptable, "not acceptable")
+			})
+			resp, _ := get(app, "/books", map[string]string{fiber.HeaderIfModifiedSince: "Tue, 02 Jan 2024 15:04:05 GMT"})
+			assert.Equal(t, http.StatusNotAcceptable, resp.StatusCode)
+		}
+	})
+
+	t.Run("ModifiedInCurrentSecond", func(t *testing.T) {
+		calls = 0
+		cache := NewResponseCache(ResponseCacheConfig{
+			// Not over before the request is answered.
+			LastModified: func() time.Time { return time.Now().Add(time.Second) },
+			MaxAge:       time.Minute,
+		})
+		app := newApp(cache, sendBooks)
+		resp, _ := get(app, "/books", map[string]string{fiber.HeaderIfModifiedSince: time.Now().UTC().Format(http.TimeFormat)})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get(fiber.HeaderLastModified))
+		assert.Equal(t, "private, max-age=60", resp.Header.Get(fiber.HeaderCacheControl))
+	})
+
+	t.Run("NoLastModified", func(t *testing.T) {
+		calls = 0
+		app := newApp(NewResponseCache(ResponseCacheConfig{}), sendBooks)
+		resp, _ := get(app, "/books", map[string]string{fiber.HeaderIfModifiedSince: "Fri, 01 Jan 2100 00:00:00 GMT"})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get(fiber.HeaderLastModified))
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -0,0 +1,48 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	DeprecationHeaderName = "Deprecation"
+	SunsetHeaderName      = "Sunset"
+)
+
+type DeprecationConfig struct {
+	// DeprecatedAt is when the API was or will be deprecated.
+	DeprecatedAt time.Time
+	// SunsetAt is when the API is expected to stop responding.
+	SunsetAt time.Time
+	// Successor is the path of the API that replaces the deprecated one.
+	Successor string
+}
+
+// NewDeprecation returns a middleware that announces the deprecation of the
+// API with the Deprecation header of RFC 9745 and the Sunset header of
+// RFC 8594, linking to the successor version. The headers are set on all the
+// responses, errors included. Nothing is set when neither date is configured.
+func NewDeprecation(cfg DeprecationConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if cfg.DeprecatedAt.IsZero() && cfg.SunsetAt.IsZero() {
+			return c.Next()
+		}
+		if !cfg.DeprecatedAt.IsZero() {
+			c.Set(DeprecationHeaderName, fmt.Sprintf("@%d", cfg.DeprecatedAt.Unix()))
+		}
+		if !cfg.SunsetAt.IsZero() {
+			c.Set(SunsetHeaderName, cfg.SunsetAt.UTC().Format(http.TimeFormat))
+		}
+		if cfg.Successor != "" {
+			c.Append(fiber.HeaderLink, fmt.Sprintf(`<%s>; rel="successor-version"`, cfg.Successor))
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecation(t *testing.T) {
+	newApp := func(cfg DeprecationConfig) *fiber.App {
+		app := fiber.New()
+		app.Use(NewDeprecation(cfg))
+		app.Get("/books", func(c *fiber.Ctx) error {
+			return c.SendString("books")
+		})
+		return app
+	}
+	get := func(app *fiber.App, target string) *http.Response {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, target, nil))
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Deprecated", func(t *testing.T) {
+		app := newApp(DeprecationConfig{
+			DeprecatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			SunsetAt:     time.Date(2025, 12, 31, 23, 59, 59, 0, time.FixedZone("CET", 3600)),
+			Successor:    "/api/v2/reading-list",
+		})
+		resp := get(app, "/books")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "@1735689600", resp.Header.Get(DeprecationHeaderName))
+		assert.Equal(t, "Wed, 31 Dec 2025 22:59:59 GMT", resp.Header.Get(SunsetHeaderName))
+		assert.Equal(t, `</api/v2/reading-list>; rel="successor-version"`, resp.Header.Get(fiber.HeaderLink))
+	})
+
+	t.Run("ErrorResponse", func(t *testing.T) {
+		app := newApp(DeprecationConfig{DeprecatedAt: time.Unix(1735689600, 0)})
+		resp := get(app, "/missing")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, "@1735689600", resp.Header.Get(DeprecationHeaderName))
+		assert.Empty(t, resp.Header.Get(SunsetHeaderName))
+		assert.Empty(t, resp.Header.Get(fiber.HeaderLink))
+	})
+
+	t.Run("NotConfigured", func(t *testing.T) {
+		app := newApp(DeprecationConfig{Successor: "/api/v2/reading-list"})
+		resp := get(app, "/books")
+		assert.Empty(t, resp.Header.Get(DeprecationHeaderName))
+		assert.Empty(t, resp.Header.Get(SunsetHeaderName))
+		assert.Empty(t, resp.Header.Get(fiber.HeaderLink))
+	})
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+const (
+	IdempotencyKeyHeaderName      = "Idempotency-Key"
+	IdempotencyReplayedHeaderName = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	idempotencySweepPeriod  = time.Minute
+)
+
+// idempotencyEntry is the first response recorded for an idempotency key.
+type idempotencyEntry struct {
+	fingerprint string
+	// completed is false while the first request is still being processed.
+	completed   bool
+	status      int
+	contentType string
+	body        []byte
+	expiresAt   time.Time
+}
+
+type idempotencyStore struct {
+	lock      sync.Mutex
+	entries   map[string]*idempotencyEntry
+	ttl       time.Duration
+	lastSweep time.Time
+}
+
+// NewIdempotency returns a middleware that replays the response of the first
+// request made with an Idempotency-Key header for the given ttl. Reusing a key
+// with a different request is rejected with 422, and a retry that arrives
+// while the first request is still in progress is rejected with 409.
+// Requests without the header are not affected. Error responses are not
+// recorded, so they can be retried with the same key.
+func NewIdempotency(ttl time.Duration) fiber.Handler {
+	store := &idempotencyStore{
+		entries: make(map[string]*idempotencyEntry),
+		ttl:     ttl,
+	}
+	return store.handle
+}
+
+func (s *idempotencyStore) handle(c *fiber.Ctx) error {
+	key := c.Get(IdempotencyKeyHeaderName)
+	if key == "" {
+		return c.Next()
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return fiber.NewError(http.StatusBadRequest, "the idempotency key must not be longer than 255 characters")
+	}
+	// The keys of the tenants are apart, as the same request applies to different books.
+	tenant, _ := utils.GetTenant(c)
+	storeKey := c.Method() + " " + c.Path() + " " + tenant + " " + key
+	fingerprint := requestFingerprint(c)
+
+	s.lock.Lock()
+	now := time.Now()
+	s.sweep(now)
+	if entry, ok := s.entries[storeKey]; ok && now.Before(entry.expiresAt) {
+		s.lock.Unlock()
+		switch {
+		case entry.fingerprint != fingerprint:
+			return fiber.NewError(http.StatusUnprocessableEntity, "the idempotency key was already used for a different request")
+		case !entry.completed:
+			return fiber.NewError(http.StatusConflict, "a request with the same idempotency key is in progress")
+		}
+		c.Set(IdempotencyReplayedHeaderName, "true")
+		c.Set(fiber.HeaderContentType, entry.contentType)
+		return c.Status(entry.status).Send(entry.body)
+	}
+	entry := &idempotencyEntry{fingerprint: fingerprint, expiresAt: now.Add(s.ttl)}
+	s.entries[storeKey] = entry
+	s.lock.Unlock()
+
+	err := c.Next()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	status := c.Response().StatusCode()
+	if err != nil || status >= http.StatusInternalServerError {
+		delete(s.entries, storeKey)
+		return err
+	}
+	entry.completed = true
+	entry.status = status
+	entry.contentType = string(c.Response().Header.ContentType())
+	entry.body = append([]byte(nil), c.Response().Body()...)
+	return nil
+}
+
+// sweep removes the expired entries at most once per idempotencySweepPeriod.
+// Callers must hold the lock.
+func (s *idempotencyStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < idempotencySweepPeriod {
+		return
+	}
+	s.lastSweep = now
+	for key, entry := range s.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+func requestFingerprint(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(c.OriginalURL()))
+	hash.Write([]byte{0})
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotency(t *testing.T) {
+	calls := 0
+	app := fiber.New()
+	app.Post("/books", NewIdempotency(time.Hour), func(c *fiber.Ctx) error {
+		calls++
+		if string(c.Body()) == "fail" {
+			return fiber.NewError(http.StatusInternalServerError, "internal server error")
+		}
+		return c.Status(http.StatusCreated).JSON(fiber.Map{"call": calls})
+	})
+
+	post := func(key, body string) (*http.Response, string) {
+		req := httptest.NewRequest(http.MethodPost, "/books", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeaderName, key)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(respBody)
+	}
+
+	t.Run("Replay", func(t *testing.T) {
+		resp, body := post("key-1", "book")
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, `{"call":1}`, body)
+
+		resp, body = post("key-1", "book")
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, `{"call":1}`, body)
+		assert.Equal(t, "true", resp.Header.Get(IdempotencyReplayedHeaderName))
+		assert.Equal(t, fiber.MIMEApplicationJSON, resp.Header.Get(fiber.HeaderContentType))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("DifferentBody", func(t *testing.T) {
+		resp, _ := post("key-1", "another book")
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("WithoutKey", func(t *testing.T) {
+		post("", "book")
+		post("", "book")
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("ErrorsAreNotRecorded", func(t *testing.T) {
+		resp, _ := post("key-2", "fail")
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		resp, _ = post("key-2", "fail")
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, 5, calls)
+	})
+}
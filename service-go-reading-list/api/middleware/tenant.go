@@ -0,0 +1,89 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/tenancy"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+// TenantResolver returns the tenant named by the request, or an empty
+// string when it names none.
+type TenantResolver func(c *fiber.Ctx) string
+
+// TenantFromHeader reads the tenant from the header. Any client can set it,
+// so it is only trusted behind a gateway that sets it, or when it is checked
+// against the tenant claim of the bearer token.
+func TenantFromHeader(name string) TenantResolver {
+	return func(c *fiber.Ctx) string {
+		return c.Get(name)
+	}
+}
+
+// TenantFromHost reads the tenant from the subdomain of the suffix in the
+// host name, e.g. acme from acme.books.example.com with the suffix
+// .books.example.com.
+func TenantFromHost(suffix string) TenantResolver {
+	suffix = "." + strings.TrimPrefix(strings.ToLower(suffix), ".")
+	return func(c *fiber.Ctx) string {
+		tenant, ok := strings.CutSuffix(strings.ToLower(c.Hostname()), suffix)
+		if !ok || strings.Contains(tenant, ".") {
+			return ""
+		}
+		return tenant
+	}
+}
+
+// TenantFromClaim reads the tenant from a string claim of the bearer token.
+// It must run after the authorization middleware.
+func TenantFromClaim(claim string) TenantResolver {
+	return func(c *fiber.Ctx) string {
+		principal, ok := utils.GetPrincipal(c)
+		if !ok {
+			return ""
+		}
+		tenant, _ := principal.Claims[claim].(string)
+		return tenant
+	}
+}
+
+// NewTenantResolution returns a middleware that resolves the tenant of each
+// request, whose data is then isolated from the other tenants. The requests
+// that do not name a tenant are served for the fallback tenant, or rejected
+// with 400 when there is none, as are the requests naming an invalid tenant.
+// When claim is set, the requests whose bearer token has no string claim
+// naming the tenant are rejected with 403. It must then run after the
+// authorization middleware.
+func NewTenantResolution(resolver TenantResolver, fallback string, claim string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tenant := resolver(c)
+		if tenant == "" {
+			tenant = fallback
+		}
+		if tenant == "" {
+			return fiber.NewError(http.StatusBadRequest, "the tenant of the request is required")
+		}
+		// The header is copied, as it points into the request buffer, which
+		// is reused once the request is served, while the tenant keys the
+		// data of the tenant.
+		tenant, ok := tenancy.NormalizeId(strings.Clone(tenant))
+		if !ok {
+			return fiber.NewError(http.StatusBadRequest, fmt.Sprintf("the tenant [%s] is not valid", tenant))
+		}
+		if claim != "" {
+			tokenTenant, _ := tenancy.NormalizeId(TenantFromClaim(claim)(c))
+			if tokenTenant != tenant {
+				return fiber.NewError(http.StatusForbidden, fmt.Sprintf("the tenant [%s] is not the tenant of the token", tenant))
+			}
+		}
+		utils.SetTenant(c, tenant)
+		return c.Next()
+	}
+}
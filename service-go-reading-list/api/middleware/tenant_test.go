@@ -0,0 +1,117 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/auth"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+func TestTenantResolution(t *testing.T) {
+	// newApp authorizes the requests with the principal, when it is set,
+	// whose tenant is then checked.
+	newApp := func(resolver TenantResolver, fallback string, principal *auth.Principal) *fiber.App {
+		app := fiber.New()
+		claim := ""
+		if principal != nil {
+			app.Use(func(c *fiber.Ctx) error {
+				utils.SetPrincipal(c, principal)
+				return c.Next()
+			})
+			claim = "org"
+		}
+		app.Use(NewTenantResolution(resolver, fallback, claim))
+		app.Get("/books", func(c *fiber.Ctx) error {
+			tenant, _ := utils.GetTenant(c)
+			return c.SendString(tenant)
+		})
+		return app
+	}
+	get := func(app *fiber.App, req *http.Request) (int, string) {
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	t.Run("Header", func(t *testing.T) {
+		app := newApp(TenantFromHeader("X-Tenant-Id"), "", nil)
+		req := httptest.NewRequest(http.MethodGet, "/books", nil)
+		req.Header.Set("X-Tenant-Id", "Acme")
+		status, body := get(app, req)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "acme", body)
+
+		status, body = get(app, httptest.NewRequest(http.MethodGet, "/books", nil))
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "the tenant of the request is required", body)
+
+		req = httptest.NewRequest(http.MethodGet, "/books", nil)
+		req.Header.Set("X-Tenant-Id", "acme/books")
+		status, body = get(app, req)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "the tenant [acme/books] is not valid", body)
+	})
+
+	t.Run("HeaderOfToken", func(t *testing.T) {
+		// The header cannot name another tenant than the one of the token.
+		app := newApp(TenantFromHeader("X-Tenant-Id"), "", &auth.Principal{Claims: map[string]interface{}{"org": "Acme"}})
+		req := httptest.NewRequest(http.MethodGet, "/books", nil)
+		req.Header.Set("X-Tenant-Id", "acme")
+		status, body := get(app, req)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "acme", body)
+
+		req = httptest.NewRequest(http.MethodGet, "/books", nil)
+		req.Header.Set("X-Tenant-Id", "globex")
+		status, body = get(app, req)
+		assert.Equal(t, http.StatusForbidden, status)
+		assert.Equal(t, "the tenant [globex] is not the tenant of the token", body)
+
+		// The tokens without the claim cannot name any tenant.
+		app = newApp(TenantFromHeader("X-Tenant-Id"), "", &auth.Principal{})
+		status, _ = get(app, req)
+		assert.Equal(t, http.StatusForbidden, status)
+	})
+
+	t.Run("Host", func(t *testing.T) {
+		app := newApp(TenantFromHost(".books.example.com"), "", nil)
+		status, body := get(app, httptest.NewRequest(http.MethodGet, "http://acme.books.example.com/books", nil))
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "acme", body)
+
+		status, _ = get(app, httptest.NewRequest(http.MethodGet, "http://books.example.com/books", nil))
+		assert.Equal(t, http.StatusBadRequest, status)
+		status, _ = get(app, httptest.NewRequest(http.MethodGet, "http://eu.acme.books.example.com/books", nil))
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("Claim", func(t *testing.T) {
+		app := newApp(TenantFromClaim("org"), "", &auth.Principal{Claims: map[string]interface{}{"org": "globex"}})
+		status, body := get(app, httptest.NewRequest(http.MethodGet, "/books", nil))
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "globex", body)
+
+		app = newApp(TenantFromClaim("org"), "", &auth.Principal{Claims: map[string]interface{}{"org": 42}})
+		status, _ = get(app, httptest.NewRequest(http.MethodGet, "/books", nil))
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("Fallback", func(t *testing.T) {
+		app := newApp(TenantFromClaim("org"), "default", nil)
+		status, body := get(app, httptest.NewRequest(http.MethodGet, "/books", nil))
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "default", body)
+	})
+}
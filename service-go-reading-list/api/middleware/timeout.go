@@ -0,0 +1,70 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// The metrics of the request contexts are published with expvar under
+// request_context.
+var (
+	requestsDeadlineExceeded = new(expvar.Int)
+	requestsCanceled         = new(expvar.Int)
+)
+
+func init() {
+	metrics := expvar.NewMap("request_context")
+	metrics.Set("deadline_exceeded", requestsDeadlineExceeded)
+	metrics.Set("canceled", requestsCanceled)
+}
+
+// NewCancellation returns a middleware that derives the context of every
+// request from ctx, so that the requests still running when ctx is canceled,
+// e.g. because the service is shutting down, fail with context.Canceled,
+// which is answered with 503.
+func NewCancellation(ctx context.Context) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.SetUserContext(ctx)
+		return c.Next()
+	}
+}
+
+// NewTimeout returns a middleware that gives the context of every request,
+// which utils.GetRequestContext passes to the controllers and the
+// repositories, a deadline of timeout from when the request is received.
+// The operations that are still running at the deadline fail with
+// context.DeadlineExceeded, which is answered with 504. The requests are not
+// given a deadline when timeout is zero, but their cancellation is still
+// counted.
+func NewTimeout(timeout time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+			c.SetUserContext(ctx)
+		}
+		err := c.Next()
+		switch ctxErr := ctx.Err(); {
+		case errors.Is(ctxErr, context.DeadlineExceeded):
+			requestsDeadlineExceeded.Add(1)
+			logrus.WithFields(logrus.Fields{
+				"method":  c.Method(),
+				"path":    c.Path(),
+				"timeout": timeout,
+			}).Warn("the request exceeded its deadline")
+		case errors.Is(ctxErr, context.Canceled):
+			requestsCanceled.Add(1)
+		}
+		return err
+	}
+}
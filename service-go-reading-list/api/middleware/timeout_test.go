@@ -0,0 +1,80 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+func TestTimeout(t *testing.T) {
+	newApp := func(timeout time.Duration) *fiber.App {
+		app := fiber.New(fiber.Config{ErrorHandler: utils.FiberErrorHandler})
+		app.Use(NewTimeout(timeout))
+		// The slow operation waits for the context of the request.
+		app.Get("/slow", func(c *fiber.Ctx) error {
+			ctx := utils.GetRequestContext(c)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Second):
+				return c.SendString("done")
+			}
+		})
+		app.Get("/deadline", func(c *fiber.Ctx) error {
+			_, ok := utils.GetRequestContext(c).Deadline()
+			return c.JSON(ok)
+		})
+		return app
+	}
+	get := func(app *fiber.App, target string) (int, string) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, target, nil))
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	t.Run("DeadlineExceeded", func(t *testing.T) {
+		exceeded := requestsDeadlineExceeded.Value()
+		status, body := get(newApp(10*time.Millisecond), "/slow")
+		assert.Equal(t, http.StatusGatewayTimeout, status)
+		assert.JSONEq(t, `{"message":"the request timed out"}`, body)
+		assert.Equal(t, exceeded+1, requestsDeadlineExceeded.Value())
+	})
+
+	t.Run("Deadline", func(t *testing.T) {
+		_, body := get(newApp(time.Minute), "/deadline")
+		assert.Equal(t, "true", body)
+		// The requests have no deadline when the timeout is zero.
+		_, body = get(newApp(0), "/deadline")
+		assert.Equal(t, "false", body)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		canceled := requestsCanceled.Value()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		app := fiber.New(fiber.Config{ErrorHandler: utils.FiberErrorHandler})
+		app.Use(NewCancellation(ctx))
+		app.Use(NewTimeout(time.Minute))
+		app.Get("/books", func(c *fiber.Ctx) error {
+			return utils.GetRequestContext(c).Err()
+		})
+		status, body := get(app, "/books")
+		assert.Equal(t, http.StatusServiceUnavailable, status)
+		assert.JSONEq(t, `{"message":"the request was canceled"}`, body)
+		assert.Equal(t, canceled+1, requestsCanceled.Value())
+	})
+}
@@ -0,0 +1,222 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/sirupsen/logrus"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/openapi"
+)
+
+// unsupportedContentTypeReason is the prefix of the reason reported by
+// openapi3filter when the request body has a content type that is not
+// declared for the operation.
+const unsupportedContentTypeReason = "header Content-Type has unexpected value"
+
+// schemaMediaTypes are the media types of the bodies that are validated
+// against their schemas. The bodies of the other media types, such as XML and
+// CSV, are only checked to have a content type declared for the operation.
+var schemaMediaTypes = []string{fiber.MIMEApplicationJSON, "application/yaml"}
+
+type ValidationConfig struct {
+	// ValidateResponses enables the validation of the responses, which is
+	// meant for development and testing as it buffers and decodes every
+	// response body.
+	ValidateResponses bool
+	// FailOnDrift replaces a response that does not conform to the
+	// definition with a 500 instead of only logging it.
+	FailOnDrift bool
+}
+
+// NewValidation returns a middleware that validates the parameters, the
+// content type and the body of each request against the operation declared
+// for it in the OpenAPI definition before it reaches the handler. Invalid
+// requests are rejected with 400, and bodies with an undeclared content type
+// with 415. Properties that are not declared in the schemas are rejected too.
+// Requests outside the base path of the definition are passed through, and
+// the requests under it that do not match any operation are answered with
+// 404 without reaching a route, as they cannot be validated.
+func NewValidation(spec *openapi.Spec, cfg ValidationConfig) fiber.Handler {
+	spec.DisallowAdditionalProperties()
+	options := &openapi3filter.Options{
+		// The security requirements are enforced by the authorization middleware.
+		AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+		SkipSettingDefaults:   true,
+		IncludeResponseStatus: true,
+	}
+	return func(c *fiber.Ctx) error {
+		method := c.Method()
+		if method == fiber.MethodHead {
+			method = fiber.MethodGet
+		}
+		route, params, ok := spec.FindRoute(method, c.Path())
+		if !ok {
+			if spec.HasPath(c.Path()) {
+				return makeHttpUndeclaredRouteError(c)
+			}
+			return c.Next()
+		}
+		request, err := adaptor.ConvertRequest(c, false)
+		if err != nil {
+			logrus.Errorf("failed to convert the request for validation: %v", err)
+			return fiber.NewError(http.StatusInternalServerError, "internal server error")
+		}
+		input := &openapi3filter.RequestValidationInput{
+			Request:    request,
+			PathParams: params,
+			Route: &routers.Route{
+				Spec:      spec.Doc,
+				Path:      route.Path,
+				PathItem:  route.PathItem,
+				Method:    route.Method,
+				Operation: route.Operation,
+			},
+			Options: options,
+		}
+		if contentType := c.Get(fiber.HeaderContentType); len(c.Body()) > 0 && !isSchemaMediaType(contentType) {
+			if requestBody := route.Operation.RequestBody; requestBody != nil && requestBody.Value.Content.Get(contentType) == nil {
+				return makeHttpUnsupportedContentTypeError(contentType)
+			}
+			requestOptions := *options
+			requestOptions.ExcludeRequestBody = true
+			input.Options = &requestOptions
+		}
+		if err := openapi3filter.ValidateRequest(c.UserContext(), input); err != nil {
+			return makeHttpValidationError(err)
+		}
+		if !cfg.ValidateResponses {
+			return c.Next()
+		}
+
+		// Render the error of the handler first, so that the error
+		// response is validated as well.
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				return err
+			}
+		}
+		// The responses to the requests that timed out or were canceled are
+		// about the request rather than the operation, so they are not
+		// documented by the operations.
+		if c.UserContext().Err() != nil {
+			return nil
+		}
+		response := c.Response()
+		header := make(http.Header)
+		response.Header.VisitAll(func(key, value []byte) {
+			header.Add(string(key), string(value))
+		})
+		responseInput := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 response.StatusCode(),
+			Header:                 header,
+			Options:                options,
+		}
+		responseInput.SetBodyBytes(response.Body())
+		if err := validateResponse(c, route, responseInput); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"method": c.Method(),
+				"path":   c.Path(),
+				"status": response.StatusCode(),
+			}).Warnf("the response does not conform to the OpenAPI definition: %s", describeValidationError(err))
+			if cfg.FailOnDrift {
+				return fiber.NewError(http.StatusInternalServerError, "the response does not conform to the OpenAPI definition")
+			}
+		}
+		return nil
+	}
+}
+
+// validateResponse validates the response, only checking that the content
+// type of a body that cannot be validated against its schema is declared.
+func validateResponse(c *fiber.Ctx, route *openapi.Route, input *openapi3filter.ResponseValidationInput) error {
+	contentType := input.Header.Get(fiber.HeaderContentType)
+	if len(c.Response().Body()) == 0 || isSchemaMediaType(contentType) {
+		return openapi3filter.ValidateResponse(c.UserContext(), input)
+	}
+	options := *input.Options
+	options.ExcludeResponseBody = true
+	input.Options = &options
+	if err := openapi3filter.ValidateResponse(c.UserContext(), input); err != nil {
+		return err
+	}
+	response := route.Operation.Responses.Status(input.Status)
+	if response != nil && len(response.Value.Content) > 0 && response.Value.Content.Get(contentType) == nil {
+		return &openapi3filter.ResponseError{Input: input, Reason: fmt.Sprintf("the content type [%s] is not declared", contentType)}
+	}
+	return nil
+}
+
+func isSchemaMediaType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && slices.Contains(schemaMediaTypes, mediaType)
+}
+
+func makeHttpValidationError(err error) *fiber.Error {
+	var requestErr *openapi3filter.RequestError
+	if errors.As(err, &requestErr) && requestErr.RequestBody != nil && strings.HasPrefix(requestErr.Reason, unsupportedContentTypeReason) {
+		return makeHttpUnsupportedContentTypeError(requestErr.Input.Request.Header.Get(fiber.HeaderContentType))
+	}
+	return fiber.NewError(http.StatusBadRequest, describeValidationError(err))
+}
+
+func makeHttpUnsupportedContentTypeError(contentType string) *fiber.Error {
+	return fiber.NewError(http.StatusUnsupportedMediaType, fmt.Sprintf("the content type [%s] is not supported", contentType))
+}
+
+// describeValidationError returns a one line description of a validation
+// error, without the schema dump included in its Error.
+func describeValidationError(err error) string {
+	var requestErr *openapi3filter.RequestError
+	var responseErr *openapi3filter.ResponseError
+	var schemaErr *openapi3.SchemaError
+	switch {
+	case errors.As(err, &requestErr) && requestErr.Parameter != nil:
+		return fmt.Sprintf("invalid %s parameter [%s]: %s", requestErr.Parameter.In, requestErr.Parameter.Name, describeCause(requestErr.Reason, requestErr.Err))
+	case errors.As(err, &requestErr) && errors.Is(requestErr.Err, openapi3filter.ErrInvalidRequired):
+		return "the request body is required"
+	case errors.As(err, &requestErr):
+		return "invalid request body: " + describeCause(requestErr.Reason, requestErr.Err)
+	case errors.As(err, &responseErr):
+		return describeCause(responseErr.Reason, responseErr.Err)
+	case errors.As(err, &schemaErr):
+		return describeCause("", schemaErr)
+	}
+	return err.Error()
+}
+
+func describeCause(reason string, cause error) string {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(cause, &schemaErr) {
+		// The reason of a composed schema such as allOf is generic, the
+		// reason of the schema that failed is more useful.
+		reason := schemaErr.Reason
+		for origin := schemaErr; errors.As(origin.Origin, &origin); {
+			reason = origin.Reason
+		}
+		if pointer := schemaErr.JSONPointer(); len(pointer) > 0 {
+			return fmt.Sprintf("%s at [%s]", reason, strings.Join(pointer, "."))
+		}
+		return reason
+	}
+	if cause == nil {
+		return reason
+	}
+	if reason == "" {
+		return cause.Error()
+	}
+	return reason + ": " + cause.Error()
+}
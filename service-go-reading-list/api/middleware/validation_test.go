@@ -0,0 +1,192 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/openapi"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+const testValidationSpec = `
+openapi: 3.0.1
+info:
+  title: Test
+  version: "1.0"
+servers:
+- url: /api
+paths:
+  /books:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Book'
+          application/xml:
+            schema:
+              $ref: '#/components/schemas/Book'
+        required: true
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Book'
+  /books/{id}:
+    delete:
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+      - name: hard
+        in: query
+        schema:
+          type: boolean
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Book'
+components:
+  schemas:
+    Book:
+      type: object
+      required:
+      - title
+      properties:
+        id:
+          type: string
+        title:
+          type: string
+`
+
+func newValidationTestApp(t *testing.T, cfg ValidationConfig) *fiber.App {
+	spec, err := openapi.Load([]byte(testValidationSpec))
+	require.NoError(t, err)
+	app := fiber.New(fiber.Config{ErrorHandler: utils.FiberErrorHandler})
+	api := app.Group("/api", NewValidation(spec, cfg))
+	api.Post("/books", func(c *fiber.Ctx) error {
+		return c.Status(http.StatusCreated).JSON(fiber.Map{"id": "1", "title": "Dune"})
+	})
+	api.Delete("/books/:id", func(c *fiber.Ctx) error {
+		// The response drifts from the definition: the title is missing.
+		return c.JSON(fiber.Map{"id": c.Params("id")})
+	})
+	api.Get("/undeclared", func(c *fiber.Ctx) error { return c.SendStatus(http.StatusOK) })
+	return app
+}
+
+func TestValidation(t *testing.T) {
+	app := newValidationTestApp(t, ValidationConfig{})
+
+	request := func(method, path, contentType, body string) (*http.Response, string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set(fiber.HeaderContentType, contentType)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		var errorResponse utils.ErrorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&errorResponse)
+		return resp, errorResponse.Message
+	}
+
+	t.Run("ValidRequest", func(t *testing.T) {
+		resp, _ := request(http.MethodPost, "/api/books", fiber.MIMEApplicationJSON, `{"title":"Dune"}`)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	})
+
+	t.Run("InvalidBody", func(t *testing.T) {
+		resp, message := request(http.MethodPost, "/api/books", fiber.MIMEApplicationJSON, `{"title":1}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "invalid request body: value must be a string at [title]", message)
+
+		resp, message = request(http.MethodPost, "/api/books", fiber.MIMEApplicationJSON, `{}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, message, `property "title" is missing`)
+
+		resp, message = request(http.MethodPost, "/api/books", fiber.MIMEApplicationJSON, "")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "the request body is required", message)
+	})
+
+	t.Run("UnknownField", func(t *testing.T) {
+		resp, message := request(http.MethodPost, "/api/books", fiber.MIMEApplicationJSON, `{"title":"Dune","rating":5}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, message, `property "rating" is unsupported`)
+	})
+
+	t.Run("UnsupportedContentType", func(t *testing.T) {
+		resp, _ := request(http.MethodPost, "/api/books", fiber.MIMETextPlain, "Dune")
+		assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+	})
+
+	t.Run("BodyWithoutSchemaValidation", func(t *testing.T) {
+		// XML bodies cannot be validated against the schemas, only their
+		// content type is checked.
+		resp, _ := request(http.MethodPost, "/api/books", fiber.MIMEApplicationXML, `<book><rating>5</rating></book>`)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	})
+
+	t.Run("InvalidParameter", func(t *testing.T) {
+		resp, message := request(http.MethodDelete, "/api/books/1?hard=maybe", "", "")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, message, "invalid query parameter [hard]")
+	})
+
+	t.Run("MixedCase", func(t *testing.T) {
+		// The routes are matched regardless of case, and so are the
+		// operations the requests are validated against.
+		resp, _ := request(http.MethodPost, "/API/Books", fiber.MIMEApplicationJSON, `{"title":1}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("UndeclaredRoute", func(t *testing.T) {
+		// The routes under the base path that the definition does not
+		// declare cannot be validated, so they are not reached.
+		resp, message := request(http.MethodGet, "/api/undeclared", "", "")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, "Cannot GET /api/undeclared", message)
+	})
+
+	t.Run("ResponsesNotValidated", func(t *testing.T) {
+		resp, _ := request(http.MethodDelete, "/api/books/1", "", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestValidationStrict(t *testing.T) {
+	request := func(app *fiber.App, method, path string) *http.Response {
+		resp, err := app.Test(httptest.NewRequest(method, path, nil))
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("LogOnDrift", func(t *testing.T) {
+		app := newValidationTestApp(t, ValidationConfig{ValidateResponses: true})
+		resp := request(app, http.MethodDelete, "/api/books/1")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("FailOnDrift", func(t *testing.T) {
+		app := newValidationTestApp(t, ValidationConfig{ValidateResponses: true, FailOnDrift: true})
+		resp := request(app, http.MethodDelete, "/api/books/1")
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	})
+}
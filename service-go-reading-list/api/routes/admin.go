@@ -0,0 +1,112 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package routes
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+// registerAdminRoutes registers the routes that administer the service as a
+// whole. They must be registered before the tenant resolution, as they are
+// not made for a tenant. They are not registered when the requests are not
+// authorized, as anyone could then delete the tenants.
+func registerAdminRoutes(router fiber.Router) {
+	if config.GetConfig().Auth.Mode == "" {
+		return
+	}
+	r := router.Group("/reading-list/admin")
+	r.Get("/tenants", ListTenants)
+	r.Delete("/tenants/:tenant", DeleteTenant)
+	r.Get("/backup", Backup)
+}
+
+// ListTenants
+//
+//	@ID			listTenants
+//	@Summary	List the tenants with their number of books
+//	@Tags		admin
+//	@Produce	json
+//	@Security	default[admin]
+//	@Router		/admin/tenants [get]
+//	@Success	200	{array}	models.Tenant	"successful operation"
+func ListTenants(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	tenants, err := tenantController.ListTenants(ctx)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(tenants)
+}
+
+// DeleteTenant
+//
+//	@ID				deleteTenant
+//	@Summary		Delete a tenant
+//	@Description	Permanently removes the books, the goals, the covers and the highlights of the tenant. The tenant starts over with its initial data when it is next used.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			tenant	path	string	true	"Tenant ID"
+//	@Security		default[admin]
+//	@Router			/admin/tenants/{tenant} [delete]
+//	@Success		200	{object}	models.Tenant		"successful operation"
+//	@Failure		400	{object}	utils.ErrorResponse	"invalid tenant id"
+//	@Failure		404	{object}	utils.ErrorResponse	"tenant not found"
+func DeleteTenant(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	tenant, err := tenantController.DeleteTenant(ctx, c.Params("tenant"))
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(tenant)
+}
+
+// Backup
+//
+//	@ID				backup
+//	@Summary		Back up the books
+//	@Description	Streams a consistent copy of the books of all the tenants, which the service can be restored from at startup with DATA_RESTORE_PATH.
+//	@Tags			admin
+//	@Produce		octet-stream,json
+//	@Security		default[admin]
+//	@Router			/admin/backup [get]
+//	@Success		200	{file}		file				"successful operation"
+//	@Failure		501	{object}	utils.ErrorResponse	"backups not supported by the configured storage"
+func Backup(c *fiber.Ctx) error {
+	// The backup is streamed after the handler returns, so it is not bound by
+	// the deadline of the request, and is stopped by closing the pipe instead.
+	ctx := context.WithoutCancel(utils.GetRequestContext(c))
+	reader, writer := io.Pipe()
+	go func() {
+		_ = writer.CloseWithError(bookController.Backup(ctx, writer))
+	}()
+	backup := &backupStream{Reader: bufio.NewReader(reader), pipe: reader}
+	// The errors are answered as such until the backup starts to be written.
+	if _, err := backup.Peek(1); err != nil {
+		_ = reader.Close()
+		return err
+	}
+	c.Attachment(fmt.Sprintf("books-%s.db", time.Now().UTC().Format("20060102T150405Z")))
+	c.Set(fiber.HeaderContentType, fiber.MIMEOctetStream)
+	return c.Status(fiber.StatusOK).SendStream(backup)
+}
+
+// backupStream closes the pipe of the backup once it is sent, or when the
+// client goes away, which stops the backup.
+type backupStream struct {
+	*bufio.Reader
+	pipe *io.PipeReader
+}
+
+func (s *backupStream) Close() error {
+	return s.pipe.Close()
+}
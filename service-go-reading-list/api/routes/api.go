@@ -0,0 +1,53 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package routes
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+
+	v2 "github.com/wso2/choreo-sample-apps/go/rest-api/api/routes/v2"
+)
+
+func Initialize(app *fiber.App) {
+	requestContext, cancelRequests = context.WithCancel(context.Background())
+	initControllers()
+	startBackgroundJobs()
+
+	RegisterHealthRoutes(app)
+	RegisterMetricsRoutes(app)
+	responseCache := newResponseCache()
+	apiVersion1 := app.Group("/api/v1", apiMiddleware(apiV1)...)
+	// The routes are matched in the order they are registered, so the
+	// admin routes are not subject to the tenant resolution.
+	registerAdminRoutes(apiVersion1)
+	apiVersion1.Use(tenantResolution())
+	registerReadingListRoutes(apiVersion1, responseCache)
+	registerCoverRoutes(apiVersion1)
+	registerHighlightRoutes(apiVersion1)
+	registerStatsRoutes(apiVersion1)
+	registerAuditRoutes(apiVersion1)
+	apiVersion2 := app.Group("/api/v2", append(apiMiddleware(apiV2), tenantResolution())...)
+	v2.RegisterRoutes(apiVersion2, v2.Controllers{
+		Books:           bookController,
+		Recommendations: recommendationController,
+	}, responseCache)
+	verifyRoutes(app)
+}
+
+// CancelRequests cancels the API requests that are still running, which are
+// answered with 503. The requests received after are canceled too.
+func CancelRequests() {
+	cancelRequests()
+}
+
+// Shutdown cancels the API requests that are still running, stops the
+// background jobs started by Initialize and releases the resources held by
+// the controllers.
+func Shutdown() {
+	CancelRequests()
+	stopBackgroundJobsAndWait()
+	runShutdownHooks()
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+// mimeApplicationJSONLines is the media type of the audit log export, with
+// one JSON entry per line.
+const mimeApplicationJSONLines = "application/x-ndjson"
+
+func registerAuditRoutes(router fiber.Router) {
+	router.Get("/reading-list/audit", ListAuditEntries)
+}
+
+// ListAuditEntries
+//
+//	@ID				listAuditEntries
+//	@Summary		List the changes to the books
+//	@Description	Lists the audit entries of the books, oldest first. Each entry records who changed which book, how and when. Set the Accept header to application/x-ndjson to export the entries as JSON lines.
+//	@Tags			audit
+//	@Produce		json,application/x-ndjson
+//	@Param			bookId	query	string	false	"Only the changes to the book"
+//	@Param			since	query	string	false	"Only the changes from the time, in RFC 3339 format"	format(date-time)
+//	@Security		default[read:audit]
+//	@Router			/audit [get]
+//	@Success		200	{array}		models.AuditEntry	"successful operation"
+//	@Failure		400	{object}	utils.ErrorResponse	"invalid filter"
+//	@Failure		406	{object}	utils.ErrorResponse	"unsupported accepted media types"
+func ListAuditEntries(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	mediaType := c.Accepts(fiber.MIMEApplicationJSON, mimeApplicationJSONLines)
+	if mediaType == "" {
+		return fiber.NewError(http.StatusNotAcceptable, fmt.Sprintf("the accepted media types are not supported, use one of [%s, %s]", fiber.MIMEApplicationJSON, mimeApplicationJSONLines))
+	}
+	filter := models.AuditFilter{BookId: c.Query("bookId")}
+	if since := c.Query("since"); since != "" {
+		var err error
+		if filter.Since, err = time.Parse(time.RFC3339, since); err != nil {
+			return fiber.NewError(http.StatusBadRequest, "since should be a time in RFC 3339 format")
+		}
+	}
+	entries, err := bookController.ListAuditEntries(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if mediaType == fiber.MIMEApplicationJSON {
+		return c.Status(fiber.StatusOK).JSON(entries)
+	}
+	c.Set(fiber.HeaderContentType, mimeApplicationJSONLines)
+	encoder := json.NewEncoder(c.Status(fiber.StatusOK))
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+// testJWTSecret signs the tokens of the tests that run with AUTH_MODE.
+const testJWTSecret = "test-secret"
+
+// testScopes are all the scopes of the API definitions.
+const testScopes = "read:books write:books read:audit admin"
+
+// setTestAuth makes the API authorize the requests with the tokens of testToken.
+func setTestAuth(t *testing.T) {
+	t.Setenv(config.AuthMode, config.AuthModeJWT)
+	t.Setenv(config.AuthJWTSecret, testJWTSecret)
+}
+
+// testToken returns a token with the scopes, whose tenant claim names the
+// tenant when it is set.
+func testToken(t *testing.T, scopes, tenant string) string {
+	claims := jwt.MapClaims{"sub": "alice", "scope": scopes, "exp": time.Now().Add(time.Hour).Unix()}
+	if tenant != "" {
+		claims[config.DefaultTenantClaim] = tenant
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJWTSecret))
+	require.NoError(t, err)
+	return token
+}
+
+func TestAdminRoutes(t *testing.T) {
+	get := func(app *fiber.App, token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/reading-list/admin/tenants", nil)
+		if token != "" {
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	t.Run("WithoutAuth", func(t *testing.T) {
+		_, err := config.LoadConfig()
+		require.NoError(t, err)
+		app := fiber.New(fiber.Config{ErrorHandler: utils.FiberErrorHandler})
+		Initialize(app)
+		defer Shutdown()
+		assert.Equal(t, http.StatusNotFound, get(app, ""))
+	})
+
+	t.Run("WithAuth", func(t *testing.T) {
+		setTestAuth(t)
+		_, err := config.LoadConfig()
+		require.NoError(t, err)
+		app := fiber.New(fiber.Config{ErrorHandler: utils.FiberErrorHandler})
+		Initialize(app)
+		defer Shutdown()
+		assert.Equal(t, http.StatusUnauthorized, get(app, ""))
+		assert.Equal(t, http.StatusForbidden, get(app, testToken(t, "read:books write:books", "")))
+		assert.Equal(t, http.StatusOK, get(app, testToken(t, testScopes, "")))
+	})
+}
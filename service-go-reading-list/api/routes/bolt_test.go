@@ -0,0 +1,100 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package routes
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+func TestBoltStorage(t *testing.T) {
+	t.Setenv(config.DataDir, t.TempDir())
+	t.Setenv(config.DataStore, config.DataStoreBolt)
+	// The backup is an admin route, which is only served with AUTH_MODE.
+	setTestAuth(t)
+	_, err := config.LoadConfig()
+	require.NoError(t, err)
+
+	newApp := func() *fiber.App {
+		app := fiber.New(fiber.Config{ErrorHandler: utils.FiberErrorHandler})
+		Initialize(app)
+		return app
+	}
+	send := func(app *fiber.App, method, target, body string) (*http.Response, string) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		}
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+testToken(t, testScopes, ""))
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		contents, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(contents)
+	}
+
+	app := newApp()
+	for _, book := range []string{
+		`{"id":"dune","title":"Dune","author":"Frank Herbert","status":"reading"}`,
+		`{"id":"emma","title":"Emma","author":"Jane Austen","status":"read"}`,
+		`{"id":"persuasion","title":"Persuasion","author":"Jane Austen","status":"to_read"}`,
+	} {
+		resp, body := send(app, http.MethodPost, "/api/v1/reading-list/books", book)
+		require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+	}
+
+	t.Run("Filters", func(t *testing.T) {
+		resp, body := send(app, http.MethodGet, "/api/v1/reading-list/books?author=jane%20austen", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, body, `"id":"emma"`)
+		assert.Contains(t, body, `"id":"persuasion"`)
+		assert.NotContains(t, body, `"id":"dune"`)
+
+		resp, body = send(app, http.MethodGet, "/api/v2/reading-list/books?status=read&author=Jane%20Austen", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, body, `"id":"emma"`)
+		assert.NotContains(t, body, `"id":"persuasion"`)
+	})
+
+	var backup string
+	t.Run("Backup", func(t *testing.T) {
+		resp, body := send(app, http.MethodGet, "/api/v1/reading-list/admin/backup", "")
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		assert.Equal(t, fiber.MIMEOctetStream, resp.Header.Get(fiber.HeaderContentType))
+		assert.Regexp(t, `^attachment; filename="books-\d{8}T\d{6}Z\.db"$`, resp.Header.Get(fiber.HeaderContentDisposition))
+		assert.NotEmpty(t, body)
+		backup = body
+	})
+	Shutdown()
+
+	t.Run("Restore", func(t *testing.T) {
+		backupPath := filepath.Join(t.TempDir(), "backup.db")
+		require.NoError(t, os.WriteFile(backupPath, []byte(backup), 0o600))
+		t.Setenv(config.DataDir, t.TempDir())
+		t.Setenv(config.DataRestorePath, backupPath)
+		_, err := config.LoadConfig()
+		require.NoError(t, err)
+
+		app := newApp()
+		defer Shutdown()
+		resp, body := send(app, http.MethodGet, "/api/v1/reading-list/books/emma", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode, body)
+		resp, body = send(app, http.MethodGet, "/api/v1/reading-list/books?status=reading", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, body, `"id":"dune"`)
+		assert.NotContains(t, body, `"id":"emma"`)
+	})
+}
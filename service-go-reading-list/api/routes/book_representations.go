@@ -0,0 +1,221 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package routes
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/invopop/yaml"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	v1 "github.com/wso2/choreo-sample-apps/go/rest-api/internal/models/v1"
+)
+
+const (
+	mimeApplicationYAML = "application/yaml"
+	mimeTextCSV         = "text/csv"
+)
+
+// bookMediaTypes are the representations of the books, in the order of
+// preference when the Accept header allows several of them.
+var bookMediaTypes = []string{fiber.MIMEApplicationJSON, fiber.MIMEApplicationXML, mimeTextCSV, mimeApplicationYAML}
+
+// bookCSVColumns are the columns of the CSV representation of the books.
+// The first row of a CSV document is a header with the column names.
+var bookCSVColumns = []string{"id", "title", "author", "status", "createdAt", "updatedAt", "startedAt", "finishedAt", "deletedAt"}
+
+// bookList is the root element of the XML representation of a list of books.
+type bookList struct {
+	XMLName xml.Name  `xml:"books"`
+	Books   []v1.Book `xml:"book"`
+}
+
+// negotiateBookMediaType returns the representation of the books that is
+// preferred by the Accept header of the request, which defaults to JSON.
+// It is called before the request is processed, so that a request that
+// cannot be answered has no effect.
+func negotiateBookMediaType(c *fiber.Ctx) (string, error) {
+	c.Vary(fiber.HeaderAccept)
+	mediaType := c.Accepts(bookMediaTypes...)
+	if mediaType == "" {
+		return "", fiber.NewError(http.StatusNotAcceptable, fmt.Sprintf("the accepted media types are not supported, use one of [%s]", strings.Join(bookMediaTypes, ", ")))
+	}
+	return mediaType, nil
+}
+
+// parseBook decodes the request body into the book according to its content type.
+func parseBook(c *fiber.Ctx, book *v1.Book) error {
+	contentType := c.Get(fiber.HeaderContentType)
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return makeHttpUnsupportedMediaTypeError(contentType)
+	}
+	switch mediaType {
+	case fiber.MIMEApplicationJSON:
+		err = json.Unmarshal(c.Body(), book)
+	case fiber.MIMEApplicationXML:
+		err = xml.Unmarshal(c.Body(), book)
+	case mimeApplicationYAML:
+		err = yaml.Unmarshal(c.Body(), book)
+	case mimeTextCSV:
+		*book, err = unmarshalBookCSV(c.Body())
+	default:
+		return makeHttpUnsupportedMediaTypeError(contentType)
+	}
+	if err != nil {
+		return makeHttpBadRequestError(err)
+	}
+	return nil
+}
+
+// sendBook writes the book in the negotiated representation.
+func sendBook(c *fiber.Ctx, status int, mediaType string, book v1.Book) error {
+	switch mediaType {
+	case fiber.MIMEApplicationXML:
+		return sendXML(c.Status(status), xml.StartElement{Name: xml.Name{Local: "book"}}, book)
+	case mimeTextCSV:
+		return sendBooksCSV(c.Status(status), []v1.Book{book})
+	case mimeApplicationYAML:
+		return sendYAML(c.Status(status), book)
+	}
+	return c.Status(status).JSON(book)
+}
+
+// sendBooks writes the books in the negotiated representation.
+func sendBooks(c *fiber.Ctx, status int, mediaType string, books []v1.Book) error {
+	switch mediaType {
+	case fiber.MIMEApplicationXML:
+		return sendXML(c.Status(status), xml.StartElement{}, bookList{Books: books})
+	case mimeTextCSV:
+		return sendBooksCSV(c.Status(status), books)
+	case mimeApplicationYAML:
+		return sendYAML(c.Status(status), books)
+	}
+	return c.Status(status).JSON(books)
+}
+
+func sendXML(c *fiber.Ctx, start xml.StartElement, v interface{}) error {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	var err error
+	if start.Name.Local != "" {
+		err = encoder.EncodeElement(v, start)
+	} else {
+		err = encoder.Encode(v)
+	}
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationXMLCharsetUTF8)
+	return c.Send(buf.Bytes())
+}
+
+func sendYAML(c *fiber.Ctx, v interface{}) error {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, mimeApplicationYAML)
+	return c.Send(data)
+}
+
+func sendBooksCSV(c *fiber.Ctx, books []v1.Book) error {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	_ = writer.Write(bookCSVColumns)
+	for _, book := range books {
+		_ = writer.Write([]string{
+			book.Id,
+			book.Title,
+			book.Author,
+			book.Status.String(),
+			formatCSVTime(book.CreatedAt),
+			formatCSVTime(book.UpdatedAt),
+			formatCSVTime(book.StartedAt),
+			formatCSVTime(book.FinishedAt),
+			formatCSVTime(book.DeletedAt),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, mimeTextCSV+"; charset=utf-8; header=present")
+	return c.Send(buf.Bytes())
+}
+
+// unmarshalBookCSV decodes a CSV document with a header and a single book.
+// The columns that are not in the header are left empty.
+func unmarshalBookCSV(data []byte) (v1.Book, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return v1.Book{}, err
+	}
+	if len(records) != 2 {
+		return v1.Book{}, errors.New("a header and a single book are expected")
+	}
+	book := v1.Book{}
+	for i, column := range records[0] {
+		value := records[1][i]
+		switch column {
+		case "id":
+			book.Id = value
+		case "title":
+			book.Title = value
+		case "author":
+			book.Author = value
+		case "status":
+			book.Status = models.ReadStatus(value)
+		case "createdAt":
+			book.CreatedAt, err = parseCSVTime(column, value)
+		case "updatedAt":
+			book.UpdatedAt, err = parseCSVTime(column, value)
+		case "startedAt":
+			book.StartedAt, err = parseCSVTime(column, value)
+		case "finishedAt":
+			book.FinishedAt, err = parseCSVTime(column, value)
+		case "deletedAt":
+			book.DeletedAt, err = parseCSVTime(column, value)
+		default:
+			return v1.Book{}, fmt.Errorf("unknown column [%s]", column)
+		}
+		if err != nil {
+			return v1.Book{}, err
+		}
+	}
+	return book, nil
+}
+
+func formatCSVTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+func parseCSVTime(column, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time in column [%s]: %w", column, err)
+	}
+	return &t, nil
+}
+
+func makeHttpUnsupportedMediaTypeError(contentType string) *fiber.Error {
+	return fiber.NewError(http.StatusUnsupportedMediaType, fmt.Sprintf("the content type [%s] is not supported, use one of [%s]", contentType, strings.Join(bookMediaTypes, ", ")))
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package routes
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	v1 "github.com/wso2/choreo-sample-apps/go/rest-api/internal/models/v1"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+func TestBookRepresentations(t *testing.T) {
+	finishedAt := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	books := []v1.Book{
+		{Id: "dune", Title: "Dune", Author: "Frank Herbert", Status: models.ReadStatusRead, FinishedAt: &finishedAt},
+		{Id: "emma", Title: "Emma, a novel", Author: "Jane Austen", Status: models.ReadStatusToRead},
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: utils.FiberErrorHandler})
+	app.Get("/books", func(c *fiber.Ctx) error {
+		mediaType, err := negotiateBookMediaType(c)
+		if err != nil {
+			return err
+		}
+		return sendBooks(c, http.StatusOK, mediaType, books)
+	})
+	// Echoes the parsed book in the negotiated representation.
+	app.Post("/books", func(c *fiber.Ctx) error {
+		mediaType, err := negotiateBookMediaType(c)
+		if err != nil {
+			return err
+		}
+		book := v1.Book{}
+		if err := parseBook(c, &book); err != nil {
+			return err
+		}
+		return sendBook(c, http.StatusOK, mediaType, book)
+	})
+
+	request := func(method, contentType, accept, body string) (*http.Response, string) {
+		req := httptest.NewRequest(method, "/books", strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set(fiber.HeaderContentType, contentType)
+		}
+		if accept != "" {
+			req.Header.Set(fiber.HeaderAccept, accept)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(data)
+	}
+
+	t.Run("DefaultsToJSON", func(t *testing.T) {
+		resp, _ := request(http.MethodGet, "", "", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, fiber.MIMEApplicationJSON, resp.Header.Get(fiber.HeaderContentType))
+		assert.Equal(t, fiber.HeaderAccept, resp.Header.Get(fiber.HeaderVary))
+	})
+
+	t.Run("XML", func(t *testing.T) {
+		resp, body := request(http.MethodGet, "", "application/xml", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var list bookList
+		require.NoError(t, xml.Unmarshal([]byte(body), &list))
+		assert.Equal(t, books, list.Books)
+
+		_, body = request(http.MethodPost, "application/xml", "application/xml", `<book><id>dune</id><title>Dune</title></book>`)
+		assert.Contains(t, body, "<book><id>dune</id><title>Dune</title>")
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		resp, body := request(http.MethodGet, "", "text/csv", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "id,title,author,status,createdAt,updatedAt,startedAt,finishedAt,deletedAt\n"+
+			"dune,Dune,Frank Herbert,read,,,,2024-01-02T15:04:05Z,\n"+
+			"emma,\"Emma, a novel\",Jane Austen,to_read,,,,,\n", body)
+
+		book, err := unmarshalBookCSV([]byte("title,finishedAt\nDune,2024-01-02T15:04:05Z\n"))
+		require.NoError(t, err)
+		assert.Equal(t, v1.Book{Title: "Dune", FinishedAt: &finishedAt}, book)
+
+		resp, _ = request(http.MethodPost, "text/csv", "", "title,rating\nDune,5\n")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		resp, _ = request(http.MethodPost, "text/csv", "", "title\nDune\nEmma\n")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("YAML", func(t *testing.T) {
+		resp, body := request(http.MethodPost, "application/yaml", "application/yaml", "id: dune\ntitle: Dune\nfinishedAt: 2024-01-02T15:04:05Z\n")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "author: \"\"\nfinishedAt: \"2024-01-02T15:04:05Z\"\nid: dune\nstatus: \"\"\ntitle: Dune\n", body)
+	})
+
+	t.Run("NotAcceptable", func(t *testing.T) {
+		resp, _ := request(http.MethodGet, "", "text/html", "")
+		assert.Equal(t, http.StatusNotAcceptable, resp.StatusCode)
+
+		// The preferred representation that is supported wins.
+		resp, _ = request(http.MethodGet, "", "text/html, application/yaml;q=0.5, text/csv;q=0.8", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get(fiber.HeaderContentType), "text/csv")
+	})
+
+	t.Run("UnsupportedMediaType", func(t *testing.T) {
+		resp, _ := request(http.MethodPost, "text/plain", "", "Dune")
+		assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+		resp, _ = request(http.MethodPost, "", "", `{"title":"Dune"}`)
+		assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+	})
+}
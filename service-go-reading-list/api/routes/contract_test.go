@@ -0,0 +1,372 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/docs"
+	v2docs "github.com/wso2/choreo-sample-apps/go/rest-api/docs/v2"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/openapi"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/tenancy"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+// contractStep is a request made by the contract test and the status it is
+// expected to be answered with. The steps run in order against the same
+// service, so a step can rely on the data created by the previous ones.
+type contractStep struct {
+	method string
+	// target is the request path relative to the base path of the API.
+	target  string
+	body    string
+	headers map[string]string
+	// conditional sends the request with an If-Modified-Since header of the
+	// current time, after waiting for the second of the last change to be
+	// over so that the response can be validated.
+	conditional bool
+	status      int
+	// captures names the id of the resource in the response, which replaces
+	// the name in braces in the targets of the next steps.
+	captures string
+}
+
+// apiContract is an API version checked by the contract test.
+type apiContract struct {
+	version string
+	// definition is the OpenAPI 3 definition and swaggerPath the swag
+	// generated definition it is converted from.
+	definition  []byte
+	swaggerPath string
+	steps       []contractStep
+}
+
+var apiContracts = []apiContract{
+	{version: apiV1, definition: docs.OpenAPISpec, swaggerPath: "../../docs/swagger.json", steps: contractStepsV1},
+	{version: apiV2, definition: v2docs.OpenAPISpec, swaggerPath: "../../docs/v2/v2_swagger.json", steps: contractStepsV2},
+}
+
+// contractStepsV1 exercise every response documented in docs/openapi.yaml.
+var contractStepsV1 = []contractStep{
+	{method: http.MethodPost, target: "/books", body: `{"id":"dune","title":"Dune","author":"Frank Herbert"}`, status: http.StatusCreated},
+	{method: http.MethodPost, target: "/books", body: `{"author":"Frank Herbert"}`, status: http.StatusBadRequest},
+	{method: http.MethodPost, target: "/books", body: `{"id":"dune","title":"Dune"}`, status: http.StatusConflict},
+	{method: http.MethodPost, target: "/books", body: `{"id":"emma","title":"Emma"}`, headers: map[string]string{"Idempotency-Key": "add-emma"}, status: http.StatusCreated},
+	{method: http.MethodPost, target: "/books", body: `{"id":"persuasion","title":"Persuasion"}`, headers: map[string]string{"Idempotency-Key": "add-emma"}, status: http.StatusUnprocessableEntity},
+	{method: http.MethodPost, target: "/books", body: `<book><id>jane-eyre</id><title>Jane Eyre</title></book>`, headers: map[string]string{"Content-Type": "application/xml", "Accept": "application/xml"}, status: http.StatusCreated},
+	{method: http.MethodPost, target: "/books", body: "Jane Eyre", headers: map[string]string{"Content-Type": "text/plain"}, status: http.StatusUnsupportedMediaType},
+	{method: http.MethodPost, target: "/books", body: `{"title":"Villette"}`, headers: map[string]string{"Accept": "text/html"}, status: http.StatusNotAcceptable},
+	{method: http.MethodGet, target: "/books", status: http.StatusOK},
+	{method: http.MethodGet, target: "/books", headers: map[string]string{"Accept": "text/csv"}, status: http.StatusOK},
+	{method: http.MethodGet, target: "/books", headers: map[string]string{"Accept": "text/html"}, status: http.StatusNotAcceptable},
+	{method: http.MethodGet, target: "/books?status=read&author=frank%20herbert", status: http.StatusOK},
+	{method: http.MethodGet, target: "/books?status=unknown", status: http.StatusBadRequest},
+	{method: http.MethodGet, target: "/books", conditional: true, status: http.StatusNotModified},
+	{method: http.MethodGet, target: "/books/dune", status: http.StatusOK},
+	{method: http.MethodGet, target: "/books/dune", conditional: true, status: http.StatusNotModified},
+	{method: http.MethodGet, target: "/books/dune", headers: map[string]string{"Accept": "application/yaml"}, status: http.StatusOK},
+	{method: http.MethodGet, target: "/books/dune", headers: map[string]string{"Accept": "text/html"}, status: http.StatusNotAcceptable},
+	{method: http.MethodGet, target: "/books/missing", status: http.StatusNotFound},
+	{method: http.MethodPut, target: "/books/dune", body: `{"title":"Dune","author":"Frank Herbert","status":"reading"}`, status: http.StatusOK},
+	{method: http.MethodPut, target: "/books/dune", body: "title,author,status\nDune,Frank Herbert,reading\n", headers: map[string]string{"Content-Type": "text/csv", "Accept": "text/csv"}, status: http.StatusOK},
+	{method: http.MethodPut, target: "/books/dune", body: `{"author":"Frank Herbert"}`, status: http.StatusBadRequest},
+	{method: http.MethodPut, target: "/books/dune", body: "Dune", headers: map[string]string{"Content-Type": "text/plain"}, status: http.StatusUnsupportedMediaType},
+	{method: http.MethodPut, target: "/books/dune", body: `{"title":"Dune"}`, headers: map[string]string{"Accept": "text/html"}, status: http.StatusNotAcceptable},
+	{method: http.MethodPut, target: "/books/missing", body: `{"title":"Missing"}`, status: http.StatusNotFound},
+
+	{method: http.MethodPut, target: "/books/dune/cover", body: testCoverForm, headers: map[string]string{"Content-Type": testCoverFormType}, status: http.StatusOK},
+	{method: http.MethodPut, target: "/books/dune/cover", body: testCover, headers: map[string]string{"Content-Type": "image/png"}, status: http.StatusOK},
+	{method: http.MethodPut, target: "/books/dune/cover", body: testCover[:len(testCover)/2], headers: map[string]string{"Content-Type": "image/png"}, status: http.StatusBadRequest},
+	{method: http.MethodPut, target: "/books/dune/cover", body: testCover, headers: map[string]string{"Content-Type": "image/jpeg"}, status: http.StatusUnsupportedMediaType},
+	{method: http.MethodPut, target: "/books/dune/cover", body: strings.Repeat(testCover, 4), headers: map[string]string{"Content-Type": "image/png"}, status: http.StatusRequestEntityTooLarge},
+	{method: http.MethodPut, target: "/books/missing/cover", body: testCover, headers: map[string]string{"Content-Type": "image/png"}, status: http.StatusNotFound},
+	{method: http.MethodGet, target: "/books/dune/cover", status: http.StatusOK},
+	{method: http.MethodGet, target: "/books/dune/cover?size=small", status: http.StatusOK},
+	{method: http.MethodGet, target: "/books/dune/cover", conditional: true, status: http.StatusNotModified},
+	{method: http.MethodGet, target: "/books/dune/cover?size=huge", status: http.StatusBadRequest},
+	{method: http.MethodGet, target: "/books/emma/cover", status: http.StatusNotFound},
+
+	{method: http.MethodPost, target: "/books/dune/highlights", body: `{"text":"I must not fear.","page":12,"tags":["fear"]}`, status: http.StatusCreated, captures: "highlightId"},
+	{method: http.MethodPost, target: "/books/dune/highlights", body: `{"page":12}`, status: http.StatusBadRequest},
+	{method: http.MethodPost, target: "/books/missing/highlights", body: `{"text":"I must not fear."}`, status: http.StatusNotFound},
+	{method: http.MethodGet, target: "/books/dune/highlights", status: http.StatusOK},
+	{method: http.MethodGet, target: "/books/dune/highlights?q=fear&tag=fear", status: http.StatusOK},
+	{method: http.MethodGet, target: "/books/missing/highlights", status: http.StatusNotFound},
+	{method: http.MethodGet, target: "/books/dune/highlights/{highlightId}", status: http.StatusOK},
+	{method: http.MethodGet, target: "/books/dune/highlights/missing", status: http.StatusNotFound},
+	{method: http.MethodPut, target: "/books/dune/highlights/{highlightId}", body: `{"text":"I must not fear.","location":"Chapter 1","note":"The litany against fear."}`, status: http.StatusOK},
+	{method: http.MethodPut, target: "/books/dune/highlights/{highlightId}", body: `{"text":"","page":-1}`, status: http.StatusBadRequest},
+	{method: http.MethodPut, target: "/books/dune/highlights/missing", body: `{"text":"I must not fear."}`, status: http.StatusNotFound},
+	{method: http.MethodGet, target: "/books/dune/highlights/export", status: http.StatusOK},
+	{method: http.MethodGet, target: "/books/missing/highlights/export", status: http.StatusNotFound},
+	{method: http.MethodGet, target: "/highlights?q=litany", status: http.StatusOK},
+	{method: http.MethodDelete, target: "/books/dune/highlights/{highlightId}", status: http.StatusOK},
+	{method: http.MethodDelete, target: "/books/dune/highlights/{highlightId}", status: http.StatusNotFound},
+
+	{method: http.MethodPost, target: "/books:batch", body: `{"operations":[{"op":"update","id":"dune","book":{"title":"Dune","status":"read"}}]}`, status: http.StatusOK},
+	{method: http.MethodPost, target: "/books:batch", body: `{"operations":[]}`, status: http.StatusBadRequest},
+	{method: http.MethodPost, target: "/books:batch", body: `{"operations":[{"op":"delete","id":"missing"}]}`, status: http.StatusNotFound},
+	{method: http.MethodPost, target: "/books:batch", body: `{"operations":[{"op":"create","book":{"id":"dune","title":"Dune"}}]}`, status: http.StatusConflict},
+	{method: http.MethodPost, target: "/books:batch", body: `{"operations":[{"op":"create","book":{"title":"Sense and Sensibility"}}]}`, headers: map[string]string{"Idempotency-Key": "batch-1"}, status: http.StatusOK},
+	{method: http.MethodPost, target: "/books:batch", body: `{"operations":[{"op":"create","book":{"title":"Mansfield Park"}}]}`, headers: map[string]string{"Idempotency-Key": "batch-1"}, status: http.StatusUnprocessableEntity},
+
+	{method: http.MethodPost, target: "/books", body: `{"title":"The Dune"}`, status: http.StatusConflict},
+	{method: http.MethodPost, target: "/books?allowDuplicate=true", body: `{"id":"dune-2","title":"Dune","author":"Frank Herbert"}`, status: http.StatusCreated},
+	{method: http.MethodGet, target: "/books/duplicates", status: http.StatusOK},
+	{method: http.MethodPost, target: "/books/dune/merge", body: `{"sourceId":"dune-2"}`, status: http.StatusOK},
+	{method: http.MethodPost, target: "/books/dune/merge", body: `{"sourceId":"dune"}`, status: http.StatusBadRequest},
+	{method: http.MethodPost, target: "/books/dune/merge", body: `{"sourceId":"dune-2"}`, status: http.StatusNotFound},
+
+	{method: http.MethodDelete, target: "/books/emma", headers: map[string]string{"Accept": "text/html"}, status: http.StatusNotAcceptable},
+	{method: http.MethodDelete, target: "/books/emma", status: http.StatusOK},
+	{method: http.MethodDelete, target: "/books/missing", status: http.StatusNotFound},
+	{method: http.MethodGet, target: "/books/trash", headers: map[string]string{"Accept": "application/xml"}, status: http.StatusOK},
+	{method: http.MethodGet, target: "/books/trash", headers: map[string]string{"Accept": "text/html"}, status: http.StatusNotAcceptable},
+	{method: http.MethodGet, target: "/books/trash", conditional: true, status: http.StatusNotModified},
+	{method: http.MethodPost, target: "/books/emma/restore", headers: map[string]string{"Accept": "text/html"}, status: http.StatusNotAcceptable},
+	{method: http.MethodPost, target: "/books/emma/restore", status: http.StatusOK},
+	{method: http.MethodPost, target: "/books/missing/restore", status: http.StatusNotFound},
+	{method: http.MethodDelete, target: "/books/emma?hard=true", status: http.StatusOK},
+	{method: http.MethodGet, target: "/books/recommendations", status: http.StatusOK},
+	{method: http.MethodGet, target: "/books/recommendations?limit=0", status: http.StatusBadRequest},
+
+	{method: http.MethodGet, target: "/stats", status: http.StatusOK},
+	{method: http.MethodPut, target: "/goals/2024", body: `{"target":12}`, status: http.StatusOK},
+	{method: http.MethodPut, target: "/goals/2024", body: `{"target":0}`, status: http.StatusBadRequest},
+	{method: http.MethodGet, target: "/goals", status: http.StatusOK},
+	{method: http.MethodGet, target: "/goals/2024", status: http.StatusOK},
+	{method: http.MethodGet, target: "/goals/2023", status: http.StatusNotFound},
+	{method: http.MethodGet, target: "/goals/next", status: http.StatusBadRequest},
+	{method: http.MethodDelete, target: "/goals/2024", status: http.StatusOK},
+	{method: http.MethodDelete, target: "/goals/2024", status: http.StatusNotFound},
+	{method: http.MethodDelete, target: "/goals/next", status: http.StatusBadRequest},
+
+	{method: http.MethodGet, target: "/audit", status: http.StatusOK},
+	{method: http.MethodGet, target: "/audit?bookId=dune&since=2024-01-01T00:00:00Z", headers: map[string]string{"Accept": "application/x-ndjson"}, status: http.StatusOK},
+	{method: http.MethodGet, target: "/audit?since=yesterday", status: http.StatusBadRequest},
+	{method: http.MethodGet, target: "/audit", headers: map[string]string{"Accept": "text/html"}, status: http.StatusNotAcceptable},
+
+	{method: http.MethodPost, target: "/books", body: `{"id":"dune","title":"Dune"}`, headers: map[string]string{"X-Tenant-Id": "globex"}, status: http.StatusCreated},
+	{method: http.MethodGet, target: "/admin/tenants", status: http.StatusOK},
+	{method: http.MethodDelete, target: "/admin/tenants/globex", status: http.StatusOK},
+	{method: http.MethodDelete, target: "/admin/tenants/globex", status: http.StatusNotFound},
+	{method: http.MethodDelete, target: "/admin/tenants/not_valid", status: http.StatusBadRequest},
+	{method: http.MethodGet, target: "/admin/backup", status: http.StatusNotImplemented},
+}
+
+// contractStepsV2 exercise every response documented in docs/v2/openapi.yaml.
+var contractStepsV2 = []contractStep{
+	{method: http.MethodPost, target: "/books", body: `{"id":"middlemarch","title":"Middlemarch","author":"George Eliot","tags":["classic"],"rating":5}`, status: http.StatusCreated},
+	{method: http.MethodPost, target: "/books", body: `{"author":"George Eliot"}`, status: http.StatusBadRequest},
+	{method: http.MethodPost, target: "/books", body: `{"id":"middlemarch","title":"Middlemarch"}`, status: http.StatusConflict},
+	{method: http.MethodPost, target: "/books", body: `{"id":"adam-bede","title":"Adam Bede"}`, headers: map[string]string{"Idempotency-Key": "add-adam-bede"}, status: http.StatusCreated},
+	{method: http.MethodPost, target: "/books", body: `{"id":"romola","title":"Romola"}`, headers: map[string]string{"Idempotency-Key": "add-adam-bede"}, status: http.StatusUnprocessableEntity},
+	{method: http.MethodGet, target: "/books?limit=1", status: http.StatusOK},
+	{method: http.MethodGet, target: "/books?offset=-1", status: http.StatusBadRequest},
+	{method: http.MethodGet, target: "/books", conditional: true, status: http.StatusNotModified},
+	{method: http.MethodGet, target: "/books/middlemarch", status: http.StatusOK},
+	{method: http.MethodGet, target: "/books/middlemarch", conditional: true, status: http.StatusNotModified},
+	{method: http.MethodGet, target: "/books/missing", status: http.StatusNotFound},
+	{method: http.MethodPut, target: "/books/middlemarch", body: `{"title":"Middlemarch","status":"reading","rating":4}`, status: http.StatusOK},
+	{method: http.MethodPut, target: "/books/middlemarch", body: `{"title":"Middlemarch","rating":6}`, status: http.StatusBadRequest},
+	{method: http.MethodPut, target: "/books/missing", body: `{"title":"Missing"}`, status: http.StatusNotFound},
+	{method: http.MethodPost, target: "/books", body: `{"title":"Middlemarch!"}`, status: http.StatusConflict},
+	{method: http.MethodPost, target: "/books?allowDuplicate=true", body: `{"id":"middlemarch-2","title":"Middlemarch","author":"George Eliot","tags":["novel"]}`, status: http.StatusCreated},
+	{method: http.MethodGet, target: "/books/duplicates", status: http.StatusOK},
+	{method: http.MethodPost, target: "/books/middlemarch/merge", body: `{"sourceId":"middlemarch-2"}`, status: http.StatusOK},
+	{method: http.MethodPost, target: "/books/middlemarch/merge", body: `{}`, status: http.StatusBadRequest},
+	{method: http.MethodPost, target: "/books/missing/merge", body: `{"sourceId":"middlemarch"}`, status: http.StatusNotFound},
+	{method: http.MethodDelete, target: "/books/adam-bede", status: http.StatusOK},
+	{method: http.MethodDelete, target: "/books/missing", status: http.StatusNotFound},
+	{method: http.MethodGet, target: "/books/recommendations?limit=5", status: http.StatusOK},
+	{method: http.MethodGet, target: "/books/recommendations?limit=51", status: http.StatusBadRequest},
+}
+
+// swaggerDocument is the part of the swag generated definition compared with
+// the OpenAPI 3 definition.
+type swaggerDocument struct {
+	BasePath string                                         `json:"basePath"`
+	Paths    map[string]map[string]swaggerDocumentOperation `json:"paths"`
+}
+
+type swaggerDocumentOperation struct {
+	Responses map[string]json.RawMessage `json:"responses"`
+}
+
+// TestAPIContract checks that the routes of each API version and the
+// responses they return match its OpenAPI definition and the swag generated
+// definition. The responses
+// are also validated against the schemas of docs/openapi.yaml, so a step
+// that gets a 500 has most likely returned a body that drifted from them.
+func TestAPIContract(t *testing.T) {
+	t.Setenv(config.OpenAPIValidation, config.OpenAPIValidationStrict)
+	t.Setenv(config.OpenAPIValidationFailOnDrift, "true")
+	t.Setenv(config.TenantMode, config.TenantModeHeader)
+	t.Setenv(config.TenantFallback, tenancy.DefaultTenant)
+	t.Setenv(config.CoverMaxSize, strconv.Itoa(2*len(testCover)))
+	setTestAuth(t)
+	_, err := config.LoadConfig()
+	require.NoError(t, err)
+
+	app := fiber.New(fiber.Config{ErrorHandler: utils.FiberErrorHandler})
+	Initialize(app)
+	defer Shutdown()
+
+	for _, contract := range apiContracts {
+		t.Run(contract.version, func(t *testing.T) {
+			testAPIContract(t, app, contract)
+		})
+	}
+}
+
+func testAPIContract(t *testing.T, app *fiber.App, contract apiContract) {
+	spec, err := openapi.Load(contract.definition)
+	require.NoError(t, err)
+	documented := documentedResponses(spec)
+
+	t.Run("SwaggerMatchesOpenAPI", func(t *testing.T) {
+		contents, err := os.ReadFile(contract.swaggerPath)
+		require.NoError(t, err)
+		var swagger swaggerDocument
+		require.NoError(t, json.Unmarshal(contents, &swagger))
+
+		assert.Equal(t, spec.BasePath, strings.TrimRight(swagger.BasePath, "/"), "the base paths differ")
+		swaggerResponses := make(map[string][]int)
+		for path, operations := range swagger.Paths {
+			for method, operation := range operations {
+				var statuses []int
+				for status := range operation.Responses {
+					code, err := strconv.Atoi(status)
+					require.NoError(t, err, "response %s of %s %s in swagger.json", status, method, path)
+					statuses = append(statuses, code)
+				}
+				sort.Ints(statuses)
+				swaggerResponses[operationKey(method, path)] = statuses
+			}
+		}
+		assert.Equal(t, documented, swaggerResponses, "swagger.json and openapi.yaml declare different operations or responses")
+	})
+
+	t.Run("RoutesMatchOpenAPI", func(t *testing.T) {
+		registered := make(map[string]bool)
+		for _, route := range app.GetRoutes(true) {
+			if route.Method == fiber.MethodHead {
+				continue
+			}
+			template, ok := spec.TemplateFromFiberPath(route.Path)
+			if !ok {
+				continue
+			}
+			key := operationKey(route.Method, template)
+			registered[key] = true
+			assert.Contains(t, documented, key, "route %s %s is not documented", route.Method, route.Path)
+		}
+		for key := range documented {
+			assert.True(t, registered[key], "operation %s is documented but not registered", key)
+		}
+	})
+
+	t.Run("Responses", func(t *testing.T) {
+		exercised := make(map[string]map[int]bool)
+		lastChange := time.Now()
+		var captured []string
+		for _, step := range contract.steps {
+			name := fmt.Sprintf("%s %s", step.method, step.target)
+			target := strings.NewReplacer(captured...).Replace(step.target)
+			req := httptest.NewRequest(step.method, spec.BasePath+target, strings.NewReader(step.body))
+			if step.body != "" {
+				req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			}
+			tenant := tenancy.DefaultTenant
+			if header, ok := step.headers[config.DefaultTenantHeader]; ok {
+				tenant = header
+			}
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer "+testToken(t, testScopes, tenant))
+			for key, value := range step.headers {
+				req.Header.Set(key, value)
+			}
+			if step.conditional {
+				time.Sleep(time.Until(lastChange.Truncate(time.Second).Add(time.Second)))
+				req.Header.Set(fiber.HeaderIfModifiedSince, time.Now().UTC().Format(http.TimeFormat))
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err, name)
+			if step.method != http.MethodGet {
+				lastChange = time.Now()
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if !assert.Equal(t, step.status, resp.StatusCode, "%s: %s", name, body) {
+				continue
+			}
+			if step.captures != "" {
+				var resource struct {
+					Id string `json:"id"`
+				}
+				require.NoError(t, json.Unmarshal(body, &resource), name)
+				captured = append(captured, "{"+step.captures+"}", resource.Id)
+			}
+
+			path, _, _ := strings.Cut(target, "?")
+			route, _, ok := spec.FindRoute(step.method, spec.BasePath+path)
+			require.True(t, ok, "%s is not documented", name)
+			key := operationKey(route.Method, route.Path)
+			assert.Contains(t, documented[key], resp.StatusCode, "%s: response %d is not documented", name, resp.StatusCode)
+			if exercised[key] == nil {
+				exercised[key] = make(map[int]bool)
+			}
+			exercised[key][resp.StatusCode] = true
+		}
+		for key, statuses := range documented {
+			for _, status := range statuses {
+				if _, ok := exercisedElsewhere[key][status]; ok {
+					continue
+				}
+				assert.True(t, exercised[key][status], "response %d of %s is documented but not exercised", status, key)
+			}
+		}
+	})
+}
+
+// exercisedElsewhere are the documented responses that depend on a
+// configuration the contract test does not run with, keyed by operationKey,
+// and the tests that exercise them instead.
+var exercisedElsewhere = map[string]map[int]string{
+	"GET /admin/backup": {http.StatusOK: "TestBoltStorage"},
+}
+
+// documentedResponses returns the documented response statuses of every
+// operation, keyed by operationKey.
+func documentedResponses(spec *openapi.Spec) map[string][]int {
+	responses := make(map[string][]int)
+	for _, route := range spec.Routes() {
+		var statuses []int
+		for status := range route.Operation.Responses.Map() {
+			if code, err := strconv.Atoi(status); err == nil {
+				statuses = append(statuses, code)
+			}
+		}
+		sort.Ints(statuses)
+		responses[operationKey(route.Method, route.Path)] = statuses
+	}
+	return responses
+}
+
+func operationKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
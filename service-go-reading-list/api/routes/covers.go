@@ -0,0 +1,152 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package routes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+// coverFormField is the field of the cover in the multipart forms.
+const coverFormField = "cover"
+
+func registerCoverRoutes(router fiber.Router) {
+	r := router.Group("/reading-list/books")
+	r.Put("/:id/cover", PutCover)
+	r.Get("/:id/cover", GetCover)
+}
+
+// PutCover
+//
+//	@ID				putCover
+//	@Summary		Upload the cover image of a book
+//	@Description	Replaces the cover of the book with a JPEG, PNG or GIF image of at most COVER_MAX_SIZE bytes, sent as the cover field of a form or as the body of the request.
+//	@Tags			books
+//	@Accept			multipart/form-data,image/jpeg,image/png,image/gif
+//	@Produce		json
+//	@Param			id		path		string	true	"Book ID"
+//	@Param			cover	formData	file	true	"Cover image"
+//	@Security		default[write:books]
+//	@Router			/books/{id}/cover [put]
+//	@Success		200	{object}	models.Cover		"successful operation"
+//	@Failure		400	{object}	utils.ErrorResponse	"invalid image"
+//	@Failure		404	{object}	utils.ErrorResponse	"book not found"
+//	@Failure		413	{object}	utils.ErrorResponse	"image too large"
+//	@Failure		415	{object}	utils.ErrorResponse	"unsupported image type"
+func PutCover(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	data, contentType, err := readCover(c)
+	if err != nil {
+		return err
+	}
+	cover, err := coverController.PutCover(ctx, c.Params("id"), data, contentType)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(cover)
+}
+
+// readCover returns the image of the cover field of a form, or the body of
+// the request, with its content type. The content type is empty when it is
+// not known, so that it is detected from the image.
+func readCover(c *fiber.Ctx) ([]byte, string, error) {
+	mediaType, _, _ := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+	if mediaType != fiber.MIMEMultipartForm {
+		return c.Body(), c.Get(fiber.HeaderContentType), nil
+	}
+	header, err := c.FormFile(coverFormField)
+	if err != nil {
+		return nil, "", fiber.NewError(http.StatusBadRequest, fmt.Sprintf("the cover should be sent as the %s field of the form", coverFormField))
+	}
+	file, err := header.Open()
+	if err != nil {
+		return nil, "", err
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, "", err
+	}
+	contentType := header.Header.Get(fiber.HeaderContentType)
+	if contentType == fiber.MIMEOctetStream {
+		contentType = ""
+	}
+	return data, contentType, nil
+}
+
+// GetCover
+//
+//	@ID				getCover
+//	@Summary		Get the cover image of a book
+//	@Description	Returns the original cover of the book, or its thumbnail fitting in a square of 128 (small), 320 (medium) or 640 (large) pixels. The thumbnails are made when the cover is uploaded and stored with it.
+//	@Tags			books
+//	@Produce		image/jpeg,image/png,image/gif,json
+//	@Param			id					path	string	true	"Book ID"
+//	@Param			size				query	string	false	"Size of the image"	Enums(small, medium, large, original)	default(original)
+//	@Param			If-None-Match		header	string	false	"Answers with 304 if the image still has one of the entity tags"
+//	@Param			If-Modified-Since	header	string	false	"Answers with 304 if the image has not changed since then, unless If-None-Match is set"
+//	@Security		default[read:books]
+//	@Router			/books/{id}/cover [get]
+//	@Success		200	{file}		file			"successful operation"
+//	@Header			200	{string}	ETag			"Entity tag of the image"
+//	@Header			200	{string}	Last-Modified	"When the image was stored"
+//	@Success		304	"not modified"
+//	@Failure		400	{object}	utils.ErrorResponse	"invalid size"
+//	@Failure		404	{object}	utils.ErrorResponse	"book or cover not found"
+func GetCover(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	cover, err := coverController.GetCover(ctx, c.Params("id"), models.CoverSize(c.Query("size")))
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(cover.Data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	lastModified := cover.ModifiedAt.Truncate(time.Second)
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	c.Set(fiber.HeaderCacheControl, coverCacheControl(config.GetConfig().CoverMaxAge))
+	if notModified(c, etag, lastModified) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	c.Set(fiber.HeaderContentType, cover.ContentType)
+	return c.Status(fiber.StatusOK).Send(cover.Data)
+}
+
+// coverCacheControl lets the clients keep the covers for maxAge, after which
+// they revalidate them with their ETag.
+func coverCacheControl(maxAge time.Duration) string {
+	if maxAge <= 0 {
+		return "private, no-cache"
+	}
+	return fmt.Sprintf("private, max-age=%d", int(maxAge.Seconds()))
+}
+
+// notModified reports whether the conditional headers of the request match
+// the image. As required by RFC 9110, If-Modified-Since is ignored when
+// If-None-Match is set, and the entity tags are compared weakly.
+func notModified(c *fiber.Ctx, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := c.Get(fiber.HeaderIfNoneMatch); ifNoneMatch != "" {
+		for _, tag := range strings.Split(ifNoneMatch, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == etag || tag == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	since, err := http.ParseTime(c.Get(fiber.HeaderIfModifiedSince))
+	return err == nil && !since.After(time.Now()) && !lastModified.After(since)
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package routes
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+// testCover is a PNG cover of 200x300 pixels.
+var testCover = newTestCover(200, 300)
+
+// testCoverForm is a multipart form with testCover in its cover field, and
+// testCoverFormType its content type.
+var testCoverForm, testCoverFormType = newTestCoverForm(testCover)
+
+func newTestCover(width, height int) string {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		panic(err)
+	}
+	return buf.String()
+}
+
+func newTestCoverForm(cover string) (string, string) {
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="cover"; filename="cover.png"`)
+	header.Set("Content-Type", "image/png")
+	part, err := form.CreatePart(header)
+	if err == nil {
+		_, err = part.Write([]byte(cover))
+	}
+	if err == nil {
+		err = form.Close()
+	}
+	if err != nil {
+		panic(err)
+	}
+	return buf.String(), form.FormDataContentType()
+}
+
+func TestCovers(t *testing.T) {
+	t.Setenv(config.CoverMaxAge, "1h")
+	_, err := config.LoadConfig()
+	require.NoError(t, err)
+
+	app := fiber.New(fiber.Config{ErrorHandler: utils.FiberErrorHandler})
+	Initialize(app)
+	defer Shutdown()
+
+	send := func(method, target, body string, headers map[string]string) *http.Response {
+		req := httptest.NewRequest(method, "/api/v1/reading-list"+target, strings.NewReader(body))
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+	resp := send(http.MethodPost, "/books", `{"id":"dune","title":"Dune"}`, map[string]string{fiber.HeaderContentType: fiber.MIMEApplicationJSON})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	t.Run("Upload", func(t *testing.T) {
+		resp := send(http.MethodPut, "/books/dune/cover", testCoverForm, map[string]string{fiber.HeaderContentType: testCoverFormType})
+		body, _ := io.ReadAll(resp.Body)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.Contains(t, string(body), `"contentType":"image/png","size":`)
+		assert.Contains(t, string(body), `"width":200,"height":300`)
+
+		resp = send(http.MethodGet, "/books/dune/cover", "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body, _ = io.ReadAll(resp.Body)
+		assert.Equal(t, testCover, string(body))
+		assert.Equal(t, "image/png", resp.Header.Get(fiber.HeaderContentType))
+
+		// The forms need the cover field.
+		form, contentType := newTestCoverForm("")
+		form = strings.Replace(form, `name="cover"`, `name="image"`, 1)
+		resp = send(http.MethodPut, "/books/dune/cover", form, map[string]string{fiber.HeaderContentType: contentType})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Caching", func(t *testing.T) {
+		resp := send(http.MethodGet, "/books/dune/cover?size=small", "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "private, max-age=3600", resp.Header.Get(fiber.HeaderCacheControl))
+		assert.NotEmpty(t, resp.Header.Get(fiber.HeaderLastModified))
+		etag := resp.Header.Get(fiber.HeaderETag)
+		require.NotEmpty(t, etag)
+
+		resp = send(http.MethodGet, "/books/dune/cover?size=small", "", map[string]string{fiber.HeaderIfNoneMatch: `"other", W/` + etag})
+		assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+		assert.Equal(t, etag, resp.Header.Get(fiber.HeaderETag))
+		// The thumbnails are told apart from the cover.
+		resp = send(http.MethodGet, "/books/dune/cover", "", map[string]string{fiber.HeaderIfNoneMatch: etag})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		// If-Modified-Since is ignored when If-None-Match is set.
+		resp = send(http.MethodGet, "/books/dune/cover?size=small", "", map[string]string{
+			fiber.HeaderIfNoneMatch:     `"other"`,
+			fiber.HeaderIfModifiedSince: "Fri, 01 Jan 2100 00:00:00 GMT",
+		})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		// A new cover has a new entity tag.
+		resp = send(http.MethodPut, "/books/dune/cover", newTestCover(100, 100), map[string]string{fiber.HeaderContentType: "image/png"})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp = send(http.MethodGet, "/books/dune/cover?size=small", "", map[string]string{fiber.HeaderIfNoneMatch: etag})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		resp := send(http.MethodDelete, "/books/dune", "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp = send(http.MethodGet, "/books/dune/cover", "", nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		resp = send(http.MethodPost, "/books/dune/restore", "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp = send(http.MethodGet, "/books/dune/cover", "", nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		// The cover is removed with the book, so a new book with the same id
+		// has no cover.
+		resp = send(http.MethodDelete, "/books/dune?hard=true", "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp = send(http.MethodPost, "/books", `{"id":"dune","title":"Dune"}`, map[string]string{fiber.HeaderContentType: fiber.MIMEApplicationJSON})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		resp = send(http.MethodGet, "/books/dune/cover", "", nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
@@ -0,0 +1,254 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package routes
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+const mimeTextMarkdown = "text/markdown"
+
+// markdownEscaper escapes the characters that Markdown would take for
+// formatting in the text of the books and highlights.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`, `<`, `\<`, `>`, `\>`, `#`, `\#`,
+)
+
+func registerHighlightRoutes(router fiber.Router) {
+	router.Get("/reading-list/highlights", SearchHighlights)
+	r := router.Group("/reading-list/books")
+	r.Get("/:id/highlights/export", ExportHighlights)
+	r.Get("/:id/highlights", ListHighlights)
+	r.Post("/:id/highlights", AddHighlight)
+	r.Get("/:id/highlights/:highlightId", GetHighlight)
+	r.Put("/:id/highlights/:highlightId", UpdateHighlight)
+	r.Delete("/:id/highlights/:highlightId", DeleteHighlight)
+}
+
+// SearchHighlights
+//
+//	@ID				searchHighlights
+//	@Summary		Search the highlights of all the books
+//	@Description	Returns the highlights of the books on the reading list, grouped by book in reading order. The highlights of the books in the trash are left out.
+//	@Tags			highlights
+//	@Produce		json
+//	@Param			q	query	string	false	"Only the highlights whose text or note contain it, regardless of case"
+//	@Param			tag	query	string	false	"Only the highlights with the tag"
+//	@Security		default[read:books]
+//	@Router			/highlights [get]
+//	@Success		200	{array}	models.Highlight	"successful operation"
+func SearchHighlights(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	highlights, err := highlightController.SearchHighlights(ctx, highlightFilter(c))
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(highlights)
+}
+
+// ListHighlights
+//
+//	@ID			listHighlights
+//	@Summary	List the highlights of a book in reading order
+//	@Tags		highlights
+//	@Produce	json
+//	@Param		id	path	string	true	"Book ID"
+//	@Param		q	query	string	false	"Only the highlights whose text or note contain it, regardless of case"
+//	@Param		tag	query	string	false	"Only the highlights with the tag"
+//	@Security	default[read:books]
+//	@Router		/books/{id}/highlights [get]
+//	@Success	200	{array}		models.Highlight	"successful operation"
+//	@Failure	404	{object}	utils.ErrorResponse	"book not found"
+func ListHighlights(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	highlights, err := highlightController.ListHighlights(ctx, c.Params("id"), highlightFilter(c))
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(highlights)
+}
+
+// AddHighlight
+//
+//	@ID			addHighlight
+//	@Summary	Add a highlight to a book
+//	@Tags		highlights
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path	string				true	"Book ID"
+//	@Param		request	body	models.Highlight	true	"Highlight details"
+//	@Security	default[write:books]
+//	@Router		/books/{id}/highlights [post]
+//	@Success	201	{object}	models.Highlight	"successful operation"
+//	@Failure	400	{object}	utils.ErrorResponse	"invalid highlight details"
+//	@Failure	404	{object}	utils.ErrorResponse	"book not found"
+func AddHighlight(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	newHighlight := models.Highlight{}
+	if err := c.BodyParser(&newHighlight); err != nil {
+		return makeHttpBadRequestError(err)
+	}
+	highlight, err := highlightController.AddHighlight(ctx, strings.Clone(c.Params("id")), newHighlight)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(highlight)
+}
+
+// GetHighlight
+//
+//	@ID			getHighlight
+//	@Summary	Get a highlight of a book by id
+//	@Tags		highlights
+//	@Produce	json
+//	@Param		id			path	string	true	"Book ID"
+//	@Param		highlightId	path	string	true	"Highlight ID"
+//	@Security	default[read:books]
+//	@Router		/books/{id}/highlights/{highlightId} [get]
+//	@Success	200	{object}	models.Highlight	"successful operation"
+//	@Failure	404	{object}	utils.ErrorResponse	"book or highlight not found"
+func GetHighlight(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	highlight, err := highlightController.GetHighlight(ctx, c.Params("id"), c.Params("highlightId"))
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(highlight)
+}
+
+// UpdateHighlight
+//
+//	@ID			updateHighlight
+//	@Summary	Update a highlight of a book by id
+//	@Tags		highlights
+//	@Accept		json
+//	@Produce	json
+//	@Param		id			path	string				true	"Book ID"
+//	@Param		highlightId	path	string				true	"Highlight ID"
+//	@Param		request		body	models.Highlight	true	"Highlight details"
+//	@Security	default[write:books]
+//	@Router		/books/{id}/highlights/{highlightId} [put]
+//	@Success	200	{object}	models.Highlight	"successful operation"
+//	@Failure	400	{object}	utils.ErrorResponse	"invalid highlight details"
+//	@Failure	404	{object}	utils.ErrorResponse	"book or highlight not found"
+func UpdateHighlight(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	updatedHighlight := models.Highlight{}
+	if err := c.BodyParser(&updatedHighlight); err != nil {
+		return makeHttpBadRequestError(err)
+	}
+	updatedHighlight.Id = strings.Clone(c.Params("highlightId"))
+	highlight, err := highlightController.UpdateHighlight(ctx, c.Params("id"), updatedHighlight)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(highlight)
+}
+
+// DeleteHighlight
+//
+//	@ID			deleteHighlight
+//	@Summary	Delete a highlight of a book by id
+//	@Tags		highlights
+//	@Produce	json
+//	@Param		id			path	string	true	"Book ID"
+//	@Param		highlightId	path	string	true	"Highlight ID"
+//	@Security	default[write:books]
+//	@Router		/books/{id}/highlights/{highlightId} [delete]
+//	@Success	200	{object}	models.Highlight	"successful operation"
+//	@Failure	404	{object}	utils.ErrorResponse	"book or highlight not found"
+func DeleteHighlight(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	highlight, err := highlightController.DeleteHighlight(ctx, c.Params("id"), c.Params("highlightId"))
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(highlight)
+}
+
+// ExportHighlights
+//
+//	@ID				exportHighlights
+//	@Summary		Export the highlights of a book as Markdown
+//	@Description	Returns a Markdown document with the title and author of the book, followed by its highlights in reading order as quotes with their page, location, note and tags.
+//	@Tags			highlights
+//	@Produce		text/markdown,json
+//	@Param			id	path	string	true	"Book ID"
+//	@Security		default[read:books]
+//	@Router			/books/{id}/highlights/export [get]
+//	@Success		200	{file}		file				"successful operation"
+//	@Failure		404	{object}	utils.ErrorResponse	"book not found"
+func ExportHighlights(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	book, err := bookController.GetBook(ctx, c.Params("id"))
+	if err != nil {
+		return err
+	}
+	highlights, err := highlightController.ListHighlights(ctx, book.Id, models.HighlightFilter{})
+	if err != nil {
+		return err
+	}
+	c.Attachment(book.Id + "-highlights.md")
+	c.Set(fiber.HeaderContentType, mimeTextMarkdown+"; charset=utf-8")
+	return c.Status(fiber.StatusOK).Send(marshalHighlightsMarkdown(book, highlights))
+}
+
+// highlightFilter returns the filter of the highlights in the query.
+func highlightFilter(c *fiber.Ctx) models.HighlightFilter {
+	return models.HighlightFilter{Query: c.Query("q"), Tag: c.Query("tag")}
+}
+
+// marshalHighlightsMarkdown writes the highlights of the book as a Markdown
+// document, with the text of each highlight quoted.
+func marshalHighlightsMarkdown(book models.Book, highlights []models.Highlight) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n", markdownEscaper.Replace(markdownLine(book.Title)))
+	if book.Author != "" {
+		fmt.Fprintf(&buf, "\n*%s*\n", markdownEscaper.Replace(markdownLine(book.Author)))
+	}
+	for _, highlight := range highlights {
+		buf.WriteString("\n")
+		for _, line := range strings.Split(strings.TrimSpace(highlight.Text), "\n") {
+			if line = strings.TrimSpace(line); line == "" {
+				buf.WriteString(">\n")
+			} else {
+				fmt.Fprintf(&buf, "> %s\n", markdownEscaper.Replace(line))
+			}
+		}
+		var where []string
+		if highlight.Page > 0 {
+			where = append(where, fmt.Sprintf("page %d", highlight.Page))
+		}
+		if highlight.Location != "" {
+			where = append(where, markdownEscaper.Replace(markdownLine(highlight.Location)))
+		}
+		if len(where) > 0 {
+			fmt.Fprintf(&buf, "\n— %s\n", strings.Join(where, ", "))
+		}
+		if note := strings.TrimSpace(highlight.Note); note != "" {
+			fmt.Fprintf(&buf, "\n%s\n", markdownEscaper.Replace(note))
+		}
+		if len(highlight.Tags) > 0 {
+			tags := make([]string, len(highlight.Tags))
+			for i, tag := range highlight.Tags {
+				tags[i] = "`" + strings.ReplaceAll(tag, "`", "'") + "`"
+			}
+			fmt.Fprintf(&buf, "\nTags: %s\n", strings.Join(tags, ", "))
+		}
+	}
+	return buf.Bytes()
+}
+
+// markdownLine joins the lines of a text that is written on a single line,
+// such as a heading.
+func markdownLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package routes
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+func TestExportHighlights(t *testing.T) {
+	_, err := config.LoadConfig()
+	require.NoError(t, err)
+
+	app := fiber.New(fiber.Config{ErrorHandler: utils.FiberErrorHandler})
+	Initialize(app)
+	defer Shutdown()
+
+	send := func(method, target, body string) *http.Response {
+		req := httptest.NewRequest(method, "/api/v1/reading-list"+target, strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+	resp := send(http.MethodPost, "/books", `{"id":"dune","title":"Dune","author":"Frank Herbert"}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	for _, highlight := range []string{
+		`{"text":"He who controls the spice controls the universe.","tags":["spice"]}`,
+		`{"text":"I must not fear.\nFear is the mind-killer.","page":12,"location":"Chapter 1","note":"The *litany* against fear.","tags":["fear","litany"]}`,
+	} {
+		resp = send(http.MethodPost, "/books/dune/highlights", highlight)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+	}
+
+	resp = send(http.MethodGet, "/books/dune/highlights/export", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/markdown; charset=utf-8", resp.Header.Get(fiber.HeaderContentType))
+	assert.Equal(t, `attachment; filename="dune-highlights.md"`, resp.Header.Get(fiber.HeaderContentDisposition))
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, `# Dune
+
+*Frank Herbert*
+
+> I must not fear.
+> Fear is the mind-killer.
+
+— page 12, Chapter 1
+
+The \*litany\* against fear.
+
+Tags: `+"`fear`, `litany`"+`
+
+> He who controls the spice controls the universe.
+
+Tags: `+"`spice`"+`
+`, string(body))
+
+	// The highlights are removed with the book, so a new book with the same
+	// id has none.
+	resp = send(http.MethodDelete, "/books/dune?hard=true", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp = send(http.MethodPost, "/books", `{"id":"dune","title":"Dune"}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp = send(http.MethodGet, "/books/dune/highlights", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, _ = io.ReadAll(resp.Body)
+	assert.JSONEq(t, `[]`, string(body))
+}
+
+func TestMarshalHighlightsMarkdown(t *testing.T) {
+	// The title is written on one line, and a book without highlights has
+	// only its title.
+	book := models.Book{Id: "dune", Title: "Dune:\n# Messiah"}
+	assert.Equal(t, "# Dune: \\# Messiah\n", string(marshalHighlightsMarkdown(book, nil)))
+
+	// The paragraphs of a highlight stay in the quote.
+	highlights := []models.Highlight{{Text: "Fear is the mind-killer.\n\nI will face my fear."}}
+	assert.Equal(t, "# Dune: \\# Messiah\n\n> Fear is the mind-killer.\n>\n> I will face my fear.\n", string(marshalHighlightsMarkdown(book, highlights)))
+}
@@ -4,15 +4,152 @@
 package routes
 
 import (
+	"context"
+	"io"
+	"log"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
 	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
 	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/controllers"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
 	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/storage"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/tenancy"
+)
+
+var (
+	bookController           *controllers.BookController
+	statsController          *controllers.StatsController
+	recommendationController *controllers.RecommendationController
+	tenantController         *controllers.TenantController
+	coverController          *controllers.CoverController
+	highlightController      *controllers.HighlightController
 )
 
-var bookController *controllers.BookController
+// backgroundJobs are started by startBackgroundJobs and run until Shutdown is called.
+var backgroundJobs []func(ctx context.Context)
+
+// runningJobs waits for the background jobs to return once they are stopped.
+var runningJobs sync.WaitGroup
+
+// shutdownHooks release the resources acquired by initControllers.
+var shutdownHooks []func() error
+
+// stopBackgroundJobs cancels the jobs started by startBackgroundJobs.
+var stopBackgroundJobs context.CancelFunc = func() {}
+
+// requestContext is the context the contexts of the API requests are
+// derived from. It is canceled by CancelRequests.
+var (
+	requestContext context.Context
+	cancelRequests context.CancelFunc = func() {}
+)
 
 func initControllers() {
-	initialData := config.LoadInitialData()
-	bookRepository := repositories.NewBookRepository(initialData.Books)
-	bookController = controllers.NewBookController(bookRepository)
+	cfg := config.GetConfig()
+	bookRepository := newTenantBookRepository(cfg, newBookRepository(cfg))
+	goalRepository := repositories.NewTenantGoalRepository()
+	highlightRepository := newHighlightRepository(cfg)
+	books := controllers.NewBookController(bookRepository, newAuditRepository(cfg))
+	bookController = books
+	statsController = controllers.NewStatsController(bookRepository, goalRepository)
+	coverController = controllers.NewCoverController(bookController, newCoverRepository(cfg), cfg.CoverMaxSize)
+	highlightController = controllers.NewHighlightController(bookController, highlightRepository)
+	tenantController = controllers.NewTenantController(bookController, bookRepository, goalRepository, coverController, highlightRepository)
+	recommendationController = controllers.NewRecommendationController(bookRepository, config.LoadCatalogue())
+	backgroundJobs = append(backgroundJobs, func(ctx context.Context) {
+		books.RunTrashPurger(ctx, cfg.TrashPurgeInterval, cfg.TrashRetention)
+	})
+}
+
+func newBookRepository(cfg *config.Config) models.BookRepository {
+	initialData, err := config.LoadTenantInitialData(tenancy.DefaultTenant)
+	if err != nil {
+		log.Fatal(err)
+	}
+	repo, err := storage.OpenBookRepository(cfg, initialData.Books)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if persistent, ok := repo.(*repositories.PersistentBookRepository); ok {
+		backgroundJobs = append(backgroundJobs, func(ctx context.Context) {
+			persistent.RunCompaction(ctx, cfg.SnapshotInterval)
+		})
+	}
+	if closer, ok := repo.(io.Closer); ok {
+		shutdownHooks = append(shutdownHooks, closer.Close)
+	}
+	return repo
+}
+
+func newTenantBookRepository(cfg *config.Config, books models.BookRepository) *repositories.TenantBookRepository {
+	repo, err := storage.NewTenantBookRepository(cfg, books)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return repo
+}
+
+func newAuditRepository(cfg *config.Config) models.AuditRepository {
+	repo, err := storage.OpenAuditRepository(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if closer, ok := repo.(io.Closer); ok {
+		shutdownHooks = append(shutdownHooks, closer.Close)
+	}
+	return repo
+}
+
+func newCoverRepository(cfg *config.Config) models.BlobRepository {
+	repo, err := storage.OpenCoverRepository(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return repo
+}
+
+func newHighlightRepository(cfg *config.Config) *repositories.TenantHighlightRepository {
+	repo, err := storage.OpenHighlightRepository(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	backgroundJobs = append(backgroundJobs, func(ctx context.Context) {
+		repo.RunCompaction(ctx, cfg.SnapshotInterval)
+	})
+	shutdownHooks = append(shutdownHooks, repo.Close)
+	return repo
+}
+
+func startBackgroundJobs() {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopBackgroundJobs = cancel
+	for _, job := range backgroundJobs {
+		runningJobs.Add(1)
+		go func() {
+			defer runningJobs.Done()
+			job(ctx)
+		}()
+	}
+}
+
+// stopBackgroundJobsAndWait stops the background jobs and waits for them to
+// return, so that they do not use the resources released by the shutdown
+// hooks. The jobs are dropped, so that the service can be initialized again.
+func stopBackgroundJobsAndWait() {
+	stopBackgroundJobs()
+	runningJobs.Wait()
+	backgroundJobs = nil
+}
+
+func runShutdownHooks() {
+	for _, hook := range shutdownHooks {
+		if err := hook(); err != nil {
+			logrus.Errorf("shutdown error: %v", err)
+		}
+	}
+	// The hooks are run once, so that the service can be initialized again.
+	shutdownHooks = nil
 }
@@ -0,0 +1,148 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package routes
+
+import (
+	"log"
+	"os"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/api/middleware"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/docs"
+	v2docs "github.com/wso2/choreo-sample-apps/go/rest-api/docs/v2"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/auth"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/openapi"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/tenancy"
+)
+
+const (
+	apiV1 = "v1"
+	apiV2 = "v2"
+)
+
+// apiDefinitions are the OpenAPI definitions of the API versions.
+var apiDefinitions = map[string][]byte{
+	apiV1: docs.OpenAPISpec,
+	apiV2: v2docs.OpenAPISpec,
+}
+
+var apiSpecs = make(map[string]*openapi.Spec)
+
+// apiMiddleware returns the middleware applied to all the routes of an API version.
+func apiMiddleware(version string) []fiber.Handler {
+	cfg := config.GetConfig()
+	handlers := []fiber.Handler{middleware.NewCancellation(requestContext), middleware.NewTimeout(cfg.RequestTimeout)}
+	if version == apiV1 {
+		handlers = append(handlers, middleware.NewDeprecation(middleware.DeprecationConfig{
+			DeprecatedAt: cfg.V1DeprecatedAt,
+			SunsetAt:     cfg.V1SunsetAt,
+			Successor:    loadAPISpec(apiV2).BasePath,
+		}))
+	}
+	if cfg.Auth.Mode != "" {
+		handlers = append(handlers, middleware.NewAuthorization(loadAPISpec(version), newTokenValidator(cfg.Auth)))
+	}
+	if cfg.OpenAPIValidation != "" {
+		handlers = append(handlers, middleware.NewValidation(loadAPISpec(version), middleware.ValidationConfig{
+			ValidateResponses: cfg.OpenAPIValidation == config.OpenAPIValidationStrict,
+			FailOnDrift:       cfg.OpenAPIValidationFailOnDrift,
+		}))
+	}
+	return handlers
+}
+
+// tenantResolution returns the middleware that resolves the tenant of the
+// requests, which is the default tenant when multi-tenancy is not enabled.
+// When the requests are authorized, the tenant named by the header or the
+// host must be the one of the tenant claim of the token.
+func tenantResolution() fiber.Handler {
+	cfg := config.GetConfig()
+	var resolver middleware.TenantResolver
+	claim := ""
+	if cfg.Auth.Mode != "" {
+		claim = cfg.Tenancy.Claim
+	}
+	switch cfg.Tenancy.Mode {
+	case config.TenantModeHeader:
+		resolver = middleware.TenantFromHeader(cfg.Tenancy.Header)
+	case config.TenantModeHost:
+		resolver = middleware.TenantFromHost(cfg.Tenancy.HostSuffix)
+	case config.TenantModeClaim:
+		resolver = middleware.TenantFromClaim(cfg.Tenancy.Claim)
+	default:
+		return middleware.NewTenantResolution(func(c *fiber.Ctx) string { return "" }, tenancy.DefaultTenant, "")
+	}
+	return middleware.NewTenantResolution(resolver, cfg.Tenancy.FallbackTenant, claim)
+}
+
+// newResponseCache returns the cache of the book list and get responses,
+// which is invalidated by every change to the books. The books shared in
+// Redis are also changed by the other replicas, which this one does not see,
+// so no response is kept and the conditional requests are not answered then.
+func newResponseCache() *middleware.ResponseCache {
+	cfg := config.GetConfig()
+	cacheConfig := middleware.ResponseCacheConfig{
+		LastModified: bookController.LastModified,
+		MaxEntries:   cfg.ResponseCacheSize,
+		MaxAge:       cfg.ResponseCacheMaxAge,
+	}
+	if cfg.RedisURL != "" {
+		cacheConfig.LastModified = nil
+		cacheConfig.MaxEntries = 0
+	}
+	cache := middleware.NewResponseCache(cacheConfig)
+	bookController.OnChange(cache.Invalidate)
+	return cache
+}
+
+// verifyRoutes fails the startup when a registered route is not covered by
+// the security requirements of the OpenAPI definition.
+func verifyRoutes(app *fiber.App) {
+	for version := range apiDefinitions {
+		if err := middleware.VerifyRouteSecurity(app.GetRoutes(true), loadAPISpec(version)); err != nil {
+			log.Fatalf("the %s API routes do not match the OpenAPI definition: %s", version, err)
+		}
+	}
+}
+
+func loadAPISpec(version string) *openapi.Spec {
+	if spec, ok := apiSpecs[version]; ok {
+		return spec
+	}
+	spec, err := openapi.Load(apiDefinitions[version])
+	if err != nil {
+		log.Fatal(err)
+	}
+	apiSpecs[version] = spec
+	return spec
+}
+
+func newTokenValidator(cfg config.AuthConfig) auth.TokenValidator {
+	if cfg.Mode == config.AuthModeIntrospection {
+		return auth.NewIntrospectionValidator(auth.IntrospectionValidatorConfig{
+			Endpoint:     cfg.IntrospectionURL,
+			ClientId:     cfg.IntrospectionClientId,
+			ClientSecret: cfg.IntrospectionClientSecret,
+		})
+	}
+	jwtConfig := auth.JWTValidatorConfig{
+		Secret:   []byte(cfg.JWTSecret),
+		Issuer:   cfg.JWTIssuer,
+		Audience: cfg.JWTAudience,
+	}
+	if cfg.JWTSecret == "" && cfg.JWTPublicKeyPath != "" {
+		publicKey, err := os.ReadFile(cfg.JWTPublicKeyPath)
+		if err != nil {
+			log.Fatalf("failed to read the JWT public key at [%s]: %s", cfg.JWTPublicKeyPath, err)
+		}
+		jwtConfig.PublicKeyPEM = publicKey
+	}
+	validator, err := auth.NewJWTValidator(jwtConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return validator
+}
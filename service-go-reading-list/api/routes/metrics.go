@@ -0,0 +1,15 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/expvar"
+)
+
+// RegisterMetricsRoutes serves the expvar variables, including the response
+// cache metrics, as JSON at /debug/vars.
+func RegisterMetricsRoutes(r fiber.Router) {
+	r.Get("/debug/vars", expvar.New())
+}
@@ -4,130 +4,295 @@
 package routes
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/api/middleware"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
 	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	v1 "github.com/wso2/choreo-sample-apps/go/rest-api/internal/models/v1"
 
 	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
 )
 
-func registerReadingListRoutes(router fiber.Router) {
+func registerReadingListRoutes(router fiber.Router, responseCache *middleware.ResponseCache) {
+	idempotent := middleware.NewIdempotency(config.GetConfig().IdempotencyTTL)
+	router.Post("/reading-list/books\\:batch", idempotent, ExecuteBatch)
 	r := router.Group("/reading-list/books")
-	r.Post("/", AddBook)
-	r.Get("/:id", GetBook)
+	r.Post("/", idempotent, AddBook)
+	r.Get("/trash", responseCache.Handle, ListTrash)
+	r.Get("/recommendations", GetRecommendations)
+	r.Get("/duplicates", ListDuplicates)
+	r.Post("/:id/merge", MergeBook)
+	r.Post("/:id/restore", RestoreBook)
+	r.Get("/:id", responseCache.Handle, GetBook)
 	r.Put("/:id", UpdateBook)
 	r.Delete("/:id", DeleteBook)
-	r.Get("/", ListBooks)
+	r.Get("/", responseCache.Handle, ListBooks)
 }
 
 // AddBook
 //
+//	@ID			addBook
 //	@Summary	Add a new book to the reading list
 //	@Tags		books
-//	@Accept		json
-//	@Produce	json
-//	@Param		request	body	models.Book	true	"New book details"
+//	@Accept		json,application/xml,text/csv,application/yaml
+//	@Produce	json,application/xml,text/csv,application/yaml
+//	@Param		request			body	v1.Book	true	"New book details"
+//	@Param		Idempotency-Key	header	string	false	"Replays the first response for retries with the same key"
+//	@Param		allowDuplicate	query	bool	false	"Add the book even when it looks like a book on the reading list"
+//	@Security	default[write:books]
 //	@Router		/books [post]
-//	@Success	201	{object}	models.Book			"successful operation"
-//	@Failure	400	{object}	utils.ErrorResponse	"invalid book details"
-//	@Failure	409	{object}	utils.ErrorResponse	"book already exists"
+//	@Success	201	{object}	v1.Book						"successful operation"
+//	@Failure	400	{object}	utils.ErrorResponse			"invalid book details"
+//	@Failure	406	{object}	utils.ErrorResponse			"unsupported accepted media types"
+//	@Failure	409	{object}	models.DuplicateConflict	"book already exists or looks like a duplicate"
+//	@Failure	415	{object}	utils.ErrorResponse			"unsupported content type"
+//	@Failure	422	{object}	utils.ErrorResponse			"idempotency key reused with a different request"
 func AddBook(c *fiber.Ctx) error {
 	ctx := utils.GetRequestContext(c)
-	newBook := models.Book{}
-	if err := c.BodyParser(&newBook); err != nil {
-		return makeHttpBadRequestError(err)
-	}
-	res, err := bookController.AddBook(ctx, newBook)
+	mediaType, err := negotiateBookMediaType(c)
 	if err != nil {
 		return err
 	}
-	return c.Status(fiber.StatusCreated).JSON(res)
+	newBook := v1.Book{}
+	if err := parseBook(c, &newBook); err != nil {
+		return err
+	}
+	res, err := bookController.AddBook(ctx, newBook.Model(models.Book{}), c.QueryBool("allowDuplicate"))
+	if err != nil {
+		return sendDuplicateConflict(c, err)
+	}
+	return sendBook(c, fiber.StatusCreated, mediaType, v1.NewBook(res))
 }
 
 // UpdateBook
 //
+//	@ID			updateBook
 //	@Summary	Update a reading list book by id
 //	@Tags		books
-//	@Accept		json
-//	@Produce	json
-//	@Param		id		path	string		true	"Book ID"
-//	@Param		request	body	models.Book	true	"Updated book details"
+//	@Accept		json,application/xml,text/csv,application/yaml
+//	@Produce	json,application/xml,text/csv,application/yaml
+//	@Param		id		path	string	true	"Book ID"
+//	@Param		request	body	v1.Book	true	"Updated book details"
+//	@Security	default[write:books]
 //	@Router		/books/{id} [put]
-//	@Success	200	{object}	models.Book			"successful operation"
+//	@Success	200	{object}	v1.Book				"successful operation"
 //	@Failure	400	{object}	utils.ErrorResponse	"invalid book details"
 //	@Failure	404	{object}	utils.ErrorResponse	"book not found"
+//	@Failure	406	{object}	utils.ErrorResponse	"unsupported accepted media types"
+//	@Failure	415	{object}	utils.ErrorResponse	"unsupported content type"
 func UpdateBook(c *fiber.Ctx) error {
 	ctx := utils.GetRequestContext(c)
-	id := c.Params("id")
-	updatedBook := models.Book{}
-	if err := c.BodyParser(&updatedBook); err != nil {
-		return makeHttpBadRequestError(err)
+	// The id is stored with the book, so it must not point into the request
+	// buffer that Fiber reuses for the next request.
+	id := strings.Clone(c.Params("id"))
+	mediaType, err := negotiateBookMediaType(c)
+	if err != nil {
+		return err
+	}
+	updatedBook := v1.Book{}
+	if err := parseBook(c, &updatedBook); err != nil {
+		return err
 	}
 	updatedBook.Id = id
-	book, err := bookController.UpdateBook(ctx, updatedBook)
+	// The details that version 1 lacks are kept from the current book.
+	book, err := bookController.MergeBook(ctx, id, updatedBook.Model)
 	if err != nil {
 		return err
 	}
-	return c.Status(fiber.StatusOK).JSON(book)
+	return sendBook(c, fiber.StatusOK, mediaType, v1.NewBook(book))
 }
 
 // DeleteBook
 //
-//	@Summary	Delete a reading list book by id
+//	@ID				deleteBook
+//	@Summary		Delete a reading list book by id
+//	@Description	Moves the book to the trash unless hard is set, in which case the book is removed permanently.
+//	@Tags			books
+//	@Produce		json,application/xml,text/csv,application/yaml
+//	@Param			id		path	string	true	"Book ID"
+//	@Param			hard	query	bool	false	"Permanently remove the book instead of moving it to the trash"
+//	@Security		default[write:books]
+//	@Router			/books/{id} [delete]
+//	@Success		200	{object}	v1.Book				"successful operation"
+//	@Failure		404	{object}	utils.ErrorResponse	"book not found"
+//	@Failure		406	{object}	utils.ErrorResponse	"unsupported accepted media types"
+func DeleteBook(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	id := c.Params("id")
+	mediaType, err := negotiateBookMediaType(c)
+	if err != nil {
+		return err
+	}
+	var book models.Book
+	if c.QueryBool("hard") {
+		book, err = bookController.PurgeBook(ctx, id)
+	} else {
+		book, err = bookController.DeleteBook(ctx, id)
+	}
+	if err != nil {
+		return err
+	}
+	return sendBook(c, fiber.StatusOK, mediaType, v1.NewBook(book))
+}
+
+// ListTrash
+//
+//	@ID			listTrash
+//	@Summary	List the books in the trash
+//	@Tags		books
+//	@Produce	json,application/xml,text/csv,application/yaml
+//	@Param		If-Modified-Since	header	string	false	"Answers with 304 if the books have not changed since then"
+//	@Security	default[read:books]
+//	@Router		/books/trash [get]
+//	@Success	200	{array}		v1.Book			"successful operation"
+//	@Header		200	{string}	Last-Modified	"When the books were last changed"
+//	@Success	304	"not modified"
+//	@Failure	406	{object}	utils.ErrorResponse	"unsupported accepted media types"
+func ListTrash(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	mediaType, err := negotiateBookMediaType(c)
+	if err != nil {
+		return err
+	}
+	books, err := bookController.ListTrash(ctx)
+	if err != nil {
+		return err
+	}
+	return sendBooks(c, fiber.StatusOK, mediaType, v1.NewBooks(books))
+}
+
+// RestoreBook
+//
+//	@ID			restoreBook
+//	@Summary	Restore a book from the trash
 //	@Tags		books
-//	@Produce	json
+//	@Produce	json,application/xml,text/csv,application/yaml
 //	@Param		id	path	string	true	"Book ID"
-//	@Router		/books/{id} [delete]
-//	@Success	200	{object}	models.Book			"successful operation"
-//	@Failure	404	{object}	utils.ErrorResponse	"book not found"
-func DeleteBook(c *fiber.Ctx) error {
+//	@Security	default[write:books]
+//	@Router		/books/{id}/restore [post]
+//	@Success	200	{object}	v1.Book				"successful operation"
+//	@Failure	404	{object}	utils.ErrorResponse	"book not found in the trash"
+//	@Failure	406	{object}	utils.ErrorResponse	"unsupported accepted media types"
+func RestoreBook(c *fiber.Ctx) error {
 	ctx := utils.GetRequestContext(c)
 	id := c.Params("id")
-	book, err := bookController.DeleteBook(ctx, id)
+	mediaType, err := negotiateBookMediaType(c)
+	if err != nil {
+		return err
+	}
+	book, err := bookController.RestoreBook(ctx, id)
 	if err != nil {
 		return err
 	}
-	return c.Status(fiber.StatusOK).JSON(book)
+	return sendBook(c, fiber.StatusOK, mediaType, v1.NewBook(book))
 }
 
 // GetBook
 //
+//	@ID			getBook
 //	@Summary	Get reading list book by id
 //
 //	@Tags		books
 //
-//	@Produce	json
-//	@Param		id	path	string	true	"Book ID"
+//	@Produce	json,application/xml,text/csv,application/yaml
+//	@Param		id					path	string	true	"Book ID"
+//	@Param		If-Modified-Since	header	string	false	"Answers with 304 if the books have not changed since then"
+//	@Security	default[read:books]
 //	@Router		/books/{id} [get]
-//	@Success	200	{object}	models.Book			"successful operation"
+//	@Success	200	{object}	v1.Book			"successful operation"
+//	@Header		200	{string}	Last-Modified	"When the books were last changed"
+//	@Success	304	"not modified"
 //	@Failure	404	{object}	utils.ErrorResponse	"book not found"
+//	@Failure	406	{object}	utils.ErrorResponse	"unsupported accepted media types"
 func GetBook(c *fiber.Ctx) error {
 	ctx := utils.GetRequestContext(c)
 	id := c.Params("id")
+	mediaType, err := negotiateBookMediaType(c)
+	if err != nil {
+		return err
+	}
 
 	book, err := bookController.GetBook(ctx, id)
 	if err != nil {
 		return err
 	}
-	return c.Status(fiber.StatusOK).JSON(book)
+	return sendBook(c, fiber.StatusOK, mediaType, v1.NewBook(book))
 }
 
 // ListBooks
 //
+//	@ID			listBooks
 //	@Summary	List all the reading list books
 //	@Tags		books
-//	@Produce	json
+//	@Produce	json,application/xml,text/csv,application/yaml
+//	@Param		status				query	string	false	"Only the books with the status"	Enums(to_read, reading, read)
+//	@Param		author				query	string	false	"Only the books of the author, regardless of case"
+//	@Param		If-Modified-Since	header	string	false	"Answers with 304 if the books have not changed since then"
+//	@Security	default[read:books]
 //	@Router		/books [get]
-//	@Success	200	{array}	models.Book	"successful operation"
+//	@Success	200	{array}		v1.Book			"successful operation"
+//	@Header		200	{string}	Last-Modified	"When the books were last changed"
+//	@Success	304	"not modified"
+//	@Failure	400	{object}	utils.ErrorResponse	"invalid status"
+//	@Failure	406	{object}	utils.ErrorResponse	"unsupported accepted media types"
 func ListBooks(c *fiber.Ctx) error {
 	ctx := utils.GetRequestContext(c)
-	books, err := bookController.ListBooks(ctx)
+	mediaType, err := negotiateBookMediaType(c)
+	if err != nil {
+		return err
+	}
+	books, err := bookController.ListBooks(ctx, bookFilter(c))
 	if err != nil {
 		return err
 	}
-	return c.Status(fiber.StatusOK).JSON(books)
+	return sendBooks(c, fiber.StatusOK, mediaType, v1.NewBooks(books))
+}
+
+// ExecuteBatch
+//
+//	@ID				executeBatch
+//	@Summary		Apply several book operations atomically
+//	@Description	Applies a list of create, update and delete operations all-or-nothing. If any operation fails, none of them are applied and the response status is the status of the failed operation.
+//	@Tags			books
+//	@Accept			json
+//	@Produce		json
+//	@Param			request			body	v1.BatchRequest	true	"Operations to apply"
+//	@Param			Idempotency-Key	header	string			false	"Replays the first response for retries with the same key"
+//	@Security		default[write:books]
+//	@Router			/books:batch [post]
+//	@Success		200	{object}	v1.BatchResponse	"successful operation"
+//	@Failure		400	{object}	v1.BatchResponse	"invalid operation"
+//	@Failure		404	{object}	v1.BatchResponse	"book not found"
+//	@Failure		409	{object}	v1.BatchResponse	"book already exists"
+//	@Failure		422	{object}	utils.ErrorResponse	"idempotency key reused with a different request"
+func ExecuteBatch(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	request := v1.BatchRequest{}
+	if err := c.BodyParser(&request); err != nil {
+		return makeHttpBadRequestError(err)
+	}
+	operations := make([]models.BatchOperation, len(request.Operations))
+	for i, operation := range request.Operations {
+		operations[i] = operation.Model()
+	}
+	results, err := bookController.ExecuteBatch(ctx, operations)
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) && results != nil {
+		return c.Status(fiberErr.Code).JSON(v1.BatchResponse{Message: fiberErr.Message, Results: v1.NewBatchOperationResults(results)})
+	} else if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(v1.BatchResponse{Results: v1.NewBatchOperationResults(results)})
+}
+
+// bookFilter returns the filter of the books in the query.
+func bookFilter(c *fiber.Ctx) models.BookFilter {
+	return models.BookFilter{Status: models.ReadStatus(c.Query("status")), Author: c.Query("author")}
 }
 
 func makeHttpBadRequestError(err error) *fiber.Error {
@@ -0,0 +1,91 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package routes
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/redis/redistest"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/storage"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+func TestRedisStorage(t *testing.T) {
+	server, err := redistest.NewServer()
+	require.NoError(t, err)
+	defer server.Close()
+	t.Setenv(config.RedisURL, server.URL())
+	_, err = config.LoadConfig()
+	require.NoError(t, err)
+
+	// newReplica starts an instance of the service sharing the Redis.
+	newReplica := func() *fiber.App {
+		app := fiber.New(fiber.Config{ErrorHandler: utils.FiberErrorHandler})
+		Initialize(app)
+		return app
+	}
+	send := func(app *fiber.App, method, target, body string) (int, string) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		contents, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(contents)
+	}
+
+	app := newReplica()
+	status, body := send(app, http.MethodPost, "/api/v1/reading-list/books", `{"id":"dune","title":"Dune","author":"Frank Herbert","status":"to_read"}`)
+	assert.Equal(t, http.StatusCreated, status, body)
+	status, body = send(app, http.MethodPost, "/api/v1/reading-list/books:batch", `{"operations":[
+		{"op":"create","book":{"id":"emma","title":"Emma","author":"Jane Austen","status":"read"}},
+		{"op":"delete","id":"dune"}
+	]}`)
+	assert.Equal(t, http.StatusOK, status, body)
+	Shutdown()
+
+	// The books outlive the replica that added them.
+	app = newReplica()
+	defer Shutdown()
+	status, body = send(app, http.MethodGet, "/api/v1/reading-list/books", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `"id":"emma"`)
+	assert.NotContains(t, body, `"id":"dune"`)
+	status, body = send(app, http.MethodPost, "/api/v1/reading-list/books/dune/restore", "")
+	assert.Equal(t, http.StatusOK, status, body)
+	status, _ = send(app, http.MethodPost, "/api/v1/reading-list/books", `{"id":"emma","title":"Emma"}`)
+	assert.Equal(t, http.StatusConflict, status)
+
+	// The changes of the other replicas are not hidden by the response cache.
+	status, _ = send(app, http.MethodGet, "/api/v1/reading-list/books", "")
+	assert.Equal(t, http.StatusOK, status)
+	other, err := storage.OpenBookRepository(config.GetConfig(), nil)
+	require.NoError(t, err)
+	defer other.(io.Closer).Close()
+	_, err = other.Add(context.Background(), models.Book{Id: "ulysses", Title: "Ulysses", Author: "James Joyce", Status: models.ReadStatusToRead})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/reading-list/books", nil)
+	req.Header.Set(fiber.HeaderIfModifiedSince, time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	contents, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(fiber.HeaderLastModified))
+	assert.Contains(t, string(contents), `"id":"ulysses"`)
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+func registerStatsRoutes(router fiber.Router) {
+	router.Get("/reading-list/stats", GetStats)
+	r := router.Group("/reading-list/goals")
+	r.Get("/", ListGoals)
+	r.Get("/:year", GetGoal)
+	r.Put("/:year", PutGoal)
+	r.Delete("/:year", DeleteGoal)
+}
+
+// GetStats
+//
+//	@ID			getStats
+//	@Summary	Get reading statistics
+//	@Tags		stats
+//	@Produce	json
+//	@Security	default[read:books]
+//	@Router		/stats [get]
+//	@Success	200	{object}	models.ReadingStats	"successful operation"
+func GetStats(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	stats, err := statsController.GetStats(ctx)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(stats)
+}
+
+// ListGoals
+//
+//	@ID			listGoals
+//	@Summary	List the reading goals with their progress
+//	@Tags		goals
+//	@Produce	json
+//	@Security	default[read:books]
+//	@Router		/goals [get]
+//	@Success	200	{array}	models.GoalProgress	"successful operation"
+func ListGoals(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	goals, err := statsController.ListGoals(ctx)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(goals)
+}
+
+// GetGoal
+//
+//	@ID			getGoal
+//	@Summary	Get the reading goal of a year with its progress
+//	@Tags		goals
+//	@Produce	json
+//	@Param		year	path	int	true	"Year"
+//	@Security	default[read:books]
+//	@Router		/goals/{year} [get]
+//	@Success	200	{object}	models.GoalProgress	"successful operation"
+//	@Failure	400	{object}	utils.ErrorResponse	"invalid year"
+//	@Failure	404	{object}	utils.ErrorResponse	"goal not found"
+func GetGoal(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	year, err := c.ParamsInt("year")
+	if err != nil {
+		return makeHttpInvalidYearError()
+	}
+	progress, err := statsController.GetGoal(ctx, year)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(progress)
+}
+
+// PutGoal
+//
+//	@ID			setGoal
+//	@Summary	Set the reading goal of a year
+//	@Tags		goals
+//	@Accept		json
+//	@Produce	json
+//	@Param		year	path	int			true	"Year"
+//	@Param		request	body	models.Goal	true	"Goal details"
+//	@Security	default[write:books]
+//	@Router		/goals/{year} [put]
+//	@Success	200	{object}	models.GoalProgress	"successful operation"
+//	@Failure	400	{object}	utils.ErrorResponse	"invalid goal details"
+func PutGoal(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	year, err := c.ParamsInt("year")
+	if err != nil {
+		return makeHttpInvalidYearError()
+	}
+	goal := models.Goal{}
+	if err := c.BodyParser(&goal); err != nil {
+		return makeHttpBadRequestError(err)
+	}
+	goal.Year = year
+	progress, err := statsController.PutGoal(ctx, goal)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(progress)
+}
+
+// DeleteGoal
+//
+//	@ID			deleteGoal
+//	@Summary	Delete the reading goal of a year
+//	@Tags		goals
+//	@Produce	json
+//	@Param		year	path	int	true	"Year"
+//	@Security	default[write:books]
+//	@Router		/goals/{year} [delete]
+//	@Success	200	{object}	models.Goal			"successful operation"
+//	@Failure	400	{object}	utils.ErrorResponse	"invalid year"
+//	@Failure	404	{object}	utils.ErrorResponse	"goal not found"
+func DeleteGoal(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	year, err := c.ParamsInt("year")
+	if err != nil {
+		return makeHttpInvalidYearError()
+	}
+	goal, err := statsController.DeleteGoal(ctx, year)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(goal)
+}
+
+func makeHttpInvalidYearError() *fiber.Error {
+	return fiber.NewError(http.StatusBadRequest, "the year should be a number")
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package routes
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+func TestTenancy(t *testing.T) {
+	dataDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "acme.json"), []byte(`{"books":[{"id":"hobbit","title":"The Hobbit","status":"read"}]}`), 0o600))
+	t.Setenv(config.TenantMode, config.TenantModeHeader)
+	t.Setenv(config.TenantInitialDataDir, dataDir)
+	t.Setenv(config.TenantBookQuota, "2")
+	t.Setenv(config.TenantBookQuotas, "globex=1")
+	setTestAuth(t)
+	_, err := config.LoadConfig()
+	require.NoError(t, err)
+
+	app := fiber.New(fiber.Config{ErrorHandler: utils.FiberErrorHandler})
+	Initialize(app)
+	defer Shutdown()
+
+	send := func(method, target, tenant, body string) (int, string) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		}
+		if tenant != "" {
+			req.Header.Set(config.DefaultTenantHeader, tenant)
+		}
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+testToken(t, testScopes, tenant))
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		contents, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(contents)
+	}
+	bookIds := func(tenant string) []string {
+		status, body := send(http.MethodGet, "/api/v2/reading-list/books", tenant, "")
+		require.Equal(t, http.StatusOK, status, body)
+		var page struct {
+			Items []struct {
+				Id string `json:"id"`
+			} `json:"items"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &page))
+		ids := make([]string, 0)
+		for _, book := range page.Items {
+			ids = append(ids, book.Id)
+		}
+		return ids
+	}
+
+	t.Run("Isolation", func(t *testing.T) {
+		assert.Equal(t, []string{"hobbit"}, bookIds("acme"))
+		assert.Equal(t, []string{}, bookIds("globex"))
+
+		status, body := send(http.MethodPost, "/api/v1/reading-list/books", "Globex", `{"id":"hobbit","title":"The Hobbit"}`)
+		assert.Equal(t, http.StatusCreated, status, body)
+		status, _ = send(http.MethodGet, "/api/v1/reading-list/books/hobbit", "initech", "")
+		assert.Equal(t, http.StatusNotFound, status)
+		// The ids cannot address the books of another tenant.
+		status, body = send(http.MethodPost, "/api/v1/reading-list/books", "initech", `{"id":"acme/hobbit","title":"The Hobbit"}`)
+		assert.Equal(t, http.StatusBadRequest, status, body)
+	})
+
+	t.Run("Resolution", func(t *testing.T) {
+		status, body := send(http.MethodGet, "/api/v1/reading-list/books", "", "")
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Contains(t, body, "the tenant of the request is required")
+		status, _ = send(http.MethodGet, "/api/v1/reading-list/books", "not a tenant", "")
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		// The token must name the tenant of the header.
+		for _, token := range []string{testToken(t, testScopes, "globex"), testToken(t, testScopes, "")} {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/reading-list/books", nil)
+			req.Header.Set(config.DefaultTenantHeader, "acme")
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		}
+	})
+
+	t.Run("BrokenInitialData", func(t *testing.T) {
+		// The tenant whose initial data cannot be read is answered with 500,
+		// and is given it once it is fixed.
+		path := filepath.Join(dataDir, "umbrella.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"books":`), 0o600))
+		status, _ := send(http.MethodGet, "/api/v1/reading-list/books", "umbrella", "")
+		assert.Equal(t, http.StatusInternalServerError, status)
+		require.NoError(t, os.WriteFile(path, []byte(`{"books":[{"id":"emma","title":"Emma"}]}`), 0o600))
+		assert.Equal(t, []string{"emma"}, bookIds("umbrella"))
+		status, _ = send(http.MethodDelete, "/api/v1/reading-list/admin/tenants/umbrella", "", "")
+		require.Equal(t, http.StatusOK, status)
+	})
+
+	t.Run("Quota", func(t *testing.T) {
+		status, body := send(http.MethodPost, "/api/v2/reading-list/books", "globex", `{"title":"Emma"}`)
+		assert.Equal(t, http.StatusForbidden, status)
+		assert.Contains(t, body, "the book quota of the tenant is exceeded")
+		status, _ = send(http.MethodPost, "/api/v2/reading-list/books", "acme", `{"title":"Emma"}`)
+		assert.Equal(t, http.StatusCreated, status)
+		status, _ = send(http.MethodPost, "/api/v2/reading-list/books", "acme", `{"title":"Persuasion"}`)
+		assert.Equal(t, http.StatusForbidden, status)
+	})
+
+	t.Run("Admin", func(t *testing.T) {
+		// The admin routes are not made for a tenant.
+		status, body := send(http.MethodGet, "/api/v1/reading-list/admin/tenants", "", "")
+		assert.Equal(t, http.StatusOK, status)
+		assert.JSONEq(t, `[
+			{"id":"acme","books":2,"trashed":0,"quota":2},
+			{"id":"globex","books":1,"trashed":0,"quota":1}
+		]`, body)
+
+		status, body = send(http.MethodDelete, "/api/v1/reading-list/admin/tenants/acme", "", "")
+		assert.Equal(t, http.StatusOK, status)
+		assert.JSONEq(t, `{"id":"acme","books":2,"trashed":0,"quota":2}`, body)
+		// A deleted tenant starts over with its initial data.
+		assert.Equal(t, []string{"hobbit"}, bookIds("acme"))
+		assert.Equal(t, []string{"hobbit"}, bookIds("globex"))
+	})
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package routes
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	// The deadline of every request is exceeded before it reaches the
+	// repository.
+	t.Setenv(config.RequestTimeout, "1ns")
+	t.Setenv(config.OpenAPIValidation, config.OpenAPIValidationStrict)
+	t.Setenv(config.OpenAPIValidationFailOnDrift, "true")
+	_, err := config.LoadConfig()
+	require.NoError(t, err)
+
+	app := fiber.New(fiber.Config{ErrorHandler: utils.FiberErrorHandler})
+	Initialize(app)
+	defer Shutdown()
+	get := func(target string) (int, []byte) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, target, nil))
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, body
+	}
+	var metrics struct {
+		RequestContext struct {
+			DeadlineExceeded int64 `json:"deadline_exceeded"`
+		} `json:"request_context"`
+	}
+	status, body := get("/debug/vars")
+	require.Equal(t, http.StatusOK, status)
+	require.NoError(t, json.Unmarshal(body, &metrics))
+	exceeded := metrics.RequestContext.DeadlineExceeded
+
+	// The responses are not reported as drifting from the definition.
+	for _, target := range []string{"/api/v1/reading-list/books", "/api/v2/reading-list/books/dune"} {
+		status, body = get(target)
+		assert.Equal(t, http.StatusGatewayTimeout, status, target)
+		assert.JSONEq(t, `{"message":"the request timed out"}`, string(body), target)
+	}
+	// The routes outside the API have no deadline.
+	status, _ = get("/healthz")
+	assert.Equal(t, http.StatusOK, status)
+
+	status, body = get("/debug/vars")
+	require.Equal(t, http.StatusOK, status)
+	require.NoError(t, json.Unmarshal(body, &metrics))
+	assert.Equal(t, exceeded+2, metrics.RequestContext.DeadlineExceeded)
+}
+
+func TestCancelRequests(t *testing.T) {
+	t.Setenv(config.OpenAPIValidation, config.OpenAPIValidationStrict)
+	t.Setenv(config.OpenAPIValidationFailOnDrift, "true")
+	_, err := config.LoadConfig()
+	require.NoError(t, err)
+
+	app := fiber.New(fiber.Config{ErrorHandler: utils.FiberErrorHandler})
+	Initialize(app)
+	defer Shutdown()
+	get := func(target string) (int, []byte) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, target, nil))
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, body
+	}
+	// The requests of a service that is shutting down are canceled.
+	CancelRequests()
+	for _, target := range []string{"/api/v1/reading-list/books", "/api/v2/reading-list/books"} {
+		status, body := get(target)
+		assert.Equal(t, http.StatusServiceUnavailable, status, target)
+		assert.JSONEq(t, `{"message":"the request was canceled"}`, string(body), target)
+	}
+	status, _ := get("/healthz")
+	assert.Equal(t, http.StatusOK, status)
+}
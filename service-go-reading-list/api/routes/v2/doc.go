@@ -0,0 +1,17 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package v2 registers the version 2 routes of the reading list API, which
+// return the books with all their details and page the book lists.
+//
+//	@title									Choreo Reading List
+//	@version								2.0
+//	@description							This is a sample service that manages a list of reading items.
+//	@host									localhost:8080
+//	@BasePath								/api/v2/reading-list
+//
+//	@securityDefinitions.oauth2.implicit	default
+//	@authorizationUrl						https://test.com
+//	@scope.read:books						Grants read access
+//	@scope.write:books						Grants write access
+package v2
@@ -0,0 +1,73 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v2
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/controllers"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+// ListDuplicates
+//
+//	@ID				listDuplicates
+//	@Summary		List the books that look like duplicates
+//	@Description	Groups the books that have the same ISBN, or the same author and nearly the same title. The books of a group are ordered by when they were added.
+//	@Tags			books
+//	@Produce		json
+//	@Security		default[read:books]
+//	@Router			/books/duplicates [get]
+//	@Success		200	{array}	models.DuplicateCluster	"successful operation"
+func ListDuplicates(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	clusters, err := bookController.FindDuplicateClusters(ctx)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(clusters)
+}
+
+// MergeBook
+//
+//	@ID				mergeBook
+//	@Summary		Merge a book into another
+//	@Description	Folds the source book into the book, keeping the reading history of both, and moves the source book to the trash.
+//	@Tags			books
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path	string				true	"Book ID"
+//	@Param			request	body	models.MergeRequest	true	"Book to merge"
+//	@Security		default[write:books]
+//	@Router			/books/{id}/merge [post]
+//	@Success		200	{object}	models.Book			"successful operation"
+//	@Failure		400	{object}	utils.ErrorResponse	"invalid merge request"
+//	@Failure		404	{object}	utils.ErrorResponse	"book not found"
+func MergeBook(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	request := models.MergeRequest{}
+	if err := c.BodyParser(&request); err != nil {
+		return makeHttpBadRequestError(err)
+	}
+	book, err := bookController.MergeBooks(ctx, c.Params("id"), request.SourceId)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(book)
+}
+
+// sendDuplicateConflict answers with the candidates of a duplicate book error.
+func sendDuplicateConflict(c *fiber.Ctx, err error) error {
+	var duplicateErr *controllers.DuplicateBookError
+	if !errors.As(err, &duplicateErr) {
+		return err
+	}
+	return c.Status(fiber.StatusConflict).JSON(models.DuplicateConflict{
+		Message:    duplicateErr.Error(),
+		Candidates: duplicateErr.CandidateIds(),
+	})
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v2
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/api/middleware"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/controllers"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+var (
+	bookController           *controllers.BookController
+	recommendationController *controllers.RecommendationController
+)
+
+// Controllers are the controllers shared with the version 1 routes.
+type Controllers struct {
+	Books           *controllers.BookController
+	Recommendations *controllers.RecommendationController
+}
+
+// RegisterRoutes registers the version 2 routes, which share the controllers
+// and the response cache with the version 1 routes.
+func RegisterRoutes(router fiber.Router, ctrls Controllers, responseCache *middleware.ResponseCache) {
+	bookController = ctrls.Books
+	recommendationController = ctrls.Recommendations
+	idempotent := middleware.NewIdempotency(config.GetConfig().IdempotencyTTL)
+	r := router.Group("/reading-list/books")
+	r.Post("/", idempotent, AddBook)
+	r.Get("/recommendations", GetRecommendations)
+	r.Get("/duplicates", ListDuplicates)
+	r.Post("/:id/merge", MergeBook)
+	r.Get("/:id", responseCache.Handle, GetBook)
+	r.Put("/:id", UpdateBook)
+	r.Delete("/:id", DeleteBook)
+	r.Get("/", responseCache.Handle, ListBooks)
+}
+
+// AddBook
+//
+//	@ID			addBook
+//	@Summary	Add a new book to the reading list
+//	@Tags		books
+//	@Accept		json
+//	@Produce	json
+//	@Param		request			body	models.Book	true	"New book details"
+//	@Param		Idempotency-Key	header	string		false	"Replays the first response for retries with the same key"
+//	@Param		allowDuplicate	query	bool		false	"Add the book even when it looks like a book on the reading list"
+//	@Security	default[write:books]
+//	@Router		/books [post]
+//	@Success	201	{object}	models.Book					"successful operation"
+//	@Failure	400	{object}	utils.ErrorResponse			"invalid book details"
+//	@Failure	409	{object}	models.DuplicateConflict	"book already exists or looks like a duplicate"
+//	@Failure	422	{object}	utils.ErrorResponse			"idempotency key reused with a different request"
+func AddBook(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	newBook := models.Book{}
+	if err := c.BodyParser(&newBook); err != nil {
+		return makeHttpBadRequestError(err)
+	}
+	res, err := bookController.AddBook(ctx, newBook, c.QueryBool("allowDuplicate"))
+	if err != nil {
+		return sendDuplicateConflict(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(res)
+}
+
+// UpdateBook
+//
+//	@ID			updateBook
+//	@Summary	Update a reading list book by id
+//	@Tags		books
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path	string		true	"Book ID"
+//	@Param		request	body	models.Book	true	"Updated book details"
+//	@Security	default[write:books]
+//	@Router		/books/{id} [put]
+//	@Success	200	{object}	models.Book			"successful operation"
+//	@Failure	400	{object}	utils.ErrorResponse	"invalid book details"
+//	@Failure	404	{object}	utils.ErrorResponse	"book not found"
+func UpdateBook(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	updatedBook := models.Book{}
+	if err := c.BodyParser(&updatedBook); err != nil {
+		return makeHttpBadRequestError(err)
+	}
+	// The id is stored with the book, so it must not point into the request
+	// buffer that Fiber reuses for the next request.
+	updatedBook.Id = strings.Clone(c.Params("id"))
+	book, err := bookController.UpdateBook(ctx, updatedBook)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(book)
+}
+
+// DeleteBook
+//
+//	@ID				deleteBook
+//	@Summary		Delete a reading list book by id
+//	@Description	Moves the book to the trash unless hard is set, in which case the book is removed permanently.
+//	@Tags			books
+//	@Produce		json
+//	@Param			id		path	string	true	"Book ID"
+//	@Param			hard	query	bool	false	"Permanently remove the book instead of moving it to the trash"
+//	@Security		default[write:books]
+//	@Router			/books/{id} [delete]
+//	@Success		200	{object}	models.Book			"successful operation"
+//	@Failure		404	{object}	utils.ErrorResponse	"book not found"
+func DeleteBook(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	id := c.Params("id")
+	var book models.Book
+	var err error
+	if c.QueryBool("hard") {
+		book, err = bookController.PurgeBook(ctx, id)
+	} else {
+		book, err = bookController.DeleteBook(ctx, id)
+	}
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(book)
+}
+
+// GetBook
+//
+//	@ID			getBook
+//	@Summary	Get reading list book by id
+//	@Tags		books
+//	@Produce	json
+//	@Param		id					path	string	true	"Book ID"
+//	@Param		If-Modified-Since	header	string	false	"Answers with 304 if the books have not changed since then"
+//	@Security	default[read:books]
+//	@Router		/books/{id} [get]
+//	@Success	200	{object}	models.Book		"successful operation"
+//	@Header		200	{string}	Last-Modified	"When the books were last changed"
+//	@Success	304	"not modified"
+//	@Failure	404	{object}	utils.ErrorResponse	"book not found"
+func GetBook(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	book, err := bookController.GetBook(ctx, c.Params("id"))
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(book)
+}
+
+// ListBooks
+//
+//	@ID				listBooks
+//	@Summary		List a page of the reading list books
+//	@Description	Returns the books in the order they were added. The next page starts at nextOffset, which is omitted on the last page.
+//	@Tags			books
+//	@Produce		json
+//	@Param			offset				query	int		false	"Number of books to skip"			minimum(0)	default(0)
+//	@Param			limit				query	int		false	"Maximum number of books to return"	minimum(1)	maximum(100)	default(20)
+//	@Param			status				query	string	false	"Only the books with the status"	Enums(to_read, reading, read)
+//	@Param			author				query	string	false	"Only the books of the author, regardless of case"
+//	@Param			If-Modified-Since	header	string	false	"Answers with 304 if the books have not changed since then"
+//	@Security		default[read:books]
+//	@Router			/books [get]
+//	@Success		200	{object}	models.BookPage	"successful operation"
+//	@Header			200	{string}	Last-Modified	"When the books were last changed"
+//	@Success		304	"not modified"
+//	@Failure		400	{object}	utils.ErrorResponse	"invalid offset, limit or status"
+func ListBooks(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	page, err := bookController.ListBooksPage(ctx, bookFilter(c), c.QueryInt("offset", 0), c.QueryInt("limit", controllers.DefaultPageLimit))
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(page)
+}
+
+// bookFilter returns the filter of the books in the query.
+func bookFilter(c *fiber.Ctx) models.BookFilter {
+	return models.BookFilter{Status: models.ReadStatus(c.Query("status")), Author: c.Query("author")}
+}
+
+func makeHttpBadRequestError(err error) *fiber.Error {
+	return fiber.NewError(http.StatusBadRequest, fmt.Sprintf("failed to parse the payload: %s", err.Error()))
+}
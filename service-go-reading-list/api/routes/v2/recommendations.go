@@ -0,0 +1,32 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v2
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/controllers"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+// GetRecommendations
+//
+//	@ID				getRecommendations
+//	@Summary		Recommend the books to read next
+//	@Description	Ranks the books to read and the books of the catalogue by their author, their tags and their similarity to the read books, favouring the highly rated ones. Each recommendation explains its score.
+//	@Tags			books
+//	@Produce		json
+//	@Param			limit	query	int	false	"Maximum number of recommendations"	minimum(1)	maximum(50)	default(10)
+//	@Security		default[read:books]
+//	@Router			/books/recommendations [get]
+//	@Success		200	{array}		models.Recommendation	"successful operation"
+//	@Failure		400	{object}	utils.ErrorResponse		"invalid limit"
+func GetRecommendations(c *fiber.Ctx) error {
+	ctx := utils.GetRequestContext(c)
+	recommendations, err := recommendationController.Recommend(ctx, c.QueryInt("limit", controllers.DefaultRecommendationLimit))
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(recommendations)
+}
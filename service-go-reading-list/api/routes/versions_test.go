@@ -0,0 +1,79 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package routes
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/api/middleware"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+func TestAPIVersions(t *testing.T) {
+	t.Setenv(config.V1DeprecatedAt, "2025-01-01T00:00:00Z")
+	t.Setenv(config.V1SunsetAt, "2026-01-01T00:00:00Z")
+	_, err := config.LoadConfig()
+	require.NoError(t, err)
+
+	app := fiber.New(fiber.Config{ErrorHandler: utils.FiberErrorHandler})
+	Initialize(app)
+	defer Shutdown()
+
+	send := func(method, target, body string) (*http.Response, map[string]interface{}) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		contents, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		var book map[string]interface{}
+		require.NoError(t, json.Unmarshal(contents, &book), string(contents))
+		return resp, book
+	}
+
+	resp, _ := send(http.MethodPost, "/api/v2/reading-list/books", `{"id":"ivanhoe","title":"Ivanhoe","isbn":"9780140436587","tags":["historical"],"rating":4}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(middleware.DeprecationHeaderName))
+
+	t.Run("V1KeepsItsShape", func(t *testing.T) {
+		resp, book := send(http.MethodGet, "/api/v1/reading-list/books/ivanhoe", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "Ivanhoe", book["title"])
+		assert.NotContains(t, book, "isbn")
+		assert.NotContains(t, book, "tags")
+		assert.NotContains(t, book, "rating")
+	})
+
+	t.Run("V1UpdateKeepsV2Fields", func(t *testing.T) {
+		resp, _ := send(http.MethodPut, "/api/v1/reading-list/books/ivanhoe", `{"title":"Ivanhoe","status":"reading"}`)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp, book := send(http.MethodGet, "/api/v2/reading-list/books/ivanhoe", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "reading", book["status"])
+		assert.Equal(t, "9780140436587", book["isbn"])
+		assert.Equal(t, []interface{}{"historical"}, book["tags"])
+		assert.Equal(t, float64(4), book["rating"])
+	})
+
+	t.Run("V1Deprecation", func(t *testing.T) {
+		resp, _ := send(http.MethodGet, "/api/v1/reading-list/books/missing", "")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, "@1735689600", resp.Header.Get(middleware.DeprecationHeaderName))
+		assert.Equal(t, "Thu, 01 Jan 2026 00:00:00 GMT", resp.Header.Get(middleware.SunsetHeaderName))
+		assert.Equal(t, `</api/v2/reading-list>; rel="successor-version"`, resp.Header.Get(fiber.HeaderLink))
+	})
+}
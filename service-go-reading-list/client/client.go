@@ -0,0 +1,393 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package client is a typed client of the version 1 of the reading list API.
+// The types and the operations of client_gen.go are generated from
+// docs/openapi.yaml, so regenerate them after the definition changes with:
+//
+//	go generate ./client
+//
+// The requests carry the correlation id of their context in the
+// x-correlation-id header. They are retried with backoff when the server is
+// unavailable or limits the requests, as long as sending them again is safe.
+package client
+
+//go:generate go run ./internal/gen/cmd -spec ../docs/openapi.yaml -o client_gen.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+// CorrelationIdHeader is the header of the correlation id, which the service
+// records in its logs and audit entries.
+const CorrelationIdHeader = "x-correlation-id"
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// maxErrorBodySize bounds the body of an error response kept in Error.
+const maxErrorBodySize = 1 << 20
+
+// ErrNotModified is returned by the operations that take If-Modified-Since
+// when the server answers with 304.
+var ErrNotModified = errors.New("the resource is not modified")
+
+// Error is an error status of the API.
+type Error struct {
+	StatusCode int
+	// Message is the message of the body, which is empty when the body is
+	// not JSON.
+	Message string
+	// CorrelationId is the correlation id the request was sent with.
+	CorrelationId string
+	Body          []byte
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("the API answered with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("the API answered with status %d: %s", e.StatusCode, e.Message)
+}
+
+// DecodeBody decodes the JSON body of the error into v, for the statuses
+// whose body is documented to be something else than an ErrorResponse.
+func (e *Error) DecodeBody(v interface{}) error {
+	return json.Unmarshal(e.Body, v)
+}
+
+// RetryPolicy is how the requests are retried when the server answers with
+// 429 or a 5xx status, or cannot be reached. Only the requests that are safe
+// to send again are retried on the 5xx statuses and the network errors: the
+// ones with a GET, PUT or DELETE method or an Idempotency-Key.
+type RetryPolicy struct {
+	// MaxAttempts is how many times a request is sent at most. Zero or one
+	// disables the retries.
+	MaxAttempts int
+	// MinBackoff is the wait before the first retry, which doubles for every
+	// retry up to MaxBackoff. A Retry-After header of the response sets a
+	// longer wait. The waits are randomized by up to a half.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is the retry policy of the clients without
+// WithRetryPolicy.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 4, MinBackoff: 100 * time.Millisecond, MaxBackoff: 5 * time.Second}
+
+// backoff returns the wait before the retry that follows the attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	wait := p.MaxBackoff
+	if attempt < 32 && p.MinBackoff<<(attempt-1) < p.MaxBackoff {
+		wait = p.MinBackoff << (attempt - 1)
+	}
+	if wait <= 0 {
+		return 0
+	}
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// Client calls the operations of the API of a server.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	header     http.Header
+	retry      RetryPolicy
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sends the requests with the HTTP client, instead of one
+// that gives up on an attempt after 30 seconds.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithToken sends the bearer token with every request.
+func WithToken(token string) Option {
+	return WithHeader("Authorization", "Bearer "+token)
+}
+
+// WithTenant sends the requests for the tenant, in the default TENANT_HEADER
+// header. Use WithHeader when the service reads the tenant of another header.
+func WithTenant(tenant string) Option {
+	return WithHeader(config.DefaultTenantHeader, tenant)
+}
+
+// WithHeader sends the header with every request.
+func WithHeader(name, value string) Option {
+	return func(c *Client) {
+		c.header.Set(name, value)
+	}
+}
+
+// WithRetryPolicy retries the requests with the policy, instead of
+// DefaultRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// New returns a client of the API of the server at serverURL, e.g.
+// http://localhost:8080, which the operations are sent to under BasePath.
+func New(serverURL string, opts ...Option) (*Client, error) {
+	parsed, err := url.Parse(serverURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid server URL [%s]", serverURL)
+	}
+	c := &Client{
+		baseURL:    strings.TrimSuffix(serverURL, "/") + BasePath,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		header:     http.Header{},
+		retry:      DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+type correlationIdKey struct{}
+
+// WithCorrelationId returns a context whose requests are sent with the
+// correlation id. Without it, the requests carry the correlation id of the
+// request being served by the service, if any, or a random one.
+func WithCorrelationId(ctx context.Context, correlationId string) context.Context {
+	return context.WithValue(ctx, correlationIdKey{}, correlationId)
+}
+
+func correlationIdOf(ctx context.Context) string {
+	if correlationId, _ := ctx.Value(correlationIdKey{}).(string); correlationId != "" {
+		return correlationId
+	}
+	if correlationId := utils.GetCorrelationId(ctx); correlationId != "" {
+		return correlationId
+	}
+	return uuid.NewString()
+}
+
+// request is an operation to send, which the generated methods fill in.
+type request struct {
+	method string
+	// path is relative to BasePath, with the path parameters escaped.
+	path   string
+	query  url.Values
+	header http.Header
+	// body is sent as JSON when it is not nil.
+	body interface{}
+	// binaryBody is sent as is with contentType when body is nil.
+	binaryBody  []byte
+	contentType string
+}
+
+func newRequest(method, path string) *request {
+	return &request{method: method, path: path, query: url.Values{}, header: http.Header{}}
+}
+
+// setBinaryBody sets the body to send as is, whose content type is detected
+// when it is empty.
+func (r *request) setBinaryBody(body []byte, contentType string) {
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	r.binaryBody, r.contentType = body, contentType
+}
+
+// setQuery sets the query parameter, unless the value is the zero value.
+func (r *request) setQuery(name string, value interface{}) {
+	if !isZero(value) {
+		r.query.Set(name, formatParam(value))
+	}
+}
+
+// setHeader sets the header, unless the value is the zero value.
+func (r *request) setHeader(name string, value interface{}) {
+	if !isZero(value) {
+		r.header.Set(name, formatParam(value))
+	}
+}
+
+// ensureIdempotencyKey sets a random Idempotency-Key, unless the caller set
+// one, so that the request can be retried without being applied twice.
+func (r *request) ensureIdempotencyKey() {
+	if r.header.Get(idempotencyKeyHeader) == "" {
+		r.header.Set(idempotencyKeyHeader, uuid.NewString())
+	}
+}
+
+// idempotent reports whether the request can be sent again when its
+// response is lost or is a server error.
+func (r *request) idempotent() bool {
+	switch r.method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return r.header.Get(idempotencyKeyHeader) != ""
+}
+
+func pathParam(value interface{}) string {
+	return url.PathEscape(formatParam(value))
+}
+
+func formatParam(value interface{}) string {
+	if t, ok := value.(time.Time); ok {
+		return t.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(value)
+}
+
+func isZero(value interface{}) bool {
+	v := reflect.ValueOf(value)
+	return !v.IsValid() || v.IsZero()
+}
+
+// do sends the request, retrying it as allowed by the retry policy, and
+// decodes the successful response into result, which is nil when the
+// response has no body. A binary response is handed to a *io.ReadCloser
+// result, which the caller must close.
+func (c *Client) do(ctx context.Context, req *request, result interface{}) error {
+	body, contentType := req.binaryBody, req.contentType
+	if req.body != nil {
+		var err error
+		if body, err = json.Marshal(req.body); err != nil {
+			return fmt.Errorf("failed to marshal the request body: %w", err)
+		}
+		contentType = "application/json"
+	}
+	target := c.baseURL + req.path
+	if len(req.query) > 0 {
+		target += "?" + req.query.Encode()
+	}
+	// The retries carry the correlation id of the first attempt.
+	correlationId := correlationIdOf(ctx)
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, req, target, body, contentType, correlationId)
+		var retryAfter time.Duration
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return err
+		case err != nil:
+			if !req.idempotent() {
+				return err
+			}
+		case resp.StatusCode == http.StatusTooManyRequests,
+			resp.StatusCode >= http.StatusInternalServerError && req.idempotent():
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		default:
+			return c.handleResponse(resp, correlationId, result)
+		}
+		if attempt >= c.retry.MaxAttempts {
+			if err != nil {
+				return err
+			}
+			return c.handleResponse(resp, correlationId, result)
+		}
+		if resp != nil {
+			// The connection is reused once the body is read.
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodySize))
+			_ = resp.Body.Close()
+		}
+		wait := c.retry.backoff(attempt)
+		if retryAfter > wait {
+			wait = retryAfter
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, req *request, target string, body []byte, contentType, correlationId string) (*http.Response, error) {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, target, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the request: %w", err)
+	}
+	for name, values := range c.header {
+		httpReq.Header[name] = values
+	}
+	for name, values := range req.header {
+		httpReq.Header[name] = values
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if body != nil {
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	httpReq.Header.Set(CorrelationIdHeader, correlationId)
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send %s %s: %w", req.method, req.path, err)
+	}
+	return resp, nil
+}
+
+func (c *Client) handleResponse(resp *http.Response, correlationId string, result interface{}) error {
+	if readCloser, ok := result.(*io.ReadCloser); ok && resp.StatusCode < http.StatusMultipleChoices {
+		*readCloser = resp.Body
+		return nil
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		return ErrNotModified
+	case resp.StatusCode >= http.StatusBadRequest:
+		apiErr := &Error{StatusCode: resp.StatusCode, CorrelationId: correlationId}
+		apiErr.Body, _ = io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		var errorResponse ErrorResponse
+		if json.Unmarshal(apiErr.Body, &errorResponse) == nil {
+			apiErr.Message = errorResponse.Message
+		}
+		return apiErr
+	case resp.StatusCode >= http.StatusMultipleChoices:
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode the response: %w", err)
+	}
+	return nil
+}
+
+// parseRetryAfter returns the wait of a Retry-After header, in seconds or
+// as an HTTP date, which is zero when it is missing or invalid.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
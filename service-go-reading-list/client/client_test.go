@@ -0,0 +1,264 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/api/routes"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/client/internal/gen"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/docs"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+// testRetryPolicy retries without making the tests wait.
+var testRetryPolicy = RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+func TestGeneratedCodeIsUpToDate(t *testing.T) {
+	generated, err := gen.Generate(docs.OpenAPISpec, "client")
+	require.NoError(t, err)
+	existing, err := os.ReadFile("client_gen.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(generated), string(existing), "client_gen.go is out of date with docs/openapi.yaml, run go generate ./client")
+}
+
+func TestClient(t *testing.T) {
+	t.Setenv("INIT_DATA_PATH", "")
+	_, err := config.LoadConfig()
+	require.NoError(t, err)
+	app := fiber.New(fiber.Config{ErrorHandler: utils.FiberErrorHandler, DisableStartupMessage: true})
+	routes.Initialize(app)
+	defer routes.Shutdown()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = app.Listener(listener) }()
+	defer func() { _ = app.Shutdown() }()
+	c, err := New("http://"+listener.Addr().String()+"/", WithRetryPolicy(testRetryPolicy))
+	require.NoError(t, err)
+	ctx := WithCorrelationId(context.Background(), "correlation-1")
+
+	book, err := c.AddBook(ctx, Book{Id: "dune", Title: "Dune", Author: "Frank Herbert"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, ReadStatusToRead, book.Status)
+	book.Status = ReadStatusReading
+	book, err = c.UpdateBook(ctx, "dune", *book)
+	require.NoError(t, err)
+	assert.NotEmpty(t, book.StartedAt)
+	books, err := c.ListBooks(ctx, &ListBooksParams{Status: ReadStatusReading})
+	require.NoError(t, err)
+	require.Len(t, books, 1)
+	assert.Equal(t, "Dune", books[0].Title)
+
+	// The covers are sent and returned as is.
+	var cover bytes.Buffer
+	require.NoError(t, png.Encode(&cover, image.NewGray(image.Rect(0, 0, 4, 6))))
+	uploaded, err := c.PutCover(ctx, "dune", cover.Bytes(), "")
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", uploaded.ContentType)
+	assert.Equal(t, 6, uploaded.Height)
+	body, err := c.GetCover(ctx, "dune", &GetCoverParams{Size: "small"})
+	require.NoError(t, err)
+	thumb, err := io.ReadAll(body)
+	_ = body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, cover.Bytes(), thumb)
+
+	// The highlights are exported as Markdown.
+	highlight, err := c.AddHighlight(ctx, "dune", Highlight{Text: "I must not fear.", Page: 12, Tags: []string{"fear"}})
+	require.NoError(t, err)
+	assert.Equal(t, "dune", highlight.BookId)
+	found, err := c.SearchHighlights(ctx, &SearchHighlightsParams{Tag: "fear"})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, highlight.Id, found[0].Id)
+	body, err = c.ExportHighlights(ctx, "dune")
+	require.NoError(t, err)
+	export, err := io.ReadAll(body)
+	_ = body.Close()
+	require.NoError(t, err)
+	assert.Contains(t, string(export), "> I must not fear.\n\n— page 12\n")
+
+	// The error statuses are decoded with their documented bodies.
+	_, err = c.GetBook(ctx, "missing", nil)
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "the book id [missing] is not found", apiErr.Message)
+	assert.Equal(t, "correlation-1", apiErr.CorrelationId)
+	_, err = c.AddBook(ctx, Book{Title: "Dune", Author: "frank herbert"}, nil)
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+	var conflict DuplicateConflict
+	require.NoError(t, apiErr.DecodeBody(&conflict))
+	assert.Equal(t, []string{"dune"}, conflict.Candidates)
+	// The admin routes are only served with AUTH_MODE.
+	_, err = c.Backup(ctx)
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+
+	_, err = c.DeleteBook(ctx, "dune", nil)
+	require.NoError(t, err)
+	trash, err := c.ListTrash(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	_, err = c.RestoreBook(ctx, "dune")
+	require.NoError(t, err)
+	_, err = c.DeleteBook(ctx, "dune", &DeleteBookParams{Hard: true})
+	require.NoError(t, err)
+
+	progress, err := c.SetGoal(ctx, 2024, Goal{Target: 12})
+	require.NoError(t, err)
+	assert.Equal(t, 12, progress.Target)
+	_, err = c.GetGoal(ctx, 2024)
+	require.NoError(t, err)
+
+	// The changes are audited with the correlation id of the context.
+	entries, err := c.ListAuditEntries(ctx, &ListAuditEntriesParams{BookId: "dune", Since: time.Now().Add(-time.Hour)})
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	for _, entry := range entries {
+		assert.Equal(t, "correlation-1", entry.CorrelationId)
+	}
+}
+
+// recordingServer answers the requests with the statuses in turn, and
+// records the headers of the requests.
+type recordingServer struct {
+	mu       sync.Mutex
+	statuses []int
+	headers  []http.Header
+}
+
+func (s *recordingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.headers = append(s.headers, r.Header.Clone())
+	status := http.StatusOK
+	if len(s.statuses) > 0 {
+		status, s.statuses = s.statuses[0], s.statuses[1:]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	switch {
+	case status == http.StatusOK:
+		_, _ = w.Write([]byte(`{"id": "dune", "title": "Dune"}`))
+	case status >= http.StatusBadRequest:
+		_, _ = w.Write([]byte(`{"message": "try again"}`))
+	}
+}
+
+func newRecordingClient(t *testing.T, statuses ...int) (*Client, *recordingServer) {
+	recorder := &recordingServer{statuses: statuses}
+	server := httptest.NewServer(recorder)
+	t.Cleanup(server.Close)
+	c, err := New(server.URL, WithRetryPolicy(testRetryPolicy), WithToken("secret"), WithTenant("acme"))
+	require.NoError(t, err)
+	return c, recorder
+}
+
+func TestNotModified(t *testing.T) {
+	c, recorder := newRecordingClient(t, http.StatusNotModified)
+	since := time.Now().UTC().Format(http.TimeFormat)
+	_, err := c.GetBook(context.Background(), "dune", &GetBookParams{IfModifiedSince: since})
+	assert.ErrorIs(t, err, ErrNotModified)
+	require.Len(t, recorder.headers, 1)
+	assert.Equal(t, since, recorder.headers[0].Get("If-Modified-Since"))
+}
+
+func TestRetry(t *testing.T) {
+	t.Run("Idempotent", func(t *testing.T) {
+		c, recorder := newRecordingClient(t, http.StatusServiceUnavailable, http.StatusBadGateway)
+		book, err := c.GetBook(context.Background(), "dune", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "Dune", book.Title)
+		require.Len(t, recorder.headers, 3)
+		// The attempts are the same request.
+		correlationId := recorder.headers[0].Get(CorrelationIdHeader)
+		assert.NotEmpty(t, correlationId)
+		for _, header := range recorder.headers {
+			assert.Equal(t, correlationId, header.Get(CorrelationIdHeader))
+			assert.Equal(t, "Bearer secret", header.Get("Authorization"))
+			assert.Equal(t, "acme", header.Get(config.DefaultTenantHeader))
+		}
+	})
+
+	t.Run("IdempotencyKey", func(t *testing.T) {
+		c, recorder := newRecordingClient(t, http.StatusInternalServerError)
+		_, err := c.AddBook(context.Background(), Book{Title: "Dune"}, nil)
+		require.NoError(t, err)
+		require.Len(t, recorder.headers, 2)
+		key := recorder.headers[0].Get(idempotencyKeyHeader)
+		assert.NotEmpty(t, key)
+		assert.Equal(t, key, recorder.headers[1].Get(idempotencyKeyHeader))
+	})
+
+	t.Run("NotIdempotent", func(t *testing.T) {
+		c, recorder := newRecordingClient(t, http.StatusInternalServerError)
+		_, err := c.MergeBook(context.Background(), "dune", MergeRequest{SourceId: "dune-2"})
+		var apiErr *Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+		assert.Equal(t, "try again", apiErr.Message)
+		assert.Len(t, recorder.headers, 1)
+	})
+
+	t.Run("TooManyRequests", func(t *testing.T) {
+		// The requests that are limited are not applied, so they are retried
+		// whatever their method.
+		c, recorder := newRecordingClient(t, http.StatusTooManyRequests)
+		_, err := c.RestoreBook(context.Background(), "dune")
+		require.NoError(t, err)
+		assert.Len(t, recorder.headers, 2)
+	})
+
+	t.Run("MaxAttempts", func(t *testing.T) {
+		c, recorder := newRecordingClient(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+		_, err := c.GetBook(context.Background(), "dune", nil)
+		var apiErr *Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+		assert.Len(t, recorder.headers, 3)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		c, _ := newRecordingClient(t, http.StatusServiceUnavailable)
+		c.retry = RetryPolicy{MaxAttempts: 2, MinBackoff: time.Hour, MaxBackoff: time.Hour}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := c.GetBook(ctx, "dune", nil)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+	})
+
+	t.Run("RetryAfter", func(t *testing.T) {
+		assert.Equal(t, 2*time.Second, parseRetryAfter("2"))
+		assert.InDelta(t, float64(time.Minute), float64(parseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))), float64(2*time.Second))
+		assert.Zero(t, parseRetryAfter("soon"))
+	})
+}
+
+func TestCorrelationId(t *testing.T) {
+	// The correlation id of the request served by the service is passed on.
+	ctx := context.WithValue(context.Background(), "correlation-id", "served-1")
+	require.Equal(t, "served-1", utils.GetCorrelationId(ctx))
+	assert.Equal(t, "served-1", correlationIdOf(ctx))
+	assert.Equal(t, "explicit-1", correlationIdOf(WithCorrelationId(ctx, "explicit-1")))
+	assert.NotEqual(t, correlationIdOf(context.Background()), correlationIdOf(context.Background()))
+}
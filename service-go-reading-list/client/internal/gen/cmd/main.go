@@ -0,0 +1,36 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Command cmd writes the code generated by gen for an OpenAPI definition,
+// see the go:generate directive of the client package.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/client/internal/gen"
+)
+
+func main() {
+	specPath := flag.String("spec", "", "path of the OpenAPI definition")
+	outputPath := flag.String("o", "", "path of the generated file")
+	packageName := flag.String("package", "client", "package of the generated file")
+	flag.Parse()
+	if *specPath == "" || *outputPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	spec, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := gen.Generate(spec, *packageName)
+	if err != nil {
+		log.Fatalf("failed to generate the client of [%s]: %v", *specPath, err)
+	}
+	if err := os.WriteFile(*outputPath, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
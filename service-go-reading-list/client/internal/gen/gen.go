@@ -0,0 +1,622 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package gen generates the typed operations and schemas of the client
+// package from the OpenAPI definition of the service.
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/openapi"
+)
+
+const (
+	schemaRefPrefix      = "#/components/schemas/"
+	idempotencyKeyHeader = "Idempotency-Key"
+)
+
+// methodOrder orders the operations of a path.
+var methodOrder = map[string]int{
+	http.MethodGet:    0,
+	http.MethodPost:   1,
+	http.MethodPut:    2,
+	http.MethodPatch:  3,
+	http.MethodDelete: 4,
+}
+
+// Generate returns the formatted Go source of the package that declares a
+// type for every schema of the definition and a method of Client for every
+// operation, named after its operationId.
+func Generate(spec []byte, packageName string) ([]byte, error) {
+	loaded, err := openapi.Load(spec)
+	if err != nil {
+		return nil, err
+	}
+	g := &generator{spec: loaded, types: map[string]string{}, imports: map[string]bool{"context": true, "net/http": true}}
+	if err := g.collectTypes(); err != nil {
+		return nil, err
+	}
+	var body bytes.Buffer
+	g.out = &body
+	if err := g.writeTypes(); err != nil {
+		return nil, err
+	}
+	if err := g.writeOperations(); err != nil {
+		return nil, err
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Copyright 2025 The OpenChoreo Authors\n// SPDX-License-Identifier: Apache-2.0\n\n")
+	fmt.Fprintf(&src, "// Code generated by client/internal/gen from the OpenAPI definition of %s %s. DO NOT EDIT.\n\n", loaded.Doc.Info.Title, loaded.Doc.Info.Version)
+	fmt.Fprintf(&src, "package %s\n\n", packageName)
+	imports := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	src.WriteString("import (\n")
+	for _, path := range imports {
+		fmt.Fprintf(&src, "%q\n", path)
+	}
+	src.WriteString(")\n\n")
+	src.WriteString("// BasePath is the path of the API on the server, which prefixes the paths of\n// the operations.\n")
+	fmt.Fprintf(&src, "const BasePath = %q\n\n", loaded.BasePath)
+	src.Write(body.Bytes())
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format the generated code: %w", err)
+	}
+	return formatted, nil
+}
+
+type generator struct {
+	spec *openapi.Spec
+	// types maps the names of the schemas to the names of their types.
+	types   map[string]string
+	imports map[string]bool
+	out     *bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(g.out, format, args...)
+}
+
+// collectTypes names the types of the schemas after the schemas without the
+// Go package they were generated from, e.g. Book for v1.Book.
+func (g *generator) collectTypes() error {
+	schemas := map[string]string{}
+	for name := range g.spec.Doc.Components.Schemas {
+		typeName := exportName(name[strings.LastIndex(name, ".")+1:])
+		if other, ok := schemas[typeName]; ok {
+			return fmt.Errorf("the schemas [%s] and [%s] are both named %s", other, name, typeName)
+		}
+		schemas[typeName] = name
+		g.types[name] = typeName
+	}
+	return nil
+}
+
+func (g *generator) writeTypes() error {
+	names := make([]string, 0, len(g.types))
+	for name := range g.types {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return g.types[names[i]] < g.types[names[j]] })
+	for _, name := range names {
+		schema := g.spec.Doc.Components.Schemas[name].Value
+		typeName := g.types[name]
+		g.printf("// %s is the %s schema of the API.\n", typeName, name)
+		if len(schema.Enum) > 0 {
+			if err := g.writeEnum(typeName, schema); err != nil {
+				return fmt.Errorf("schema [%s]: %w", name, err)
+			}
+			continue
+		}
+		if !schema.Type.Is(openapi3.TypeObject) || schema.AdditionalProperties.Schema != nil {
+			goType, err := g.goType(g.spec.Doc.Components.Schemas[name].Value, false)
+			if err != nil {
+				return fmt.Errorf("schema [%s]: %w", name, err)
+			}
+			g.printf("type %s %s\n\n", typeName, goType)
+			continue
+		}
+		g.printf("type %s struct {\n", typeName)
+		properties := make([]string, 0, len(schema.Properties))
+		for property := range schema.Properties {
+			properties = append(properties, property)
+		}
+		sort.Strings(properties)
+		for _, property := range properties {
+			propertySchema := schema.Properties[property]
+			goType, err := g.goTypeOf(propertySchema, true)
+			if err != nil {
+				return fmt.Errorf("schema [%s] property [%s]: %w", name, property, err)
+			}
+			if propertySchema.Value.Description != "" {
+				g.writeComment(propertySchema.Value.Description)
+			}
+			tag := property
+			if !contains(schema.Required, property) {
+				tag += ",omitempty"
+			}
+			g.printf("%s %s `json:%q`\n", exportName(property), goType, tag)
+		}
+		g.printf("}\n\n")
+	}
+	return nil
+}
+
+func (g *generator) writeEnum(typeName string, schema *openapi3.Schema) error {
+	if !schema.Type.Is(openapi3.TypeString) {
+		return fmt.Errorf("only the enums of strings are supported")
+	}
+	varNames, _ := schema.Extensions["x-enum-varnames"].([]interface{})
+	g.printf("type %s string\n\n", typeName)
+	g.printf("// The values of %s.\nconst (\n", typeName)
+	for i, value := range schema.Enum {
+		name := typeName + exportName(fmt.Sprint(value))
+		if i < len(varNames) {
+			name = fmt.Sprint(varNames[i])
+		}
+		g.printf("%s %s = %q\n", name, typeName, fmt.Sprint(value))
+	}
+	g.printf(")\n\n")
+	return nil
+}
+
+// goTypeOf returns the type of the schema, which is a pointer when it is an
+// optional struct.
+func (g *generator) goTypeOf(ref *openapi3.SchemaRef, optional bool) (string, error) {
+	if ref.Ref != "" {
+		typeName, ok := g.types[strings.TrimPrefix(ref.Ref, schemaRefPrefix)]
+		if !ok {
+			return "", fmt.Errorf("unknown schema [%s]", ref.Ref)
+		}
+		if optional && isStruct(ref.Value) {
+			return "*" + typeName, nil
+		}
+		return typeName, nil
+	}
+	return g.goType(ref.Value, optional)
+}
+
+func (g *generator) goType(schema *openapi3.Schema, optional bool) (string, error) {
+	switch {
+	case len(schema.AllOf) == 1 && schema.Type == nil:
+		// swag wraps the references that have a description or an example.
+		return g.goTypeOf(schema.AllOf[0], optional)
+	case len(schema.Enum) > 0:
+		// An inline enum has the type of the schema with the same values.
+		for name, typeName := range g.types {
+			if sameValues(g.spec.Doc.Components.Schemas[name].Value.Enum, schema.Enum) {
+				return typeName, nil
+			}
+		}
+	case schema.Type == nil && len(schema.Properties) == 0 && len(schema.AllOf) == 0:
+		return "interface{}", nil
+	}
+	switch {
+	case schema.Type.Is(openapi3.TypeString):
+		if schema.Format == "date-time" {
+			g.imports["time"] = true
+			if optional {
+				// The zero time is only omitted from JSON by a pointer.
+				return "*time.Time", nil
+			}
+			return "time.Time", nil
+		}
+		return "string", nil
+	case schema.Type.Is(openapi3.TypeInteger):
+		return "int", nil
+	case schema.Type.Is(openapi3.TypeNumber):
+		return "float64", nil
+	case schema.Type.Is(openapi3.TypeBoolean):
+		return "bool", nil
+	case schema.Type.Is(openapi3.TypeArray):
+		items, err := g.goTypeOf(schema.Items, false)
+		return "[]" + items, err
+	case schema.Type.Is(openapi3.TypeObject) && schema.AdditionalProperties.Schema != nil && len(schema.Properties) == 0:
+		values, err := g.goTypeOf(schema.AdditionalProperties.Schema, false)
+		return "map[string]" + values, err
+	}
+	return "", fmt.Errorf("unsupported schema of type %v, only the component schemas can be objects", schema.Type.Slice())
+}
+
+// operation is an operation of the definition with the Go types of its
+// parameters and responses.
+type operation struct {
+	route *openapi.Route
+	name  string
+	// pathArgs are the arguments of the path parameters, in the order of
+	// the path.
+	pathArgs []argument
+	// params are the query and header parameters.
+	params   []argument
+	bodyType string
+	// binaryBody is set when the body is sent as is with its content type.
+	binaryBody bool
+	// resultType is empty when the successful response has no body, and
+	// io.ReadCloser when it is binary.
+	resultType string
+	errors     []int
+	// errorTypes are the types of the error bodies that are not an
+	// ErrorResponse, by status.
+	errorTypes     map[int]string
+	notModified    bool
+	idempotencyKey bool
+}
+
+type argument struct {
+	// wireName is the name of the parameter in the request, name the name
+	// of its argument and field the name of its field in the params.
+	wireName, name, field, in, goType, description string
+}
+
+func (g *generator) writeOperations() error {
+	routes := g.spec.Routes()
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return methodOrder[routes[i].Method] < methodOrder[routes[j].Method]
+	})
+	names := map[string]string{}
+	for _, route := range routes {
+		op, err := g.newOperation(route)
+		if err != nil {
+			return fmt.Errorf("operation %s %s: %w", route.Method, route.Path, err)
+		}
+		if other, ok := names[op.name]; ok {
+			return fmt.Errorf("the operations %s and %s %s are both named %s", other, route.Method, route.Path, op.name)
+		}
+		names[op.name] = route.Method + " " + route.Path
+		g.writeOperation(op)
+	}
+	return nil
+}
+
+func (g *generator) newOperation(route *openapi.Route) (*operation, error) {
+	if route.Operation.OperationID == "" {
+		return nil, fmt.Errorf("the operation has no operationId")
+	}
+	op := &operation{route: route, name: exportName(route.Operation.OperationID), errorTypes: map[int]string{}}
+	parameters := append(openapi3.Parameters{}, route.PathItem.Parameters...)
+	parameters = append(parameters, route.Operation.Parameters...)
+	for _, parameter := range parameters {
+		p := parameter.Value
+		goType, err := g.goTypeOf(p.Schema, false)
+		if err != nil {
+			return nil, fmt.Errorf("parameter [%s]: %w", p.Name, err)
+		}
+		arg := argument{wireName: p.Name, name: unexportName(p.Name), field: exportName(p.Name), in: p.In, goType: goType, description: p.Description}
+		switch p.In {
+		case openapi3.ParameterInPath:
+			op.pathArgs = append(op.pathArgs, arg)
+		case openapi3.ParameterInQuery, openapi3.ParameterInHeader:
+			op.params = append(op.params, arg)
+			op.idempotencyKey = op.idempotencyKey || p.In == openapi3.ParameterInHeader && p.Name == idempotencyKeyHeader
+		default:
+			return nil, fmt.Errorf("parameter [%s]: unsupported location %s", p.Name, p.In)
+		}
+	}
+	// The path arguments follow the order of the path.
+	for _, arg := range op.pathArgs {
+		if !strings.Contains(route.Path, "{"+arg.wireName+"}") {
+			return nil, fmt.Errorf("path parameter [%s] is not in the path", arg.wireName)
+		}
+	}
+	sort.SliceStable(op.pathArgs, func(i, j int) bool {
+		return strings.Index(route.Path, "{"+op.pathArgs[i].wireName+"}") < strings.Index(route.Path, "{"+op.pathArgs[j].wireName+"}")
+	})
+	if body := route.Operation.RequestBody; body != nil {
+		media := body.Value.Content.Get("application/json")
+		switch {
+		case media != nil && media.Schema != nil:
+			bodyType, err := g.goTypeOf(media.Schema, false)
+			if err != nil {
+				return nil, fmt.Errorf("request body: %w", err)
+			}
+			op.bodyType = bodyType
+		case hasBinaryMedia(body.Value.Content):
+			// The binary bodies are sent as is, e.g. the images, rather than
+			// in the forms the operation may also accept.
+			op.bodyType = "[]byte"
+			op.binaryBody = true
+		default:
+			return nil, fmt.Errorf("the request body is neither JSON nor binary")
+		}
+	}
+
+	statuses := make([]int, 0, route.Operation.Responses.Len())
+	for code := range route.Operation.Responses.Map() {
+		status, err := strconv.Atoi(code)
+		if err != nil {
+			return nil, fmt.Errorf("unsupported response [%s]", code)
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	success := false
+	for _, status := range statuses {
+		response := route.Operation.Responses.Status(status).Value
+		var schema *openapi3.SchemaRef
+		if media := response.Content.Get("application/json"); media != nil {
+			schema = media.Schema
+		}
+		switch {
+		case status == http.StatusNotModified:
+			op.notModified = true
+		case status >= 200 && status < 300:
+			// The first successful response is the result.
+			if success {
+				continue
+			}
+			success = true
+			if schema == nil {
+				continue
+			}
+			if isBinary(schema) {
+				g.imports["io"] = true
+				op.resultType = "io.ReadCloser"
+				continue
+			}
+			resultType, err := g.goTypeOf(schema, false)
+			if err != nil {
+				return nil, fmt.Errorf("response [%d]: %w", status, err)
+			}
+			op.resultType = resultType
+		case status >= 400:
+			op.errors = append(op.errors, status)
+			if schema != nil && schema.Ref != schemaRefPrefix+"utils.ErrorResponse" {
+				errorType, err := g.goTypeOf(schema, false)
+				if err != nil {
+					return nil, fmt.Errorf("response [%d]: %w", status, err)
+				}
+				op.errorTypes[status] = errorType
+			}
+		}
+	}
+	return op, nil
+}
+
+// hasBinaryMedia reports whether one of the media types of the content is a
+// binary string.
+func hasBinaryMedia(content openapi3.Content) bool {
+	for _, media := range content {
+		if isBinary(media.Schema) {
+			return true
+		}
+	}
+	return false
+}
+
+func isBinary(schema *openapi3.SchemaRef) bool {
+	return schema != nil && schema.Ref == "" && schema.Value.Type.Is(openapi3.TypeString) && schema.Value.Format == "binary"
+}
+
+func (g *generator) writeOperation(op *operation) {
+	o := op.route.Operation
+	paramsType := op.name + "Params"
+	if len(op.params) > 0 {
+		g.writeComment(fmt.Sprintf("%s are the optional parameters of %s, which are not sent when they are the zero value.", paramsType, op.name))
+		g.printf("type %s struct {\n", paramsType)
+		for _, param := range op.params {
+			if param.description != "" {
+				g.writeComment(param.field + ": " + param.description + ".")
+			}
+			g.printf("%s %s\n", param.field, param.goType)
+		}
+		g.printf("}\n\n")
+	}
+
+	summary := strings.TrimSuffix(o.Summary, ".")
+	if summary == "" {
+		g.writeComment(fmt.Sprintf("%s sends %s %s.", op.name, op.route.Method, op.route.Path))
+	} else {
+		g.writeComment(fmt.Sprintf("%s sends %s %s, to %s.", op.name, op.route.Method, op.route.Path, strings.ToLower(summary[:1])+summary[1:]))
+	}
+	if o.Description != "" {
+		g.printf("//\n")
+		g.writeComment(o.Description)
+	}
+	if op.idempotencyKey {
+		g.printf("//\n// A random %s is sent when params do not set one, so that the\n// request can be retried.\n", idempotencyKeyHeader)
+	}
+	if op.binaryBody {
+		g.printf("//\n// The body is sent as is, with its content type detected when contentType\n// is empty.\n")
+	}
+	if op.notModified {
+		g.printf("//\n// It returns ErrNotModified when the server answers with 304.\n")
+	}
+	if len(op.errors) > 0 {
+		g.printf("//\n")
+		g.writeComment(errorsComment(op))
+	}
+
+	args := []string{"ctx context.Context"}
+	for _, arg := range op.pathArgs {
+		args = append(args, arg.name+" "+arg.goType)
+	}
+	if op.bodyType != "" {
+		args = append(args, "body "+op.bodyType)
+	}
+	if op.binaryBody {
+		args = append(args, "contentType string")
+	}
+	if len(op.params) > 0 {
+		args = append(args, "params *"+paramsType)
+	}
+	resultType, zero := op.resultType, ""
+	switch {
+	case resultType == "":
+	case resultType == "io.ReadCloser" || strings.HasPrefix(resultType, "[]") || strings.HasPrefix(resultType, "map["):
+		zero = "nil"
+	case g.isStructType(resultType):
+		resultType, zero = "*"+resultType, "nil"
+	default:
+		zero = "result"
+	}
+	if resultType == "" {
+		g.printf("func (c *Client) %s(%s) error {\n", op.name, strings.Join(args, ", "))
+	} else {
+		g.printf("func (c *Client) %s(%s) (%s, error) {\n", op.name, strings.Join(args, ", "), resultType)
+	}
+
+	path := strconv.Quote(op.route.Path)
+	for _, arg := range op.pathArgs {
+		path = strings.Replace(path, "{"+arg.wireName+"}", `"+pathParam(`+arg.name+`)+"`, 1)
+	}
+	path = strings.TrimSuffix(strings.TrimPrefix(path, `""+`), `+""`)
+	g.printf("req := newRequest(http.Method%s, %s)\n", methodName(op.route.Method), path)
+	if op.binaryBody {
+		g.printf("req.setBinaryBody(body, contentType)\n")
+	} else if op.bodyType != "" {
+		g.printf("req.body = body\n")
+	}
+	if len(op.params) > 0 {
+		g.printf("if params != nil {\n")
+		for _, param := range op.params {
+			if param.in == openapi3.ParameterInHeader {
+				g.printf("req.setHeader(%q, params.%s)\n", param.wireName, param.field)
+			} else {
+				g.printf("req.setQuery(%q, params.%s)\n", param.wireName, param.field)
+			}
+		}
+		g.printf("}\n")
+	}
+	if op.idempotencyKey {
+		g.printf("req.ensureIdempotencyKey()\n")
+	}
+	switch {
+	case resultType == "":
+		g.printf("return c.do(ctx, req, nil)\n}\n\n")
+	case resultType == "io.ReadCloser":
+		g.printf("var result io.ReadCloser\n")
+		g.printf("if err := c.do(ctx, req, &result); err != nil {\nreturn nil, err\n}\nreturn result, nil\n}\n\n")
+	case zero == "nil" && strings.HasPrefix(resultType, "*"):
+		g.printf("result := new(%s)\n", strings.TrimPrefix(resultType, "*"))
+		g.printf("if err := c.do(ctx, req, result); err != nil {\nreturn nil, err\n}\nreturn result, nil\n}\n\n")
+	default:
+		g.printf("var result %s\n", resultType)
+		g.printf("if err := c.do(ctx, req, &result); err != nil {\nreturn %s, err\n}\nreturn result, nil\n}\n\n", zero)
+	}
+}
+
+// errorsComment documents the error statuses of the operation.
+func errorsComment(op *operation) string {
+	statuses := make([]string, len(op.errors))
+	for i, status := range op.errors {
+		statuses[i] = strconv.Itoa(status)
+	}
+	comment := "The API answers with the error status " + joinOr(statuses) + ", which is returned as an *Error."
+	byType := map[string][]string{}
+	var types []string
+	for _, status := range op.errors {
+		if errorType, ok := op.errorTypes[status]; ok {
+			if byType[errorType] == nil {
+				types = append(types, errorType)
+			}
+			byType[errorType] = append(byType[errorType], strconv.Itoa(status))
+		}
+	}
+	for _, errorType := range types {
+		comment += " The body of " + joinOr(byType[errorType]) + " decodes into a " + errorType + " with Error.DecodeBody."
+	}
+	return comment
+}
+
+// writeComment writes the text as a comment wrapped at 80 columns.
+func (g *generator) writeComment(text string) {
+	line := "//"
+	for _, word := range strings.Fields(text) {
+		if len(line)+1+len(word) > 80 && line != "//" {
+			g.printf("%s\n", line)
+			line = "//"
+		}
+		line += " " + word
+	}
+	g.printf("%s\n", line)
+}
+
+func (g *generator) isStructType(typeName string) bool {
+	for name, other := range g.types {
+		if other == typeName {
+			return isStruct(g.spec.Doc.Components.Schemas[name].Value)
+		}
+	}
+	return false
+}
+
+func isStruct(schema *openapi3.Schema) bool {
+	return schema.Type.Is(openapi3.TypeObject) && len(schema.Enum) == 0 && schema.AdditionalProperties.Schema == nil
+}
+
+func sameValues(a, b []interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if fmt.Sprint(a[i]) != fmt.Sprint(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func joinOr(values []string) string {
+	if len(values) == 1 {
+		return values[0]
+	}
+	return strings.Join(values[:len(values)-1], ", ") + " or " + values[len(values)-1]
+}
+
+func methodName(method string) string {
+	return string(method[0]) + strings.ToLower(method[1:])
+}
+
+// exportName returns the exported Go name of a JSON or header name, e.g.
+// BookId for bookId and IfModifiedSince for If-Modified-Since.
+func exportName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// unexportName returns the name with a lower case first letter.
+func unexportName(name string) string {
+	name = exportName(name)
+	if name == "" {
+		return name
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
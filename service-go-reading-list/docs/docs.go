@@ -19,38 +19,282 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/books": {
+        "/admin/backup": {
+            "get": {
+                "security": [
+                    {
+                        "default": [
+                            "admin"
+                        ]
+                    }
+                ],
+                "description": "Streams a consistent copy of the books of all the tenants, which the service can be restored from at startup with DATA_RESTORE_PATH.",
+                "produces": [
+                    "application/octet-stream",
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Back up the books",
+                "operationId": "backup",
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "501": {
+                        "description": "backups not supported by the configured storage",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tenants": {
             "get": {
+                "security": [
+                    {
+                        "default": [
+                            "admin"
+                        ]
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List the tenants with their number of books",
+                "operationId": "listTenants",
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tenant"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tenants/{tenant}": {
+            "delete": {
+                "security": [
+                    {
+                        "default": [
+                            "admin"
+                        ]
+                    }
+                ],
+                "description": "Permanently removes the books, the goals, the covers and the highlights of the tenant. The tenant starts over with its initial data when it is next used.",
                 "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete a tenant",
+                "operationId": "deleteTenant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "tenant",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tenant"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid tenant id",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "tenant not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/audit": {
+            "get": {
+                "security": [
+                    {
+                        "default": [
+                            "read:audit"
+                        ]
+                    }
+                ],
+                "description": "Lists the audit entries of the books, oldest first. Each entry records who changed which book, how and when. Set the Accept header to application/x-ndjson to export the entries as JSON lines.",
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "List the changes to the books",
+                "operationId": "listAuditEntries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only the changes to the book",
+                        "name": "bookId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Only the changes from the time, in RFC 3339 format",
+                        "name": "since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AuditEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "unsupported accepted media types",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books": {
+            "get": {
+                "security": [
+                    {
+                        "default": [
+                            "read:books"
+                        ]
+                    }
+                ],
+                "produces": [
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/yaml"
+                ],
                 "tags": [
                     "books"
                 ],
                 "summary": "List all the reading list books",
+                "operationId": "listBooks",
+                "parameters": [
+                    {
+                        "enum": [
+                            "to_read",
+                            "reading",
+                            "read"
+                        ],
+                        "type": "string",
+                        "description": "Only the books with the status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the books of the author, regardless of case",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Answers with 304 if the books have not changed since then",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "successful operation",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Book"
+                                "$ref": "#/definitions/v1.Book"
                             }
+                        },
+                        "headers": {
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "When the books were last changed"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "not modified"
+                    },
+                    "400": {
+                        "description": "invalid status",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "unsupported accepted media types",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
                         }
                     }
                 }
             },
             "post": {
+                "security": [
+                    {
+                        "default": [
+                            "write:books"
+                        ]
+                    }
+                ],
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/yaml"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/yaml"
                 ],
                 "tags": [
                     "books"
                 ],
                 "summary": "Add a new book to the reading list",
+                "operationId": "addBook",
                 "parameters": [
                     {
                         "description": "New book details",
@@ -58,15 +302,27 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Book"
+                            "$ref": "#/definitions/v1.Book"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response for retries with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Add the book even when it looks like a book on the reading list",
+                        "name": "allowDuplicate",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "201": {
                         "description": "successful operation",
                         "schema": {
-                            "$ref": "#/definitions/models.Book"
+                            "$ref": "#/definitions/v1.Book"
                         }
                     },
                     "400": {
@@ -75,8 +331,26 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.ErrorResponse"
                         }
                     },
+                    "406": {
+                        "description": "unsupported accepted media types",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
                     "409": {
-                        "description": "book already exists",
+                        "description": "book already exists or looks like a duplicate",
+                        "schema": {
+                            "$ref": "#/definitions/models.DuplicateConflict"
+                        }
+                    },
+                    "415": {
+                        "description": "unsupported content type",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "idempotency key reused with a different request",
                         "schema": {
                             "$ref": "#/definitions/utils.ErrorResponse"
                         }
@@ -84,97 +358,161 @@ const docTemplate = `{
                 }
             }
         },
-        "/books/{id}": {
+        "/books/duplicates": {
             "get": {
+                "security": [
+                    {
+                        "default": [
+                            "read:books"
+                        ]
+                    }
+                ],
+                "description": "Groups the books that have the same ISBN, or the same author and nearly the same title. The books of a group are ordered by when they were added.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "books"
                 ],
-                "summary": "Get reading list book by id",
+                "summary": "List the books that look like duplicates",
+                "operationId": "listDuplicates",
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.DuplicateCluster"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/books/recommendations": {
+            "get": {
+                "security": [
+                    {
+                        "default": [
+                            "read:books"
+                        ]
+                    }
+                ],
+                "description": "Ranks the books to read and the books of the catalogue by their author, their tags and their similarity to the read books, favouring the highly rated ones. Each recommendation explains its score.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Recommend the books to read next",
+                "operationId": "getRecommendations",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Book ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "maximum": 50,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Maximum number of recommendations",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "successful operation",
                         "schema": {
-                            "$ref": "#/definitions/models.Book"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.Recommendation"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "book not found",
+                    "400": {
+                        "description": "invalid limit",
                         "schema": {
                             "$ref": "#/definitions/utils.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/books/trash": {
+            "get": {
+                "security": [
+                    {
+                        "default": [
+                            "read:books"
+                        ]
+                    }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/yaml"
                 ],
                 "tags": [
                     "books"
                 ],
-                "summary": "Update a reading list book by id",
+                "summary": "List the books in the trash",
+                "operationId": "listTrash",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Book ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated book details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Book"
-                        }
+                        "description": "Answers with 304 if the books have not changed since then",
+                        "name": "If-Modified-Since",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "successful operation",
                         "schema": {
-                            "$ref": "#/definitions/models.Book"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.Book"
+                            }
+                        },
+                        "headers": {
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "When the books were last changed"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "invalid book details",
-                        "schema": {
-                            "$ref": "#/definitions/utils.ErrorResponse"
-                        }
+                    "304": {
+                        "description": "not modified"
                     },
-                    "404": {
-                        "description": "book not found",
+                    "406": {
+                        "description": "unsupported accepted media types",
                         "schema": {
                             "$ref": "#/definitions/utils.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/books/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "default": [
+                            "read:books"
+                        ]
+                    }
+                ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/yaml"
                 ],
                 "tags": [
                     "books"
                 ],
-                "summary": "Delete a reading list book by id",
+                "summary": "Get reading list book by id",
+                "operationId": "getBook",
                 "parameters": [
                     {
                         "type": "string",
@@ -182,53 +520,1360 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Answers with 304 if the books have not changed since then",
+                        "name": "If-Modified-Since",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "successful operation",
                         "schema": {
-                            "$ref": "#/definitions/models.Book"
+                            "$ref": "#/definitions/v1.Book"
+                        },
+                        "headers": {
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "When the books were last changed"
+                            }
                         }
                     },
+                    "304": {
+                        "description": "not modified"
+                    },
                     "404": {
                         "description": "book not found",
                         "schema": {
                             "$ref": "#/definitions/utils.ErrorResponse"
                         }
+                    },
+                    "406": {
+                        "description": "unsupported accepted media types",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "default": [
+                            "write:books"
+                        ]
+                    }
+                ],
+                "consumes": [
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/yaml"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/yaml"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Update a reading list book by id",
+                "operationId": "updateBook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated book details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.Book"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Book"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid book details",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "book not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "unsupported accepted media types",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "unsupported content type",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "default": [
+                            "write:books"
+                        ]
+                    }
+                ],
+                "description": "Moves the book to the trash unless hard is set, in which case the book is removed permanently.",
+                "produces": [
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/yaml"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Delete a reading list book by id",
+                "operationId": "deleteBook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Permanently remove the book instead of moving it to the trash",
+                        "name": "hard",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Book"
+                        }
+                    },
+                    "404": {
+                        "description": "book not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "unsupported accepted media types",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/cover": {
+            "get": {
+                "security": [
+                    {
+                        "default": [
+                            "read:books"
+                        ]
+                    }
+                ],
+                "description": "Returns the original cover of the book, or its thumbnail fitting in a square of 128 (small), 320 (medium) or 640 (large) pixels. The thumbnails are made when the cover is uploaded and stored with it.",
+                "produces": [
+                    "image/jpeg",
+                    "image/png",
+                    "image/gif",
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Get the cover image of a book",
+                "operationId": "getCover",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "small",
+                            "medium",
+                            "large",
+                            "original"
+                        ],
+                        "type": "string",
+                        "default": "original",
+                        "description": "Size of the image",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Answers with 304 if the image still has one of the entity tags",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Answers with 304 if the image has not changed since then, unless If-None-Match is set",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "type": "file"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the image"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "When the image was stored"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "not modified"
+                    },
+                    "400": {
+                        "description": "invalid size",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "book or cover not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "default": [
+                            "write:books"
+                        ]
+                    }
+                ],
+                "description": "Replaces the cover of the book with a JPEG, PNG or GIF image of at most COVER_MAX_SIZE bytes, sent as the cover field of a form or as the body of the request.",
+                "consumes": [
+                    "multipart/form-data",
+                    "image/jpeg",
+                    "image/png",
+                    "image/gif"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Upload the cover image of a book",
+                "operationId": "putCover",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Cover image",
+                        "name": "cover",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "$ref": "#/definitions/models.Cover"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid image",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "book not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "image too large",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "unsupported image type",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/highlights": {
+            "get": {
+                "security": [
+                    {
+                        "default": [
+                            "read:books"
+                        ]
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "highlights"
+                ],
+                "summary": "List the highlights of a book in reading order",
+                "operationId": "listHighlights",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the highlights whose text or note contain it, regardless of case",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the highlights with the tag",
+                        "name": "tag",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Highlight"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "book not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "default": [
+                            "write:books"
+                        ]
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "highlights"
+                ],
+                "summary": "Add a highlight to a book",
+                "operationId": "addHighlight",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Highlight details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Highlight"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "successful operation",
+                        "schema": {
+                            "$ref": "#/definitions/models.Highlight"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid highlight details",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "book not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/highlights/export": {
+            "get": {
+                "security": [
+                    {
+                        "default": [
+                            "read:books"
+                        ]
+                    }
+                ],
+                "description": "Returns a Markdown document with the title and author of the book, followed by its highlights in reading order as quotes with their page, location, note and tags.",
+                "produces": [
+                    "text/markdown",
+                    "application/json"
+                ],
+                "tags": [
+                    "highlights"
+                ],
+                "summary": "Export the highlights of a book as Markdown",
+                "operationId": "exportHighlights",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "book not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/highlights/{highlightId}": {
+            "get": {
+                "security": [
+                    {
+                        "default": [
+                            "read:books"
+                        ]
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "highlights"
+                ],
+                "summary": "Get a highlight of a book by id",
+                "operationId": "getHighlight",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Highlight ID",
+                        "name": "highlightId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "$ref": "#/definitions/models.Highlight"
+                        }
+                    },
+                    "404": {
+                        "description": "book or highlight not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "default": [
+                            "write:books"
+                        ]
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "highlights"
+                ],
+                "summary": "Update a highlight of a book by id",
+                "operationId": "updateHighlight",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Highlight ID",
+                        "name": "highlightId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Highlight details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Highlight"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "$ref": "#/definitions/models.Highlight"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid highlight details",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "book or highlight not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "default": [
+                            "write:books"
+                        ]
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "highlights"
+                ],
+                "summary": "Delete a highlight of a book by id",
+                "operationId": "deleteHighlight",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Highlight ID",
+                        "name": "highlightId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "$ref": "#/definitions/models.Highlight"
+                        }
+                    },
+                    "404": {
+                        "description": "book or highlight not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/merge": {
+            "post": {
+                "security": [
+                    {
+                        "default": [
+                            "write:books"
+                        ]
+                    }
+                ],
+                "description": "Folds the source book into the book, keeping the reading history of both, and moves the source book to the trash.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Merge a book into another",
+                "operationId": "mergeBook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Book to merge",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MergeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Book"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid merge request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "book not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "default": [
+                            "write:books"
+                        ]
+                    }
+                ],
+                "produces": [
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/yaml"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Restore a book from the trash",
+                "operationId": "restoreBook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Book"
+                        }
+                    },
+                    "404": {
+                        "description": "book not found in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "unsupported accepted media types",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books:batch": {
+            "post": {
+                "security": [
+                    {
+                        "default": [
+                            "write:books"
+                        ]
+                    }
+                ],
+                "description": "Applies a list of create, update and delete operations all-or-nothing. If any operation fails, none of them are applied and the response status is the status of the failed operation.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Apply several book operations atomically",
+                "operationId": "executeBatch",
+                "parameters": [
+                    {
+                        "description": "Operations to apply",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.BatchRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response for retries with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "$ref": "#/definitions/v1.BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid operation",
+                        "schema": {
+                            "$ref": "#/definitions/v1.BatchResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "book not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.BatchResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "book already exists",
+                        "schema": {
+                            "$ref": "#/definitions/v1.BatchResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "idempotency key reused with a different request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/goals": {
+            "get": {
+                "security": [
+                    {
+                        "default": [
+                            "read:books"
+                        ]
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "goals"
+                ],
+                "summary": "List the reading goals with their progress",
+                "operationId": "listGoals",
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.GoalProgress"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/goals/{year}": {
+            "get": {
+                "security": [
+                    {
+                        "default": [
+                            "read:books"
+                        ]
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "goals"
+                ],
+                "summary": "Get the reading goal of a year with its progress",
+                "operationId": "getGoal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Year",
+                        "name": "year",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "$ref": "#/definitions/models.GoalProgress"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid year",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "goal not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "default": [
+                            "write:books"
+                        ]
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "goals"
+                ],
+                "summary": "Set the reading goal of a year",
+                "operationId": "setGoal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Year",
+                        "name": "year",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Goal details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Goal"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "$ref": "#/definitions/models.GoalProgress"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid goal details",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "default": [
+                            "write:books"
+                        ]
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "goals"
+                ],
+                "summary": "Delete the reading goal of a year",
+                "operationId": "deleteGoal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Year",
+                        "name": "year",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "$ref": "#/definitions/models.Goal"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid year",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "goal not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/highlights": {
+            "get": {
+                "security": [
+                    {
+                        "default": [
+                            "read:books"
+                        ]
+                    }
+                ],
+                "description": "Returns the highlights of the books on the reading list, grouped by book in reading order. The highlights of the books in the trash are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "highlights"
+                ],
+                "summary": "Search the highlights of all the books",
+                "operationId": "searchHighlights",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only the highlights whose text or note contain it, regardless of case",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the highlights with the tag",
+                        "name": "tag",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Highlight"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stats": {
+            "get": {
+                "security": [
+                    {
+                        "default": [
+                            "read:books"
+                        ]
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get reading statistics",
+                "operationId": "getStats",
+                "responses": {
+                    "200": {
+                        "description": "successful operation",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReadingStats"
+                        }
                     }
                 }
             }
         }
     },
     "definitions": {
-        "models.Book": {
+        "models.AuditChange": {
             "type": "object",
             "properties": {
-                "author": {
+                "after": {},
+                "before": {}
+            }
+        },
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "Actor is the subject or the client of the token of the request, or\nanonymous when the API security is not enforced.",
                     "type": "string",
-                    "example": "J. R. R. Tolkien"
+                    "example": "alice@example.com"
                 },
-                "id": {
+                "bookId": {
                     "type": "string",
                     "example": "fe2594d0-ccea-42a2-97ac-0487458b5642"
                 },
-                "status": {
+                "changes": {
+                    "description": "Changes are the fields of the book that changed, by their JSON name.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.AuditChange"
+                    }
+                },
+                "correlationId": {
+                    "type": "string",
+                    "example": "7d3b5c1e-8f2a-4c6b-9e0d-1a2b3c4d5e6f"
+                },
+                "id": {
+                    "description": "Id orders the entries of the audit log. It is assigned when the entry is appended.",
+                    "type": "integer",
+                    "example": 42
+                },
+                "operation": {
                     "enum": [
-                        "to_read",
-                        "reading",
-                        "read"
+                        "add",
+                        "update",
+                        "delete",
+                        "restore",
+                        "purge",
+                        "merge"
                     ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/models.ReadStatus"
+                            "$ref": "#/definitions/models.AuditOperation"
                         }
                     ],
-                    "example": "to_read"
+                    "example": "update"
                 },
-                "title": {
+                "tenant": {
                     "type": "string",
-                    "example": "The Lord of the Rings"
+                    "example": "default"
+                },
+                "timestamp": {
+                    "type": "string",
+                    "example": "2024-01-02T15:04:05Z"
+                }
+            }
+        },
+        "models.AuditOperation": {
+            "type": "string",
+            "enum": [
+                "add",
+                "update",
+                "delete",
+                "restore",
+                "purge",
+                "merge"
+            ],
+            "x-enum-varnames": [
+                "AuditOperationAdd",
+                "AuditOperationUpdate",
+                "AuditOperationDelete",
+                "AuditOperationRestore",
+                "AuditOperationPurge",
+                "AuditOperationMerge"
+            ]
+        },
+        "models.AuthorCount": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string",
+                    "example": "J. R. R. Tolkien"
+                },
+                "books": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.BatchOperationType": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "delete"
+            ],
+            "x-enum-varnames": [
+                "BatchOperationCreate",
+                "BatchOperationUpdate",
+                "BatchOperationDelete"
+            ]
+        },
+        "models.Cover": {
+            "type": "object",
+            "properties": {
+                "bookId": {
+                    "type": "string",
+                    "example": "dune"
+                },
+                "contentType": {
+                    "type": "string",
+                    "example": "image/jpeg"
+                },
+                "height": {
+                    "type": "integer",
+                    "example": 900
+                },
+                "size": {
+                    "description": "Size is the size of the original image in bytes.",
+                    "type": "integer",
+                    "example": 48213
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "width": {
+                    "type": "integer",
+                    "example": 600
+                }
+            }
+        },
+        "models.DuplicateConflict": {
+            "type": "object",
+            "properties": {
+                "candidates": {
+                    "description": "Candidates are the ids of the books that look like the new book. They\nare omitted when the id of the new book is already taken.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "fe2594d0-ccea-42a2-97ac-0487458b5642"
+                    ]
+                },
+                "message": {
+                    "type": "string",
+                    "example": "the book looks like a duplicate of [fe2594d0-ccea-42a2-97ac-0487458b5642], set allowDuplicate to add it anyway"
+                }
+            }
+        },
+        "models.Goal": {
+            "type": "object",
+            "properties": {
+                "target": {
+                    "type": "integer",
+                    "example": 24
+                },
+                "year": {
+                    "type": "integer",
+                    "example": 2024
+                }
+            }
+        },
+        "models.GoalProgress": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "onTrack": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "percentComplete": {
+                    "description": "PercentComplete is Completed as a percentage of Target.",
+                    "type": "number",
+                    "example": 41.7
+                },
+                "projectedCompletion": {
+                    "description": "ProjectedCompletion is when the target will be reached at the current\npace. It is omitted when the target will not be reached within the year.",
+                    "type": "string",
+                    "example": "2024-11-20T00:00:00Z"
+                },
+                "projectedTotal": {
+                    "description": "ProjectedTotal is the number of books that will be finished by the end\nof the year at the current pace.",
+                    "type": "integer",
+                    "example": 22
+                },
+                "remaining": {
+                    "type": "integer",
+                    "example": 14
+                },
+                "target": {
+                    "type": "integer",
+                    "example": 24
+                },
+                "year": {
+                    "type": "integer",
+                    "example": 2024
+                }
+            }
+        },
+        "models.Highlight": {
+            "type": "object",
+            "properties": {
+                "bookId": {
+                    "type": "string",
+                    "example": "dune"
+                },
+                "createdAt": {
+                    "description": "CreatedAt and UpdatedAt are maintained by the service.",
+                    "type": "string",
+                    "example": "2024-01-02T15:04:05Z"
+                },
+                "id": {
+                    "description": "Id and BookId are maintained by the service.",
+                    "type": "string",
+                    "example": "3f1c0b9e-6a2d-4b8e-9d3f-2a7c5e1b4d6f"
+                },
+                "location": {
+                    "description": "Location is where the passage is when the book has no pages, such as\nthe location of an e-book or a chapter.",
+                    "type": "string",
+                    "example": "Chapter 1"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "The litany against fear."
+                },
+                "page": {
+                    "description": "Page is the page of the passage, when the book has pages.",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 12
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "fear",
+                        "litany"
+                    ]
+                },
+                "text": {
+                    "type": "string",
+                    "example": "I must not fear. Fear is the mind-killer."
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2024-01-02T15:04:05Z"
+                }
+            }
+        },
+        "models.MergeRequest": {
+            "type": "object",
+            "properties": {
+                "sourceId": {
+                    "description": "SourceId is the book to fold into the merged book. It is moved to the trash.",
+                    "type": "string",
+                    "example": "b1b4b3b0-0b1a-4b1a-8b1a-0b1a0b1a0b1a"
                 }
             }
         },
@@ -245,6 +1890,81 @@ const docTemplate = `{
                 "ReadStatusRead"
             ]
         },
+        "models.ReadingStats": {
+            "type": "object",
+            "properties": {
+                "averageDaysToFinish": {
+                    "description": "AverageDaysToFinish is the average number of days between starting and\nfinishing a book. It is omitted when no finished book has a start date.",
+                    "type": "number",
+                    "example": 12.5
+                },
+                "booksByStatus": {
+                    "description": "BooksByStatus is the number of books per read status.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "finishedByMonth": {
+                    "description": "FinishedByMonth is the number of books finished per month, keyed by \"2006-01\".",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "finishedByYear": {
+                    "description": "FinishedByYear is the number of books finished per year, keyed by \"2006\".",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "topAuthors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AuthorCount"
+                    }
+                },
+                "totalBooks": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "models.RecommendationSource": {
+            "type": "string",
+            "enum": [
+                "reading_list",
+                "catalogue"
+            ],
+            "x-enum-varnames": [
+                "RecommendationSourceReadingList",
+                "RecommendationSourceCatalogue"
+            ]
+        },
+        "models.Tenant": {
+            "type": "object",
+            "properties": {
+                "books": {
+                    "description": "Books is the number of books on the reading list of the tenant and\nTrashed the number of its books in the trash.",
+                    "type": "integer",
+                    "example": 12
+                },
+                "id": {
+                    "type": "string",
+                    "example": "acme"
+                },
+                "quota": {
+                    "description": "Quota is how many books, including the ones in the trash, the tenant\ncan have. The books are not limited when it is zero.",
+                    "type": "integer",
+                    "example": 100
+                },
+                "trashed": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
         "utils.ErrorResponse": {
             "type": "object",
             "properties": {
@@ -253,6 +1973,202 @@ const docTemplate = `{
                     "example": "error message"
                 }
             }
+        },
+        "v1.BatchOperation": {
+            "type": "object",
+            "properties": {
+                "book": {
+                    "description": "Book is the book to create or the updated book details.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v1.Book"
+                        }
+                    ]
+                },
+                "id": {
+                    "description": "Id is the book to update or delete.",
+                    "type": "string",
+                    "example": "fe2594d0-ccea-42a2-97ac-0487458b5642"
+                },
+                "op": {
+                    "enum": [
+                        "create",
+                        "update",
+                        "delete"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.BatchOperationType"
+                        }
+                    ],
+                    "example": "update"
+                }
+            }
+        },
+        "v1.BatchOperationResult": {
+            "type": "object",
+            "properties": {
+                "book": {
+                    "$ref": "#/definitions/v1.Book"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "the book id [fe2594d0-ccea-42a2-97ac-0487458b5642] is not found"
+                },
+                "op": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.BatchOperationType"
+                        }
+                    ],
+                    "example": "update"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "v1.BatchRequest": {
+            "type": "object",
+            "properties": {
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.BatchOperation"
+                    }
+                }
+            }
+        },
+        "v1.BatchResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "operation 1 failed: book title is required"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.BatchOperationResult"
+                    }
+                }
+            }
+        },
+        "v1.Book": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string",
+                    "example": "J. R. R. Tolkien"
+                },
+                "createdAt": {
+                    "description": "CreatedAt and UpdatedAt are maintained by the service.",
+                    "type": "string",
+                    "example": "2024-01-02T15:04:05Z"
+                },
+                "deletedAt": {
+                    "description": "DeletedAt is set when the book is moved to the trash.",
+                    "type": "string",
+                    "example": "2024-01-02T15:04:05Z"
+                },
+                "finishedAt": {
+                    "description": "FinishedAt is set when the status changes to read.",
+                    "type": "string",
+                    "example": "2024-01-02T15:04:05Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "fe2594d0-ccea-42a2-97ac-0487458b5642"
+                },
+                "startedAt": {
+                    "description": "StartedAt is set when the status changes to reading.",
+                    "type": "string",
+                    "example": "2024-01-02T15:04:05Z"
+                },
+                "status": {
+                    "enum": [
+                        "to_read",
+                        "reading",
+                        "read"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ReadStatus"
+                        }
+                    ],
+                    "example": "to_read"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "The Lord of the Rings"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2024-01-02T15:04:05Z"
+                }
+            }
+        },
+        "v1.DuplicateCluster": {
+            "type": "object",
+            "properties": {
+                "books": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.Book"
+                    }
+                }
+            }
+        },
+        "v1.Recommendation": {
+            "type": "object",
+            "properties": {
+                "book": {
+                    "$ref": "#/definitions/v1.Book"
+                },
+                "reasons": {
+                    "description": "Reasons explain the score.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "by J. R. R. Tolkien",
+                        " the author of [The Hobbit]"
+                    ]
+                },
+                "score": {
+                    "description": "Score ranks the recommendations, the higher the better.",
+                    "type": "number",
+                    "example": 3.5
+                },
+                "source": {
+                    "description": "Source tells whether the book is on the reading list or comes from the catalogue.",
+                    "enum": [
+                        "reading_list",
+                        "catalogue"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RecommendationSource"
+                        }
+                    ],
+                    "example": "reading_list"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "default": {
+            "type": "oauth2",
+            "flow": "implicit",
+            "authorizationUrl": "https://test.com",
+            "scopes": {
+                "admin": "Grants access to the administration of the tenants",
+                "read:audit": "Grants read access to the audit log",
+                "read:books": "Grants read access",
+                "write:books": "Grants write access"
+            }
         }
     }
 }`
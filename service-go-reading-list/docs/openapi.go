@@ -0,0 +1,14 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package docs
+
+import (
+	_ "embed"
+)
+
+// OpenAPISpec is the OpenAPI 3 definition of the service, served to clients
+// and used at runtime to enforce the declared security requirements.
+//
+//go:embed openapi.yaml
+var OpenAPISpec []byte
@@ -0,0 +1,118 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTValidator(t *testing.T) {
+	ctx := context.Background()
+	secret := []byte("test-secret")
+	validator, err := NewJWTValidator(JWTValidatorConfig{Secret: secret, Issuer: "test-issuer"})
+	require.NoError(t, err)
+
+	sign := func(claims jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+		require.NoError(t, err)
+		return token
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		principal, err := validator.Validate(ctx, sign(jwt.MapClaims{
+			"sub": "alice", "iss": "test-issuer", "scope": "read:books write:books", "exp": time.Now().Add(time.Hour).Unix(),
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", principal.Subject)
+		assert.True(t, principal.HasScopes("read:books", "write:books"))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for name, claims := range map[string]jwt.MapClaims{
+			"Expired":      {"iss": "test-issuer", "exp": time.Now().Add(-time.Hour).Unix()},
+			"NoExpiry":     {"iss": "test-issuer"},
+			"WrongIssuer":  {"iss": "another-issuer", "exp": time.Now().Add(time.Hour).Unix()},
+			"WrongPayload": nil,
+		} {
+			t.Run(name, func(t *testing.T) {
+				token := "not-a-jwt"
+				if claims != nil {
+					token = sign(claims)
+				}
+				_, err := validator.Validate(ctx, token)
+				assert.ErrorIs(t, err, ErrInvalidToken)
+			})
+		}
+	})
+
+	t.Run("PublicKey", func(t *testing.T) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		require.NoError(t, err)
+		validator, err := NewJWTValidator(JWTValidatorConfig{PublicKeyPEM: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})})
+		require.NoError(t, err)
+
+		token, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+			"sub": "bob", "scp": []string{"read:books"}, "exp": time.Now().Add(time.Hour).Unix(),
+		}).SignedString(key)
+		require.NoError(t, err)
+		principal, err := validator.Validate(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"read:books"}, principal.Scopes)
+
+		// A token signed with the HMAC secret must not be accepted.
+		_, err = validator.Validate(ctx, sign(jwt.MapClaims{"exp": time.Now().Add(time.Hour).Unix()}))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestIntrospectionValidator(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientId, clientSecret, ok := r.BasicAuth()
+		if !ok || clientId != "reading-list" || clientSecret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		response := map[string]interface{}{"active": false}
+		if r.FormValue("token") == "active-token" {
+			response = map[string]interface{}{"active": true, "sub": "alice", "client_id": "web", "scope": "read:books"}
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	validator := NewIntrospectionValidator(IntrospectionValidatorConfig{Endpoint: server.URL, ClientId: "reading-list", ClientSecret: "secret"})
+
+	principal, err := validator.Validate(ctx, "active-token")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", principal.Subject)
+	assert.Equal(t, "web", principal.ClientId)
+	assert.True(t, principal.HasScopes("read:books"))
+	assert.False(t, principal.HasScopes("write:books"))
+
+	_, err = validator.Validate(ctx, "revoked-token")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	// Failures of the endpoint itself are not token errors.
+	unauthorized := NewIntrospectionValidator(IntrospectionValidatorConfig{Endpoint: server.URL})
+	_, err = unauthorized.Validate(ctx, "active-token")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrInvalidToken)
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// IntrospectionValidator validates opaque tokens against an OAuth2 token
+// introspection endpoint (RFC 7662).
+type IntrospectionValidator struct {
+	endpoint     string
+	clientId     string
+	clientSecret string
+	client       *http.Client
+}
+
+type IntrospectionValidatorConfig struct {
+	Endpoint string
+	// ClientId and ClientSecret authenticate the service to the endpoint with HTTP basic authentication.
+	ClientId     string
+	ClientSecret string
+	Timeout      time.Duration
+}
+
+func NewIntrospectionValidator(cfg IntrospectionValidatorConfig) *IntrospectionValidator {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	return &IntrospectionValidator{
+		endpoint:     cfg.Endpoint,
+		clientId:     cfg.ClientId,
+		clientSecret: cfg.ClientSecret,
+		client:       &http.Client{Timeout: timeout},
+	}
+}
+
+func (v *IntrospectionValidator) Validate(ctx context.Context, token string) (*Principal, error) {
+	form := url.Values{"token": {token}, "token_type_hint": {"access_token"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if v.clientId != "" {
+		req.SetBasicAuth(url.QueryEscape(v.clientId), url.QueryEscape(v.clientSecret))
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token introspection failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token introspection failed with status %d", resp.StatusCode)
+	}
+	claims := map[string]interface{}{}
+	if err := json.NewDecoder(resp.Body).Decode(&claims); err != nil {
+		return nil, fmt.Errorf("invalid token introspection response: %w", err)
+	}
+	if active, _ := claims["active"].(bool); !active {
+		return nil, fmt.Errorf("%w: the token is not active", ErrInvalidToken)
+	}
+	if exp, ok := claims["exp"].(float64); ok && time.Unix(int64(exp), 0).Before(time.Now()) {
+		return nil, fmt.Errorf("%w: the token is expired", ErrInvalidToken)
+	}
+	return &Principal{
+		Subject:  firstNonEmpty(stringClaim(claims, "sub"), stringClaim(claims, "username")),
+		ClientId: stringClaim(claims, "client_id"),
+		Scopes:   parseScopes(claims),
+		Claims:   claims,
+	}, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTValidator validates self-contained JWT access tokens locally, either with
+// a shared HMAC secret or with an RSA, ECDSA or Ed25519 public key.
+type JWTValidator struct {
+	key     interface{}
+	methods []string
+	options []jwt.ParserOption
+}
+
+type JWTValidatorConfig struct {
+	// Secret is the shared secret of HS256/HS384/HS512 signed tokens.
+	Secret []byte
+	// PublicKeyPEM is the PEM encoded public key of asymmetrically signed tokens.
+	PublicKeyPEM []byte
+	// Issuer, when set, must match the iss claim.
+	Issuer string
+	// Audience, when set, must be one of the aud claim values.
+	Audience string
+}
+
+func NewJWTValidator(cfg JWTValidatorConfig) (*JWTValidator, error) {
+	v := &JWTValidator{options: []jwt.ParserOption{jwt.WithExpirationRequired()}}
+	switch {
+	case len(cfg.Secret) > 0:
+		v.key = cfg.Secret
+		v.methods = []string{"HS256", "HS384", "HS512"}
+	case len(cfg.PublicKeyPEM) > 0:
+		key, methods, err := parsePublicKey(cfg.PublicKeyPEM)
+		if err != nil {
+			return nil, err
+		}
+		v.key = key
+		v.methods = methods
+	default:
+		return nil, errors.New("a JWT secret or public key is required")
+	}
+	v.options = append(v.options, jwt.WithValidMethods(v.methods))
+	if cfg.Issuer != "" {
+		v.options = append(v.options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		v.options = append(v.options, jwt.WithAudience(cfg.Audience))
+	}
+	return v, nil
+}
+
+func (v *JWTValidator) Validate(ctx context.Context, token string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return v.key, nil
+	}, v.options...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+	return &Principal{
+		Subject:  stringClaim(claims, "sub"),
+		ClientId: firstNonEmpty(stringClaim(claims, "client_id"), stringClaim(claims, "azp")),
+		Scopes:   parseScopes(claims),
+		Claims:   claims,
+	}, nil
+}
+
+func parsePublicKey(data []byte) (crypto.PublicKey, []string, error) {
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return key, []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}, nil
+	}
+	if key, err := jwt.ParseECPublicKeyFromPEM(data); err == nil {
+		return key, []string{"ES256", "ES384", "ES512"}, nil
+	}
+	if key, err := jwt.ParseEdPublicKeyFromPEM(data); err == nil {
+		return key, []string{"EdDSA"}, nil
+	}
+	return nil, nil, errors.New("the JWT public key should be a PEM encoded RSA, ECDSA or Ed25519 public key")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
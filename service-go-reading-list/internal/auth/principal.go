@@ -0,0 +1,80 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package auth validates OAuth2 bearer tokens.
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidToken is returned when a bearer token is malformed, expired,
+// inactive or otherwise not acceptable.
+var ErrInvalidToken = errors.New("invalid token")
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	// Subject identifies the caller, e.g. the sub claim of the token.
+	Subject string
+	// ClientId is the OAuth2 client the token was issued to, if known.
+	ClientId string
+	Scopes   []string
+	// Claims are the raw claims of the token or the introspection response.
+	Claims map[string]interface{}
+}
+
+// HasScopes reports whether the principal was granted all the given scopes.
+func (p *Principal) HasScopes(scopes ...string) bool {
+	for _, scope := range scopes {
+		if !slices.Contains(p.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// TokenValidator validates a bearer token and returns the principal it represents.
+type TokenValidator interface {
+	Validate(ctx context.Context, token string) (*Principal, error)
+}
+
+type principalCtxKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalCtxKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal stored by WithPrincipal, if any.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalCtxKey{}).(*Principal)
+	return principal, ok
+}
+
+// parseScopes reads the scopes from either a space separated "scope" claim
+// (RFC 8693 / RFC 7662) or a "scp" array claim.
+func parseScopes(claims map[string]interface{}) []string {
+	if scope, ok := claims["scope"].(string); ok {
+		return strings.Fields(scope)
+	}
+	var scopes []string
+	switch scp := claims["scp"].(type) {
+	case string:
+		scopes = strings.Fields(scp)
+	case []interface{}:
+		for _, s := range scp {
+			if str, ok := s.(string); ok {
+				scopes = append(scopes, str)
+			}
+		}
+	}
+	return scopes
+}
+
+func stringClaim(claims map[string]interface{}, name string) string {
+	s, _ := claims[name].(string)
+	return s
+}
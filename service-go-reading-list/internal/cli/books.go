@@ -0,0 +1,264 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/invopop/yaml"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+)
+
+const booksUsage = `Usage: readinglist books <command> [flags]
+
+Commands:
+  list          list the books
+  get <id>      show a book
+  add           add a book
+  update <id>   change the fields of a book
+  delete <id>   move a book to the trash, or remove it with -purge
+`
+
+func (a *App) runBooks(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(a.Stderr, booksUsage)
+		return &usageError{}
+	}
+	switch args[0] {
+	case "list":
+		return a.runBooksList(ctx, args[1:])
+	case "get":
+		return a.runBooksGet(ctx, args[1:])
+	case "add":
+		return a.runBooksAdd(ctx, args[1:])
+	case "update":
+		return a.runBooksUpdate(ctx, args[1:])
+	case "delete":
+		return a.runBooksDelete(ctx, args[1:])
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(a.Stdout, booksUsage)
+		return nil
+	}
+	return usagef("unknown command %q, run readinglist books -h for the commands", args[0])
+}
+
+func (a *App) runBooksList(ctx context.Context, args []string) error {
+	var opts options
+	var filter models.BookFilter
+	fs := a.newFlagSet("books list", "[flags]")
+	opts.register(fs, OutputTable)
+	fs.Func("status", "list the books with the status: to_read, reading or read", func(s string) error {
+		filter.Status = models.ReadStatus(s)
+		return nil
+	})
+	fs.StringVar(&filter.Author, "author", "", "list the books of the author, regardless of case")
+	if _, err := parseArgs(fs, args, 0, &opts, OutputTable, OutputJSON, OutputYAML); err != nil {
+		return err
+	}
+	return a.withStore(&opts, func(store bookStore) error {
+		books, err := store.List(ctx, filter)
+		if err != nil {
+			return err
+		}
+		return writeBooks(a.Stdout, opts.output, books)
+	})
+}
+
+func (a *App) runBooksGet(ctx context.Context, args []string) error {
+	var opts options
+	fs := a.newFlagSet("books get", "<id> [flags]")
+	opts.register(fs, OutputTable)
+	positional, err := parseArgs(fs, args, 1, &opts, OutputTable, OutputJSON, OutputYAML)
+	if err != nil {
+		return err
+	}
+	return a.withStore(&opts, func(store bookStore) error {
+		book, err := store.Get(ctx, positional[0])
+		if err != nil {
+			return err
+		}
+		return writeBook(a.Stdout, opts.output, book)
+	})
+}
+
+func (a *App) runBooksAdd(ctx context.Context, args []string) error {
+	var opts options
+	var book models.Book
+	fs := a.newFlagSet("books add", "-title <title> -author <author> [flags]")
+	opts.register(fs, OutputTable)
+	fs.StringVar(&book.Id, "id", "", "id of the book, which is generated when it is not set")
+	registerBookFlags(fs, &book)
+	allowDuplicate := fs.Bool("allow-duplicate", false, "add the book even when it looks like a book on the reading list")
+	if _, err := parseArgs(fs, args, 0, &opts, OutputTable, OutputJSON, OutputYAML); err != nil {
+		return err
+	}
+	return a.withStore(&opts, func(store bookStore) error {
+		added, err := store.Add(ctx, book, *allowDuplicate)
+		if err != nil {
+			return err
+		}
+		return writeBook(a.Stdout, opts.output, added)
+	})
+}
+
+func (a *App) runBooksUpdate(ctx context.Context, args []string) error {
+	var opts options
+	// The flags are parsed into changes, and only the ones that are set are
+	// applied to the current book.
+	var changes models.Book
+	fs := a.newFlagSet("books update", "<id> [flags]")
+	opts.register(fs, OutputTable)
+	registerBookFlags(fs, &changes)
+	positional, err := parseArgs(fs, args, 1, &opts, OutputTable, OutputJSON, OutputYAML)
+	if err != nil {
+		return err
+	}
+	return a.withStore(&opts, func(store bookStore) error {
+		book, err := store.Get(ctx, positional[0])
+		if err != nil {
+			return err
+		}
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "title":
+				book.Title = changes.Title
+			case "author":
+				book.Author = changes.Author
+			case "status":
+				book.Status = changes.Status
+			case "isbn":
+				book.ISBN = changes.ISBN
+			case "tags":
+				book.Tags = changes.Tags
+			case "rating":
+				book.Rating = changes.Rating
+			}
+		})
+		updated, err := store.Update(ctx, book)
+		if err != nil {
+			return err
+		}
+		return writeBook(a.Stdout, opts.output, updated)
+	})
+}
+
+func (a *App) runBooksDelete(ctx context.Context, args []string) error {
+	var opts options
+	fs := a.newFlagSet("books delete", "<id> [flags]")
+	opts.register(fs, OutputTable)
+	purge := fs.Bool("purge", false, "remove the book permanently instead of moving it to the trash")
+	positional, err := parseArgs(fs, args, 1, &opts, OutputTable, OutputJSON, OutputYAML)
+	if err != nil {
+		return err
+	}
+	return a.withStore(&opts, func(store bookStore) error {
+		book, err := store.Delete(ctx, positional[0], *purge)
+		if err != nil {
+			return err
+		}
+		return writeBook(a.Stdout, opts.output, book)
+	})
+}
+
+// registerBookFlags registers the flags of the fields of the book that are
+// set by the users.
+func registerBookFlags(fs *flag.FlagSet, book *models.Book) {
+	fs.StringVar(&book.Title, "title", "", "title of the book")
+	fs.StringVar(&book.Author, "author", "", "author of the book")
+	fs.Func("status", "status of the book: to_read, reading or read", func(s string) error {
+		book.Status = models.ReadStatus(s)
+		return nil
+	})
+	fs.StringVar(&book.ISBN, "isbn", "", "ISBN of the book")
+	fs.Func("tags", "comma-separated tags of the book", func(s string) error {
+		book.Tags = nil
+		for _, tag := range strings.Split(s, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				book.Tags = append(book.Tags, tag)
+			}
+		}
+		return nil
+	})
+	fs.IntVar(&book.Rating, "rating", 0, "rating of the book between 1 and 5, or 0 when it is not rated")
+}
+
+// parseArgs parses the flags of the command, which takes exactly nargs
+// arguments, validates the options against the output formats it supports
+// and returns the arguments.
+func parseArgs(fs *flag.FlagSet, args []string, nargs int, opts *options, formats ...string) ([]string, error) {
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(positional) != nargs {
+		fs.Usage()
+		return nil, &usageError{}
+	}
+	return positional, opts.validate(formats...)
+}
+
+// withStore runs f with the store of the options and closes it afterwards.
+func (a *App) withStore(opts *options, f func(store bookStore) error) (err error) {
+	store, err := a.openStore(opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := store.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to close the storage: %w", closeErr)
+		}
+	}()
+	return f(store)
+}
+
+func writeBook(w io.Writer, format string, book models.Book) error {
+	if format == OutputTable {
+		return writeBooks(w, format, []models.Book{book})
+	}
+	return writeValue(w, format, book)
+}
+
+// writeBooks writes the books in the format, as a table of their main
+// fields or as the representations of the API.
+func writeBooks(w io.Writer, format string, books []models.Book) error {
+	if format != OutputTable {
+		if books == nil {
+			books = []models.Book{}
+		}
+		return writeValue(w, format, books)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTITLE\tAUTHOR\tSTATUS\tRATING\tTAGS")
+	for _, book := range books {
+		rating := "-"
+		if book.Rating > 0 {
+			rating = strconv.Itoa(book.Rating)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", book.Id, book.Title, book.Author, book.Status, rating, strings.Join(book.Tags, ","))
+	}
+	return tw.Flush()
+}
+
+// writeValue writes the JSON or YAML representation of v.
+func writeValue(w io.Writer, format string, v interface{}) error {
+	if format == OutputYAML {
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to marshal the output: %w", err)
+		}
+		_, err = w.Write(data)
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,208 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cli implements the readinglist commands of the service binary,
+// which manage the books through the REST API of a running instance or
+// directly on the storage configured by the environment.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/tenancy"
+)
+
+// The environment variables that set the defaults of the -server and
+// -token flags.
+const (
+	ServerEnv = "READINGLIST_SERVER"
+	TokenEnv  = "READINGLIST_TOKEN"
+)
+
+// The output formats.
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+const usage = `Usage: readinglist <command> [flags]
+
+Commands:
+  serve               run the service, which is the default without a command
+  books list          list the books
+  books get <id>      show a book
+  books add           add a book
+  books update <id>   change the fields of a book
+  books delete <id>   move a book to the trash, or remove it with -purge
+  import <file>       add the books of a JSON or YAML file, or of stdin with -
+  export              write the books in the format of the initial data
+  seed                add the books of the initial data that are missing
+  migrate             copy all the books to another storage
+  loadtest            send a mix of requests to an instance and report the
+                      throughput and the latencies
+
+The commands talk to the instance at -server, which defaults to
+$READINGLIST_SERVER, and otherwise operate directly on the storage configured
+by the environment of the service (DATA_DIR, DATA_STORE and REDIS_URL).
+Run readinglist <command> -h for the flags of a command.
+`
+
+// App runs the commands with its standard streams.
+type App struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// Serve runs the service until the context is done.
+	Serve func(ctx context.Context)
+}
+
+// Run runs the command of args, which exclude the name of the program, and
+// returns the exit code: 0 on success, 1 when the command failed and 2 when
+// it was not used correctly.
+func (a *App) Run(ctx context.Context, args []string) int {
+	if len(args) == 0 || args[0] == "serve" {
+		if len(args) > 1 {
+			return a.exitCode(usagef("serve takes no arguments, it is configured by the environment"))
+		}
+		a.Serve(ctx)
+		return 0
+	}
+	var run func(ctx context.Context, args []string) error
+	switch args[0] {
+	case "books":
+		run = a.runBooks
+	case "import":
+		run = a.runImport
+	case "export":
+		run = a.runExport
+	case "seed":
+		run = a.runSeed
+	case "migrate":
+		run = a.runMigrate
+	case "loadtest":
+		run = a.runLoadTest
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(a.Stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(a.Stderr, "readinglist: unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+	// The logs of the storage are only relevant when something goes wrong.
+	logrus.SetOutput(a.Stderr)
+	logrus.SetLevel(logrus.WarnLevel)
+	if _, err := config.LoadConfig(); err != nil {
+		return a.exitCode(err)
+	}
+	return a.exitCode(run(ctx, args[1:]))
+}
+
+func (a *App) exitCode(err error) int {
+	var usageErr *usageError
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.As(err, &usageErr):
+		// The flag package reports its own errors.
+		if usageErr.message != "" {
+			fmt.Fprintf(a.Stderr, "readinglist: %s\n", usageErr.message)
+		}
+		return 2
+	}
+	fmt.Fprintf(a.Stderr, "readinglist: %s\n", err)
+	return 1
+}
+
+// usageError is returned when a command is not used correctly.
+type usageError struct {
+	message string
+}
+
+func (e *usageError) Error() string {
+	return e.message
+}
+
+func usagef(format string, args ...interface{}) error {
+	return &usageError{message: fmt.Sprintf(format, args...)}
+}
+
+// newFlagSet returns the flags of the command, which are described after
+// its synopsis when the command is run with -h.
+func (a *App) newFlagSet(command, synopsis string) *flag.FlagSet {
+	fs := flag.NewFlagSet("readinglist "+command, flag.ContinueOnError)
+	fs.SetOutput(a.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.Stderr, "Usage: readinglist %s %s\n\nFlags:\n", command, synopsis)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses the flags of fs wherever they are in args, unlike
+// fs.Parse which stops at the first argument, and returns the arguments.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		} else if err != nil {
+			return nil, &usageError{}
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// options are the flags shared by the commands that operate on the books.
+type options struct {
+	server string
+	token  string
+	tenant string
+	output string
+}
+
+func (o *options) register(fs *flag.FlagSet, defaultOutput string) {
+	fs.StringVar(&o.server, "server", os.Getenv(ServerEnv), "URL of the instance to talk to, instead of operating on the configured storage")
+	fs.StringVar(&o.token, "token", os.Getenv(TokenEnv), "bearer token sent to the instance")
+	fs.StringVar(&o.tenant, "tenant", "", "tenant of the books, instead of the default tenant")
+	fs.StringVar(&o.output, "output", defaultOutput, "output format: table, json or yaml")
+	fs.StringVar(&o.output, "o", defaultOutput, "shorthand for -output")
+}
+
+func (o *options) validate(formats ...string) error {
+	if o.tenant != "" {
+		tenant, ok := tenancy.NormalizeId(o.tenant)
+		if !ok {
+			return usagef("invalid tenant [%s]", o.tenant)
+		}
+		o.tenant = tenant
+	}
+	for _, format := range formats {
+		if o.output == format {
+			return nil
+		}
+	}
+	return usagef("-output should be one of [%s]", strings.Join(formats, ", "))
+}
+
+// openStore returns the books of the instance at o.server, or of the
+// configured storage when it is not set.
+func (a *App) openStore(o *options) (bookStore, error) {
+	if o.server != "" {
+		return newAPIStore(o.server, o.token, o.tenant)
+	}
+	return openDirectStore(config.GetConfig(), o.tenant)
+}
@@ -0,0 +1,260 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/api/routes"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/tenancy"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+const initialData = `{"books": [
+	{"id": "emma", "title": "Emma", "author": "Jane Austen", "status": "read"},
+	{"id": "persuasion", "title": "Persuasion", "author": "Jane Austen", "status": "to_read"}
+]}`
+
+// run runs the command and returns its exit code and output.
+func run(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	app := &App{
+		Stdin:  strings.NewReader(stdin),
+		Stdout: &stdout,
+		Stderr: &stderr,
+		Serve:  func(context.Context) { t.Fatal("the service is not expected to be served") },
+	}
+	code := app.Run(context.Background(), args)
+	return code, stdout.String(), stderr.String()
+}
+
+// runJSON runs the command, which must succeed, and decodes its JSON output
+// into v, which is reset first.
+func runJSON(t *testing.T, v interface{}, args ...string) {
+	t.Helper()
+	code, stdout, stderr := run(t, "", append(args, "-o", OutputJSON)...)
+	require.Equal(t, 0, code, stderr)
+	value := reflect.ValueOf(v).Elem()
+	value.Set(reflect.Zero(value.Type()))
+	require.NoError(t, json.Unmarshal([]byte(stdout), v), stdout)
+}
+
+func setInitialData(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "initial_data.json")
+	require.NoError(t, os.WriteFile(path, []byte(initialData), 0o600))
+	t.Setenv("INIT_DATA_PATH", path)
+}
+
+// testBooks runs the books commands against the store selected by the
+// environment and the extra flags, which starts with the initial data.
+func testBooks(t *testing.T, flags ...string) {
+	var books []models.Book
+	runJSON(t, &books, append([]string{"books", "list"}, flags...)...)
+	require.Len(t, books, 2)
+	assert.Equal(t, "emma", books[0].Id)
+
+	var book models.Book
+	runJSON(t, &book, append([]string{"books", "add", "-id", "dune", "-title", "Dune", "-author", "Frank Herbert", "-tags", "sf, classic"}, flags...)...)
+	assert.Equal(t, models.ReadStatusToRead, book.Status)
+	assert.Equal(t, []string{"sf", "classic"}, book.Tags)
+	// The flags can follow the arguments.
+	runJSON(t, &book, append([]string{"books", "update", "dune", "-status", "read", "-rating", "5"}, flags...)...)
+	assert.Equal(t, models.ReadStatusRead, book.Status)
+	assert.Equal(t, 5, book.Rating)
+	assert.Equal(t, "Dune", book.Title)
+	assert.NotNil(t, book.FinishedAt)
+
+	code, _, stderr := run(t, "", append([]string{"books", "add", "-title", "Dune", "-author", "frank herbert"}, flags...)...)
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "looks like a duplicate of [dune]")
+	code, _, stderr = run(t, "", append([]string{"books", "get", "missing"}, flags...)...)
+	assert.Equal(t, 1, code)
+	assert.Equal(t, "readinglist: the book id [missing] is not found\n", stderr)
+
+	runJSON(t, &books, append([]string{"books", "list", "-status", "read"}, flags...)...)
+	assert.Len(t, books, 2)
+	runJSON(t, &books, append([]string{"books", "list", "-author", "JANE AUSTEN"}, flags...)...)
+	assert.Len(t, books, 2)
+	code, stdout, stderr := run(t, "", append([]string{"books", "list", "-author", "frank herbert"}, flags...)...)
+	require.Equal(t, 0, code, stderr)
+	assert.Regexp(t, `^ID\s+TITLE\s+AUTHOR\s+STATUS\s+RATING\s+TAGS\s*\ndune\s+Dune\s+Frank Herbert\s+read\s+5\s+sf,classic\s*\n$`, stdout)
+	code, stdout, stderr = run(t, "", append([]string{"books", "get", "dune", "-o", OutputYAML}, flags...)...)
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "title: Dune\n")
+
+	// The exported books are imported again once they are purged.
+	code, exported, stderr := run(t, "", append([]string{"export"}, flags...)...)
+	require.Equal(t, 0, code, stderr)
+	runJSON(t, &book, append([]string{"books", "delete", "dune", "-purge"}, flags...)...)
+	runJSON(t, &book, append([]string{"books", "delete", "emma"}, flags...)...)
+	assert.True(t, book.IsDeleted())
+	code, _, stderr = run(t, exported, append([]string{"import", "-"}, flags...)...)
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stderr, "added 1 books, skipped 2 and failed 0")
+	runJSON(t, &book, append([]string{"books", "get", "dune"}, flags...)...)
+	assert.Equal(t, 5, book.Rating)
+
+	// The seeded books are the missing ones of the initial data.
+	runJSON(t, &book, append([]string{"books", "delete", "persuasion", "-purge"}, flags...)...)
+	code, _, stderr = run(t, "", append([]string{"seed"}, flags...)...)
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stderr, "added 1 books, skipped 1 and failed 0")
+	runJSON(t, &books, append([]string{"books", "list"}, flags...)...)
+	assert.Len(t, books, 2)
+
+	// The books of the tenants are separate.
+	runJSON(t, &books, append([]string{"books", "list", "-tenant", "acme", "-author", "frank herbert"}, flags...)...)
+	assert.Empty(t, books)
+}
+
+func TestDirect(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv(config.DataDir, dataDir)
+	setInitialData(t)
+
+	testBooks(t)
+
+	// The changes are audited as made by the command line.
+	contents, err := os.ReadFile(filepath.Join(dataDir, "audit.jsonl"))
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), `"actor":"`+cliActor+`"`)
+
+	t.Run("Migrate", func(t *testing.T) {
+		// The books of every tenant are copied with the trash.
+		code, _, stderr := run(t, "", "books", "add", "-tenant", "acme", "-title", "Walden", "-author", "Henry David Thoreau", "-id", "walden")
+		require.Equal(t, 0, code, stderr)
+		code, _, stderr = run(t, "", "migrate", "-to-data-dir", dataDir, "-to-data-store", config.DataStoreBolt)
+		require.Equal(t, 0, code, stderr)
+		assert.Contains(t, stderr, "copied 6 books, 1 of them in the trash, and skipped 0")
+		// The books that were already copied are skipped.
+		code, _, stderr = run(t, "", "migrate", "-to-data-dir", dataDir, "-to-data-store", config.DataStoreBolt)
+		require.Equal(t, 0, code, stderr)
+		assert.Contains(t, stderr, "copied 0 books, 0 of them in the trash, and skipped 6")
+
+		t.Setenv(config.DataStore, config.DataStoreBolt)
+		var books []models.Book
+		runJSON(t, &books, "books", "list")
+		assert.Len(t, books, 2)
+		runJSON(t, &books, "books", "list", "-tenant", "acme")
+		assert.Len(t, books, 3)
+	})
+}
+
+// startServer serves the API, with the tenant of the requests read from the
+// header of the tenant, and returns its URL.
+func startServer(t *testing.T) string {
+	t.Setenv(config.DataDir, t.TempDir())
+	t.Setenv(config.TenantMode, config.TenantModeHeader)
+	t.Setenv(config.TenantFallback, tenancy.DefaultTenant)
+	setInitialData(t)
+	_, err := config.LoadConfig()
+	require.NoError(t, err)
+	app := fiber.New(fiber.Config{ErrorHandler: utils.FiberErrorHandler, DisableStartupMessage: true})
+	routes.Initialize(app)
+	t.Cleanup(routes.Shutdown)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = app.Listener(listener) }()
+	t.Cleanup(func() { _ = app.Shutdown() })
+
+	// The storage is not opened by the commands.
+	t.Setenv(config.DataDir, "")
+	return "http://" + listener.Addr().String()
+}
+
+func TestServer(t *testing.T) {
+	testBooks(t, "-server", startServer(t))
+
+	t.Run("Unreachable", func(t *testing.T) {
+		code, _, stderr := run(t, "", "books", "list", "-server", "http://127.0.0.1:1")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "failed to reach the server")
+	})
+}
+
+func TestLoadTest(t *testing.T) {
+	server := startServer(t)
+	results := filepath.Join(t.TempDir(), "results.json")
+	var result loadResult
+	runJSON(t, &result, "loadtest", "-server", server, "-tenant", "acme", "-requests", "200", "-concurrency", "4", "-books", "10", "-file", results)
+	assert.Equal(t, 200, result.Total.Requests)
+	assert.Zero(t, result.Total.Failed)
+	assert.Positive(t, result.Total.Throughput)
+	assert.LessOrEqual(t, result.Total.Latency.P50, result.Total.Latency.P99)
+	assert.LessOrEqual(t, result.Total.Latency.P99, result.Total.Latency.Max)
+	require.Len(t, result.Operations, len(loadOperations))
+	assert.Positive(t, result.Operations[loadGet].Requests)
+	assert.Empty(t, result.Comparison)
+
+	// The books added by the test are purged.
+	var books []models.Book
+	runJSON(t, &books, "books", "list", "-server", server, "-tenant", "acme", "-author", loadTestAuthor)
+	assert.Empty(t, books)
+
+	// The results are compared with the ones of an earlier run.
+	code, stdout, stderr := run(t, "", "loadtest", "-server", server, "-requests", "20", "-mix", "list=1,get=1", "-books", "5", "-baseline", results)
+	require.Equal(t, 0, code, stderr)
+	assert.Regexp(t, `(?m)^OPERATION\s+REQUESTS\s+FAILED\s+RPS`, stdout)
+	assert.Regexp(t, `(?m)^total\s+20\s+0\s`, stdout)
+	assert.Regexp(t, `(?m)^get\s+[+-]\d+\.\d%`, stdout)
+	assert.NotRegexp(t, `(?m)^add\s`, stdout)
+	assert.Contains(t, stderr, "purged the 5 books added by the test")
+
+	// The failed requests fail the command.
+	code, _, stderr = run(t, "", "loadtest", "-server", server+"/missing", "-requests", "5", "-mix", "list=1", "-books", "0")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "5 of the 5 requests failed")
+}
+
+func TestUsage(t *testing.T) {
+	t.Setenv(config.DataDir, t.TempDir())
+	for _, args := range [][]string{
+		{"unknown"},
+		{"books"},
+		{"books", "get"},
+		{"books", "get", "a", "b"},
+		{"books", "list", "-unknown"},
+		{"books", "list", "-o", "xml"},
+		{"books", "list", "-tenant", "Not A Tenant"},
+		{"export", "-o", OutputTable},
+		{"migrate"},
+		{"migrate", "-to-data-dir", "dir", "-to-data-store", "csv"},
+		{"serve", "now"},
+		{"loadtest"},
+		{"loadtest", "-server", "http://localhost:8080", "-mix", "list=1,delete=1"},
+		{"loadtest", "-server", "http://localhost:8080", "-mix", "list=0"},
+		{"loadtest", "-server", "http://localhost:8080", "-concurrency", "0"},
+		{"loadtest", "-server", "http://localhost:8080", "-books", "0"},
+	} {
+		code, _, stderr := run(t, "", args...)
+		assert.Equal(t, 2, code, args)
+		assert.NotEmpty(t, stderr, args)
+	}
+	code, stdout, _ := run(t, "", "help")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "books list")
+	code, _, stderr := run(t, "", "books", "list", "-h")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stderr, "-author")
+
+	t.Setenv(config.DataDir, "")
+	code, _, stderr = run(t, "", "books", "list")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "no storage is configured")
+}
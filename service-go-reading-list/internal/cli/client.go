@@ -0,0 +1,166 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+// booksPath is the path of the books in the version 2 of the API.
+const booksPath = "/api/v2/reading-list/books"
+
+// listPageLimit is the size of the pages the books are listed by, which is
+// the largest the API allows.
+const listPageLimit = 100
+
+// apiTimeout bounds every request to the instance.
+const apiTimeout = 30 * time.Second
+
+// apiStore manages the books through the REST API of a running instance.
+type apiStore struct {
+	client  *http.Client
+	baseURL *url.URL
+	token   string
+	tenant  string
+}
+
+func newAPIStore(server, token, tenant string) (*apiStore, error) {
+	baseURL, err := url.Parse(strings.TrimSuffix(server, "/") + booksPath)
+	if err != nil || baseURL.Host == "" {
+		return nil, usagef("invalid server URL [%s]", server)
+	}
+	return &apiStore{
+		client:  &http.Client{Timeout: apiTimeout},
+		baseURL: baseURL,
+		token:   token,
+		tenant:  tenant,
+	}, nil
+}
+
+func (s *apiStore) List(ctx context.Context, filter models.BookFilter) ([]models.Book, error) {
+	var books []models.Book
+	offset := 0
+	for {
+		query := url.Values{
+			"offset": {strconv.Itoa(offset)},
+			"limit":  {strconv.Itoa(listPageLimit)},
+		}
+		if filter.Status != "" {
+			query.Set("status", filter.Status.String())
+		}
+		if filter.Author != "" {
+			query.Set("author", filter.Author)
+		}
+		var page models.BookPage
+		if err := s.do(ctx, http.MethodGet, "", query, nil, &page); err != nil {
+			return nil, err
+		}
+		books = append(books, page.Items...)
+		if page.NextOffset == nil {
+			return books, nil
+		}
+		offset = *page.NextOffset
+	}
+}
+
+func (s *apiStore) Get(ctx context.Context, id string) (models.Book, error) {
+	var book models.Book
+	err := s.do(ctx, http.MethodGet, id, nil, nil, &book)
+	return book, err
+}
+
+func (s *apiStore) Add(ctx context.Context, book models.Book, allowDuplicate bool) (models.Book, error) {
+	var query url.Values
+	if allowDuplicate {
+		query = url.Values{"allowDuplicate": {"true"}}
+	}
+	var added models.Book
+	err := s.do(ctx, http.MethodPost, "", query, book, &added)
+	return added, err
+}
+
+func (s *apiStore) Update(ctx context.Context, book models.Book) (models.Book, error) {
+	var updated models.Book
+	err := s.do(ctx, http.MethodPut, book.Id, nil, book, &updated)
+	return updated, err
+}
+
+func (s *apiStore) Delete(ctx context.Context, id string, purge bool) (models.Book, error) {
+	var query url.Values
+	if purge {
+		query = url.Values{"hard": {"true"}}
+	}
+	var book models.Book
+	err := s.do(ctx, http.MethodDelete, id, query, nil, &book)
+	return book, err
+}
+
+func (s *apiStore) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// do sends the request for the book with the id, or for the books when it
+// is empty, and decodes the response into result. The error statuses are
+// returned as *fiber.Error with the message of the response.
+func (s *apiStore) do(ctx context.Context, method, id string, query url.Values, body, result interface{}) error {
+	target := *s.baseURL
+	if id != "" {
+		target = *target.JoinPath(id)
+	}
+	target.RawQuery = query.Encode()
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal the request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create the request: %w", err)
+	}
+	req.Header.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+	if body != nil {
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	}
+	if s.token != "" {
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+s.token)
+	}
+	if s.tenant != "" {
+		req.Header.Set(config.DefaultTenantHeader, s.tenant)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the server: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		var errResp utils.ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil || errResp.Message == "" {
+			errResp.Message = http.StatusText(resp.StatusCode)
+		}
+		return fiber.NewError(resp.StatusCode, errResp.Message)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode the response of the server: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,543 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/client"
+)
+
+// The operations of the load test.
+const (
+	loadList   = "list"
+	loadGet    = "get"
+	loadAdd    = "add"
+	loadUpdate = "update"
+)
+
+var loadOperations = []string{loadList, loadGet, loadAdd, loadUpdate}
+
+const defaultLoadMix = "list=10,get=70,add=10,update=10"
+
+// loadTestAuthor is the author of the books added by the load test.
+const loadTestAuthor = "Load Tester"
+
+// loadResult is the outcome of a load test, which is written as JSON to be
+// compared with later runs.
+type loadResult struct {
+	Server      string         `json:"server"`
+	Tenant      string         `json:"tenant,omitempty"`
+	StartedAt   time.Time      `json:"startedAt"`
+	Seconds     float64        `json:"seconds"`
+	Concurrency int            `json:"concurrency"`
+	Books       int            `json:"books"`
+	Mix         map[string]int `json:"mix"`
+	Total       *loadStats     `json:"total"`
+	// Operations are the stats of every operation of the mix.
+	Operations map[string]*loadStats `json:"operations"`
+	// Comparison is the relative change of the stats since the baseline,
+	// when there is one, by operation and for the total.
+	Comparison map[string]loadComparison `json:"comparison,omitempty"`
+}
+
+// loadStats are the stats of the requests of an operation.
+type loadStats struct {
+	Requests int `json:"requests"`
+	Failed   int `json:"failed"`
+	// Failures are the numbers of failed requests by status, or network for
+	// the requests that got no response.
+	Failures map[string]int `json:"failures,omitempty"`
+	// Throughput is in requests per second.
+	Throughput float64       `json:"throughput"`
+	Latency    loadLatencies `json:"latency"`
+	latencies  []time.Duration
+}
+
+// loadLatencies are in milliseconds.
+type loadLatencies struct {
+	Min  float64 `json:"min"`
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P95  float64 `json:"p95"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+// loadComparison is the change of the stats relative to the baseline, in
+// percent.
+type loadComparison struct {
+	Throughput float64 `json:"throughput"`
+	P50        float64 `json:"p50"`
+	P99        float64 `json:"p99"`
+}
+
+func (a *App) runLoadTest(ctx context.Context, args []string) error {
+	var opts options
+	fs := a.newFlagSet("loadtest", "-server <url> [flags]")
+	opts.register(fs, OutputTable)
+	duration := fs.Duration("duration", 10*time.Second, "how long the requests are sent for")
+	requests := fs.Int("requests", 0, "number of requests to send, instead of sending them for -duration")
+	concurrency := fs.Int("concurrency", 8, "number of requests sent at the same time")
+	books := fs.Int("books", 100, "number of books added before the test for the get and update requests")
+	mixFlag := fs.String("mix", defaultLoadMix, "relative weights of the list, get, add and update requests")
+	path := fs.String("file", "", "file to write the results to as JSON, to compare later runs with")
+	baselinePath := fs.String("baseline", "", "JSON results of an earlier run to compare the results with")
+	keep := fs.Bool("keep", false, "keep the books added by the test, which are otherwise purged")
+	if _, err := parseArgs(fs, args, 0, &opts, OutputTable, OutputJSON, OutputYAML); err != nil {
+		return err
+	}
+	mix, err := parseLoadMix(*mixFlag)
+	switch {
+	case err != nil:
+		return usagef("invalid -mix [%s]: %s", *mixFlag, err)
+	case opts.server == "":
+		return usagef("-server is required, the load test runs against a running instance")
+	case *concurrency <= 0:
+		return usagef("-concurrency should be positive")
+	case *requests < 0, *requests == 0 && *duration <= 0:
+		return usagef("-duration or -requests should be positive")
+	case *books <= 0 && (mix[loadGet] > 0 || mix[loadUpdate] > 0):
+		return usagef("-books should be positive for the get and update requests")
+	}
+	var baseline *loadResult
+	if *baselinePath != "" {
+		if baseline, err = readLoadResult(*baselinePath); err != nil {
+			return err
+		}
+	}
+
+	// The requests are not retried, so that the failures and the latencies
+	// are the ones of the server.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = *concurrency
+	clientOpts := []client.Option{
+		client.WithHTTPClient(&http.Client{Timeout: apiTimeout, Transport: transport}),
+		client.WithRetryPolicy(client.RetryPolicy{MaxAttempts: 1}),
+	}
+	if opts.token != "" {
+		clientOpts = append(clientOpts, client.WithToken(opts.token))
+	}
+	if opts.tenant != "" {
+		clientOpts = append(clientOpts, client.WithTenant(opts.tenant))
+	}
+	c, err := client.New(opts.server, clientOpts...)
+	if err != nil {
+		return usagef("invalid server URL [%s]", opts.server)
+	}
+	defer transport.CloseIdleConnections()
+
+	test := &loadTest{client: c, mix: mix, runId: uuid.NewString()[:8]}
+	fmt.Fprintf(a.Stderr, "adding %d books\n", *books)
+	err = test.forEach(ctx, *concurrency, *books, func(ctx context.Context, i int) error {
+		_, err := test.addBook(ctx)
+		return err
+	})
+	if !*keep {
+		defer func() {
+			// The books are purged even when the test is interrupted.
+			purgeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), apiTimeout)
+			defer cancel()
+			added := test.books()
+			err := test.forEach(purgeCtx, *concurrency, len(added), func(ctx context.Context, i int) error {
+				_, err := c.DeleteBook(ctx, added[i].Id, &client.DeleteBookParams{Hard: true})
+				return err
+			})
+			if err != nil {
+				fmt.Fprintf(a.Stderr, "failed to purge the books added by the test: %s\n", err)
+				return
+			}
+			fmt.Fprintf(a.Stderr, "purged the %d books added by the test\n", len(added))
+		}()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add the books of the test: %w", err)
+	}
+
+	fmt.Fprintf(a.Stderr, "sending the requests with a concurrency of %d\n", *concurrency)
+	var runCtx context.Context
+	var cancel context.CancelFunc
+	if *requests > 0 {
+		runCtx, cancel = context.WithCancel(ctx)
+	} else {
+		runCtx, cancel = context.WithTimeout(ctx, *duration)
+	}
+	defer cancel()
+	result := test.run(runCtx, *concurrency, *requests)
+	result.Server = opts.server
+	result.Tenant = opts.tenant
+	result.Books = *books
+	if baseline != nil {
+		result.compare(baseline)
+	}
+
+	if *path != "" {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal the results: %w", err)
+		}
+		if err := os.WriteFile(*path, append(data, '\n'), 0o600); err != nil {
+			return fmt.Errorf("failed to write the results: %w", err)
+		}
+		fmt.Fprintf(a.Stderr, "wrote the results to [%s]\n", *path)
+	}
+	if opts.output == OutputTable {
+		err = writeLoadResult(a, result)
+	} else {
+		err = writeValue(a.Stdout, opts.output, result)
+	}
+	if err != nil {
+		return err
+	}
+	if result.Total.Failed > 0 {
+		return fmt.Errorf("%d of the %d requests failed", result.Total.Failed, result.Total.Requests)
+	}
+	return nil
+}
+
+// parseLoadMix parses the weights of the operations, e.g. list=1,get=9. The
+// operations that are left out are not sent.
+func parseLoadMix(value string) (map[string]int, error) {
+	mix := map[string]int{}
+	total := 0
+	for _, part := range strings.Split(value, ",") {
+		name, weightValue, ok := strings.Cut(strings.TrimSpace(part), "=")
+		weight, err := strconv.Atoi(weightValue)
+		switch {
+		case !ok || err != nil || weight < 0:
+			return nil, fmt.Errorf("expected <operation>=<weight>, got [%s]", part)
+		case !contains(loadOperations, name):
+			return nil, fmt.Errorf("unknown operation [%s], expected one of [%s]", name, strings.Join(loadOperations, ", "))
+		}
+		mix[name] = weight
+		total += weight
+	}
+	if total == 0 {
+		return nil, errors.New("no operation has a weight")
+	}
+	return mix, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func readLoadResult(path string) (*loadResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the baseline: %w", err)
+	}
+	var result loadResult
+	if err := json.Unmarshal(data, &result); err != nil || result.Total == nil {
+		return nil, fmt.Errorf("[%s] is not the JSON results of a load test", path)
+	}
+	return &result, nil
+}
+
+// loadTest sends the requests of a load test.
+type loadTest struct {
+	client *client.Client
+	mix    map[string]int
+	// runId tells the books of a run apart from the ones that earlier runs
+	// kept with -keep.
+	runId    string
+	sequence atomic.Int64
+
+	lock  sync.Mutex
+	added []client.Book
+}
+
+// forEach calls f for 0 to n-1 from concurrency goroutines, and returns the
+// first error.
+func (t *loadTest) forEach(ctx context.Context, concurrency, n int, f func(ctx context.Context, i int) error) error {
+	var next atomic.Int64
+	var firstErr error
+	var once sync.Once
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := int(next.Add(1)) - 1; i < n && ctx.Err() == nil; i = int(next.Add(1)) - 1 {
+				if err := f(ctx, i); err != nil {
+					once.Do(func() { firstErr = err })
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr == nil {
+		return ctx.Err()
+	}
+	return firstErr
+}
+
+func (t *loadTest) addBook(ctx context.Context) (*client.Book, error) {
+	book := client.Book{
+		Title:  fmt.Sprintf("Load test %s %d", t.runId, t.sequence.Add(1)),
+		Author: loadTestAuthor,
+		Status: client.ReadStatusToRead,
+	}
+	// The titles of the books differ by their number only, which makes them
+	// look like duplicates of each other.
+	added, err := t.client.AddBook(ctx, book, &client.AddBookParams{AllowDuplicate: true})
+	if err != nil {
+		return nil, err
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.added = append(t.added, *added)
+	return added, nil
+}
+
+// books returns the books added by the test.
+func (t *loadTest) books() []client.Book {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return append([]client.Book(nil), t.added...)
+}
+
+func (t *loadTest) randomBook(rng *rand.Rand) client.Book {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.added[rng.Intn(len(t.added))]
+}
+
+// pick returns an operation at random, in the proportions of the mix.
+func (t *loadTest) pick(rng *rand.Rand) string {
+	total := 0
+	for _, weight := range t.mix {
+		total += weight
+	}
+	n := rng.Intn(total)
+	for _, operation := range loadOperations {
+		if n < t.mix[operation] {
+			return operation
+		}
+		n -= t.mix[operation]
+	}
+	return loadOperations[len(loadOperations)-1]
+}
+
+func (t *loadTest) send(ctx context.Context, operation string, rng *rand.Rand) error {
+	switch operation {
+	case loadList:
+		_, err := t.client.ListBooks(ctx, nil)
+		return err
+	case loadGet:
+		_, err := t.client.GetBook(ctx, t.randomBook(rng).Id, nil)
+		return err
+	case loadAdd:
+		_, err := t.addBook(ctx)
+		return err
+	default:
+		book := t.randomBook(rng)
+		book.Status = []client.ReadStatus{client.ReadStatusToRead, client.ReadStatusReading, client.ReadStatusRead}[rng.Intn(3)]
+		_, err := t.client.UpdateBook(ctx, book.Id, book)
+		return err
+	}
+}
+
+// run sends the requests from concurrency goroutines until ctx is done or,
+// when it is positive, the number of requests is sent.
+func (t *loadTest) run(ctx context.Context, concurrency, requests int) *loadResult {
+	result := &loadResult{
+		StartedAt:   time.Now().UTC(),
+		Concurrency: concurrency,
+		Mix:         t.mix,
+		Total:       &loadStats{},
+		Operations:  map[string]*loadStats{},
+	}
+	for operation, weight := range t.mix {
+		if weight > 0 {
+			result.Operations[operation] = &loadStats{}
+		}
+	}
+	var sent atomic.Int64
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			// Every goroutine keeps its own stats, which are merged at the
+			// end, so that they do not contend for them.
+			stats := map[string]*loadStats{}
+			for operation := range result.Operations {
+				stats[operation] = &loadStats{}
+			}
+			for ctx.Err() == nil && (requests <= 0 || sent.Add(1) <= int64(requests)) {
+				operation := t.pick(rng)
+				requestStart := time.Now()
+				err := t.send(ctx, operation, rng)
+				elapsed := time.Since(requestStart)
+				if err != nil && ctx.Err() != nil {
+					// The request was cut short by the end of the test.
+					break
+				}
+				stats[operation].record(elapsed, err)
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			for operation, s := range stats {
+				result.Operations[operation].merge(s)
+			}
+		}(time.Now().UnixNano() + int64(w))
+	}
+	wg.Wait()
+	result.Seconds = time.Since(start).Seconds()
+	for _, stats := range result.Operations {
+		result.Total.merge(stats)
+		stats.summarize(result.Seconds)
+	}
+	result.Total.summarize(result.Seconds)
+	return result
+}
+
+func (s *loadStats) record(elapsed time.Duration, err error) {
+	s.Requests++
+	s.latencies = append(s.latencies, elapsed)
+	if err == nil {
+		return
+	}
+	s.Failed++
+	if s.Failures == nil {
+		s.Failures = map[string]int{}
+	}
+	var apiErr *client.Error
+	if errors.As(err, &apiErr) {
+		s.Failures[strconv.Itoa(apiErr.StatusCode)]++
+	} else {
+		s.Failures["network"]++
+	}
+}
+
+func (s *loadStats) merge(other *loadStats) {
+	s.Requests += other.Requests
+	s.Failed += other.Failed
+	for failure, count := range other.Failures {
+		if s.Failures == nil {
+			s.Failures = map[string]int{}
+		}
+		s.Failures[failure] += count
+	}
+	s.latencies = append(s.latencies, other.latencies...)
+}
+
+// summarize computes the throughput over the seconds of the test and the
+// latency percentiles.
+func (s *loadStats) summarize(seconds float64) {
+	if seconds > 0 {
+		s.Throughput = float64(s.Requests) / seconds
+	}
+	if len(s.latencies) == 0 {
+		return
+	}
+	sort.Slice(s.latencies, func(i, j int) bool { return s.latencies[i] < s.latencies[j] })
+	var sum time.Duration
+	for _, latency := range s.latencies {
+		sum += latency
+	}
+	s.Latency = loadLatencies{
+		Min:  milliseconds(s.latencies[0]),
+		Mean: milliseconds(sum / time.Duration(len(s.latencies))),
+		P50:  milliseconds(s.percentile(50)),
+		P90:  milliseconds(s.percentile(90)),
+		P95:  milliseconds(s.percentile(95)),
+		P99:  milliseconds(s.percentile(99)),
+		Max:  milliseconds(s.latencies[len(s.latencies)-1]),
+	}
+}
+
+// percentile returns the nearest-rank percentile of the sorted latencies.
+func (s *loadStats) percentile(p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(s.latencies))))
+	if rank < 1 {
+		rank = 1
+	}
+	return s.latencies[rank-1]
+}
+
+func milliseconds(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Microsecond)) / 1000
+}
+
+// compare sets the Comparison of the result with the baseline, for the
+// total and the operations that both have.
+func (r *loadResult) compare(baseline *loadResult) {
+	r.Comparison = map[string]loadComparison{"total": compareLoadStats(r.Total, baseline.Total)}
+	for operation, stats := range r.Operations {
+		if baselineStats, ok := baseline.Operations[operation]; ok {
+			r.Comparison[operation] = compareLoadStats(stats, baselineStats)
+		}
+	}
+}
+
+func compareLoadStats(stats, baseline *loadStats) loadComparison {
+	return loadComparison{
+		Throughput: percentChange(stats.Throughput, baseline.Throughput),
+		P50:        percentChange(stats.Latency.P50, baseline.Latency.P50),
+		P99:        percentChange(stats.Latency.P99, baseline.Latency.P99),
+	}
+}
+
+func percentChange(value, baseline float64) float64 {
+	if baseline == 0 {
+		return 0
+	}
+	return math.Round((value-baseline)/baseline*1000) / 10
+}
+
+func writeLoadResult(a *App, result *loadResult) error {
+	tw := tabwriter.NewWriter(a.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "OPERATION\tREQUESTS\tFAILED\tRPS\tMEAN\tP50\tP90\tP99\tMAX\n")
+	names := append([]string(nil), loadOperations...)
+	names = append(names, "total")
+	for _, name := range names {
+		stats, ok := result.Operations[name]
+		if name == "total" {
+			stats, ok = result.Total, true
+		}
+		if !ok {
+			continue
+		}
+		l := stats.Latency
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%.2fms\t%.2fms\t%.2fms\t%.2fms\t%.2fms\n",
+			name, stats.Requests, stats.Failed, stats.Throughput, l.Mean, l.P50, l.P90, l.P99, l.Max)
+	}
+	if len(result.Comparison) > 0 {
+		fmt.Fprintf(tw, "\nAGAINST THE BASELINE\tRPS\tP50\tP99\n")
+		for _, name := range names {
+			if comparison, ok := result.Comparison[name]; ok {
+				fmt.Fprintf(tw, "%s\t%+.1f%%\t%+.1f%%\t%+.1f%%\n", name, comparison.Throughput, comparison.P50, comparison.P99)
+			}
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/storage"
+)
+
+// runMigrate copies the books of all the tenants, including the ones in the
+// trash, from the configured storage to the target storage. The books that
+// the target already has are skipped, so that an interrupted migration can
+// be run again.
+func (a *App) runMigrate(ctx context.Context, args []string) (err error) {
+	// The backup of DATA_RESTORE_PATH is restored by the service, not here.
+	cfg := *config.GetConfig()
+	cfg.DataRestorePath = ""
+	target := cfg
+	fs := a.newFlagSet("migrate", "-to-data-dir <dir> | -to-redis-url <url> [flags]")
+	fs.StringVar(&target.DataDir, "to-data-dir", "", "data directory to copy the books to")
+	fs.StringVar(&target.DataStore, "to-data-store", config.DataStoreWAL, "how the books are stored in the target data directory: wal or bolt")
+	fs.StringVar(&target.RedisURL, "to-redis-url", "", "URL of the Redis server to copy the books to, instead of a data directory")
+	fs.StringVar(&target.RedisKeyPrefix, "to-redis-key-prefix", config.DefaultRedisKeyPrefix, "prefix of the keys of the books in the target Redis server")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		fs.Usage()
+		return &usageError{}
+	}
+	switch {
+	case cfg.DataDir == "" && cfg.RedisURL == "":
+		return errors.New("no storage is configured to migrate from, set DATA_DIR or REDIS_URL")
+	case target.DataDir == "" && target.RedisURL == "":
+		return usagef("-to-data-dir or -to-redis-url is required")
+	case target.DataDir != "" && target.RedisURL != "":
+		return usagef("-to-data-dir and -to-redis-url are exclusive")
+	case target.DataStore != config.DataStoreWAL && target.DataStore != config.DataStoreBolt:
+		return usagef("-to-data-store should be one of [%s, %s]", config.DataStoreWAL, config.DataStoreBolt)
+	case target.RedisURL == "" && cfg.RedisURL == "" && target.DataDir == cfg.DataDir && target.DataStore == cfg.DataStore,
+		target.RedisURL != "" && target.RedisURL == cfg.RedisURL && target.RedisKeyPrefix == cfg.RedisKeyPrefix:
+		return usagef("the target is the configured storage")
+	}
+
+	// The books are copied as they are stored, so neither storage is given
+	// the initial data.
+	source, err := storage.OpenBookRepository(&cfg, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = closeRepository(source) }()
+	destination, err := storage.OpenBookRepository(&target, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// The books written to the target are only complete once it is closed.
+		if closeErr := closeRepository(destination); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to close the target: %w", closeErr)
+		}
+	}()
+
+	var result migration
+	if err := result.copy(ctx, source, destination); err != nil {
+		return err
+	}
+	// The books of the other tenants are stored apart from those of the
+	// default tenant.
+	sourceTenants, sourceOk := source.(models.BookTenantStore)
+	destinationTenants, destinationOk := destination.(models.BookTenantStore)
+	if !sourceOk || !destinationOk {
+		return errors.New("the storages do not store the books of the tenants apart")
+	}
+	tenants, err := sourceTenants.ListTenantIds(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list the tenants: %w", err)
+	}
+	for _, tenant := range tenants {
+		from, err := sourceTenants.OpenTenant(ctx, tenant, nil)
+		if err != nil {
+			return fmt.Errorf("failed to open the books of the tenant [%s]: %w", tenant, err)
+		}
+		to, err := destinationTenants.OpenTenant(ctx, tenant, nil)
+		if err != nil {
+			return fmt.Errorf("failed to open the books of the tenant [%s] in the target: %w", tenant, err)
+		}
+		if err := result.copy(ctx, from, to); err != nil {
+			return fmt.Errorf("failed to copy the books of the tenant [%s]: %w", tenant, err)
+		}
+	}
+	fmt.Fprintf(a.Stderr, "copied %d books, %d of them in the trash, and skipped %d that the target already has\n", result.copied, result.copiedDeleted, result.skipped)
+	return nil
+}
+
+// migration counts the books copied by runMigrate.
+type migration struct {
+	copied, copiedDeleted, skipped int
+}
+
+// copy copies the books of the source, including the ones in the trash, to
+// the destination.
+func (m *migration) copy(ctx context.Context, source, destination models.BookRepository) error {
+	books, err := source.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list the books: %w", err)
+	}
+	trash, err := source.ListDeleted(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list the books in the trash: %w", err)
+	}
+	for _, book := range append(books, trash...) {
+		_, err := destination.Add(ctx, book)
+		if errors.Is(err, repositories.ErrRecordAlreadyExists) {
+			m.skipped++
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to copy [%s]: %w", book.Id, err)
+		}
+		// The books are added out of the trash, and moved back to it from
+		// when they are copied.
+		if book.IsDeleted() {
+			if _, err := destination.DeleteById(ctx, book.Id); err != nil {
+				return fmt.Errorf("failed to move [%s] to the trash: %w", book.Id, err)
+			}
+			m.copiedDeleted++
+		}
+		m.copied++
+	}
+	return nil
+}
+
+func closeRepository(repo models.BookRepository) error {
+	if closer, ok := repo.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/auth"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/controllers"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/storage"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/tenancy"
+)
+
+// cliActor is the actor of the changes made directly on the storage in the
+// audit log.
+const cliActor = "readinglist-cli"
+
+// bookStore is where the commands manage the books of a tenant. The errors
+// of the operations that the API answers with an error status are
+// *fiber.Error with that status.
+type bookStore interface {
+	List(ctx context.Context, filter models.BookFilter) ([]models.Book, error)
+	Get(ctx context.Context, id string) (models.Book, error)
+	// Add adds the book. Unless allowDuplicate is set, a book that looks
+	// like books that are already on the reading list is rejected.
+	Add(ctx context.Context, book models.Book, allowDuplicate bool) (models.Book, error)
+	Update(ctx context.Context, book models.Book) (models.Book, error)
+	// Delete moves the book to the trash, or removes it when purge is set.
+	Delete(ctx context.Context, id string, purge bool) (models.Book, error)
+	Close() error
+}
+
+// isConflict reports whether the error is the one of a book whose id is
+// taken or that looks like books on the reading list.
+func isConflict(err error) bool {
+	var fiberErr *fiber.Error
+	var duplicateErr *controllers.DuplicateBookError
+	return errors.As(err, &fiberErr) && fiberErr.Code == http.StatusConflict || errors.As(err, &duplicateErr)
+}
+
+// directStore operates on the configured storage through the controller of
+// the service, so that the books are validated and the changes audited as
+// if they were made through the API.
+type directStore struct {
+	books   *controllers.BookController
+	tenant  string
+	closers []io.Closer
+}
+
+func openDirectStore(cfg *config.Config, tenant string) (*directStore, error) {
+	if cfg.DataDir == "" && cfg.RedisURL == "" {
+		return nil, errors.New("no storage is configured, set -server to talk to an instance, or DATA_DIR or REDIS_URL to operate on its storage")
+	}
+	store := &directStore{tenant: tenant}
+	initialData, err := config.LoadTenantInitialData(tenancy.DefaultTenant)
+	if err != nil {
+		return nil, err
+	}
+	books, err := storage.OpenBookRepository(cfg, initialData.Books)
+	if err != nil {
+		return nil, err
+	}
+	if closer, ok := books.(io.Closer); ok {
+		store.closers = append(store.closers, closer)
+	}
+	tenantBooks, err := storage.NewTenantBookRepository(cfg, books)
+	if err != nil {
+		_ = store.Close()
+		return nil, err
+	}
+	audit, err := storage.OpenAuditRepository(cfg)
+	if err != nil {
+		_ = store.Close()
+		return nil, err
+	}
+	if closer, ok := audit.(io.Closer); ok {
+		store.closers = append(store.closers, closer)
+	}
+	store.books = controllers.NewBookController(tenantBooks, audit)
+	// The covers and the highlights of the purged books are removed as they
+	// are by the service.
+	covers, err := storage.OpenCoverRepository(cfg)
+	if err != nil {
+		_ = store.Close()
+		return nil, err
+	}
+	controllers.NewCoverController(store.books, covers, cfg.CoverMaxSize)
+	highlights, err := storage.OpenHighlightRepository(cfg)
+	if err != nil {
+		_ = store.Close()
+		return nil, err
+	}
+	store.closers = append(store.closers, highlights)
+	controllers.NewHighlightController(store.books, highlights)
+	return store, nil
+}
+
+// context returns the context of the operations, which are made by the
+// cliActor in the tenant of the store.
+func (s *directStore) context(ctx context.Context) context.Context {
+	ctx = auth.WithPrincipal(ctx, &auth.Principal{Subject: cliActor})
+	if s.tenant != "" {
+		ctx = tenancy.WithTenant(ctx, s.tenant)
+	}
+	return ctx
+}
+
+// List returns the books in the order they were added, like the pages of
+// the API.
+func (s *directStore) List(ctx context.Context, filter models.BookFilter) ([]models.Book, error) {
+	var books []models.Book
+	for offset := 0; ; offset += listPageLimit {
+		page, err := s.books.ListBooksPage(s.context(ctx), filter, offset, listPageLimit)
+		if err != nil {
+			return nil, err
+		}
+		books = append(books, page.Items...)
+		if page.NextOffset == nil {
+			return books, nil
+		}
+	}
+}
+
+func (s *directStore) Get(ctx context.Context, id string) (models.Book, error) {
+	return s.books.GetBook(s.context(ctx), id)
+}
+
+func (s *directStore) Add(ctx context.Context, book models.Book, allowDuplicate bool) (models.Book, error) {
+	return s.books.AddBook(s.context(ctx), book, allowDuplicate)
+}
+
+func (s *directStore) Update(ctx context.Context, book models.Book) (models.Book, error) {
+	return s.books.UpdateBook(s.context(ctx), book)
+}
+
+func (s *directStore) Delete(ctx context.Context, id string, purge bool) (models.Book, error) {
+	if purge {
+		return s.books.PurgeBook(s.context(ctx), id)
+	}
+	return s.books.DeleteBook(s.context(ctx), id)
+}
+
+// Close releases the storage, in the reverse order it was opened.
+func (s *directStore) Close() error {
+	var errs []error
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		errs = append(errs, s.closers[i].Close())
+	}
+	s.closers = nil
+	return errors.Join(errs...)
+}
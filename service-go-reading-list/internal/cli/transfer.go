@@ -0,0 +1,164 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/invopop/yaml"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/tenancy"
+)
+
+func (a *App) runImport(ctx context.Context, args []string) error {
+	var opts options
+	fs := a.newFlagSet("import", "<file> [flags]")
+	opts.register(fs, OutputTable)
+	allowDuplicate := fs.Bool("allow-duplicate", false, "add the books even when they look like books on the reading list")
+	positional, err := parseArgs(fs, args, 1, &opts, OutputTable)
+	if err != nil {
+		return err
+	}
+	var contents []byte
+	if positional[0] == "-" {
+		contents, err = io.ReadAll(a.Stdin)
+	} else {
+		contents, err = os.ReadFile(positional[0])
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read the books: %w", err)
+	}
+	books, err := decodeBooks(contents)
+	if err != nil {
+		return fmt.Errorf("failed to decode the books of [%s]: %w", positional[0], err)
+	}
+	return a.withStore(&opts, func(store bookStore) error {
+		return a.addBooks(ctx, store, books, *allowDuplicate)
+	})
+}
+
+func (a *App) runSeed(ctx context.Context, args []string) error {
+	var opts options
+	fs := a.newFlagSet("seed", "[flags]")
+	opts.register(fs, OutputTable)
+	path := fs.String("file", "", "file of the books to seed, instead of the initial data of the tenant")
+	allowDuplicate := fs.Bool("allow-duplicate", false, "add the books even when they look like books on the reading list")
+	if _, err := parseArgs(fs, args, 0, &opts, OutputTable); err != nil {
+		return err
+	}
+	var books []models.Book
+	if *path != "" {
+		contents, err := os.ReadFile(*path)
+		if err != nil {
+			return fmt.Errorf("failed to read the books: %w", err)
+		}
+		if books, err = decodeBooks(contents); err != nil {
+			return fmt.Errorf("failed to decode the books of [%s]: %w", *path, err)
+		}
+	} else {
+		tenant := opts.tenant
+		if tenant == "" {
+			tenant = tenancy.DefaultTenant
+		}
+		initialData, err := config.LoadTenantInitialData(tenant)
+		if err != nil {
+			return err
+		}
+		books = initialData.Books
+	}
+	return a.withStore(&opts, func(store bookStore) error {
+		return a.addBooks(ctx, store, books, *allowDuplicate)
+	})
+}
+
+func (a *App) runExport(ctx context.Context, args []string) error {
+	var opts options
+	fs := a.newFlagSet("export", "[flags]")
+	opts.register(fs, OutputJSON)
+	path := fs.String("file", "", "file to write the books to, instead of stdout")
+	if _, err := parseArgs(fs, args, 0, &opts, OutputJSON, OutputYAML); err != nil {
+		return err
+	}
+	return a.withStore(&opts, func(store bookStore) error {
+		books, err := store.List(ctx, models.BookFilter{})
+		if err != nil {
+			return err
+		}
+		// The books are exported in the format of the initial data, so that
+		// they can be seeded or imported again.
+		data := config.InitialData{Books: books}
+		if data.Books == nil {
+			data.Books = []models.Book{}
+		}
+		if *path == "" {
+			return writeValue(a.Stdout, opts.output, data)
+		}
+		var buf bytes.Buffer
+		if err := writeValue(&buf, opts.output, data); err != nil {
+			return err
+		}
+		if err := os.WriteFile(*path, buf.Bytes(), 0o600); err != nil {
+			return fmt.Errorf("failed to write the books: %w", err)
+		}
+		fmt.Fprintf(a.Stderr, "exported %d books to [%s]\n", len(books), *path)
+		return nil
+	})
+}
+
+// addBooks adds the books to the store and reports what was done on
+// stderr. The books that conflict with the ones in the store, because their
+// id is taken or they look like duplicates, are skipped, so that the books
+// can be added again.
+func (a *App) addBooks(ctx context.Context, store bookStore, books []models.Book, allowDuplicate bool) error {
+	added, skipped, failed := 0, 0, 0
+	for _, book := range books {
+		name := book.Id
+		if name == "" {
+			name = book.Title
+		}
+		_, err := store.Add(ctx, book, allowDuplicate)
+		switch {
+		case err == nil:
+			added++
+		case isConflict(err):
+			skipped++
+			fmt.Fprintf(a.Stderr, "skipped [%s]: %s\n", name, err)
+		default:
+			failed++
+			fmt.Fprintf(a.Stderr, "failed to add [%s]: %s\n", name, err)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+	}
+	fmt.Fprintf(a.Stderr, "added %d books, skipped %d and failed %d\n", added, skipped, failed)
+	if failed > 0 {
+		return fmt.Errorf("failed to add %d of the %d books", failed, len(books))
+	}
+	return nil
+}
+
+// decodeBooks decodes the books of a JSON or YAML document, which is either
+// in the format of the initial data or a list of books.
+func decodeBooks(contents []byte) ([]models.Book, error) {
+	data, err := yaml.YAMLToJSON(contents)
+	if err != nil {
+		return nil, err
+	}
+	if data = bytes.TrimSpace(data); len(data) > 0 && data[0] == '[' {
+		var books []models.Book
+		err := json.Unmarshal(data, &books)
+		return books, err
+	}
+	var initialData config.InitialData
+	err = json.Unmarshal(data, &initialData)
+	return initialData.Books, err
+}
@@ -4,6 +4,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
 )
 
@@ -19,6 +21,137 @@ type Config struct {
 	// InitialDataPath sets the path to load the initial data file.
 	// Refer to the InitialData struct for the file format.
 	InitialDataPath string
+	// CataloguePath sets the path to load the catalogue of books that are
+	// recommended in addition to the ones on the reading list. It has the
+	// format of the initial data file.
+	CataloguePath string
+	// TrashRetention sets how long deleted books are kept in the trash
+	// before they are permanently removed.
+	TrashRetention time.Duration
+	// TrashPurgeInterval sets how often the trash is checked for books
+	// older than TrashRetention.
+	TrashPurgeInterval time.Duration
+	// DataDir sets the directory where the books are persisted as a snapshot
+	// and a write-ahead log. The books are kept in memory only when it is empty.
+	DataDir string
+	// DataStore selects how the books are stored in DataDir: "wal" as a
+	// snapshot and a write-ahead log, "bolt" in an embedded bbolt store
+	// indexed by status and author.
+	DataStore string
+	// DataRestorePath sets the backup of a bolt store that the books are
+	// restored from at startup when DataDir has no store yet.
+	DataRestorePath string
+	// RedisURL sets the redis://[:password@]host[:port][/db] URL of the Redis
+	// that stores the books, so that the replicas of the service share them.
+	// It takes precedence over DataDir for the books.
+	RedisURL string
+	// RedisKeyPrefix namespaces the keys of the books in Redis.
+	RedisKeyPrefix string
+	// SnapshotInterval sets how often the write-ahead log is compacted into
+	// a new snapshot when DataDir is set.
+	SnapshotInterval time.Duration
+	// IdempotencyTTL sets how long the response to a request with an
+	// Idempotency-Key header is replayed for retries with the same key.
+	IdempotencyTTL time.Duration
+	// RequestTimeout sets the deadline of the context of every API request,
+	// which is answered with 504 when it is exceeded.
+	RequestTimeout time.Duration
+	// Auth configures how the security requirements declared in
+	// docs/openapi.yaml are enforced.
+	Auth AuthConfig
+	// OpenAPIValidation selects how the requests and responses of the API are
+	// validated against docs/openapi.yaml: "request" validates the requests,
+	// "strict" validates the responses too. Validation is disabled when it is
+	// empty.
+	OpenAPIValidation string
+	// OpenAPIValidationFailOnDrift replaces the responses that do not conform
+	// to docs/openapi.yaml with a 500 in the strict mode, instead of only
+	// logging them.
+	OpenAPIValidationFailOnDrift bool
+	// ResponseCacheSize sets how many responses of the book list and get
+	// endpoints are kept in memory. Responses are not cached when it is zero.
+	ResponseCacheSize int
+	// ResponseCacheMaxAge sets the max-age of the Cache-Control header of the
+	// cached endpoints. The clients revalidate every response when it is zero.
+	ResponseCacheMaxAge time.Duration
+	// CoverMaxSize sets the maximum size in bytes of the uploaded cover
+	// images. The requests are also limited by the body limit of the server.
+	CoverMaxSize int64
+	// CoverMaxAge sets the max-age of the Cache-Control header of the cover
+	// images. The clients revalidate every cover when it is zero.
+	CoverMaxAge time.Duration
+	// V1DeprecatedAt and V1SunsetAt announce the deprecation of the version 1
+	// API in the Deprecation and Sunset headers of its responses. The headers
+	// are not set when they are zero.
+	V1DeprecatedAt time.Time
+	V1SunsetAt     time.Time
+	// Tenancy configures how the data of the tenants sharing the service is isolated.
+	Tenancy TenancyConfig
+}
+
+const (
+	DataStoreWAL  = "wal"
+	DataStoreBolt = "bolt"
+)
+
+const (
+	AuthModeJWT           = "jwt"
+	AuthModeIntrospection = "introspection"
+)
+
+const (
+	OpenAPIValidationRequest = "request"
+	OpenAPIValidationStrict  = "strict"
+)
+
+type AuthConfig struct {
+	// Mode selects how bearer tokens are validated: "jwt" validates them
+	// locally, "introspection" calls IntrospectionURL. Authorization is
+	// disabled when it is empty.
+	Mode string
+	// JWTSecret is the shared secret of HMAC signed tokens.
+	JWTSecret string
+	// JWTPublicKeyPath is the path of the PEM encoded public key of
+	// asymmetrically signed tokens. It is used when JWTSecret is empty.
+	JWTPublicKeyPath string
+	// JWTIssuer and JWTAudience, when set, must match the iss and aud claims.
+	JWTIssuer   string
+	JWTAudience string
+	// IntrospectionURL is the RFC 7662 token introspection endpoint.
+	IntrospectionURL string
+	// IntrospectionClientId and IntrospectionClientSecret authenticate the
+	// service to the introspection endpoint.
+	IntrospectionClientId     string
+	IntrospectionClientSecret string
+}
+
+const (
+	TenantModeHeader = "header"
+	TenantModeHost   = "host"
+	TenantModeClaim  = "claim"
+)
+
+type TenancyConfig struct {
+	// Mode selects where the tenant of a request is read from: "header"
+	// reads the Header header, "host" the subdomain of HostSuffix and
+	// "claim" the Claim claim of the bearer token. All the requests are
+	// served for the default tenant when it is empty.
+	Mode       string
+	Header     string
+	HostSuffix string
+	Claim      string
+	// FallbackTenant is the tenant of the requests that do not name one.
+	// They are rejected when it is empty.
+	FallbackTenant string
+	// InitialDataDir sets the directory of the initial data of the tenants,
+	// one <tenant>.json file per tenant in the format of the initial data
+	// file. A tenant without a file starts with the initial data.
+	InitialDataDir string
+	// BookQuota sets how many books, including the ones in the trash, a
+	// tenant can have. The books are not limited when it is zero.
+	BookQuota int
+	// BookQuotas overrides BookQuota for some tenants.
+	BookQuotas map[string]int
 }
 
 type InitialData struct {
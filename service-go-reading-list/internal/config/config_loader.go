@@ -5,14 +5,35 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/redis"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/tenancy"
 )
 
 const (
 	DefaultPort     = 8080
 	DefaultHostname = "localhost"
+
+	DefaultTrashRetention     = 30 * 24 * time.Hour
+	DefaultTrashPurgeInterval = time.Hour
+	DefaultSnapshotInterval   = 5 * time.Minute
+	DefaultIdempotencyTTL     = 24 * time.Hour
+	DefaultRequestTimeout     = 30 * time.Second
+	DefaultResponseCacheSize  = 1000
+	DefaultCoverMaxSize       = 2 << 20
+	DefaultRedisKeyPrefix     = "reading-list:"
+	DefaultTenantHeader       = "X-Tenant-Id"
+	DefaultTenantClaim        = "org"
 )
 
 var (
@@ -20,38 +41,245 @@ var (
 	Hostname        = "HOSTNAME"
 	Port            = "PORT"
 	initialDataPath = "INIT_DATA_PATH"
+	CataloguePath   = "CATALOGUE_PATH"
+
+	TrashRetention     = "TRASH_RETENTION"
+	TrashPurgeInterval = "TRASH_PURGE_INTERVAL"
+	DataDir            = "DATA_DIR"
+	DataStore          = "DATA_STORE"
+	DataRestorePath    = "DATA_RESTORE_PATH"
+	SnapshotInterval   = "SNAPSHOT_INTERVAL"
+	RedisURL           = "REDIS_URL"
+	RedisKeyPrefix     = "REDIS_KEY_PREFIX"
+	IdempotencyTTL     = "IDEMPOTENCY_TTL"
+	RequestTimeout     = "REQUEST_TIMEOUT"
+
+	AuthMode                      = "AUTH_MODE"
+	AuthJWTSecret                 = "AUTH_JWT_SECRET"
+	AuthJWTPublicKeyPath          = "AUTH_JWT_PUBLIC_KEY_PATH"
+	AuthJWTIssuer                 = "AUTH_JWT_ISSUER"
+	AuthJWTAudience               = "AUTH_JWT_AUDIENCE"
+	AuthIntrospectionURL          = "AUTH_INTROSPECTION_URL"
+	AuthIntrospectionClientId     = "AUTH_INTROSPECTION_CLIENT_ID"
+	AuthIntrospectionClientSecret = "AUTH_INTROSPECTION_CLIENT_SECRET"
+
+	OpenAPIValidation            = "OPENAPI_VALIDATION"
+	OpenAPIValidationFailOnDrift = "OPENAPI_VALIDATION_FAIL_ON_DRIFT"
+
+	ResponseCacheSize   = "RESPONSE_CACHE_SIZE"
+	ResponseCacheMaxAge = "RESPONSE_CACHE_MAX_AGE"
+
+	CoverMaxSize = "COVER_MAX_SIZE"
+	CoverMaxAge  = "COVER_MAX_AGE"
+
+	V1DeprecatedAt = "API_V1_DEPRECATED_AT"
+	V1SunsetAt     = "API_V1_SUNSET_AT"
+
+	TenantMode           = "TENANT_MODE"
+	TenantHeader         = "TENANT_HEADER"
+	TenantHostSuffix     = "TENANT_HOST_SUFFIX"
+	TenantClaim          = "TENANT_CLAIM"
+	TenantFallback       = "TENANT_FALLBACK"
+	TenantInitialDataDir = "TENANT_INIT_DATA_DIR"
+	TenantBookQuota      = "TENANT_BOOK_QUOTA"
+	TenantBookQuotas     = "TENANT_BOOK_QUOTAS"
 )
 
-var config Config
+// current is the configuration loaded last. LoadConfig replaces it rather
+// than changing it, so that the configuration a caller got does not change
+// under it.
+var current atomic.Pointer[Config]
 
 func GetConfig() *Config {
-	return &config
+	if config := current.Load(); config != nil {
+		return config
+	}
+	return &Config{}
 }
 
 func LoadConfig() (*Config, error) {
-	config = Config{
+	config := Config{
 		Hostname:        getEnvString(Hostname, DefaultHostname),
 		Port:            getEnvInt(Port, DefaultPort),
 		Env:             os.Getenv(EnvName),
 		InitialDataPath: os.Getenv(initialDataPath),
+		CataloguePath:   os.Getenv(CataloguePath),
+
+		TrashRetention:     getEnvDuration(TrashRetention, DefaultTrashRetention),
+		TrashPurgeInterval: getEnvDuration(TrashPurgeInterval, DefaultTrashPurgeInterval),
+		DataDir:            os.Getenv(DataDir),
+		DataStore:          getEnvString(DataStore, DataStoreWAL),
+		DataRestorePath:    os.Getenv(DataRestorePath),
+		SnapshotInterval:   getEnvDuration(SnapshotInterval, DefaultSnapshotInterval),
+		RedisURL:           os.Getenv(RedisURL),
+		RedisKeyPrefix:     getEnvString(RedisKeyPrefix, DefaultRedisKeyPrefix),
+		IdempotencyTTL:     getEnvDuration(IdempotencyTTL, DefaultIdempotencyTTL),
+		RequestTimeout:     getEnvDuration(RequestTimeout, DefaultRequestTimeout),
+		Auth: AuthConfig{
+			Mode:                      os.Getenv(AuthMode),
+			JWTSecret:                 os.Getenv(AuthJWTSecret),
+			JWTPublicKeyPath:          os.Getenv(AuthJWTPublicKeyPath),
+			JWTIssuer:                 os.Getenv(AuthJWTIssuer),
+			JWTAudience:               os.Getenv(AuthJWTAudience),
+			IntrospectionURL:          os.Getenv(AuthIntrospectionURL),
+			IntrospectionClientId:     os.Getenv(AuthIntrospectionClientId),
+			IntrospectionClientSecret: os.Getenv(AuthIntrospectionClientSecret),
+		},
+		OpenAPIValidation:            os.Getenv(OpenAPIValidation),
+		OpenAPIValidationFailOnDrift: getEnvBool(OpenAPIValidationFailOnDrift, false),
+		ResponseCacheSize:            getEnvInt(ResponseCacheSize, DefaultResponseCacheSize),
+		ResponseCacheMaxAge:          getEnvDuration(ResponseCacheMaxAge, 0),
+		CoverMaxSize:                 int64(getEnvInt(CoverMaxSize, DefaultCoverMaxSize)),
+		CoverMaxAge:                  getEnvDuration(CoverMaxAge, 0),
+		V1DeprecatedAt:               getEnvTime(V1DeprecatedAt),
+		V1SunsetAt:                   getEnvTime(V1SunsetAt),
+		Tenancy: TenancyConfig{
+			Mode:           os.Getenv(TenantMode),
+			Header:         getEnvString(TenantHeader, DefaultTenantHeader),
+			HostSuffix:     os.Getenv(TenantHostSuffix),
+			Claim:          getEnvString(TenantClaim, DefaultTenantClaim),
+			FallbackTenant: os.Getenv(TenantFallback),
+			InitialDataDir: os.Getenv(TenantInitialDataDir),
+			BookQuota:      getEnvInt(TenantBookQuota, 0),
+			BookQuotas:     getEnvQuotas(TenantBookQuotas),
+		},
+	}
+	if config.TrashRetention <= 0 {
+		return nil, fmt.Errorf("%s should be positive", TrashRetention)
+	}
+	if config.TrashPurgeInterval <= 0 {
+		return nil, fmt.Errorf("%s should be positive", TrashPurgeInterval)
+	}
+	if config.SnapshotInterval <= 0 {
+		return nil, fmt.Errorf("%s should be positive", SnapshotInterval)
+	}
+	if config.RequestTimeout <= 0 {
+		return nil, fmt.Errorf("%s should be positive", RequestTimeout)
+	}
+	if config.IdempotencyTTL <= 0 {
+		return nil, fmt.Errorf("%s should be positive", IdempotencyTTL)
+	}
+	switch config.DataStore {
+	case DataStoreWAL:
+		if config.DataRestorePath != "" {
+			return nil, fmt.Errorf("%s requires %s to be [%s]", DataRestorePath, DataStore, DataStoreBolt)
+		}
+	case DataStoreBolt:
+		if config.DataDir == "" {
+			return nil, fmt.Errorf("%s is required when %s is [%s]", DataDir, DataStore, DataStoreBolt)
+		}
+	default:
+		return nil, fmt.Errorf("%s should be one of [%s, %s]", DataStore, DataStoreWAL, DataStoreBolt)
+	}
+	if config.RedisURL != "" {
+		if _, err := redis.ParseURL(config.RedisURL); err != nil {
+			return nil, fmt.Errorf("%s should be a valid URL: %w", RedisURL, err)
+		}
+	}
+	switch config.Auth.Mode {
+	case "", AuthModeJWT:
+	case AuthModeIntrospection:
+		if config.Auth.IntrospectionURL == "" {
+			return nil, fmt.Errorf("%s is required when %s is [%s]", AuthIntrospectionURL, AuthMode, AuthModeIntrospection)
+		}
+	default:
+		return nil, fmt.Errorf("%s should be one of [%s, %s]", AuthMode, AuthModeJWT, AuthModeIntrospection)
 	}
+	switch config.OpenAPIValidation {
+	case "", OpenAPIValidationRequest, OpenAPIValidationStrict:
+	default:
+		return nil, fmt.Errorf("%s should be one of [%s, %s]", OpenAPIValidation, OpenAPIValidationRequest, OpenAPIValidationStrict)
+	}
+	if config.CoverMaxSize <= 0 {
+		return nil, fmt.Errorf("%s should be positive", CoverMaxSize)
+	}
+	if !config.V1SunsetAt.IsZero() && config.V1SunsetAt.Before(config.V1DeprecatedAt) {
+		return nil, fmt.Errorf("%s should not be before %s", V1SunsetAt, V1DeprecatedAt)
+	}
+	switch config.Tenancy.Mode {
+	case "", TenantModeHeader, TenantModeClaim:
+	case TenantModeHost:
+		if config.Tenancy.HostSuffix == "" {
+			return nil, fmt.Errorf("%s is required when %s is [%s]", TenantHostSuffix, TenantMode, TenantModeHost)
+		}
+	default:
+		return nil, fmt.Errorf("%s should be one of [%s, %s, %s]", TenantMode, TenantModeHeader, TenantModeHost, TenantModeClaim)
+	}
+	if config.Tenancy.FallbackTenant != "" {
+		fallback, ok := tenancy.NormalizeId(config.Tenancy.FallbackTenant)
+		if !ok {
+			return nil, fmt.Errorf("%s should be a valid tenant id", TenantFallback)
+		}
+		config.Tenancy.FallbackTenant = fallback
+	}
+	current.Store(&config)
 	return &config, nil
 }
 
-func LoadInitialData() (data InitialData) {
+func LoadInitialData() InitialData {
+	data, err := loadInitialData()
+	if err != nil {
+		log.Fatal(err)
+	}
+	return data
+}
+
+func loadInitialData() (data InitialData, err error) {
+	config := GetConfig()
 	if config.InitialDataPath == "" {
 		return
 	}
 	contents, err := os.ReadFile(config.InitialDataPath)
 	if err != nil {
-		log.Fatalf("failed to read initial data at [%s]: %s", config.InitialDataPath, err)
+		return data, fmt.Errorf("failed to read initial data at [%s]: %w", config.InitialDataPath, err)
 	}
 	if err := json.Unmarshal(contents, &data); err != nil {
-		log.Fatalf("failed to unmarshal initial data at [%s]: %s", config.InitialDataPath, err)
+		return data, fmt.Errorf("failed to unmarshal initial data at [%s]: %w", config.InitialDataPath, err)
 	}
 	return
 }
 
+// LoadTenantInitialData returns the initial data of the tenant, which is
+// the initial data when the tenant has no file of its own. The tenants are
+// given their initial data while they are served, so the files that cannot
+// be read are reported rather than fatal.
+func LoadTenantInitialData(tenant string) (InitialData, error) {
+	config := GetConfig()
+	if config.Tenancy.InitialDataDir == "" {
+		return loadInitialData()
+	}
+	path := filepath.Join(config.Tenancy.InitialDataDir, tenant+".json")
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return loadInitialData()
+	} else if err != nil {
+		return InitialData{}, fmt.Errorf("failed to read the initial data of the tenant [%s] at [%s]: %w", tenant, path, err)
+	}
+	var data InitialData
+	if err := json.Unmarshal(contents, &data); err != nil {
+		return InitialData{}, fmt.Errorf("failed to unmarshal the initial data of the tenant [%s] at [%s]: %w", tenant, path, err)
+	}
+	return data, nil
+}
+
+// LoadCatalogue returns the books of the catalogue, which are none when no
+// catalogue is configured.
+func LoadCatalogue() []models.Book {
+	config := GetConfig()
+	if config.CataloguePath == "" {
+		return nil
+	}
+	contents, err := os.ReadFile(config.CataloguePath)
+	if err != nil {
+		log.Fatalf("failed to read the catalogue at [%s]: %s", config.CataloguePath, err)
+	}
+	var catalogue InitialData
+	if err := json.Unmarshal(contents, &catalogue); err != nil {
+		log.Fatalf("failed to unmarshal the catalogue at [%s]: %s", config.CataloguePath, err)
+	}
+	return catalogue.Books
+}
+
 func getEnvInt(key string, defaultVal int) int {
 	s := os.Getenv(key)
 	if s == "" {
@@ -64,6 +292,66 @@ func getEnvInt(key string, defaultVal int) int {
 	return v
 }
 
+func getEnvDuration(key string, defaultVal time.Duration) time.Duration {
+	s := os.Getenv(key)
+	if s == "" {
+		return defaultVal
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		log.Panic(err)
+	}
+	return v
+}
+
+func getEnvBool(key string, defaultVal bool) bool {
+	s := os.Getenv(key)
+	if s == "" {
+		return defaultVal
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		log.Panic(err)
+	}
+	return v
+}
+
+// getEnvTime parses an RFC 3339 time, returning the zero time when the
+// variable is not set.
+func getEnvTime(key string) time.Time {
+	s := os.Getenv(key)
+	if s == "" {
+		return time.Time{}
+	}
+	v, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		log.Panic(err)
+	}
+	return v
+}
+
+// getEnvQuotas parses a comma separated list of tenant=quota pairs.
+func getEnvQuotas(key string) map[string]int {
+	quotas := make(map[string]int)
+	s := os.Getenv(key)
+	if s == "" {
+		return quotas
+	}
+	for _, pair := range strings.Split(s, ",") {
+		tenant, quota, ok := strings.Cut(pair, "=")
+		if !ok {
+			log.Panicf("%s should be a comma separated list of tenant=quota pairs", key)
+		}
+		v, err := strconv.Atoi(strings.TrimSpace(quota))
+		if err != nil {
+			log.Panic(err)
+		}
+		tenant, _ = tenancy.NormalizeId(tenant)
+		quotas[tenant] = v
+	}
+	return quotas
+}
+
 func getEnvString(key string, defaultVal string) string {
 	s := os.Getenv(key)
 	if s == "" {
@@ -0,0 +1,108 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/auth"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/tenancy"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+const (
+	// anonymousActor is the actor of the changes made without a token.
+	anonymousActor = "anonymous"
+	// systemActor is the actor of the changes made by the service itself,
+	// such as purging the expired books from the trash.
+	systemActor = "system"
+)
+
+// ListAuditEntries returns the audit entries of the tenant of the context
+// that match the filter, oldest first.
+func (c *BookController) ListAuditEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	filter.Tenant = tenancy.FromContext(ctx)
+	entries, err := c.auditRepository.List(ctx, filter)
+	if err != nil {
+		return nil, makeHttpUnexpectedError(err)
+	}
+	return entries, nil
+}
+
+// audit records the changes in the audit log. The changes have already been
+// applied, so they are recorded even when the request has since timed out,
+// and a failure to record them is logged instead of returned.
+func (c *BookController) audit(ctx context.Context, entries ...models.AuditEntry) {
+	if err := c.auditRepository.Append(context.WithoutCancel(ctx), entries...); err != nil {
+		logrus.WithFields(logrus.Fields{"entries": len(entries)}).Errorf("failed to record the changes in the audit log: %v", err)
+	}
+}
+
+// newAuditEntry returns the audit entry of the change of a book from before
+// to after, made by the caller of the request of the context. before is nil
+// for an added book and after is nil for a purged book.
+func newAuditEntry(ctx context.Context, operation models.AuditOperation, before, after *models.Book) models.AuditEntry {
+	entry := models.AuditEntry{
+		Timestamp:     time.Now().UTC(),
+		Tenant:        tenancy.FromContext(ctx),
+		Actor:         auditActor(ctx),
+		CorrelationId: utils.GetCorrelationId(ctx),
+		Operation:     operation,
+		Changes:       diffBooks(before, after),
+	}
+	if after != nil {
+		entry.BookId = after.Id
+	} else if before != nil {
+		entry.BookId = before.Id
+	}
+	return entry
+}
+
+func auditActor(ctx context.Context) string {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	switch {
+	case !ok:
+		return anonymousActor
+	case principal.Subject != "":
+		return principal.Subject
+	case principal.ClientId != "":
+		return principal.ClientId
+	}
+	return anonymousActor
+}
+
+// diffBooks returns the fields of the JSON representations of the books that
+// differ, with their values in each book.
+func diffBooks(before, after *models.Book) map[string]models.AuditChange {
+	beforeFields, afterFields := bookFields(before), bookFields(after)
+	changes := make(map[string]models.AuditChange)
+	for name, value := range beforeFields {
+		if !reflect.DeepEqual(value, afterFields[name]) {
+			changes[name] = models.AuditChange{Before: value, After: afterFields[name]}
+		}
+	}
+	for name, value := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			changes[name] = models.AuditChange{After: value}
+		}
+	}
+	return changes
+}
+
+func bookFields(book *models.Book) map[string]interface{} {
+	fields := make(map[string]interface{})
+	if book == nil {
+		return fields
+	}
+	// A book always has a JSON representation.
+	data, _ := json.Marshal(book)
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
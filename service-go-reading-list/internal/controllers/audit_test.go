@@ -0,0 +1,86 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/auth"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories"
+)
+
+func TestBookControllerAudit(t *testing.T) {
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "alice"})
+	controller := NewBookController(repositories.NewBookRepository(nil), repositories.NewAuditRepository())
+	operations := func(entries []models.AuditEntry) []models.AuditOperation {
+		var operations []models.AuditOperation
+		for _, entry := range entries {
+			operations = append(operations, entry.Operation)
+		}
+		return operations
+	}
+
+	book, err := controller.AddBook(ctx, models.Book{Id: "1", Title: "Dune"}, false)
+	require.NoError(t, err)
+	_, err = controller.UpdateBook(context.Background(), models.Book{Id: "1", Title: "Dune", Author: "Frank Herbert"})
+	require.NoError(t, err)
+	_, err = controller.DeleteBook(ctx, "1")
+	require.NoError(t, err)
+	_, err = controller.RestoreBook(ctx, "1")
+	require.NoError(t, err)
+	// Failed changes are not recorded.
+	_, err = controller.UpdateBook(ctx, models.Book{Id: "missing", Title: "Missing"})
+	assert.Error(t, err)
+	_, err = controller.ExecuteBatch(ctx, []models.BatchOperation{
+		{Op: models.BatchOperationCreate, Book: &models.Book{Id: "2", Title: "Emma"}},
+		{Op: models.BatchOperationDelete, Id: "missing"},
+	})
+	assert.Error(t, err)
+	_, err = controller.ExecuteBatch(ctx, []models.BatchOperation{
+		{Op: models.BatchOperationCreate, Book: &models.Book{Id: "2", Title: "Emma"}},
+		{Op: models.BatchOperationDelete, Id: "1"},
+	})
+	require.NoError(t, err)
+	_, err = controller.PurgeBook(ctx, "1")
+	require.NoError(t, err)
+
+	entries, err := controller.ListAuditEntries(ctx, models.AuditFilter{BookId: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, []models.AuditOperation{
+		models.AuditOperationAdd, models.AuditOperationUpdate, models.AuditOperationDelete,
+		models.AuditOperationRestore, models.AuditOperationDelete, models.AuditOperationPurge,
+	}, operations(entries))
+
+	added := entries[0]
+	assert.Equal(t, "alice", added.Actor)
+	assert.Equal(t, "1", added.BookId)
+	assert.Equal(t, models.AuditChange{After: "Dune"}, added.Changes["title"])
+	assert.Equal(t, models.AuditChange{After: string(models.ReadStatusToRead)}, added.Changes["status"])
+
+	// The update was made without a token and only changed the author and the update time.
+	updated := entries[1]
+	assert.Equal(t, anonymousActor, updated.Actor)
+	assert.Equal(t, models.AuditChange{Before: "", After: "Frank Herbert"}, updated.Changes["author"])
+	assert.Equal(t, book.UpdatedAt.Format(time.RFC3339Nano), updated.Changes["updatedAt"].Before)
+	assert.Len(t, updated.Changes, 2)
+
+	deleted, restored := entries[2], entries[3]
+	assert.Nil(t, deleted.Changes["deletedAt"].Before)
+	assert.NotNil(t, deleted.Changes["deletedAt"].After)
+	assert.Equal(t, deleted.Changes["deletedAt"].After, restored.Changes["deletedAt"].Before)
+	assert.Nil(t, restored.Changes["deletedAt"].After)
+
+	purged := entries[5]
+	assert.Equal(t, "Dune", purged.Changes["title"].Before)
+	assert.Nil(t, purged.Changes["title"].After)
+
+	entries, err = controller.ListAuditEntries(ctx, models.AuditFilter{BookId: "2"})
+	require.NoError(t, err)
+	assert.Equal(t, []models.AuditOperation{models.AuditOperationAdd}, operations(entries))
+}
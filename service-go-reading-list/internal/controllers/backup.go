@@ -0,0 +1,39 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories"
+)
+
+// Backup writes a consistent copy of the books of all the tenants to w,
+// which the service can be restored from at startup.
+func (c *BookController) Backup(ctx context.Context, w io.Writer) error {
+	backuper, ok := c.bookRepository.(models.BookBackuper)
+	if !ok {
+		return makeHttpBackupNotSupportedError()
+	}
+	n, err := backuper.Backup(ctx, w)
+	if errors.Is(err, repositories.ErrNotSupported) {
+		return makeHttpBackupNotSupportedError()
+	} else if err != nil {
+		logrus.WithError(err).WithField("bytes", n).Error("failed to back up the books")
+		return makeHttpUnexpectedError(err)
+	}
+	logrus.WithField("bytes", n).Info("backed up the books")
+	return nil
+}
+
+func makeHttpBackupNotSupportedError() *fiber.Error {
+	return fiber.NewError(http.StatusNotImplemented, "backups are not supported by the configured storage")
+}
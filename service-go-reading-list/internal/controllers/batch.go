@@ -0,0 +1,111 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories"
+)
+
+// MaxBatchOperations is the maximum number of operations accepted in a single batch.
+const MaxBatchOperations = 100
+
+// ExecuteBatch applies the operations all-or-nothing. The returned results
+// always have one entry per operation; when an operation fails, the error is
+// the failure of that operation and the other operations are reported as not applied.
+func (c *BookController) ExecuteBatch(ctx context.Context, operations []models.BatchOperation) ([]models.BatchOperationResult, error) {
+	if len(operations) == 0 {
+		return nil, fiber.NewError(http.StatusBadRequest, "at least one operation is required")
+	}
+	if len(operations) > MaxBatchOperations {
+		return nil, fiber.NewError(http.StatusBadRequest, fmt.Sprintf("a batch can have at most %d operations", MaxBatchOperations))
+	}
+	transactor, ok := c.bookRepository.(models.BookTransactor)
+	if !ok {
+		return nil, fiber.NewError(http.StatusNotImplemented, "batch operations are not supported by the configured storage")
+	}
+
+	results := make([]models.BatchOperationResult, len(operations))
+	failed := -1
+	// The changes are recorded in the audit log once they are committed.
+	pending := repositories.NewAuditRepository()
+	err := transactor.RunInTransaction(ctx, func(tx models.BookRepository) error {
+		txController := NewBookController(tx, pending)
+		for i, operation := range operations {
+			book, err := txController.executeBatchOperation(ctx, operation)
+			if err != nil {
+				failed = i
+				return err
+			}
+			results[i] = models.BatchOperationResult{Op: operation.Op, Status: batchOperationStatus(operation.Op), Book: &book}
+		}
+		return nil
+	})
+	if err == nil {
+		// The operations are applied, so they are recorded even when the
+		// request has since timed out.
+		entries, _ := pending.List(context.WithoutCancel(ctx), models.AuditFilter{})
+		c.audit(ctx, entries...)
+		c.changed()
+		return results, nil
+	}
+	if failed < 0 {
+		return nil, makeHttpUnexpectedError(err)
+	}
+
+	fiberErr := makeHttpInternalServerError()
+	errors.As(err, &fiberErr)
+	for i, operation := range operations {
+		switch {
+		case i < failed:
+			results[i] = models.BatchOperationResult{Op: operation.Op, Status: http.StatusFailedDependency, Error: "rolled back"}
+		case i == failed:
+			results[i] = models.BatchOperationResult{Op: operation.Op, Status: fiberErr.Code, Error: fiberErr.Message}
+		default:
+			results[i] = models.BatchOperationResult{Op: operation.Op, Status: http.StatusFailedDependency, Error: "not executed"}
+		}
+	}
+	return results, fiber.NewError(fiberErr.Code, fmt.Sprintf("operation %d failed: %s", failed, fiberErr.Message))
+}
+
+func (c *BookController) executeBatchOperation(ctx context.Context, operation models.BatchOperation) (models.Book, error) {
+	switch operation.Op {
+	case models.BatchOperationCreate:
+		if operation.Book == nil {
+			return models.Book{}, fiber.NewError(http.StatusBadRequest, "book is required to create a book")
+		}
+		// The books of a batch are added as given, even when they look like duplicates.
+		return c.AddBook(ctx, *operation.Book, true)
+	case models.BatchOperationUpdate:
+		if operation.Book == nil {
+			return models.Book{}, fiber.NewError(http.StatusBadRequest, "book is required to update a book")
+		}
+		updatedBook := *operation.Book
+		if operation.Id != "" {
+			updatedBook.Id = operation.Id
+		}
+		if operation.Merge == nil {
+			return c.UpdateBook(ctx, updatedBook)
+		}
+		return c.MergeBook(ctx, updatedBook.Id, operation.Merge)
+	case models.BatchOperationDelete:
+		return c.DeleteBook(ctx, operation.Id)
+	default:
+		return models.Book{}, fiber.NewError(http.StatusBadRequest, "operation should be one of [create, update, delete]")
+	}
+}
+
+func batchOperationStatus(op models.BatchOperationType) int {
+	if op == models.BatchOperationCreate {
+		return http.StatusCreated
+	}
+	return http.StatusOK
+}
@@ -8,31 +8,67 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
 	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
 	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/tenancy"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
+)
+
+const (
+	// DefaultPageLimit is the number of books in a page when no limit is requested.
+	DefaultPageLimit = 20
+	// MaxPageLimit is the maximum number of books in a page.
+	MaxPageLimit = 100
 )
 
 type BookController struct {
-	bookRepository models.BookRepository
+	bookRepository  models.BookRepository
+	auditRepository models.AuditRepository
+	changes         *changeTracker
 }
 
-func NewBookController(bookRepository models.BookRepository) *BookController {
-	return &BookController{bookRepository}
+func NewBookController(bookRepository models.BookRepository, auditRepository models.AuditRepository) *BookController {
+	return &BookController{bookRepository: bookRepository, auditRepository: auditRepository, changes: newChangeTracker()}
 }
 
-func (c *BookController) AddBook(ctx context.Context, newBook models.Book) (models.Book, error) {
+// AddBook adds the book to the reading list. Unless allowDuplicate is set, a
+// book that looks like books already on the reading list is rejected with a
+// *DuplicateBookError.
+func (c *BookController) AddBook(ctx context.Context, newBook models.Book, allowDuplicate bool) (models.Book, error) {
 	setDefaultBookFields(&newBook)
 	if err := validateBook(newBook); err != nil {
 		return models.Book{}, err
 	}
+	if !allowDuplicate {
+		duplicates, err := c.FindDuplicates(ctx, newBook)
+		if err != nil {
+			return models.Book{}, err
+		}
+		if len(duplicates) > 0 {
+			return models.Book{}, &DuplicateBookError{Candidates: duplicates}
+		}
+	}
+	now := time.Now().UTC()
+	newBook.CreatedAt = &now
+	newBook.UpdatedAt = &now
+	setStatusTimestamps(&newBook, "", now)
 	book, err := c.bookRepository.Add(ctx, newBook)
 	if errors.Is(err, repositories.ErrRecordAlreadyExists) {
 		return models.Book{}, makeHttpConflictError(newBook.Id)
+	} else if errors.Is(err, repositories.ErrQuotaExceeded) {
+		return models.Book{}, fiber.NewError(http.StatusForbidden, "the book quota of the tenant is exceeded")
 	} else if err != nil {
-		return models.Book{}, makeHttpInternalServerError()
+		return models.Book{}, makeHttpUnexpectedError(err)
 	}
+	c.audit(ctx, newAuditEntry(ctx, models.AuditOperationAdd, nil, &book))
+	c.changed()
 	return book, nil
 }
 
@@ -41,24 +77,137 @@ func (c *BookController) UpdateBook(ctx context.Context, updatedBook models.Book
 	if err := validateBook(updatedBook); err != nil {
 		return models.Book{}, err
 	}
-	book, err := c.bookRepository.Update(ctx, updatedBook)
-	if errors.Is(err, repositories.ErrRecordNotFound) {
-		return models.Book{}, makeHttpNotFoundError(updatedBook.Id)
+	return c.updateBook(ctx, updatedBook.Id, func(models.Book) (models.Book, error) {
+		return updatedBook, nil
+	})
+}
+
+// MergeBook updates the book with the details that merge returns from its
+// current ones, which carries over the details that a representation of the
+// book lacks. The book is not changed by another request in between.
+func (c *BookController) MergeBook(ctx context.Context, id string, merge func(current models.Book) models.Book) (models.Book, error) {
+	return c.updateBook(ctx, id, func(current models.Book) (models.Book, error) {
+		updatedBook := merge(current)
+		setDefaultBookFields(&updatedBook)
+		if err := validateBook(updatedBook); err != nil {
+			return models.Book{}, err
+		}
+		return updatedBook, nil
+	})
+}
+
+// updateBook updates the book with the valid details that fn returns from
+// its current ones, atomically when the repository is a models.BookUpdater.
+func (c *BookController) updateBook(ctx context.Context, id string, fn func(current models.Book) (models.Book, error)) (models.Book, error) {
+	var existingBook models.Book
+	update := func(current models.Book) (models.Book, error) {
+		updatedBook, err := fn(current)
+		if err != nil {
+			return models.Book{}, err
+		}
+		existingBook = current
+		now := time.Now().UTC()
+		updatedBook.CreatedAt = current.CreatedAt
+		updatedBook.UpdatedAt = &now
+		updatedBook.StartedAt = current.StartedAt
+		updatedBook.FinishedAt = current.FinishedAt
+		updatedBook.MergedIds = current.MergedIds
+		setStatusTimestamps(&updatedBook, current.Status, now)
+		return updatedBook, nil
+	}
+	var book models.Book
+	var err error
+	if updater, ok := c.bookRepository.(models.BookUpdater); ok {
+		book, err = updater.UpdateById(ctx, id, update)
+	} else if book, err = c.bookRepository.GetById(ctx, id); err == nil {
+		if book, err = update(book); err == nil {
+			book.Id = existingBook.Id
+			book, err = c.bookRepository.Update(ctx, book)
+		}
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return models.Book{}, fiberErr
+	} else if errors.Is(err, repositories.ErrRecordNotFound) {
+		return models.Book{}, makeHttpNotFoundError(id)
 	} else if err != nil {
-		return models.Book{}, makeHttpInternalServerError()
+		return models.Book{}, makeHttpUnexpectedError(err)
 	}
+	c.audit(ctx, newAuditEntry(ctx, models.AuditOperationUpdate, &existingBook, &book))
+	c.changed()
 	return book, nil
 }
 
-func (c *BookController) ListBooks(ctx context.Context) ([]models.Book, error) {
-	books, err := c.bookRepository.List(ctx)
+// ListBooks returns the books matching the filter.
+func (c *BookController) ListBooks(ctx context.Context, filter models.BookFilter) ([]models.Book, error) {
+	return c.listBooks(ctx, filter)
+}
+
+// ListBooksPage returns a page of the books matching the filter, ordered by
+// when they were added.
+func (c *BookController) ListBooksPage(ctx context.Context, filter models.BookFilter, offset, limit int) (models.BookPage, error) {
+	if offset < 0 {
+		return models.BookPage{}, fiber.NewError(http.StatusBadRequest, "offset should not be negative")
+	}
+	if limit < 1 || limit > MaxPageLimit {
+		return models.BookPage{}, fiber.NewError(http.StatusBadRequest, fmt.Sprintf("limit should be between 1 and %d", MaxPageLimit))
+	}
+	page := models.BookPage{Items: make([]models.Book, 0), Offset: offset, Limit: limit}
+	if pager, ok := c.bookRepository.(models.BookPager); ok && filter == (models.BookFilter{}) {
+		books, total, err := pager.ListPage(ctx, offset, limit)
+		if err != nil {
+			return models.BookPage{}, makeHttpUnexpectedError(err)
+		}
+		page.Items = append(page.Items, books...)
+		page.Total = total
+	} else {
+		books, err := c.listBooks(ctx, filter)
+		if err != nil {
+			return models.BookPage{}, err
+		}
+		sort.Slice(books, func(i, j int) bool {
+			return books[i].AddedBefore(books[j])
+		})
+		page.Total = len(books)
+		if offset < len(books) {
+			page.Items = books[offset:min(offset+limit, len(books))]
+		}
+	}
+	if end := offset + len(page.Items); len(page.Items) > 0 && end < page.Total {
+		page.NextOffset = &end
+	}
+	return page, nil
+}
+
+// listBooks returns the books matching the filter, using the index of the
+// repository when it has one.
+func (c *BookController) listBooks(ctx context.Context, filter models.BookFilter) ([]models.Book, error) {
+	switch filter.Status {
+	case "", models.ReadStatusToRead, models.ReadStatusReading, models.ReadStatusRead:
+	default:
+		return nil, fiber.NewError(http.StatusBadRequest, "status should be one of [to_read, reading, read]")
+	}
+	var books []models.Book
+	var err error
+	index, indexed := c.bookRepository.(models.BookIndex)
+	switch {
+	case indexed && filter.Status != "":
+		books, err = index.ListByStatus(ctx, filter.Status)
+	case indexed && filter.Author != "":
+		books, err = index.ListByAuthor(ctx, filter.Author)
+	default:
+		books, err = c.bookRepository.List(ctx)
+	}
 	if err != nil {
-		return nil, makeHttpInternalServerError()
+		return nil, makeHttpUnexpectedError(err)
 	}
-	if books == nil {
-		return make([]models.Book, 0), nil
+	matching := make([]models.Book, 0, len(books))
+	for _, book := range books {
+		if filter.Matches(book) {
+			matching = append(matching, book)
+		}
 	}
-	return books, nil
+	return matching, nil
 }
 
 func (c *BookController) GetBook(ctx context.Context, bookId string) (models.Book, error) {
@@ -66,7 +215,7 @@ func (c *BookController) GetBook(ctx context.Context, bookId string) (models.Boo
 	if errors.Is(err, repositories.ErrRecordNotFound) {
 		return models.Book{}, makeHttpNotFoundError(bookId)
 	} else if err != nil {
-		return models.Book{}, makeHttpInternalServerError()
+		return models.Book{}, makeHttpUnexpectedError(err)
 	}
 	return book, nil
 }
@@ -76,11 +225,121 @@ func (c *BookController) DeleteBook(ctx context.Context, bookId string) (models.
 	if errors.Is(err, repositories.ErrRecordNotFound) {
 		return models.Book{}, makeHttpNotFoundError(bookId)
 	} else if err != nil {
-		return models.Book{}, makeHttpInternalServerError()
+		return models.Book{}, makeHttpUnexpectedError(err)
+	}
+	before := book
+	before.DeletedAt = nil
+	c.audit(ctx, newAuditEntry(ctx, models.AuditOperationDelete, &before, &book))
+	c.changed()
+	return book, nil
+}
+
+func (c *BookController) PurgeBook(ctx context.Context, bookId string) (models.Book, error) {
+	book, err := c.bookRepository.PurgeById(ctx, bookId)
+	if errors.Is(err, repositories.ErrRecordNotFound) {
+		return models.Book{}, makeHttpNotFoundError(bookId)
+	} else if err != nil {
+		return models.Book{}, makeHttpUnexpectedError(err)
+	}
+	c.audit(ctx, newAuditEntry(ctx, models.AuditOperationPurge, &book, nil))
+	c.purged(ctx, []models.Book{book})
+	c.changed()
+	return book, nil
+}
+
+func (c *BookController) ListTrash(ctx context.Context) ([]models.Book, error) {
+	books, err := c.bookRepository.ListDeleted(ctx)
+	if err != nil {
+		return nil, makeHttpUnexpectedError(err)
+	}
+	if books == nil {
+		return make([]models.Book, 0), nil
+	}
+	return books, nil
+}
+
+func (c *BookController) RestoreBook(ctx context.Context, bookId string) (models.Book, error) {
+	trash, err := c.bookRepository.ListDeleted(ctx)
+	if err != nil {
+		return models.Book{}, makeHttpUnexpectedError(err)
+	}
+	book, err := c.bookRepository.RestoreById(ctx, bookId)
+	if errors.Is(err, repositories.ErrRecordNotFound) {
+		return models.Book{}, fiber.NewError(http.StatusNotFound, fmt.Sprintf("the book id [%s] is not found in the trash", bookId))
+	} else if err != nil {
+		return models.Book{}, makeHttpUnexpectedError(err)
+	}
+	// The book was in the trash, so it is listed there unless it was
+	// restored concurrently.
+	before := book
+	for _, deleted := range trash {
+		if deleted.Id == bookId {
+			before = deleted
+		}
 	}
+	c.audit(ctx, newAuditEntry(ctx, models.AuditOperationRestore, &before, &book))
+	c.changed()
 	return book, nil
 }
 
+// PurgeTrash permanently removes the books that have been in the trash for longer than the retention period.
+func (c *BookController) PurgeTrash(ctx context.Context, retention time.Duration) (int, error) {
+	before := time.Now().UTC().Add(-retention)
+	// The books purged before an error are audited too.
+	purged, err := c.bookRepository.PurgeDeletedBefore(ctx, before)
+	if len(purged) > 0 {
+		entries := make([]models.AuditEntry, len(purged))
+		for i := range purged {
+			entries[i] = newAuditEntry(ctx, models.AuditOperationPurge, &purged[i], nil)
+			entries[i].Actor = systemActor
+		}
+		c.audit(ctx, entries...)
+		c.purged(ctx, purged)
+		c.changed()
+	}
+	return len(purged), err
+}
+
+// RunTrashPurger purges expired books from the trash of every tenant every
+// interval until the context is cancelled.
+func (c *BookController) RunTrashPurger(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, tenant := range c.tenants(ctx) {
+				purged, err := c.PurgeTrash(tenancy.WithTenant(ctx, tenant), retention)
+				if err != nil {
+					logrus.WithFields(logrus.Fields{"tenant": tenant}).Errorf("failed to purge the trash: %v", err)
+				} else if purged > 0 {
+					logrus.WithFields(logrus.Fields{"tenant": tenant, "count": purged}).Info("purged expired books from the trash")
+				}
+			}
+		}
+	}
+}
+
+// tenants returns the tenants whose books are in the repository.
+func (c *BookController) tenants(ctx context.Context) []string {
+	tenantRepository, ok := c.bookRepository.(models.TenantRepository)
+	if !ok {
+		return []string{tenancy.FromContext(ctx)}
+	}
+	tenants, err := tenantRepository.ListTenants(ctx)
+	if err != nil {
+		logrus.Errorf("failed to list the tenants: %v", err)
+		return nil
+	}
+	ids := make([]string, len(tenants))
+	for i, tenant := range tenants {
+		ids[i] = tenant.Id
+	}
+	return ids
+}
+
 func makeHttpNotFoundError(id string) *fiber.Error {
 	return fiber.NewError(http.StatusNotFound, fmt.Sprintf("the book id [%s] is not found", id))
 }
@@ -93,7 +352,22 @@ func makeHttpInternalServerError() *fiber.Error {
 	return fiber.NewError(http.StatusInternalServerError, "internal server error")
 }
 
+// makeHttpUnexpectedError returns the error of a call that failed for a
+// reason the client cannot fix: 504 or 503 when the context of the request
+// is done, and 500 otherwise.
+func makeHttpUnexpectedError(err error) *fiber.Error {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return utils.MakeHttpContextError(err)
+	}
+	return makeHttpInternalServerError()
+}
+
 func validateBook(book models.Book) *fiber.Error {
+	// The ids are path segments, and the tenant of a book is kept before a
+	// slash in its id.
+	if strings.Contains(book.Id, "/") {
+		return fiber.NewError(http.StatusBadRequest, "book id should not contain [/]")
+	}
 	if book.Title == "" {
 		return fiber.NewError(http.StatusBadRequest, "book title is required")
 	}
@@ -103,11 +377,50 @@ func validateBook(book models.Book) *fiber.Error {
 		return fiber.NewError(http.StatusBadRequest, "book status should be one of [ro_read, reading, read]")
 
 	}
+	if book.Rating < 0 || book.Rating > 5 {
+		return fiber.NewError(http.StatusBadRequest, "book rating should be between 1 and 5, or 0 when the book is not rated")
+	}
 	return nil
 }
 
+// setStatusTimestamps records when the book was started and finished as its
+// status changes from the previous status. Timestamps supplied by the client
+// for a new book are kept, so that reading history can be imported.
+func setStatusTimestamps(book *models.Book, previousStatus models.ReadStatus, now time.Time) {
+	if book.Status == previousStatus {
+		return
+	}
+	switch book.Status {
+	case models.ReadStatusToRead:
+		book.StartedAt = nil
+		book.FinishedAt = nil
+	case models.ReadStatusReading:
+		if previousStatus != "" || book.StartedAt == nil {
+			book.StartedAt = &now
+		}
+		book.FinishedAt = nil
+	case models.ReadStatusRead:
+		if previousStatus != "" || book.FinishedAt == nil {
+			book.FinishedAt = &now
+		}
+	}
+}
+
 func setDefaultBookFields(book *models.Book) {
 	if book.Status == "" {
 		book.Status = models.ReadStatusToRead
 	}
+	book.Tags = normalizeTags(book.Tags)
+}
+
+// normalizeTags trims the tags and drops the empty and repeated ones.
+func normalizeTags(tags []string) []string {
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
 }
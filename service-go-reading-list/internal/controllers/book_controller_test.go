@@ -6,95 +6,227 @@ package controllers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
 	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories/repositorytest"
 )
 
-// MockBookRepository is a mock implementation of the models.BookRepository interface for testing purposes.
+// MockBookRepository is a mock implementation of the models.BookRepository
+// interface for testing purposes. It has the semantics of the real
+// repositories, checked by TestMockBookRepository, and fails every call with
+// err when it is set.
 type MockBookRepository struct {
-	data   map[string]models.Book
-	err    error
-	exists bool
+	data map[string]models.Book
+	err  error
+	lock sync.Mutex
+}
+
+// check returns the error the calls of the operation fail with, if any.
+// Callers must hold the lock.
+func (m *MockBookRepository) check(ctx context.Context, operation string) error {
+	if m.data == nil {
+		m.data = make(map[string]models.Book)
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("mockBookRepository:%s: %w", operation, err)
+	}
+	return m.err
 }
 
 func (m *MockBookRepository) Add(ctx context.Context, book models.Book) (models.Book, error) {
-	if m.exists {
-		return models.Book{}, repositories.ErrRecordAlreadyExists
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if err := m.check(ctx, "Add"); err != nil {
+		return models.Book{}, err
 	}
-	return book, m.err
+	if book.Id == "" {
+		book.Id = uuid.NewString()
+	}
+	if _, ok := m.data[book.Id]; ok {
+		return models.Book{}, fmt.Errorf("mockBookRepository:Add: %w", repositories.ErrRecordAlreadyExists)
+	}
+	book.DeletedAt = nil
+	m.data[book.Id] = book
+	return book, nil
 }
 
 func (m *MockBookRepository) Update(ctx context.Context, book models.Book) (models.Book, error) {
-	if !m.exists {
-		return models.Book{}, repositories.ErrRecordNotFound
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if err := m.check(ctx, "Update"); err != nil {
+		return models.Book{}, err
+	}
+	if existing, ok := m.data[book.Id]; !ok || existing.IsDeleted() {
+		return models.Book{}, fmt.Errorf("mockBookRepository:Update: %w", repositories.ErrRecordNotFound)
 	}
-	return book, m.err
+	book.DeletedAt = nil
+	m.data[book.Id] = book
+	return book, nil
 }
 
 func (m *MockBookRepository) List(ctx context.Context) ([]models.Book, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if err := m.check(ctx, "List"); err != nil {
+		return nil, err
+	}
 	books := make([]models.Book, 0, len(m.data))
 	for _, book := range m.data {
-		books = append(books, book)
+		if !book.IsDeleted() {
+			books = append(books, book)
+		}
 	}
-	return books, m.err
+	return books, nil
 }
 
 func (m *MockBookRepository) GetById(ctx context.Context, id string) (models.Book, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if err := m.check(ctx, "GetById"); err != nil {
+		return models.Book{}, err
+	}
 	book, ok := m.data[id]
-	if !ok {
-		return models.Book{}, repositories.ErrRecordNotFound
+	if !ok || book.IsDeleted() {
+		return models.Book{}, fmt.Errorf("mockBookRepository:GetById: %w", repositories.ErrRecordNotFound)
 	}
-	return book, m.err
+	return book, nil
 }
 
 func (m *MockBookRepository) DeleteById(ctx context.Context, id string) (models.Book, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if err := m.check(ctx, "DeleteById"); err != nil {
+		return models.Book{}, err
+	}
+	book, ok := m.data[id]
+	if !ok || book.IsDeleted() {
+		return models.Book{}, fmt.Errorf("mockBookRepository:DeleteById: %w", repositories.ErrRecordNotFound)
+	}
+	deletedAt := time.Now().UTC()
+	book.DeletedAt = &deletedAt
+	m.data[id] = book
+	return book, nil
+}
+
+func (m *MockBookRepository) ListDeleted(ctx context.Context) ([]models.Book, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if err := m.check(ctx, "ListDeleted"); err != nil {
+		return nil, err
+	}
+	books := make([]models.Book, 0)
+	for _, book := range m.data {
+		if book.IsDeleted() {
+			books = append(books, book)
+		}
+	}
+	return books, nil
+}
+
+func (m *MockBookRepository) RestoreById(ctx context.Context, id string) (models.Book, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if err := m.check(ctx, "RestoreById"); err != nil {
+		return models.Book{}, err
+	}
+	book, ok := m.data[id]
+	if !ok || !book.IsDeleted() {
+		return models.Book{}, fmt.Errorf("mockBookRepository:RestoreById: %w", repositories.ErrRecordNotFound)
+	}
+	book.DeletedAt = nil
+	m.data[id] = book
+	return book, nil
+}
+
+func (m *MockBookRepository) PurgeById(ctx context.Context, id string) (models.Book, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if err := m.check(ctx, "PurgeById"); err != nil {
+		return models.Book{}, err
+	}
 	book, ok := m.data[id]
 	if !ok {
-		return models.Book{}, repositories.ErrRecordNotFound
+		return models.Book{}, fmt.Errorf("mockBookRepository:PurgeById: %w", repositories.ErrRecordNotFound)
 	}
 	delete(m.data, id)
-	return book, m.err
+	return book, nil
+}
+
+func (m *MockBookRepository) PurgeDeletedBefore(ctx context.Context, before time.Time) ([]models.Book, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if err := m.check(ctx, "PurgeDeletedBefore"); err != nil {
+		return nil, err
+	}
+	var purged []models.Book
+	for id, book := range m.data {
+		if book.IsDeleted() && book.DeletedAt.Before(before) {
+			delete(m.data, id)
+			purged = append(purged, book)
+		}
+	}
+	return purged, nil
+}
+
+func TestMockBookRepository(t *testing.T) {
+	repositorytest.TestBookRepository(t, func(t *testing.T, initialData []models.Book) models.BookRepository {
+		data := make(map[string]models.Book, len(initialData))
+		for _, book := range initialData {
+			data[book.Id] = book
+		}
+		return &MockBookRepository{data: data}
+	})
 }
 
 func TestBookController(t *testing.T) {
 	// Create a mock repository for testing.
 	mockRepo := &MockBookRepository{
-		data:   make(map[string]models.Book),
-		err:    nil,
-		exists: false,
+		data: make(map[string]models.Book),
+		err:  nil,
 	}
 
-	controller := NewBookController(mockRepo)
+	controller := NewBookController(mockRepo, repositories.NewAuditRepository())
 
 	t.Run("AddBook", func(t *testing.T) {
 		// Test adding a new book.
-		newBook := models.Book{Title: "New Book", Author: "New Author"}
-		mockRepo.exists = false
-		book, err := controller.AddBook(context.Background(), newBook)
+		newBook := models.Book{Id: "1", Title: "New Book", Author: "New Author"}
+		book, err := controller.AddBook(context.Background(), newBook, false)
 		assert.NoError(t, err)
 		assert.Equal(t, newBook.Title, book.Title)
 
 		// Test adding a book that already exists.
-		mockRepo.exists = true
-		_, err = controller.AddBook(context.Background(), newBook)
-		assert.Equal(t, fiber.NewError(http.StatusConflict, "the book id [] is already exists"), err)
+		_, err = controller.AddBook(context.Background(), models.Book{Id: "1", Title: "Other Book"}, false)
+		assert.Equal(t, fiber.NewError(http.StatusConflict, "the book id [1] is already exists"), err)
+
+		// Test that the tags are normalized and the rating is validated.
+		book, err = controller.AddBook(context.Background(), models.Book{Title: "New Book", Tags: []string{" fantasy", "", "fantasy", "classic"}}, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"fantasy", "classic"}, book.Tags)
+		_, err = controller.AddBook(context.Background(), models.Book{Title: "New Book", Rating: 6}, false)
+		assert.Equal(t, fiber.NewError(http.StatusBadRequest, "book rating should be between 1 and 5, or 0 when the book is not rated"), err)
 	})
 
 	t.Run("UpdateBook", func(t *testing.T) {
 		// Test updating an existing book.
-		updatedBook := models.Book{Id: "1", Title: "Updated Book", Author: "Updated Author"}
-		mockRepo.exists = true
+		updatedBook := models.Book{Id: "1", Title: "Updated Book", Author: "Updated Author", Status: models.ReadStatusReading}
+		mockRepo.data = map[string]models.Book{"1": {Id: "1", Title: "Book 1", Status: models.ReadStatusToRead}}
 		book, err := controller.UpdateBook(context.Background(), updatedBook)
 		assert.NoError(t, err)
 		assert.Equal(t, updatedBook.Title, book.Title)
+		assert.NotNil(t, book.StartedAt)
+		assert.Nil(t, book.FinishedAt)
 
 		// Test updating a book that does not exist.
-		mockRepo.exists = false
+		mockRepo.data = map[string]models.Book{}
 		_, err = controller.UpdateBook(context.Background(), updatedBook)
 		assert.Equal(t, fiber.NewError(http.StatusNotFound, "the book id [1] is not found"), err)
 	})
@@ -105,16 +237,76 @@ func TestBookController(t *testing.T) {
 			"1": {Id: "1", Title: "Book 1", Author: "Author 1"},
 			"2": {Id: "2", Title: "Book 2", Author: "Author 2"},
 		}
-		books, err := controller.ListBooks(context.Background())
+		books, err := controller.ListBooks(context.Background(), models.BookFilter{})
 		assert.NoError(t, err)
 		assert.Len(t, books, 2)
 
 		// Test listing books with an error.
 		mockRepo.err = errors.New("mock error")
-		_, err = controller.ListBooks(context.Background())
+		_, err = controller.ListBooks(context.Background(), models.BookFilter{})
 		assert.Equal(t, fiber.NewError(http.StatusInternalServerError, "internal server error"), err)
 	})
 
+	t.Run("ListBooksPage", func(t *testing.T) {
+		// Test that the pages are ordered by when the books were added.
+		first := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		second := first.Add(time.Hour)
+		mockRepo.err = nil
+		mockRepo.data = map[string]models.Book{
+			"c": {Id: "c", Title: "Book C", CreatedAt: &second},
+			"b": {Id: "b", Title: "Book B", CreatedAt: &first},
+			"a": {Id: "a", Title: "Book A", CreatedAt: &second},
+			"z": {Id: "z", Title: "Book Z"},
+		}
+		page, err := controller.ListBooksPage(context.Background(), models.BookFilter{}, 0, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, page.Total)
+		assert.Equal(t, []string{"z", "b", "a"}, bookIds(page.Items))
+		assert.Equal(t, 3, *page.NextOffset)
+
+		page, err = controller.ListBooksPage(context.Background(), models.BookFilter{}, *page.NextOffset, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"c"}, bookIds(page.Items))
+		assert.Nil(t, page.NextOffset)
+
+		page, err = controller.ListBooksPage(context.Background(), models.BookFilter{}, 10, 3)
+		assert.NoError(t, err)
+		assert.Empty(t, page.Items)
+
+		_, err = controller.ListBooksPage(context.Background(), models.BookFilter{}, 0, MaxPageLimit+1)
+		assert.Equal(t, http.StatusBadRequest, err.(*fiber.Error).Code)
+		_, err = controller.ListBooksPage(context.Background(), models.BookFilter{}, -1, 3)
+		assert.Equal(t, http.StatusBadRequest, err.(*fiber.Error).Code)
+	})
+
+	t.Run("ListBooksPageOfPager", func(t *testing.T) {
+		// Test that the pages are read from the repositories that page the
+		// books, unless they are filtered.
+		ctx := context.Background()
+		controller := NewBookController(repositories.NewBookRepository([]models.Book{
+			{Id: "c", Title: "Book C", Status: models.ReadStatusRead},
+			{Id: "b", Title: "Book B", Status: models.ReadStatusToRead},
+			{Id: "a", Title: "Book A", Status: models.ReadStatusRead},
+		}), repositories.NewAuditRepository())
+		page, err := controller.ListBooksPage(ctx, models.BookFilter{}, 1, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, page.Total)
+		assert.Equal(t, []string{"b"}, bookIds(page.Items))
+		assert.Equal(t, 2, *page.NextOffset)
+
+		page, err = controller.ListBooksPage(ctx, models.BookFilter{Status: models.ReadStatusRead}, 1, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, page.Total)
+		assert.Equal(t, []string{"c"}, bookIds(page.Items))
+		assert.Nil(t, page.NextOffset)
+
+		page, err = controller.ListBooksPage(ctx, models.BookFilter{}, 5, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, page.Total)
+		assert.Empty(t, page.Items)
+		assert.NotNil(t, page.Items)
+	})
+
 	t.Run("GetBook", func(t *testing.T) {
 		// Test getting an existing book.
 		mockRepo.data = map[string]models.Book{"1": {Id: "1", Title: "Book 1", Author: "Author 1"}}
@@ -139,4 +331,166 @@ func TestBookController(t *testing.T) {
 		_, err = controller.DeleteBook(context.Background(), "2")
 		assert.Equal(t, fiber.NewError(http.StatusNotFound, "the book id [2] is not found"), err)
 	})
+
+	t.Run("Trash", func(t *testing.T) {
+		// Test restoring and purging books in the trash.
+		recent := time.Now().UTC().Add(-time.Hour)
+		expired := time.Now().UTC().Add(-48 * time.Hour)
+		mockRepo.data = map[string]models.Book{
+			"1": {Id: "1", Title: "Book 1", DeletedAt: &recent},
+			"2": {Id: "2", Title: "Book 2", DeletedAt: &expired},
+		}
+		books, err := controller.ListTrash(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, books, 2)
+
+		purged, err := controller.PurgeTrash(context.Background(), 24*time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		// The purge is audited for the books that the repository removed.
+		entries, err := controller.ListAuditEntries(context.Background(), models.AuditFilter{BookId: "2"})
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 1) {
+			assert.Equal(t, models.AuditOperationPurge, entries[0].Operation)
+			assert.Equal(t, systemActor, entries[0].Actor)
+		}
+		entries, err = controller.ListAuditEntries(context.Background(), models.AuditFilter{BookId: "1"})
+		assert.NoError(t, err)
+		for _, entry := range entries {
+			assert.NotEqual(t, models.AuditOperationPurge, entry.Operation)
+		}
+
+		book, err := controller.RestoreBook(context.Background(), "1")
+		assert.NoError(t, err)
+		assert.False(t, book.IsDeleted())
+
+		// Test restoring a book that is not in the trash.
+		_, err = controller.RestoreBook(context.Background(), "1")
+		assert.Equal(t, fiber.NewError(http.StatusNotFound, "the book id [1] is not found in the trash"), err)
+
+		// Test permanently deleting a book.
+		_, err = controller.PurgeBook(context.Background(), "1")
+		assert.NoError(t, err)
+		_, err = controller.PurgeBook(context.Background(), "1")
+		assert.Equal(t, fiber.NewError(http.StatusNotFound, "the book id [1] is not found"), err)
+	})
+
+	t.Run("ContextDone", func(t *testing.T) {
+		// Test that the requests that timed out or were canceled are told apart from the failures.
+		mockRepo.data = map[string]models.Book{"1": {Id: "1", Title: "Book 1"}}
+		ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+		defer cancel()
+		_, err := controller.GetBook(ctx, "1")
+		assert.Equal(t, fiber.NewError(http.StatusGatewayTimeout, "the request timed out"), err)
+		_, err = controller.UpdateBook(ctx, models.Book{Id: "1", Title: "Book 1"})
+		assert.Equal(t, fiber.NewError(http.StatusGatewayTimeout, "the request timed out"), err)
+
+		ctx, cancel = context.WithCancel(context.Background())
+		cancel()
+		_, err = controller.ListBooks(ctx, models.BookFilter{})
+		assert.Equal(t, fiber.NewError(http.StatusServiceUnavailable, "the request was canceled"), err)
+	})
+}
+
+func TestBookControllerExecuteBatch(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Unsupported", func(t *testing.T) {
+		controller := NewBookController(&MockBookRepository{data: make(map[string]models.Book)}, repositories.NewAuditRepository())
+		_, err := controller.ExecuteBatch(ctx, []models.BatchOperation{{Op: models.BatchOperationDelete, Id: "1"}})
+		assert.Equal(t, fiber.NewError(http.StatusNotImplemented, "batch operations are not supported by the configured storage"), err)
+	})
+
+	repo := repositories.NewBookRepository([]models.Book{
+		{Id: "1", Title: "Book 1", Status: models.ReadStatusToRead},
+		{Id: "2", Title: "Book 2", Status: models.ReadStatusToRead},
+	})
+	controller := NewBookController(repo, repositories.NewAuditRepository())
+
+	t.Run("Success", func(t *testing.T) {
+		results, err := controller.ExecuteBatch(ctx, []models.BatchOperation{
+			{Op: models.BatchOperationUpdate, Id: "1", Book: &models.Book{Title: "Book 1", Status: models.ReadStatusRead}},
+			{Op: models.BatchOperationUpdate, Id: "2", Book: &models.Book{Title: "Book 2", Status: models.ReadStatusReading}},
+			{Op: models.BatchOperationCreate, Book: &models.Book{Title: "Book 3"}},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, results, 3)
+		assert.Equal(t, http.StatusCreated, results[2].Status)
+		book, _ := controller.GetBook(ctx, "2")
+		assert.Equal(t, models.ReadStatusReading, book.Status)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		results, err := controller.ExecuteBatch(ctx, []models.BatchOperation{
+			{Op: models.BatchOperationDelete, Id: "1"},
+			{Op: models.BatchOperationUpdate, Id: "2", Book: &models.Book{}},
+			{Op: models.BatchOperationDelete, Id: "2"},
+		})
+		assert.Equal(t, fiber.NewError(http.StatusBadRequest, "operation 1 failed: book title is required"), err)
+		assert.Equal(t, []int{http.StatusFailedDependency, http.StatusBadRequest, http.StatusFailedDependency},
+			[]int{results[0].Status, results[1].Status, results[2].Status})
+
+		// Nothing is applied when an operation fails.
+		_, err = controller.GetBook(ctx, "1")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		// The details are merged with the current ones in the transaction.
+		keepRating := func(current models.Book) models.Book {
+			return models.Book{Title: current.Title + " (2nd edition)", Status: current.Status, Rating: current.Rating}
+		}
+		_, err := controller.ExecuteBatch(ctx, []models.BatchOperation{
+			{Op: models.BatchOperationUpdate, Id: "2", Book: &models.Book{Title: "Book 2", Status: models.ReadStatusRead, Rating: 4}},
+			{Op: models.BatchOperationUpdate, Id: "2", Book: &models.Book{}, Merge: keepRating},
+		})
+		assert.NoError(t, err)
+		book, _ := controller.GetBook(ctx, "2")
+		assert.Equal(t, "Book 2 (2nd edition)", book.Title)
+		assert.Equal(t, 4, book.Rating)
+
+		results, err := controller.ExecuteBatch(ctx, []models.BatchOperation{
+			{Op: models.BatchOperationUpdate, Id: "missing", Book: &models.Book{}, Merge: keepRating},
+		})
+		assert.Equal(t, fiber.NewError(http.StatusNotFound, "operation 0 failed: the book id [missing] is not found"), err)
+		assert.Equal(t, http.StatusNotFound, results[0].Status)
+	})
+}
+
+func TestBookControllerChanges(t *testing.T) {
+	ctx := context.Background()
+	repo := repositories.NewBookRepository([]models.Book{{Id: "1", Title: "Book 1", Status: models.ReadStatusToRead}})
+	controller := NewBookController(repo, repositories.NewAuditRepository())
+	changes := 0
+	controller.OnChange(func() { changes++ })
+	created := controller.LastModified()
+
+	// Reads and failed changes are not reported.
+	_, _ = controller.ListBooks(ctx, models.BookFilter{})
+	_, err := controller.UpdateBook(ctx, models.Book{Id: "missing", Title: "Missing"})
+	assert.Error(t, err)
+	assert.Equal(t, 0, changes)
+	assert.Equal(t, created, controller.LastModified())
+
+	_, err = controller.AddBook(ctx, models.Book{Id: "2", Title: "Book 2"}, false)
+	assert.NoError(t, err)
+	_, err = controller.DeleteBook(ctx, "2")
+	assert.NoError(t, err)
+	_, err = controller.RestoreBook(ctx, "2")
+	assert.NoError(t, err)
+	_, err = controller.ExecuteBatch(ctx, []models.BatchOperation{{Op: models.BatchOperationDelete, Id: "1"}})
+	assert.NoError(t, err)
+	purged, err := controller.PurgeTrash(ctx, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Equal(t, 5, changes)
+	assert.False(t, controller.LastModified().Before(created))
+}
+
+func bookIds(books []models.Book) []string {
+	ids := make([]string, len(books))
+	for i, book := range books {
+		ids[i] = book.Id
+	}
+	return ids
 }
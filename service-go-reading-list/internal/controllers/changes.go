@@ -0,0 +1,72 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+)
+
+// changeTracker records when the books were last changed and notifies the
+// listeners of every change, e.g. to invalidate cached responses.
+type changeTracker struct {
+	lock         sync.RWMutex
+	lastModified time.Time
+	listeners    []func()
+	// purgeListeners are notified of the books that are permanently removed.
+	purgeListeners []func(ctx context.Context, books []models.Book)
+}
+
+func newChangeTracker() *changeTracker {
+	return &changeTracker{lastModified: time.Now().UTC()}
+}
+
+// OnChange registers fn to be called after each change to the books.
+func (c *BookController) OnChange(fn func()) {
+	c.changes.lock.Lock()
+	defer c.changes.lock.Unlock()
+	c.changes.listeners = append(c.changes.listeners, fn)
+}
+
+// OnPurge registers fn to be called with the books that are permanently
+// removed, e.g. to remove the data kept with them.
+func (c *BookController) OnPurge(fn func(ctx context.Context, books []models.Book)) {
+	c.changes.lock.Lock()
+	defer c.changes.lock.Unlock()
+	c.changes.purgeListeners = append(c.changes.purgeListeners, fn)
+}
+
+// LastModified returns when the books were last changed, or when the
+// controller was created if they have not changed since.
+func (c *BookController) LastModified() time.Time {
+	c.changes.lock.RLock()
+	defer c.changes.lock.RUnlock()
+	return c.changes.lastModified
+}
+
+func (c *BookController) changed() {
+	c.changes.changed()
+}
+
+func (t *changeTracker) changed() {
+	t.lock.Lock()
+	t.lastModified = time.Now().UTC()
+	listeners := t.listeners
+	t.lock.Unlock()
+	for _, listener := range listeners {
+		listener()
+	}
+}
+
+func (c *BookController) purged(ctx context.Context, books []models.Book) {
+	c.changes.lock.RLock()
+	listeners := c.changes.purgeListeners
+	c.changes.lock.RUnlock()
+	for _, listener := range listeners {
+		listener(ctx, books)
+	}
+}
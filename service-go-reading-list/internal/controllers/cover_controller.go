@@ -0,0 +1,286 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"image"
+	_ "image/gif"
+	"mime"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/tenancy"
+)
+
+const (
+	// maxCoverPixels is the maximum number of pixels of the covers, which
+	// bounds the memory taken to decode them to 64 MB.
+	maxCoverPixels = 16_000_000
+	// maxCoverDecodes is the number of covers that are decoded at the same
+	// time. The other uploads wait for their turn.
+	maxCoverDecodes = 2
+	// coverLocks is the number of locks the changes to the covers are
+	// spread over.
+	coverLocks = 32
+)
+
+// thumbnailSides are the sides of the squares the thumbnails fit in.
+var thumbnailSides = map[models.CoverSize]int{
+	models.CoverSizeSmall:  128,
+	models.CoverSizeMedium: 320,
+	models.CoverSizeLarge:  640,
+}
+
+// thumbnailSizes are the sizes of the thumbnails from the largest, each of
+// which is made from the previous one.
+var thumbnailSizes = []models.CoverSize{models.CoverSizeLarge, models.CoverSizeMedium, models.CoverSizeSmall}
+
+// CoverController stores the cover images of the books with their
+// thumbnails, which are made when the cover is uploaded. The covers are
+// removed with their book when it is purged.
+type CoverController struct {
+	books   *BookController
+	covers  models.BlobRepository
+	maxSize int64
+	// decodes holds a token for each cover being decoded.
+	decodes chan struct{}
+	// locks serialize the changes to the covers of a book, so that the
+	// thumbnail of a replaced cover is not stored after the new cover.
+	locks [coverLocks]sync.Mutex
+	seed  maphash.Seed
+}
+
+// NewCoverController returns the controller of the covers of the books of
+// the books controller, which are at most maxSize bytes.
+func NewCoverController(books *BookController, covers models.BlobRepository, maxSize int64) *CoverController {
+	c := &CoverController{
+		books:   books,
+		covers:  covers,
+		maxSize: maxSize,
+		decodes: make(chan struct{}, maxCoverDecodes),
+		seed:    maphash.MakeSeed(),
+	}
+	books.OnPurge(c.deleteCovers)
+	return c
+}
+
+// PutCover replaces the cover of the book with the image, which must be a
+// JPEG, PNG or GIF of the content type. The format is detected from the
+// image when the content type is empty.
+func (c *CoverController) PutCover(ctx context.Context, bookId string, data []byte, contentType string) (models.Cover, error) {
+	if _, err := c.books.GetBook(ctx, bookId); err != nil {
+		return models.Cover{}, err
+	}
+	if int64(len(data)) > c.maxSize {
+		return models.Cover{}, fiber.NewError(http.StatusRequestEntityTooLarge, fmt.Sprintf("the cover should not be larger than %d bytes", c.maxSize))
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if errors.Is(err, image.ErrFormat) {
+		return models.Cover{}, makeHttpUnsupportedCoverError()
+	} else if err != nil {
+		return models.Cover{}, fiber.NewError(http.StatusBadRequest, "the cover is not a valid image")
+	}
+	detected := "image/" + format
+	if contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || mediaType != detected {
+			return models.Cover{}, fiber.NewError(http.StatusUnsupportedMediaType, fmt.Sprintf("the cover is a [%s] image, not [%s]", detected, contentType))
+		}
+	}
+	if int64(config.Width)*int64(config.Height) > maxCoverPixels {
+		return models.Cover{}, fiber.NewError(http.StatusBadRequest, fmt.Sprintf("the cover should not have more than %d pixels", maxCoverPixels))
+	}
+	// The image is decoded in full, so that a truncated image is rejected
+	// rather than failing to make its thumbnails.
+	thumbnails, err := c.makeThumbnails(ctx, data)
+	if errors.Is(err, errInvalidCover) {
+		return models.Cover{}, fiber.NewError(http.StatusBadRequest, "the cover is not a valid image")
+	} else if err != nil {
+		return models.Cover{}, c.makeHttpThumbnailError(ctx, bookId, err)
+	}
+
+	prefix := coverPrefix(ctx, bookId)
+	lock := c.lock(prefix)
+	lock.Lock()
+	defer lock.Unlock()
+	// The book is checked again under the lock, as its covers are deleted
+	// under the lock once it is purged, so that they are not stored after.
+	if _, err := c.books.GetBook(ctx, bookId); err != nil {
+		return models.Cover{}, err
+	}
+	// The previous cover is kept when the new one cannot be stored. The
+	// thumbnails of the previous cover are then deleted, so that they are
+	// not served with the new one when its thumbnails cannot be stored;
+	// GetCover makes the missing thumbnails.
+	if err := c.covers.Put(ctx, prefix+string(models.CoverSizeOriginal), data); err != nil {
+		return models.Cover{}, makeHttpUnexpectedError(err)
+	}
+	for _, size := range thumbnailSizes {
+		if err := c.covers.Delete(ctx, prefix+string(size)); err != nil {
+			return models.Cover{}, makeHttpUnexpectedError(err)
+		}
+	}
+	if err := c.putThumbnails(ctx, prefix, thumbnails); err != nil {
+		return models.Cover{}, makeHttpUnexpectedError(err)
+	}
+	return models.Cover{
+		BookId:      bookId,
+		ContentType: detected,
+		Size:        int64(len(data)),
+		Width:       config.Width,
+		Height:      config.Height,
+		UpdatedAt:   time.Now().UTC(),
+	}, nil
+}
+
+// GetCover returns the cover of the book in the size. The thumbnails of the
+// covers stored before they were made on upload are made the first time
+// they are requested.
+func (c *CoverController) GetCover(ctx context.Context, bookId string, size models.CoverSize) (models.CoverImage, error) {
+	if _, err := c.books.GetBook(ctx, bookId); err != nil {
+		return models.CoverImage{}, err
+	}
+	if size == "" {
+		size = models.CoverSizeOriginal
+	}
+	_, ok := thumbnailSides[size]
+	if !ok && size != models.CoverSizeOriginal {
+		return models.CoverImage{}, fiber.NewError(http.StatusBadRequest, fmt.Sprintf("size should be one of [%s, %s, %s, %s]",
+			models.CoverSizeSmall, models.CoverSizeMedium, models.CoverSizeLarge, models.CoverSizeOriginal))
+	}
+	prefix := coverPrefix(ctx, bookId)
+	blob, err := c.covers.Get(ctx, prefix+string(size))
+	if err == nil {
+		return newCoverImage(blob.Data, blob.ModifiedAt), nil
+	} else if !errors.Is(err, repositories.ErrRecordNotFound) {
+		return models.CoverImage{}, makeHttpUnexpectedError(err)
+	} else if size == models.CoverSizeOriginal {
+		return models.CoverImage{}, makeHttpCoverNotFoundError(bookId)
+	}
+
+	lock := c.lock(prefix)
+	lock.Lock()
+	defer lock.Unlock()
+	original, err := c.covers.Get(ctx, prefix+string(models.CoverSizeOriginal))
+	if errors.Is(err, repositories.ErrRecordNotFound) {
+		return models.CoverImage{}, makeHttpCoverNotFoundError(bookId)
+	} else if err != nil {
+		return models.CoverImage{}, makeHttpUnexpectedError(err)
+	}
+	// The thumbnails may have been made while waiting for the lock.
+	if blob, err := c.covers.Get(ctx, prefix+string(size)); err == nil {
+		return newCoverImage(blob.Data, blob.ModifiedAt), nil
+	}
+	thumbnails, err := c.makeThumbnails(ctx, original.Data)
+	if err != nil {
+		return models.CoverImage{}, c.makeHttpThumbnailError(ctx, bookId, err)
+	}
+	if err := c.putThumbnails(ctx, prefix, thumbnails); err != nil {
+		return models.CoverImage{}, makeHttpUnexpectedError(err)
+	}
+	return newCoverImage(thumbnails[size], time.Now().UTC()), nil
+}
+
+// errInvalidCover is returned by makeThumbnails when the cover cannot be
+// decoded.
+var errInvalidCover = errors.New("the cover is not a valid image")
+
+// makeThumbnails decodes the cover and returns its thumbnails by size, in
+// its format. The covers are decoded a few at a time, which bounds the
+// memory they take.
+func (c *CoverController) makeThumbnails(ctx context.Context, data []byte) (map[models.CoverSize][]byte, error) {
+	select {
+	case c.decodes <- struct{}{}:
+		defer func() { <-c.decodes }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidCover, err)
+	}
+	thumbnails := make(map[models.CoverSize][]byte, len(thumbnailSizes))
+	for _, size := range thumbnailSizes {
+		img = thumbnail(img, thumbnailSides[size])
+		if thumbnails[size], err = encodeThumbnail(img, format); err != nil {
+			return nil, fmt.Errorf("failed to encode the %s thumbnail: %w", size, err)
+		}
+	}
+	return thumbnails, nil
+}
+
+func (c *CoverController) putThumbnails(ctx context.Context, prefix string, thumbnails map[models.CoverSize][]byte) error {
+	for size, data := range thumbnails {
+		if err := c.covers.Put(ctx, prefix+string(size), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// makeHttpThumbnailError logs the failure to make the thumbnails of the
+// cover of the book, which is not the client's doing.
+func (c *CoverController) makeHttpThumbnailError(ctx context.Context, bookId string, err error) *fiber.Error {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return makeHttpUnexpectedError(err)
+	}
+	logrus.WithFields(logrus.Fields{"tenant": tenancy.FromContext(ctx), "bookId": bookId}).Errorf("failed to make the thumbnails of the cover: %v", err)
+	return makeHttpInternalServerError()
+}
+
+// PurgeTenant removes the covers of all the books of the tenant.
+func (c *CoverController) PurgeTenant(ctx context.Context, id string) error {
+	_, err := c.covers.DeletePrefix(ctx, id+"/")
+	return err
+}
+
+// deleteCovers removes the covers of the purged books. The books are gone
+// whether their covers can be removed or not, so the failures are logged.
+func (c *CoverController) deleteCovers(ctx context.Context, books []models.Book) {
+	for _, book := range books {
+		prefix := coverPrefix(ctx, book.Id)
+		lock := c.lock(prefix)
+		lock.Lock()
+		_, err := c.covers.DeletePrefix(ctx, prefix)
+		lock.Unlock()
+		if err != nil {
+			logrus.WithFields(logrus.Fields{"tenant": tenancy.FromContext(ctx), "bookId": book.Id}).Errorf("failed to delete the cover: %v", err)
+		}
+	}
+}
+
+func (c *CoverController) lock(prefix string) *sync.Mutex {
+	return &c.locks[maphash.String(c.seed, prefix)%coverLocks]
+}
+
+// coverPrefix is the prefix of the keys of the cover of the book and of its
+// thumbnails, which are named after their size. The id is escaped, so that
+// the prefix of a book is not the prefix of another one.
+func coverPrefix(ctx context.Context, bookId string) string {
+	return tenancy.FromContext(ctx) + "/" + url.PathEscape(bookId) + "/"
+}
+
+func newCoverImage(data []byte, modifiedAt time.Time) models.CoverImage {
+	return models.CoverImage{Data: data, ContentType: http.DetectContentType(data), ModifiedAt: modifiedAt}
+}
+
+func makeHttpCoverNotFoundError(id string) *fiber.Error {
+	return fiber.NewError(http.StatusNotFound, fmt.Sprintf("the book id [%s] has no cover", id))
+}
+
+func makeHttpUnsupportedCoverError() *fiber.Error {
+	return fiber.NewError(http.StatusUnsupportedMediaType, "the cover should be a JPEG, PNG or GIF image")
+}
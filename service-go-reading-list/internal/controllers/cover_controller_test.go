@@ -0,0 +1,281 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/tenancy"
+)
+
+// testImage returns an image of the size encoded in the format, "jpeg" or
+// "png".
+func testImage(t *testing.T, format string, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		require.NoError(t, jpeg.Encode(&buf, img, nil))
+	} else {
+		require.NoError(t, png.Encode(&buf, img))
+	}
+	return buf.Bytes()
+}
+
+// pngHeader returns the start of a PNG image of the size, which is enough to
+// read its size.
+func pngHeader(width, height uint32) []byte {
+	ihdr := binary.BigEndian.AppendUint32([]byte("IHDR"), width)
+	ihdr = binary.BigEndian.AppendUint32(ihdr, height)
+	ihdr = append(ihdr, 8, 0, 0, 0, 0)
+	data := []byte("\x89PNG\r\n\x1a\n")
+	data = binary.BigEndian.AppendUint32(data, uint32(len(ihdr)-4))
+	data = append(data, ihdr...)
+	return binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(ihdr))
+}
+
+func decodeTestImage(t *testing.T, data []byte) (image.Image, string) {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	return img, format
+}
+
+func TestCoverController(t *testing.T) {
+	ctx := context.Background()
+	books := NewBookController(&MockBookRepository{}, repositories.NewAuditRepository())
+	covers := repositories.NewBlobRepository()
+	controller := NewCoverController(books, covers, 64<<10)
+	for _, id := range []string{"dune", "emma", "persuasion"} {
+		_, err := books.AddBook(ctx, models.Book{Id: id, Title: id}, true)
+		require.NoError(t, err)
+	}
+	cover := testImage(t, "jpeg", 600, 900)
+
+	t.Run("PutCover", func(t *testing.T) {
+		put, err := controller.PutCover(ctx, "dune", cover, "image/jpeg")
+		require.NoError(t, err)
+		assert.Equal(t, "dune", put.BookId)
+		assert.Equal(t, "image/jpeg", put.ContentType)
+		assert.Equal(t, int64(len(cover)), put.Size)
+		assert.Equal(t, 600, put.Width)
+		assert.Equal(t, 900, put.Height)
+		assert.False(t, put.UpdatedAt.IsZero())
+
+		// The format is detected when the content type is not known.
+		put, err = controller.PutCover(ctx, "emma", testImage(t, "png", 20, 10), "")
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", put.ContentType)
+
+		_, err = controller.PutCover(ctx, "missing", cover, "image/jpeg")
+		assert.Equal(t, fiber.NewError(http.StatusNotFound, "the book id [missing] is not found"), err)
+		_, err = controller.PutCover(ctx, "dune", cover, "image/png")
+		assert.Equal(t, fiber.NewError(http.StatusUnsupportedMediaType, "the cover is a [image/jpeg] image, not [image/png]"), err)
+		_, err = controller.PutCover(ctx, "dune", []byte("<svg></svg>"), "")
+		assert.Equal(t, fiber.NewError(http.StatusUnsupportedMediaType, "the cover should be a JPEG, PNG or GIF image"), err)
+		_, err = controller.PutCover(ctx, "dune", cover[:len(cover)/2], "image/jpeg")
+		assert.Equal(t, fiber.NewError(http.StatusBadRequest, "the cover is not a valid image"), err)
+		_, err = controller.PutCover(ctx, "dune", make([]byte, 64<<10+1), "image/jpeg")
+		assert.Equal(t, fiber.NewError(http.StatusRequestEntityTooLarge, "the cover should not be larger than 65536 bytes"), err)
+		_, err = controller.PutCover(ctx, "dune", pngHeader(4001, 4000), "image/png")
+		assert.Equal(t, fiber.NewError(http.StatusBadRequest, "the cover should not have more than 16000000 pixels"), err)
+
+		// The rejected covers do not replace the cover.
+		original, err := controller.GetCover(ctx, "dune", models.CoverSizeOriginal)
+		require.NoError(t, err)
+		assert.Equal(t, cover, original.Data)
+	})
+
+	t.Run("GetCover", func(t *testing.T) {
+		original, err := controller.GetCover(ctx, "dune", "")
+		require.NoError(t, err)
+		assert.Equal(t, cover, original.Data)
+		assert.Equal(t, "image/jpeg", original.ContentType)
+		assert.False(t, original.ModifiedAt.IsZero())
+
+		// The thumbnails keep the aspect ratio and the format of the cover.
+		for size, bounds := range map[models.CoverSize]image.Point{
+			models.CoverSizeSmall:  {85, 128},
+			models.CoverSizeMedium: {213, 320},
+			models.CoverSizeLarge:  {426, 640},
+		} {
+			thumb, err := controller.GetCover(ctx, "dune", size)
+			require.NoError(t, err, size)
+			assert.Equal(t, "image/jpeg", thumb.ContentType, size)
+			img, format := decodeTestImage(t, thumb.Data)
+			assert.Equal(t, "jpeg", format, size)
+			assert.Equal(t, bounds, img.Bounds().Size(), size)
+
+			// The thumbnail is made with the cover.
+			stored, err := covers.Get(ctx, "default/dune/"+string(size))
+			require.NoError(t, err, size)
+			assert.Equal(t, thumb.Data, stored.Data, size)
+			again, err := controller.GetCover(ctx, "dune", size)
+			require.NoError(t, err, size)
+			assert.Equal(t, thumb.Data, again.Data, size)
+		}
+
+		// The covers that fit are not scaled up.
+		thumb, err := controller.GetCover(ctx, "emma", models.CoverSizeSmall)
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", thumb.ContentType)
+		img, _ := decodeTestImage(t, thumb.Data)
+		assert.Equal(t, image.Point{20, 10}, img.Bounds().Size())
+
+		_, err = controller.GetCover(ctx, "dune", "huge")
+		assert.Equal(t, fiber.NewError(http.StatusBadRequest, "size should be one of [small, medium, large, original]"), err)
+		_, err = controller.GetCover(ctx, "persuasion", models.CoverSizeSmall)
+		assert.Equal(t, fiber.NewError(http.StatusNotFound, "the book id [persuasion] has no cover"), err)
+		_, err = controller.GetCover(ctx, "missing", models.CoverSizeOriginal)
+		assert.Equal(t, fiber.NewError(http.StatusNotFound, "the book id [missing] is not found"), err)
+	})
+
+	t.Run("DecodesBounded", func(t *testing.T) {
+		// The uploads wait for the covers being decoded.
+		for i := 0; i < maxCoverDecodes; i++ {
+			controller.decodes <- struct{}{}
+		}
+		timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err := controller.PutCover(timeout, "persuasion", cover, "image/jpeg")
+		assert.Equal(t, fiber.NewError(http.StatusGatewayTimeout, "the request timed out"), err)
+		for i := 0; i < maxCoverDecodes; i++ {
+			<-controller.decodes
+		}
+		_, err = covers.Get(ctx, "default/persuasion/original")
+		assert.ErrorIs(t, err, repositories.ErrRecordNotFound)
+	})
+
+	t.Run("StoredWithoutThumbnails", func(t *testing.T) {
+		// The thumbnails of the covers stored without them are made when
+		// they are requested.
+		require.NoError(t, covers.Put(ctx, "default/persuasion/original", cover))
+		thumb, err := controller.GetCover(ctx, "persuasion", models.CoverSizeMedium)
+		require.NoError(t, err)
+		img, _ := decodeTestImage(t, thumb.Data)
+		assert.Equal(t, image.Point{213, 320}, img.Bounds().Size())
+		_, err = covers.Get(ctx, "default/persuasion/small")
+		assert.NoError(t, err)
+		_, err = covers.DeletePrefix(ctx, "default/persuasion/")
+		require.NoError(t, err)
+	})
+
+	t.Run("ReplaceCover", func(t *testing.T) {
+		_, err := controller.GetCover(ctx, "persuasion", models.CoverSizeOriginal)
+		require.Error(t, err)
+		_, err = controller.PutCover(ctx, "persuasion", cover, "image/jpeg")
+		require.NoError(t, err)
+		_, err = controller.GetCover(ctx, "persuasion", models.CoverSizeSmall)
+		require.NoError(t, err)
+
+		// The thumbnails of the replaced cover are dropped.
+		_, err = controller.PutCover(ctx, "persuasion", testImage(t, "png", 256, 256), "image/png")
+		require.NoError(t, err)
+		thumb, err := controller.GetCover(ctx, "persuasion", models.CoverSizeSmall)
+		require.NoError(t, err)
+		img, format := decodeTestImage(t, thumb.Data)
+		assert.Equal(t, "png", format)
+		assert.Equal(t, image.Point{128, 128}, img.Bounds().Size())
+
+		// The replaced cover is kept with its thumbnails when the new one
+		// cannot be stored.
+		failing := NewCoverController(books, &failingBlobRepository{BlobRepository: covers}, 64<<10)
+		_, err = failing.PutCover(ctx, "persuasion", cover, "image/jpeg")
+		assert.Error(t, err)
+		thumb, err = controller.GetCover(ctx, "persuasion", models.CoverSizeSmall)
+		require.NoError(t, err)
+		_, format = decodeTestImage(t, thumb.Data)
+		assert.Equal(t, "png", format)
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		// The covers of the books in the trash are kept, as the books can
+		// be restored.
+		_, err := books.DeleteBook(ctx, "dune")
+		require.NoError(t, err)
+		_, err = covers.Get(ctx, "default/dune/original")
+		assert.NoError(t, err)
+		_, err = books.RestoreBook(ctx, "dune")
+		require.NoError(t, err)
+
+		_, err = books.PurgeBook(ctx, "dune")
+		require.NoError(t, err)
+		_, err = covers.Get(ctx, "default/dune/original")
+		assert.ErrorIs(t, err, repositories.ErrRecordNotFound)
+		_, err = covers.Get(ctx, "default/dune/small")
+		assert.ErrorIs(t, err, repositories.ErrRecordNotFound)
+
+		_, err = books.DeleteBook(ctx, "emma")
+		require.NoError(t, err)
+		purged, err := books.PurgeTrash(ctx, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		_, err = covers.Get(ctx, "default/emma/original")
+		assert.ErrorIs(t, err, repositories.ErrRecordNotFound)
+		_, err = covers.Get(ctx, "default/persuasion/original")
+		assert.NoError(t, err)
+	})
+
+	t.Run("PurgeTenant", func(t *testing.T) {
+		acme := tenancy.WithTenant(ctx, "acme")
+		_, err := controller.PutCover(acme, "persuasion", cover, "image/jpeg")
+		require.NoError(t, err)
+		require.NoError(t, controller.PurgeTenant(ctx, "acme"))
+		_, err = controller.GetCover(acme, "persuasion", models.CoverSizeOriginal)
+		assert.Equal(t, fiber.NewError(http.StatusNotFound, "the book id [persuasion] has no cover"), err)
+		_, err = controller.GetCover(ctx, "persuasion", models.CoverSizeOriginal)
+		assert.NoError(t, err)
+	})
+}
+
+// failingBlobRepository fails to store the blobs.
+type failingBlobRepository struct {
+	models.BlobRepository
+}
+
+func (r *failingBlobRepository) Put(ctx context.Context, key string, data []byte) error {
+	return errors.New("the disk is full")
+}
+
+func TestThumbnail(t *testing.T) {
+	// The pixels of the thumbnail average the pixels they cover.
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		img.Set(x, 0, color.White)
+		img.Set(x, 1, color.Black)
+	}
+	thumb := thumbnail(img, 2)
+	assert.Equal(t, image.Rect(0, 0, 2, 1), thumb.Bounds())
+	assert.Equal(t, color.RGBA{R: 128, G: 128, B: 128, A: 255}, thumb.At(0, 0))
+
+	// The transparent pixels do not darken the opaque ones.
+	img = image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	img.Set(1, 0, color.NRGBA{R: 255, A: 255})
+	thumb = thumbnail(img, 1)
+	r, g, b, a := thumb.At(0, 0).RGBA()
+	assert.Equal(t, [4]uint32{0x8080, 0, 0, 0x8080}, [4]uint32{r, g, b, a})
+
+	assert.Same(t, img, thumbnail(img, 2))
+}
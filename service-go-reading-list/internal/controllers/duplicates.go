@@ -0,0 +1,327 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories"
+)
+
+// DuplicateBookError is returned by AddBook when the new book looks like
+// books that are already on the reading list.
+type DuplicateBookError struct {
+	Candidates []models.Book
+}
+
+func (e *DuplicateBookError) Error() string {
+	return fmt.Sprintf("the book looks like a duplicate of [%s], set allowDuplicate to add it anyway", strings.Join(e.CandidateIds(), ", "))
+}
+
+// CandidateIds returns the ids of the books that look like the new book.
+func (e *DuplicateBookError) CandidateIds() []string {
+	ids := make([]string, len(e.Candidates))
+	for i, book := range e.Candidates {
+		ids[i] = book.Id
+	}
+	return ids
+}
+
+// FindDuplicates returns the books on the reading list that look like the
+// given book, in the order they were added.
+func (c *BookController) FindDuplicates(ctx context.Context, book models.Book) ([]models.Book, error) {
+	books, err := c.bookRepository.List(ctx)
+	if err != nil {
+		return nil, makeHttpUnexpectedError(err)
+	}
+	var duplicates []models.Book
+	for _, other := range books {
+		if other.Id != book.Id && isDuplicate(book, other) {
+			duplicates = append(duplicates, other)
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i].AddedBefore(duplicates[j])
+	})
+	return duplicates, nil
+}
+
+// FindDuplicateClusters groups the books that look like the same book. A book
+// is in the cluster of all the books it looks like, directly or through other
+// books. The clusters are ordered by their first added book.
+func (c *BookController) FindDuplicateClusters(ctx context.Context) ([]models.DuplicateCluster, error) {
+	books, err := c.bookRepository.List(ctx)
+	if err != nil {
+		return nil, makeHttpUnexpectedError(err)
+	}
+	sort.Slice(books, func(i, j int) bool {
+		return books[i].AddedBefore(books[j])
+	})
+
+	// parents is a union-find forest over the indexes of the books.
+	parents := make([]int, len(books))
+	for i := range parents {
+		parents[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parents[i] != i {
+			parents[i] = find(parents[i])
+		}
+		return parents[i]
+	}
+	for i := range books {
+		for j := i + 1; j < len(books); j++ {
+			if isDuplicate(books[i], books[j]) {
+				// The root is the first added book of the cluster.
+				rootI, rootJ := find(i), find(j)
+				parents[max(rootI, rootJ)] = min(rootI, rootJ)
+			}
+		}
+	}
+
+	clusterOf := make(map[int]int)
+	clusters := make([]models.DuplicateCluster, 0)
+	for i, book := range books {
+		root := find(i)
+		index, ok := clusterOf[root]
+		if !ok {
+			index = len(clusters)
+			clusterOf[root] = index
+			clusters = append(clusters, models.DuplicateCluster{})
+		}
+		clusters[index].Books = append(clusters[index].Books, book)
+	}
+	duplicates := make([]models.DuplicateCluster, 0)
+	for _, cluster := range clusters {
+		if len(cluster.Books) > 1 {
+			duplicates = append(duplicates, cluster)
+		}
+	}
+	return duplicates, nil
+}
+
+// MergeBooks folds the source book into the target book and moves the source
+// book to the trash. The target keeps its details and takes the ones it is
+// missing from the source, the tags of both, the furthest reading status and
+// the reading history of both. The id of the source is recorded in the
+// MergedIds of the target.
+func (c *BookController) MergeBooks(ctx context.Context, targetId, sourceId string) (models.Book, error) {
+	if sourceId == "" {
+		return models.Book{}, fiber.NewError(http.StatusBadRequest, "the source book id is required")
+	}
+	if targetId == sourceId {
+		return models.Book{}, fiber.NewError(http.StatusBadRequest, "a book cannot be merged into itself")
+	}
+	var merged models.Book
+	var auditEntries []models.AuditEntry
+	merge := func(repo models.BookRepository) error {
+		target, err := repo.GetById(ctx, targetId)
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return makeHttpNotFoundError(targetId)
+		} else if err != nil {
+			return err
+		}
+		source, err := repo.GetById(ctx, sourceId)
+		if errors.Is(err, repositories.ErrRecordNotFound) {
+			return makeHttpNotFoundError(sourceId)
+		} else if err != nil {
+			return err
+		}
+		merged = mergeBook(target, source, time.Now().UTC())
+		if merged, err = repo.Update(ctx, merged); err != nil {
+			return err
+		}
+		trashed, err := repo.DeleteById(ctx, sourceId)
+		if err != nil {
+			return err
+		}
+		auditEntries = []models.AuditEntry{
+			newAuditEntry(ctx, models.AuditOperationMerge, &target, &merged),
+			newAuditEntry(ctx, models.AuditOperationDelete, &source, &trashed),
+		}
+		return nil
+	}
+
+	var err error
+	if transactor, ok := c.bookRepository.(models.BookTransactor); ok {
+		err = transactor.RunInTransaction(ctx, merge)
+	} else {
+		err = merge(c.bookRepository)
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return models.Book{}, fiberErr
+	} else if err != nil {
+		return models.Book{}, makeHttpUnexpectedError(err)
+	}
+	c.audit(ctx, auditEntries...)
+	c.changed()
+	return merged, nil
+}
+
+// mergeBook returns the target book with the source book folded into it.
+func mergeBook(target, source models.Book, now time.Time) models.Book {
+	merged := target
+	if merged.Author == "" {
+		merged.Author = source.Author
+	}
+	if merged.ISBN == "" {
+		merged.ISBN = source.ISBN
+	}
+	if merged.Rating == 0 {
+		merged.Rating = source.Rating
+	}
+	merged.Tags = normalizeTags(append(append([]string(nil), target.Tags...), source.Tags...))
+
+	// The status is the furthest along of the two, and so are the dates it implies.
+	if statusProgress(source.Status) > statusProgress(target.Status) {
+		merged.Status = source.Status
+	}
+	if merged.Status != models.ReadStatusToRead {
+		merged.StartedAt = earliest(target.StartedAt, source.StartedAt)
+	}
+	if merged.Status == models.ReadStatusRead {
+		merged.FinishedAt = latest(target.FinishedAt, source.FinishedAt)
+	}
+	merged.CreatedAt = earliest(target.CreatedAt, source.CreatedAt)
+	merged.UpdatedAt = &now
+	merged.MergedIds = append(append(append([]string(nil), target.MergedIds...), source.Id), source.MergedIds...)
+	return merged
+}
+
+func statusProgress(status models.ReadStatus) int {
+	switch status {
+	case models.ReadStatusReading:
+		return 1
+	case models.ReadStatusRead:
+		return 2
+	}
+	return 0
+}
+
+func earliest(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.Before(*a)) {
+		return b
+	}
+	return a
+}
+
+func latest(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.After(*a)) {
+		return b
+	}
+	return a
+}
+
+// isDuplicate tells whether two books look like the same book. Books with
+// ISBNs are the same when their ISBNs are; otherwise they are the same when
+// they have the same author and nearly the same title.
+func isDuplicate(a, b models.Book) bool {
+	isbnA, isbnB := normalizeIsbn(a.ISBN), normalizeIsbn(b.ISBN)
+	if isbnA != "" && isbnB != "" {
+		return isbnA == isbnB
+	}
+	if normalizeAuthor(a.Author) != normalizeAuthor(b.Author) {
+		return false
+	}
+	return similarTitles(normalizeTitle(a.Title), normalizeTitle(b.Title))
+}
+
+// similarTitles tells whether two normalized titles are the same but for a
+// few typos: one edit for every eight characters of the longer title.
+func similarTitles(a, b string) bool {
+	if a == b {
+		return true
+	}
+	length := max(len([]rune(a)), len([]rune(b)))
+	return length >= 8 && levenshtein(a, b) <= length/8
+}
+
+// titleArticles are the leading words ignored when comparing titles.
+var titleArticles = []string{"the", "a", "an"}
+
+// normalizeTitle lower-cases the title, replaces its punctuation with spaces
+// and drops a leading article.
+func normalizeTitle(title string) string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > 1 {
+		for _, article := range titleArticles {
+			if words[0] == article {
+				words = words[1:]
+				break
+			}
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// normalizeAuthor keeps the letters and digits of the author, so that
+// "J. R. R. Tolkien" and "JRR Tolkien" are the same author.
+func normalizeAuthor(author string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, author)
+}
+
+// normalizeIsbn returns the ISBN-13 of the ISBN, ignoring its hyphens and
+// spaces, or the ISBN as is when it is neither an ISBN-10 nor an ISBN-13.
+func normalizeIsbn(isbn string) string {
+	var digits []rune
+	for _, r := range strings.ToUpper(isbn) {
+		if unicode.IsDigit(r) || r == 'X' {
+			digits = append(digits, r)
+		}
+	}
+	if len(digits) != 10 || strings.ContainsRune(string(digits[:9]), 'X') {
+		return string(digits)
+	}
+	isbn13 := append([]rune("978"), digits[:9]...)
+	sum := 0
+	for i, r := range isbn13 {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(r-'0') * weight
+	}
+	return string(isbn13) + fmt.Sprint((10-sum%10)%10)
+}
+
+// levenshtein returns the number of single character edits between a and b.
+func levenshtein(a, b string) int {
+	runesA, runesB := []rune(a), []rune(b)
+	previous := make([]int, len(runesB)+1)
+	current := make([]int, len(runesB)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(runesA); i++ {
+		current[0] = i
+		for j := 1; j <= len(runesB); j++ {
+			cost := 1
+			if runesA[i-1] == runesB[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(runesB)]
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories"
+)
+
+func TestDuplicates(t *testing.T) {
+	ctx := context.Background()
+	date := func(day int) *time.Time {
+		d := time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	newController := func() *BookController {
+		return NewBookController(repositories.NewBookRepository([]models.Book{
+			{Id: "1", Title: "The Hobbit", Author: "J. R. R. Tolkien", Status: models.ReadStatusRead, Tags: []string{"fantasy"},
+				CreatedAt: date(1), StartedAt: date(2), FinishedAt: date(10)},
+			{Id: "2", Title: "Hobbit", Author: "JRR Tolkien", ISBN: "0-261-10221-4", Rating: 5, Tags: []string{"classic"},
+				Status: models.ReadStatusReading, CreatedAt: date(3), StartedAt: date(1)},
+			{Id: "3", Title: "A Wizard of Earthsea", Author: "Ursula K. Le Guin", Status: models.ReadStatusToRead, CreatedAt: date(4)},
+			{Id: "4", Title: "Wizard of Earthsae", Author: "Ursula K Le Guin", ISBN: "9780553383041", Status: models.ReadStatusToRead, CreatedAt: date(5)},
+			{Id: "5", Title: "The Tombs of Atuan", Author: "Ursula K. Le Guin", ISBN: "9780689845369", Status: models.ReadStatusToRead, CreatedAt: date(6)},
+		}), repositories.NewAuditRepository())
+	}
+
+	t.Run("IsDuplicate", func(t *testing.T) {
+		assert.True(t, isDuplicate(models.Book{Title: "The Hobbit", Author: "Tolkien"}, models.Book{Title: "hobbit!", Author: "tolkien"}))
+		assert.True(t, isDuplicate(models.Book{Title: "Dune", ISBN: "0-441-17271-7"}, models.Book{Title: "Dune Messiah", ISBN: "978-0441172719"}))
+		assert.False(t, isDuplicate(models.Book{Title: "Dune", ISBN: "9780441172719"}, models.Book{Title: "Dune", ISBN: "9780593098233"}))
+		assert.False(t, isDuplicate(models.Book{Title: "Dune", Author: "Frank Herbert"}, models.Book{Title: "Dune", Author: "Brian Herbert"}))
+		// Short titles must match exactly, longer ones may have a typo.
+		assert.False(t, isDuplicate(models.Book{Title: "Emma"}, models.Book{Title: "Emmy"}))
+		assert.True(t, isDuplicate(models.Book{Title: "Pride and Prejudice"}, models.Book{Title: "Pride and Prejudise"}))
+	})
+
+	t.Run("AddBook", func(t *testing.T) {
+		controller := newController()
+		_, err := controller.AddBook(ctx, models.Book{Title: "the hobbit", Author: "J.R.R. Tolkien"}, false)
+		var duplicateErr *DuplicateBookError
+		assert.True(t, errors.As(err, &duplicateErr))
+		assert.Equal(t, []string{"1", "2"}, duplicateErr.CandidateIds())
+		assert.Equal(t, "the book looks like a duplicate of [1, 2], set allowDuplicate to add it anyway", err.Error())
+
+		book, err := controller.AddBook(ctx, models.Book{Title: "the hobbit", Author: "J.R.R. Tolkien"}, true)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, book.Title)
+	})
+
+	t.Run("FindDuplicateClusters", func(t *testing.T) {
+		clusters, err := newController().FindDuplicateClusters(ctx)
+		assert.NoError(t, err)
+		var ids [][]string
+		for _, cluster := range clusters {
+			ids = append(ids, (&DuplicateBookError{Candidates: cluster.Books}).CandidateIds())
+		}
+		assert.Equal(t, [][]string{{"1", "2"}, {"3", "4"}}, ids)
+	})
+
+	t.Run("MergeBooks", func(t *testing.T) {
+		controller := newController()
+		merged, err := controller.MergeBooks(ctx, "2", "1")
+		assert.NoError(t, err)
+		assert.Equal(t, "Hobbit", merged.Title)
+		assert.Equal(t, models.ReadStatusRead, merged.Status)
+		assert.Equal(t, 5, merged.Rating)
+		assert.Equal(t, []string{"classic", "fantasy"}, merged.Tags)
+		assert.Equal(t, date(1), merged.CreatedAt)
+		assert.Equal(t, date(1), merged.StartedAt)
+		assert.Equal(t, date(10), merged.FinishedAt)
+		assert.Equal(t, []string{"1"}, merged.MergedIds)
+
+		// The source is in the trash and the history is kept through later merges and updates.
+		_, err = controller.GetBook(ctx, "1")
+		assert.Equal(t, makeHttpNotFoundError("1"), err)
+		trash, err := controller.ListTrash(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, trash, 1)
+		merged, err = controller.MergeBooks(ctx, "3", "2")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"2", "1"}, merged.MergedIds)
+		merged, err = controller.UpdateBook(ctx, models.Book{Id: "3", Title: "The Hobbit", Status: models.ReadStatusRead})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"2", "1"}, merged.MergedIds)
+
+		_, err = controller.MergeBooks(ctx, "3", "3")
+		assert.Equal(t, fiber.NewError(http.StatusBadRequest, "a book cannot be merged into itself"), err)
+		_, err = controller.MergeBooks(ctx, "3", "1")
+		assert.Equal(t, makeHttpNotFoundError("1"), err)
+		_, err = controller.MergeBooks(ctx, "missing", "4")
+		assert.Equal(t, makeHttpNotFoundError("missing"), err)
+	})
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/tenancy"
+)
+
+// HighlightController keeps the passages the readers save from the books of
+// the reading list. The highlights are removed with their book when it is
+// purged.
+type HighlightController struct {
+	books      *BookController
+	highlights models.HighlightRepository
+}
+
+// NewHighlightController returns the controller of the highlights of the
+// books of the books controller.
+func NewHighlightController(books *BookController, highlights models.HighlightRepository) *HighlightController {
+	c := &HighlightController{books: books, highlights: highlights}
+	books.OnPurge(c.deleteHighlights)
+	return c
+}
+
+func (c *HighlightController) AddHighlight(ctx context.Context, bookId string, newHighlight models.Highlight) (models.Highlight, error) {
+	newHighlight.Tags = normalizeTags(newHighlight.Tags)
+	if err := validateHighlight(newHighlight); err != nil {
+		return models.Highlight{}, err
+	}
+	if _, err := c.books.GetBook(ctx, bookId); err != nil {
+		return models.Highlight{}, err
+	}
+	now := time.Now().UTC()
+	newHighlight.BookId = bookId
+	newHighlight.CreatedAt = &now
+	newHighlight.UpdatedAt = &now
+	highlight, err := c.highlights.Add(ctx, newHighlight)
+	if err != nil {
+		return models.Highlight{}, makeHttpUnexpectedError(err)
+	}
+	return highlight, nil
+}
+
+// UpdateHighlight replaces the passage and the note of the highlight, which
+// stays on its book.
+func (c *HighlightController) UpdateHighlight(ctx context.Context, bookId string, updatedHighlight models.Highlight) (models.Highlight, error) {
+	updatedHighlight.Tags = normalizeTags(updatedHighlight.Tags)
+	if err := validateHighlight(updatedHighlight); err != nil {
+		return models.Highlight{}, err
+	}
+	existingHighlight, err := c.GetHighlight(ctx, bookId, updatedHighlight.Id)
+	if err != nil {
+		return models.Highlight{}, err
+	}
+	now := time.Now().UTC()
+	updatedHighlight.BookId = existingHighlight.BookId
+	updatedHighlight.CreatedAt = existingHighlight.CreatedAt
+	updatedHighlight.UpdatedAt = &now
+	highlight, err := c.highlights.Update(ctx, updatedHighlight)
+	if errors.Is(err, repositories.ErrRecordNotFound) {
+		return models.Highlight{}, makeHttpHighlightNotFoundError(updatedHighlight.Id)
+	} else if err != nil {
+		return models.Highlight{}, makeHttpUnexpectedError(err)
+	}
+	return highlight, nil
+}
+
+// GetHighlight returns the highlight of the book. The highlights of the
+// books in the trash are not found until the book is restored.
+func (c *HighlightController) GetHighlight(ctx context.Context, bookId string, id string) (models.Highlight, error) {
+	if _, err := c.books.GetBook(ctx, bookId); err != nil {
+		return models.Highlight{}, err
+	}
+	highlight, err := c.highlights.GetById(ctx, id)
+	if errors.Is(err, repositories.ErrRecordNotFound) || (err == nil && highlight.BookId != bookId) {
+		return models.Highlight{}, makeHttpHighlightNotFoundError(id)
+	} else if err != nil {
+		return models.Highlight{}, makeHttpUnexpectedError(err)
+	}
+	return highlight, nil
+}
+
+func (c *HighlightController) DeleteHighlight(ctx context.Context, bookId string, id string) (models.Highlight, error) {
+	if _, err := c.GetHighlight(ctx, bookId, id); err != nil {
+		return models.Highlight{}, err
+	}
+	highlight, err := c.highlights.DeleteById(ctx, id)
+	if errors.Is(err, repositories.ErrRecordNotFound) {
+		return models.Highlight{}, makeHttpHighlightNotFoundError(id)
+	} else if err != nil {
+		return models.Highlight{}, makeHttpUnexpectedError(err)
+	}
+	return highlight, nil
+}
+
+// ListHighlights returns the highlights of the book matching the filter, in
+// reading order.
+func (c *HighlightController) ListHighlights(ctx context.Context, bookId string, filter models.HighlightFilter) ([]models.Highlight, error) {
+	if _, err := c.books.GetBook(ctx, bookId); err != nil {
+		return nil, err
+	}
+	filter.BookId = bookId
+	highlights, err := c.highlights.List(ctx, filter)
+	if err != nil {
+		return nil, makeHttpUnexpectedError(err)
+	}
+	return highlights, nil
+}
+
+// SearchHighlights returns the highlights of all the books on the reading
+// list that match the filter, grouped by book. The highlights of the books
+// in the trash are left out.
+func (c *HighlightController) SearchHighlights(ctx context.Context, filter models.HighlightFilter) ([]models.Highlight, error) {
+	highlights, err := c.highlights.List(ctx, filter)
+	if err != nil {
+		return nil, makeHttpUnexpectedError(err)
+	}
+	if len(highlights) == 0 {
+		return highlights, nil
+	}
+	books, err := c.books.ListBooks(ctx, models.BookFilter{})
+	if err != nil {
+		return nil, err
+	}
+	listed := make(map[string]bool, len(books))
+	for _, book := range books {
+		listed[book.Id] = true
+	}
+	matching := make([]models.Highlight, 0, len(highlights))
+	for _, highlight := range highlights {
+		if listed[highlight.BookId] {
+			matching = append(matching, highlight)
+		}
+	}
+	return matching, nil
+}
+
+// deleteHighlights removes the highlights of the purged books. The books are
+// gone whether their highlights can be removed or not, so the failures are
+// logged.
+func (c *HighlightController) deleteHighlights(ctx context.Context, books []models.Book) {
+	for _, book := range books {
+		if _, err := c.highlights.DeleteByBook(ctx, book.Id); err != nil {
+			logrus.WithFields(logrus.Fields{"tenant": tenancy.FromContext(ctx), "bookId": book.Id}).Errorf("failed to delete the highlights: %v", err)
+		}
+	}
+}
+
+func validateHighlight(highlight models.Highlight) *fiber.Error {
+	if strings.TrimSpace(highlight.Text) == "" {
+		return fiber.NewError(http.StatusBadRequest, "highlight text is required")
+	}
+	if highlight.Page < 0 {
+		return fiber.NewError(http.StatusBadRequest, "highlight page should not be negative")
+	}
+	return nil
+}
+
+func makeHttpHighlightNotFoundError(id string) *fiber.Error {
+	return fiber.NewError(http.StatusNotFound, fmt.Sprintf("the highlight id [%s] is not found", id))
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories"
+)
+
+func TestHighlightController(t *testing.T) {
+	ctx := context.Background()
+	books := NewBookController(&MockBookRepository{}, repositories.NewAuditRepository())
+	highlights := repositories.NewTenantHighlightRepository()
+	controller := NewHighlightController(books, highlights)
+	for _, id := range []string{"dune", "emma"} {
+		_, err := books.AddBook(ctx, models.Book{Id: id, Title: id}, true)
+		require.NoError(t, err)
+	}
+
+	fear, err := controller.AddHighlight(ctx, "dune", models.Highlight{
+		Id: "ignored", BookId: "emma", Text: "I must not fear.", Page: 12, Tags: []string{" fear ", "", "fear", "litany"},
+	})
+	require.NoError(t, err)
+	_, err = controller.AddHighlight(ctx, "emma", models.Highlight{Text: "Emma Woodhouse, handsome, clever, and rich.", Tags: []string{"opening"}})
+	require.NoError(t, err)
+
+	t.Run("AddHighlight", func(t *testing.T) {
+		assert.NotEqual(t, "ignored", fear.Id)
+		assert.Equal(t, "dune", fear.BookId)
+		assert.Equal(t, []string{"fear", "litany"}, fear.Tags)
+		require.NotNil(t, fear.CreatedAt)
+		assert.Equal(t, fear.CreatedAt, fear.UpdatedAt)
+
+		_, err := controller.AddHighlight(ctx, "dune", models.Highlight{Text: " \n"})
+		assert.Equal(t, fiber.NewError(http.StatusBadRequest, "highlight text is required"), err)
+		_, err = controller.AddHighlight(ctx, "dune", models.Highlight{Text: "I must not fear.", Page: -1})
+		assert.Equal(t, fiber.NewError(http.StatusBadRequest, "highlight page should not be negative"), err)
+		_, err = controller.AddHighlight(ctx, "missing", models.Highlight{Text: "I must not fear."})
+		assert.Equal(t, fiber.NewError(http.StatusNotFound, "the book id [missing] is not found"), err)
+	})
+
+	t.Run("UpdateHighlight", func(t *testing.T) {
+		updated, err := controller.UpdateHighlight(ctx, "dune", models.Highlight{Id: fear.Id, BookId: "emma", Text: "I must not fear.", Location: "Chapter 1", Note: "The litany against fear."})
+		require.NoError(t, err)
+		assert.Equal(t, "dune", updated.BookId)
+		assert.Zero(t, updated.Page)
+		assert.Equal(t, "Chapter 1", updated.Location)
+		assert.Equal(t, fear.CreatedAt, updated.CreatedAt)
+		assert.False(t, updated.UpdatedAt.Before(*fear.UpdatedAt))
+
+		// The highlights are only found on their book.
+		_, err = controller.UpdateHighlight(ctx, "emma", models.Highlight{Id: fear.Id, Text: "I must not fear."})
+		assert.Equal(t, fiber.NewError(http.StatusNotFound, "the highlight id ["+fear.Id+"] is not found"), err)
+		_, err = controller.GetHighlight(ctx, "emma", fear.Id)
+		assert.Equal(t, fiber.NewError(http.StatusNotFound, "the highlight id ["+fear.Id+"] is not found"), err)
+		_, err = controller.DeleteHighlight(ctx, "emma", fear.Id)
+		assert.Equal(t, fiber.NewError(http.StatusNotFound, "the highlight id ["+fear.Id+"] is not found"), err)
+		_, err = controller.UpdateHighlight(ctx, "dune", models.Highlight{Id: fear.Id})
+		assert.Equal(t, fiber.NewError(http.StatusBadRequest, "highlight text is required"), err)
+	})
+
+	t.Run("SearchHighlights", func(t *testing.T) {
+		found, err := controller.SearchHighlights(ctx, models.HighlightFilter{Query: "LITANY"})
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, fear.Id, found[0].Id)
+		found, err = controller.SearchHighlights(ctx, models.HighlightFilter{Tag: "opening"})
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, "emma", found[0].BookId)
+
+		// The highlights of the books in the trash are left out until the
+		// books are restored.
+		_, err = books.DeleteBook(ctx, "emma")
+		require.NoError(t, err)
+		found, err = controller.SearchHighlights(ctx, models.HighlightFilter{})
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, "dune", found[0].BookId)
+		_, err = controller.ListHighlights(ctx, "emma", models.HighlightFilter{})
+		assert.Equal(t, fiber.NewError(http.StatusNotFound, "the book id [emma] is not found"), err)
+		_, err = books.RestoreBook(ctx, "emma")
+		require.NoError(t, err)
+		found, err = controller.SearchHighlights(ctx, models.HighlightFilter{})
+		require.NoError(t, err)
+		assert.Len(t, found, 2)
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		_, err := books.PurgeBook(ctx, "dune")
+		require.NoError(t, err)
+		remaining, err := highlights.List(ctx, models.HighlightFilter{})
+		require.NoError(t, err)
+		require.Len(t, remaining, 1)
+		assert.Equal(t, "emma", remaining[0].BookId)
+
+		_, err = books.DeleteBook(ctx, "emma")
+		require.NoError(t, err)
+		_, err = books.PurgeTrash(ctx, 0)
+		require.NoError(t, err)
+		remaining, err = highlights.List(ctx, models.HighlightFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, remaining)
+	})
+}
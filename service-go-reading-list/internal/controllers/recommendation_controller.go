@@ -0,0 +1,231 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+)
+
+const (
+	// DefaultRecommendationLimit is the number of recommendations returned
+	// when no limit is requested.
+	DefaultRecommendationLimit = 10
+	// MaxRecommendationLimit is the maximum number of recommendations returned.
+	MaxRecommendationLimit = 50
+)
+
+const (
+	// highRating is the lowest rating of the read books that similar books
+	// are recommended for.
+	highRating = 4
+	// minSimilarity is the similarity from which a book is recommended for
+	// being similar to a highly rated one.
+	minSimilarity = 0.5
+
+	authorScoreWeight     = 2.0
+	tagScoreWeight        = 1.0
+	similarityScoreWeight = 3.0
+)
+
+type RecommendationController struct {
+	bookRepository models.BookRepository
+	// catalogue are the books that are recommended in addition to the ones on the reading list.
+	catalogue []models.Book
+}
+
+func NewRecommendationController(bookRepository models.BookRepository, catalogue []models.Book) *RecommendationController {
+	books := make([]models.Book, len(catalogue))
+	for i, book := range catalogue {
+		// The catalogue books would be added to the reading list as books to read.
+		setDefaultBookFields(&book)
+		books[i] = book
+	}
+	return &RecommendationController{bookRepository, books}
+}
+
+// likedBook is a read book with how much it was liked, between 0 and 1.
+type likedBook struct {
+	book       models.Book
+	preference float64
+}
+
+// Recommend ranks the books to read next: the to_read books of the reading
+// list and the catalogue books that are not on it. The books are scored by
+// their author, their tags and their similarity to the read books, weighted
+// by the ratings of the read books. The books with no score are left out.
+func (c *RecommendationController) Recommend(ctx context.Context, limit int) ([]models.Recommendation, error) {
+	if limit < 1 || limit > MaxRecommendationLimit {
+		return nil, fiber.NewError(http.StatusBadRequest, fmt.Sprintf("limit should be between 1 and %d", MaxRecommendationLimit))
+	}
+	books, err := c.bookRepository.List(ctx)
+	if err != nil {
+		return nil, makeHttpUnexpectedError(err)
+	}
+
+	var liked []likedBook
+	listed := make(map[string]bool)
+	for _, book := range books {
+		listed[book.Id] = true
+		listed[bookIdentity(book)] = true
+		if preference := readingPreference(book); preference > 0 {
+			liked = append(liked, likedBook{book, preference})
+		}
+	}
+	recommendations := make([]models.Recommendation, 0)
+	recommend := func(book models.Book, source models.RecommendationSource) {
+		if score, reasons := scoreBook(book, liked); score > 0 {
+			recommendations = append(recommendations, models.Recommendation{
+				Book:    book,
+				Score:   math.Round(score*100) / 100,
+				Source:  source,
+				Reasons: reasons,
+			})
+		}
+	}
+	for _, book := range books {
+		if book.Status == models.ReadStatusToRead {
+			recommend(book, models.RecommendationSourceReadingList)
+		}
+	}
+	for _, book := range c.catalogue {
+		if !listed[book.Id] && !listed[bookIdentity(book)] {
+			recommend(book, models.RecommendationSourceCatalogue)
+		}
+	}
+
+	sort.Slice(recommendations, func(i, j int) bool {
+		a, b := recommendations[i], recommendations[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Book.Title != b.Book.Title {
+			return a.Book.Title < b.Book.Title
+		}
+		return a.Book.Id < b.Book.Id
+	})
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	return recommendations, nil
+}
+
+// readingPreference returns how much a read book was liked: 1 for a rating of
+// 5 down to 0 for a rating of 2 or less. Unrated read books count as half
+// liked, and the books that are not read yet are not taken into account.
+func readingPreference(book models.Book) float64 {
+	switch {
+	case book.Status != models.ReadStatusRead:
+		return 0
+	case book.Rating == 0:
+		return 0.5
+	}
+	return math.Max(0, float64(book.Rating-2)/3)
+}
+
+// scoreBook scores the book against the liked books, explaining each part of the score.
+func scoreBook(book models.Book, liked []likedBook) (float64, []string) {
+	var score float64
+	var reasons []string
+
+	var authorScore float64
+	var sameAuthor []string
+	for _, l := range liked {
+		if book.Author != "" && normalizeName(l.book.Author) == normalizeName(book.Author) {
+			authorScore += l.preference
+			sameAuthor = append(sameAuthor, l.book.Title)
+		}
+	}
+	if authorScore > 0 {
+		score += authorScoreWeight * authorScore
+		reasons = append(reasons, fmt.Sprintf("by %s, the author of [%s]", book.Author, strings.Join(sameAuthor, ", ")))
+	}
+
+	var sharedTags []string
+	for _, tag := range book.Tags {
+		var tagScore float64
+		for _, l := range liked {
+			if containsTag(l.book.Tags, tag) {
+				tagScore += l.preference
+			}
+		}
+		if tagScore > 0 {
+			score += tagScoreWeight * tagScore
+			sharedTags = append(sharedTags, tag)
+		}
+	}
+	if len(sharedTags) > 0 {
+		reasons = append(reasons, fmt.Sprintf("tagged [%s] like books you have read", strings.Join(sharedTags, ", ")))
+	}
+
+	var mostSimilar *likedBook
+	var bestSimilarity float64
+	for i, l := range liked {
+		if l.book.Rating < highRating {
+			continue
+		}
+		if similarity := bookSimilarity(book, l.book); similarity > bestSimilarity {
+			mostSimilar, bestSimilarity = &liked[i], similarity
+		}
+	}
+	if mostSimilar != nil && bestSimilarity >= minSimilarity {
+		score += similarityScoreWeight * bestSimilarity * mostSimilar.preference
+		reasons = append(reasons, fmt.Sprintf("similar to [%s], which you rated %d/5", mostSimilar.book.Title, mostSimilar.book.Rating))
+	}
+	return score, reasons
+}
+
+// bookSimilarity is the Jaccard similarity of the author and the tags of the books.
+func bookSimilarity(a, b models.Book) float64 {
+	featuresA, featuresB := bookFeatures(a), bookFeatures(b)
+	if len(featuresA) == 0 || len(featuresB) == 0 {
+		return 0
+	}
+	shared := 0
+	for feature := range featuresA {
+		if featuresB[feature] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(featuresA)+len(featuresB)-shared)
+}
+
+func bookFeatures(book models.Book) map[string]bool {
+	features := make(map[string]bool)
+	if book.Author != "" {
+		features["author:"+normalizeName(book.Author)] = true
+	}
+	for _, tag := range book.Tags {
+		features["tag:"+normalizeName(tag)] = true
+	}
+	return features
+}
+
+// bookIdentity identifies a book by its title and author, so that a
+// catalogue book is not recommended when it is already on the reading list
+// with another id.
+func bookIdentity(book models.Book) string {
+	return normalizeName(book.Title) + "\x00" + normalizeName(book.Author)
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
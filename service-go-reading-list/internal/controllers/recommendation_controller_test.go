@@ -0,0 +1,75 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories"
+)
+
+func TestRecommendationController(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := repositories.NewBookRepository([]models.Book{
+		{Id: "1", Title: "The Hobbit", Author: "J. R. R. Tolkien", Status: models.ReadStatusRead, Tags: []string{"fantasy", "adventure"}, Rating: 5},
+		{Id: "2", Title: "Dune", Author: "Frank Herbert", Status: models.ReadStatusRead, Tags: []string{"scifi"}, Rating: 2},
+		{Id: "3", Title: "The Silmarillion", Author: "J. R. R. Tolkien", Status: models.ReadStatusToRead, Tags: []string{"Fantasy"}},
+		{Id: "4", Title: "Children of Dune", Author: "Frank Herbert", Status: models.ReadStatusToRead, Tags: []string{"scifi"}},
+		{Id: "5", Title: "A Wizard of Earthsea", Author: "Ursula K. Le Guin", Status: models.ReadStatusToRead, Tags: []string{"fantasy", "adventure"}},
+		{Id: "6", Title: "Farmer Giles of Ham", Author: "J. R. R. Tolkien", Status: models.ReadStatusReading},
+	})
+	controller := NewRecommendationController(bookRepo, []models.Book{
+		{Id: "c1", Title: "The Lord of the Rings", Author: "J. R. R. Tolkien", Tags: []string{"fantasy", "adventure"}},
+		// Already on the reading list with another id.
+		{Id: "c2", Title: "the silmarillion", Author: "J.  R. R. Tolkien", Tags: []string{"fantasy"}},
+	})
+
+	t.Run("Recommend", func(t *testing.T) {
+		recommendations, err := controller.Recommend(ctx, DefaultRecommendationLimit)
+		assert.NoError(t, err)
+		assert.Len(t, recommendations, 3)
+
+		assert.Equal(t, "c1", recommendations[0].Book.Id)
+		assert.Equal(t, models.RecommendationSourceCatalogue, recommendations[0].Source)
+		assert.Equal(t, 7.0, recommendations[0].Score)
+		assert.Equal(t, models.ReadStatusToRead, recommendations[0].Book.Status)
+
+		assert.Equal(t, "3", recommendations[1].Book.Id)
+		assert.Equal(t, models.RecommendationSourceReadingList, recommendations[1].Source)
+		assert.Equal(t, 5.0, recommendations[1].Score)
+		assert.Equal(t, []string{
+			"by J. R. R. Tolkien, the author of [The Hobbit]",
+			"tagged [Fantasy] like books you have read",
+			"similar to [The Hobbit], which you rated 5/5",
+		}, recommendations[1].Reasons)
+
+		// Children of Dune is left out, as Dune was rated 2/5.
+		assert.Equal(t, "5", recommendations[2].Book.Id)
+		assert.Equal(t, 3.5, recommendations[2].Score)
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		recommendations, err := controller.Recommend(ctx, 1)
+		assert.NoError(t, err)
+		assert.Len(t, recommendations, 1)
+
+		_, err = controller.Recommend(ctx, 0)
+		assert.Equal(t, fiber.NewError(http.StatusBadRequest, "limit should be between 1 and 50"), err)
+	})
+
+	t.Run("NothingRead", func(t *testing.T) {
+		controller := NewRecommendationController(repositories.NewBookRepository([]models.Book{
+			{Id: "1", Title: "The Hobbit", Author: "J. R. R. Tolkien", Status: models.ReadStatusToRead},
+		}), nil)
+		recommendations, err := controller.Recommend(ctx, DefaultRecommendationLimit)
+		assert.NoError(t, err)
+		assert.Empty(t, recommendations)
+		assert.NotNil(t, recommendations)
+	})
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories"
+)
+
+// topAuthorsLimit is the number of authors reported in the reading stats.
+const topAuthorsLimit = 5
+
+type StatsController struct {
+	bookRepository models.BookRepository
+	goalRepository models.GoalRepository
+	now            func() time.Time
+}
+
+func NewStatsController(bookRepository models.BookRepository, goalRepository models.GoalRepository) *StatsController {
+	return &StatsController{bookRepository, goalRepository, time.Now}
+}
+
+func (c *StatsController) GetStats(ctx context.Context) (models.ReadingStats, error) {
+	books, err := c.bookRepository.List(ctx)
+	if err != nil {
+		return models.ReadingStats{}, makeHttpUnexpectedError(err)
+	}
+	stats := models.ReadingStats{
+		TotalBooks:      len(books),
+		BooksByStatus:   map[models.ReadStatus]int{models.ReadStatusToRead: 0, models.ReadStatusReading: 0, models.ReadStatusRead: 0},
+		FinishedByYear:  make(map[string]int),
+		FinishedByMonth: make(map[string]int),
+		TopAuthors:      make([]models.AuthorCount, 0),
+	}
+	authors := make(map[string]int)
+	var totalDays float64
+	timedBooks := 0
+	for _, book := range books {
+		stats.BooksByStatus[book.Status]++
+		if book.Author != "" {
+			authors[book.Author]++
+		}
+		if book.Status != models.ReadStatusRead || book.FinishedAt == nil {
+			continue
+		}
+		stats.FinishedByYear[book.FinishedAt.Format("2006")]++
+		stats.FinishedByMonth[book.FinishedAt.Format("2006-01")]++
+		if book.StartedAt != nil && !book.FinishedAt.Before(*book.StartedAt) {
+			totalDays += book.FinishedAt.Sub(*book.StartedAt).Hours() / 24
+			timedBooks++
+		}
+	}
+	for author, count := range authors {
+		stats.TopAuthors = append(stats.TopAuthors, models.AuthorCount{Author: author, Books: count})
+	}
+	sort.Slice(stats.TopAuthors, func(i, j int) bool {
+		if stats.TopAuthors[i].Books != stats.TopAuthors[j].Books {
+			return stats.TopAuthors[i].Books > stats.TopAuthors[j].Books
+		}
+		return stats.TopAuthors[i].Author < stats.TopAuthors[j].Author
+	})
+	if len(stats.TopAuthors) > topAuthorsLimit {
+		stats.TopAuthors = stats.TopAuthors[:topAuthorsLimit]
+	}
+	if timedBooks > 0 {
+		average := math.Round(totalDays/float64(timedBooks)*10) / 10
+		stats.AverageDaysToFinish = &average
+	}
+	return stats, nil
+}
+
+func (c *StatsController) PutGoal(ctx context.Context, goal models.Goal) (models.GoalProgress, error) {
+	if err := validateGoal(goal); err != nil {
+		return models.GoalProgress{}, err
+	}
+	if _, err := c.goalRepository.Put(ctx, goal); err != nil {
+		return models.GoalProgress{}, makeHttpUnexpectedError(err)
+	}
+	return c.goalProgress(ctx, goal)
+}
+
+func (c *StatsController) GetGoal(ctx context.Context, year int) (models.GoalProgress, error) {
+	goal, err := c.goalRepository.GetByYear(ctx, year)
+	if errors.Is(err, repositories.ErrRecordNotFound) {
+		return models.GoalProgress{}, makeHttpGoalNotFoundError(year)
+	} else if err != nil {
+		return models.GoalProgress{}, makeHttpUnexpectedError(err)
+	}
+	return c.goalProgress(ctx, goal)
+}
+
+func (c *StatsController) ListGoals(ctx context.Context) ([]models.GoalProgress, error) {
+	goals, err := c.goalRepository.List(ctx)
+	if err != nil {
+		return nil, makeHttpUnexpectedError(err)
+	}
+	progress := make([]models.GoalProgress, 0, len(goals))
+	for _, goal := range goals {
+		p, err := c.goalProgress(ctx, goal)
+		if err != nil {
+			return nil, err
+		}
+		progress = append(progress, p)
+	}
+	return progress, nil
+}
+
+func (c *StatsController) DeleteGoal(ctx context.Context, year int) (models.Goal, error) {
+	goal, err := c.goalRepository.DeleteByYear(ctx, year)
+	if errors.Is(err, repositories.ErrRecordNotFound) {
+		return models.Goal{}, makeHttpGoalNotFoundError(year)
+	} else if err != nil {
+		return models.Goal{}, makeHttpUnexpectedError(err)
+	}
+	return goal, nil
+}
+
+// goalProgress counts the books finished in the goal year and projects the
+// total at the end of the year from the pace so far.
+func (c *StatsController) goalProgress(ctx context.Context, goal models.Goal) (models.GoalProgress, error) {
+	books, err := c.bookRepository.List(ctx)
+	if err != nil {
+		return models.GoalProgress{}, makeHttpUnexpectedError(err)
+	}
+	progress := models.GoalProgress{Year: goal.Year, Target: goal.Target}
+	for _, book := range books {
+		if book.Status == models.ReadStatusRead && book.FinishedAt != nil && book.FinishedAt.Year() == goal.Year {
+			progress.Completed++
+		}
+	}
+	progress.Remaining = max(goal.Target-progress.Completed, 0)
+	progress.PercentComplete = math.Round(float64(progress.Completed)/float64(goal.Target)*1000) / 10
+
+	now := c.now().UTC()
+	start := time.Date(goal.Year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+	switch {
+	case now.Before(start):
+		// The year has not started yet, so there is no pace to project from.
+		progress.ProjectedTotal = progress.Completed
+	case !now.Before(end):
+		progress.ProjectedTotal = progress.Completed
+	default:
+		elapsed := now.Sub(start)
+		pace := float64(progress.Completed) / elapsed.Hours()
+		progress.ProjectedTotal = int(pace * end.Sub(start).Hours())
+		if pace > 0 && progress.Remaining > 0 {
+			completion := now.Add(time.Duration(float64(progress.Remaining) / pace * float64(time.Hour)))
+			if completion.Before(end) {
+				progress.ProjectedCompletion = &completion
+			}
+		}
+	}
+	if progress.Remaining == 0 {
+		progress.ProjectedCompletion = nil
+	}
+	progress.OnTrack = progress.Remaining == 0 || progress.ProjectedTotal >= goal.Target
+	return progress, nil
+}
+
+func validateGoal(goal models.Goal) *fiber.Error {
+	if goal.Year < 1 || goal.Year > 9999 {
+		return fiber.NewError(http.StatusBadRequest, "goal year should be between 1 and 9999")
+	}
+	if goal.Target < 1 {
+		return fiber.NewError(http.StatusBadRequest, "goal target should be at least 1")
+	}
+	return nil
+}
+
+func makeHttpGoalNotFoundError(year int) *fiber.Error {
+	return fiber.NewError(http.StatusNotFound, fmt.Sprintf("the goal for year [%d] is not found", year))
+}
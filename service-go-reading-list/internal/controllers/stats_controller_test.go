@@ -0,0 +1,83 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories"
+)
+
+func TestStatsController(t *testing.T) {
+	ctx := context.Background()
+	date := func(year int, month time.Month, day int) *time.Time {
+		d := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	bookRepo := repositories.NewBookRepository([]models.Book{
+		{Id: "1", Title: "Book 1", Author: "Author 1", Status: models.ReadStatusRead, StartedAt: date(2024, 1, 1), FinishedAt: date(2024, 1, 11)},
+		{Id: "2", Title: "Book 2", Author: "Author 1", Status: models.ReadStatusRead, StartedAt: date(2024, 2, 1), FinishedAt: date(2024, 2, 21)},
+		{Id: "3", Title: "Book 3", Author: "Author 2", Status: models.ReadStatusRead, FinishedAt: date(2023, 6, 1)},
+		{Id: "4", Title: "Book 4", Author: "Author 3", Status: models.ReadStatusReading, StartedAt: date(2024, 3, 1)},
+		{Id: "5", Title: "Book 5", Author: "Author 2", Status: models.ReadStatusToRead},
+	})
+	controller := NewStatsController(bookRepo, repositories.NewGoalRepository())
+	controller.now = func() time.Time { return time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC) }
+
+	t.Run("GetStats", func(t *testing.T) {
+		stats, err := controller.GetStats(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, stats.TotalBooks)
+		assert.Equal(t, map[models.ReadStatus]int{
+			models.ReadStatusToRead: 1, models.ReadStatusReading: 1, models.ReadStatusRead: 3,
+		}, stats.BooksByStatus)
+		assert.Equal(t, map[string]int{"2023": 1, "2024": 2}, stats.FinishedByYear)
+		assert.Equal(t, map[string]int{"2023-06": 1, "2024-01": 1, "2024-02": 1}, stats.FinishedByMonth)
+		assert.Equal(t, []models.AuthorCount{
+			{Author: "Author 1", Books: 2}, {Author: "Author 2", Books: 2}, {Author: "Author 3", Books: 1},
+		}, stats.TopAuthors)
+		// Only the books with a start date count towards the average.
+		assert.Equal(t, 15.0, *stats.AverageDaysToFinish)
+	})
+
+	t.Run("Goals", func(t *testing.T) {
+		// Two books in the first 60 days of a leap year projects to 12 books.
+		progress, err := controller.PutGoal(ctx, models.Goal{Year: 2024, Target: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, progress.Completed)
+		assert.Equal(t, 8, progress.Remaining)
+		assert.Equal(t, 20.0, progress.PercentComplete)
+		assert.Equal(t, 12, progress.ProjectedTotal)
+		assert.True(t, progress.OnTrack)
+		assert.NotNil(t, progress.ProjectedCompletion)
+
+		progress, err = controller.PutGoal(ctx, models.Goal{Year: 2024, Target: 20})
+		assert.NoError(t, err)
+		assert.False(t, progress.OnTrack)
+		assert.Nil(t, progress.ProjectedCompletion)
+
+		// A past year is complete once its target was reached.
+		progress, err = controller.PutGoal(ctx, models.Goal{Year: 2023, Target: 1})
+		assert.NoError(t, err)
+		assert.True(t, progress.OnTrack)
+
+		goals, err := controller.ListGoals(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, goals, 2)
+
+		_, err = controller.PutGoal(ctx, models.Goal{Year: 2024, Target: 0})
+		assert.Equal(t, fiber.NewError(http.StatusBadRequest, "goal target should be at least 1"), err)
+
+		_, err = controller.DeleteGoal(ctx, 2023)
+		assert.NoError(t, err)
+		_, err = controller.GetGoal(ctx, 2023)
+		assert.Equal(t, fiber.NewError(http.StatusNotFound, "the goal for year [2023] is not found"), err)
+	})
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/tenancy"
+)
+
+type TenantController struct {
+	tenantRepository models.TenantRepository
+	// purgers hold the other data of the tenants, which is removed with them.
+	purgers []models.TenantPurger
+	changes *changeTracker
+}
+
+// NewTenantController returns the controller of the tenants of the books
+// controller, whose changes are reported by the books controller.
+func NewTenantController(books *BookController, tenantRepository models.TenantRepository, purgers ...models.TenantPurger) *TenantController {
+	return &TenantController{tenantRepository: tenantRepository, purgers: purgers, changes: books.changes}
+}
+
+func (c *TenantController) ListTenants(ctx context.Context) ([]models.Tenant, error) {
+	tenants, err := c.tenantRepository.ListTenants(ctx)
+	if err != nil {
+		return nil, makeHttpUnexpectedError(err)
+	}
+	return tenants, nil
+}
+
+// DeleteTenant permanently removes the books of the tenant and its other
+// data held by the purgers, such as its goals, covers and highlights.
+func (c *TenantController) DeleteTenant(ctx context.Context, id string) (models.Tenant, error) {
+	id, ok := tenancy.NormalizeId(id)
+	if !ok {
+		return models.Tenant{}, fiber.NewError(http.StatusBadRequest, fmt.Sprintf("the tenant id [%s] is not valid", id))
+	}
+	tenant, err := c.tenantRepository.DeleteTenant(ctx, id)
+	if errors.Is(err, repositories.ErrRecordNotFound) {
+		return models.Tenant{}, fiber.NewError(http.StatusNotFound, fmt.Sprintf("the tenant [%s] is not found", id))
+	} else if err != nil {
+		return models.Tenant{}, makeHttpUnexpectedError(err)
+	}
+	for _, purger := range c.purgers {
+		if err := purger.PurgeTenant(ctx, id); err != nil {
+			return models.Tenant{}, makeHttpUnexpectedError(err)
+		}
+	}
+	c.changes.changed()
+	return tenant, nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+)
+
+// thumbnailQuality is the quality of the JPEG thumbnails.
+const thumbnailQuality = 85
+
+// thumbnail returns img scaled down to fit in a square of side pixels,
+// keeping its aspect ratio. Every pixel of the thumbnail is the average of
+// the pixels of img it covers, which keeps the thin lines and the text of
+// the covers readable. The images that already fit are returned as is.
+func thumbnail(img image.Image, side int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= side && height <= side {
+		return img
+	}
+	thumbWidth, thumbHeight := side, side
+	if width > height {
+		thumbHeight = max(1, height*side/width)
+	} else {
+		thumbWidth = max(1, width*side/height)
+	}
+	// The pixels are averaged premultiplied by their alpha, so that the
+	// colour of the transparent pixels does not bleed into the thumbnail.
+	src := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+	dst := image.NewRGBA(image.Rect(0, 0, thumbWidth, thumbHeight))
+	for y := 0; y < thumbHeight; y++ {
+		y0, y1 := y*height/thumbHeight, (y+1)*height/thumbHeight
+		for x := 0; x < thumbWidth; x++ {
+			x0, x1 := x*width/thumbWidth, (x+1)*width/thumbWidth
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[src.PixOffset(x0, sy):src.PixOffset(x1, sy)]
+				for i := 0; i < len(row); i += 4 {
+					sum[0] += int(row[i])
+					sum[1] += int(row[i+1])
+					sum[2] += int(row[i+2])
+					sum[3] += int(row[i+3])
+				}
+			}
+			n := (x1 - x0) * (y1 - y0)
+			pixel := dst.Pix[dst.PixOffset(x, y):]
+			for i, s := range sum {
+				pixel[i] = uint8((s + n/2) / n)
+			}
+		}
+	}
+	return dst
+}
+
+// encodeThumbnail encodes the thumbnail of an image of the format as JPEG
+// when the image was a JPEG, and as PNG otherwise to keep its transparency.
+func encodeThumbnail(thumb image.Image, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: thumbnailQuality})
+	} else {
+		err = png.Encode(&buf, thumb)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+	"time"
+)
+
+type AuditOperation string
+
+const (
+	AuditOperationAdd     AuditOperation = "add"
+	AuditOperationUpdate  AuditOperation = "update"
+	AuditOperationDelete  AuditOperation = "delete"
+	AuditOperationRestore AuditOperation = "restore"
+	AuditOperationPurge   AuditOperation = "purge"
+	AuditOperationMerge   AuditOperation = "merge"
+)
+
+// AuditEntry records a change to a book.
+type AuditEntry struct {
+	// Id orders the entries of the audit log. It is assigned when the entry is appended.
+	Id        int64     `json:"id" example:"42"`
+	Timestamp time.Time `json:"timestamp" example:"2024-01-02T15:04:05Z"`
+	Tenant    string    `json:"tenant" example:"default"`
+	// Actor is the subject or the client of the token of the request, or
+	// anonymous when the API security is not enforced.
+	Actor         string         `json:"actor" example:"alice@example.com"`
+	CorrelationId string         `json:"correlationId,omitempty" example:"7d3b5c1e-8f2a-4c6b-9e0d-1a2b3c4d5e6f"`
+	Operation     AuditOperation `json:"operation" example:"update" enums:"add,update,delete,restore,purge,merge"`
+	BookId        string         `json:"bookId" example:"fe2594d0-ccea-42a2-97ac-0487458b5642"`
+	// Changes are the fields of the book that changed, by their JSON name.
+	Changes map[string]AuditChange `json:"changes"`
+}
+
+// AuditChange is the value of a book field before and after a change. A
+// value is omitted when the field was not set.
+type AuditChange struct {
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// AuditFilter selects the audit entries of a tenant, of a book and those
+// recorded from a time. The zero values select all the entries.
+type AuditFilter struct {
+	Tenant string
+	BookId string
+	Since  time.Time
+}
+
+// AuditRepository is an append-only log of the changes to the books.
+type AuditRepository interface {
+	// Append adds the entries to the log in order, assigning their ids.
+	Append(ctx context.Context, entries ...AuditEntry) error
+	// List returns the entries that match the filter, oldest first.
+	List(ctx context.Context, filter AuditFilter) ([]AuditEntry, error)
+}
@@ -0,0 +1,42 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+)
+
+type BatchOperationType string
+
+const (
+	BatchOperationCreate BatchOperationType = "create"
+	BatchOperationUpdate BatchOperationType = "update"
+	BatchOperationDelete BatchOperationType = "delete"
+)
+
+// BookTransactor is implemented by the book repositories that can apply
+// several mutations atomically.
+type BookTransactor interface {
+	// RunInTransaction calls fn with a repository whose changes are applied
+	// all at once if fn returns nil and discarded if it returns an error.
+	RunInTransaction(ctx context.Context, fn func(tx BookRepository) error) error
+}
+
+type BatchOperation struct {
+	Op BatchOperationType `json:"op" example:"update" enums:"create,update,delete"`
+	// Id is the book to update or delete.
+	Id string `json:"id,omitempty" example:"fe2594d0-ccea-42a2-97ac-0487458b5642"`
+	// Book is the book to create or the updated book details.
+	Book *Book `json:"book,omitempty"`
+	// Merge, when set, returns the updated book details from the current
+	// ones of the book, which are read in the transaction of the batch.
+	Merge func(current Book) Book `json:"-"`
+}
+
+type BatchOperationResult struct {
+	Op     BatchOperationType `json:"op" example:"update"`
+	Status int                `json:"status" example:"200"`
+	Book   *Book              `json:"book,omitempty"`
+	Error  string             `json:"error,omitempty" example:"the book id [fe2594d0-ccea-42a2-97ac-0487458b5642] is not found"`
+}
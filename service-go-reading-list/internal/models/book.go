@@ -5,6 +5,9 @@ package models
 
 import (
 	"context"
+	"io"
+	"strings"
+	"time"
 )
 
 type ReadStatus string
@@ -24,6 +27,75 @@ type Book struct {
 	Title  string     `json:"title" example:"The Lord of the Rings"`
 	Author string     `json:"author" example:"J. R. R. Tolkien"`
 	Status ReadStatus `json:"status" example:"to_read" enums:"to_read,reading,read"`
+	ISBN   string     `json:"isbn,omitempty" example:"9780261103252"`
+	Tags   []string   `json:"tags,omitempty" example:"fantasy,classic"`
+	// Rating is between 1 and 5 once the book is rated.
+	Rating int `json:"rating,omitempty" example:"5" minimum:"0" maximum:"5"`
+	// CreatedAt and UpdatedAt are maintained by the service.
+	CreatedAt *time.Time `json:"createdAt,omitempty" example:"2024-01-02T15:04:05Z"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty" example:"2024-01-02T15:04:05Z"`
+	// StartedAt is set when the status changes to reading.
+	StartedAt *time.Time `json:"startedAt,omitempty" example:"2024-01-02T15:04:05Z"`
+	// FinishedAt is set when the status changes to read.
+	FinishedAt *time.Time `json:"finishedAt,omitempty" example:"2024-01-02T15:04:05Z"`
+	// DeletedAt is set when the book is moved to the trash.
+	DeletedAt *time.Time `json:"deletedAt,omitempty" example:"2024-01-02T15:04:05Z"`
+	// MergedIds are the books that were merged into this one. It is
+	// maintained by the service.
+	MergedIds []string `json:"mergedIds,omitempty" example:"b1b4b3b0-0b1a-4b1a-8b1a-0b1a0b1a0b1a"`
+}
+
+// BookPage is a page of books in a stable order.
+type BookPage struct {
+	Items []Book `json:"items"`
+	// Total is the number of books in all the pages.
+	Total  int `json:"total" example:"42"`
+	Offset int `json:"offset" example:"0"`
+	Limit  int `json:"limit" example:"20"`
+	// NextOffset is the offset of the next page. It is omitted on the last page.
+	NextOffset *int `json:"nextOffset,omitempty" example:"20"`
+}
+
+// BookFilter selects the books of a list. The empty fields match all the books.
+type BookFilter struct {
+	Status ReadStatus
+	// Author matches the author regardless of case.
+	Author string
+}
+
+// Matches reports whether the book is selected by the filter.
+func (f BookFilter) Matches(book Book) bool {
+	if f.Status != "" && book.Status != f.Status {
+		return false
+	}
+	if f.Author != "" && NormalizeAuthor(book.Author) != NormalizeAuthor(f.Author) {
+		return false
+	}
+	return true
+}
+
+// NormalizeAuthor returns the author as compared by the filters.
+func NormalizeAuthor(author string) string {
+	return strings.ToLower(strings.TrimSpace(author))
+}
+
+// IsDeleted reports whether the book is in the trash.
+func (b Book) IsDeleted() bool {
+	return b.DeletedAt != nil
+}
+
+// AddedBefore orders the books by when they were added, falling back to the
+// id for the books added at the same time or loaded from the initial data.
+func (b Book) AddedBefore(other Book) bool {
+	switch {
+	case b.CreatedAt == nil && other.CreatedAt != nil:
+		return true
+	case b.CreatedAt != nil && other.CreatedAt == nil:
+		return false
+	case b.CreatedAt != nil && !b.CreatedAt.Equal(*other.CreatedAt):
+		return b.CreatedAt.Before(*other.CreatedAt)
+	}
+	return b.Id < other.Id
 }
 
 type BookRepository interface {
@@ -31,5 +103,67 @@ type BookRepository interface {
 	Update(ctx context.Context, updatedBook Book) (Book, error)
 	List(ctx context.Context) ([]Book, error)
 	GetById(ctx context.Context, id string) (Book, error)
+	// DeleteById moves the book to the trash. Trashed books are ignored by
+	// List, GetById and Update until they are restored.
 	DeleteById(ctx context.Context, id string) (Book, error)
+	// ListDeleted returns the books in the trash.
+	ListDeleted(ctx context.Context) ([]Book, error)
+	// RestoreById moves a book out of the trash.
+	RestoreById(ctx context.Context, id string) (Book, error)
+	// PurgeById permanently removes a book, whether it is in the trash or not.
+	PurgeById(ctx context.Context, id string) (Book, error)
+	// PurgeDeletedBefore permanently removes the books that were moved to the
+	// trash before the given time and returns the books removed, including
+	// those removed before an error.
+	PurgeDeletedBefore(ctx context.Context, before time.Time) ([]Book, error)
+}
+
+// BookIndex is implemented by the book repositories that index the books,
+// so that they are listed without reading all the books.
+type BookIndex interface {
+	// ListByStatus returns the books, not in the trash, with the status.
+	ListByStatus(ctx context.Context, status ReadStatus) ([]Book, error)
+	// ListByAuthor returns the books, not in the trash, of the author as
+	// normalized by NormalizeAuthor.
+	ListByAuthor(ctx context.Context, author string) ([]Book, error)
+}
+
+// BookPager is implemented by the book repositories that keep the books in
+// the order of Book.AddedBefore, so that a page is read without reading all
+// the books.
+type BookPager interface {
+	// ListPage returns at most limit books, not in the trash, from offset in
+	// the order of Book.AddedBefore, and the number of books in all the pages.
+	ListPage(ctx context.Context, offset, limit int) ([]Book, int, error)
+}
+
+// BookUpdater is implemented by the book repositories that can update a
+// book from its current details atomically.
+type BookUpdater interface {
+	// UpdateById updates the book, not in the trash, with the details fn
+	// returns from its current ones, with no other change to the book in
+	// between. The book keeps its id. Nothing is updated when fn fails, and
+	// fn may be called again when the book was changed concurrently.
+	UpdateById(ctx context.Context, id string, fn func(current Book) (Book, error)) (Book, error)
+}
+
+// BookBackuper is implemented by the book repositories that can write a
+// consistent copy of all their books, which they can be restored from.
+type BookBackuper interface {
+	// Backup writes the copy to w and returns the number of bytes written.
+	Backup(ctx context.Context, w io.Writer) (int64, error)
+}
+
+// BookTenantStore is implemented by the book repositories that store the
+// books of the tenants other than the default one apart from their own, so
+// that the books of a tenant are read without reading those of the others.
+type BookTenantStore interface {
+	// OpenTenant returns the books of the tenant, which are created with the
+	// initial data when the tenant has none stored.
+	OpenTenant(ctx context.Context, tenant string, initialData []Book) (BookRepository, error)
+	// ListTenantIds returns the tenants that have books stored.
+	ListTenantIds(ctx context.Context) ([]string, error)
+	// DropTenant removes all the books of the tenant, which then has none
+	// stored.
+	DropTenant(ctx context.Context, tenant string) error
 }
@@ -0,0 +1,57 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// CoverSize selects the original cover image of a book or one of its
+// thumbnails.
+type CoverSize string
+
+const (
+	CoverSizeSmall    CoverSize = "small"
+	CoverSizeMedium   CoverSize = "medium"
+	CoverSizeLarge    CoverSize = "large"
+	CoverSizeOriginal CoverSize = "original"
+)
+
+// Cover describes the cover image of a book.
+type Cover struct {
+	BookId      string `json:"bookId" example:"dune"`
+	ContentType string `json:"contentType" example:"image/jpeg"`
+	// Size is the size of the original image in bytes.
+	Size      int64     `json:"size" example:"48213"`
+	Width     int       `json:"width" example:"600"`
+	Height    int       `json:"height" example:"900"`
+	UpdatedAt time.Time `json:"updatedAt" example:"2024-01-15T10:30:00Z"`
+}
+
+// CoverImage is the original cover image of a book or one of its thumbnails.
+type CoverImage struct {
+	Data        []byte
+	ContentType string
+	ModifiedAt  time.Time
+}
+
+// Blob is a binary object of a BlobRepository.
+type Blob struct {
+	Data       []byte
+	ModifiedAt time.Time
+}
+
+// BlobRepository stores binary objects, such as the cover images of the
+// books, under keys made of segments separated by slashes.
+type BlobRepository interface {
+	// Put creates or replaces the blob of the key.
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) (Blob, error)
+	// Delete removes the blob of the key, if there is one.
+	Delete(ctx context.Context, key string) error
+	// DeletePrefix removes the blobs whose keys start with prefix, which
+	// ends with a slash, and returns how many were removed.
+	DeletePrefix(ctx context.Context, prefix string) (int, error)
+}
@@ -0,0 +1,24 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+// DuplicateCluster is a group of books that look like the same book, in the
+// order they were added.
+type DuplicateCluster struct {
+	Books []Book `json:"books"`
+}
+
+type MergeRequest struct {
+	// SourceId is the book to fold into the merged book. It is moved to the trash.
+	SourceId string `json:"sourceId" example:"b1b4b3b0-0b1a-4b1a-8b1a-0b1a0b1a0b1a"`
+}
+
+// DuplicateConflict is the response to adding a book that is already on the
+// reading list.
+type DuplicateConflict struct {
+	Message string `json:"message" example:"the book looks like a duplicate of [fe2594d0-ccea-42a2-97ac-0487458b5642], set allowDuplicate to add it anyway"`
+	// Candidates are the ids of the books that look like the new book. They
+	// are omitted when the id of the new book is already taken.
+	Candidates []string `json:"candidates,omitempty" example:"fe2594d0-ccea-42a2-97ac-0487458b5642"`
+}
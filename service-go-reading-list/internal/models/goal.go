@@ -0,0 +1,40 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// Goal is the number of books to finish in a year.
+type Goal struct {
+	Year   int `json:"year" example:"2024"`
+	Target int `json:"target" example:"24"`
+}
+
+// GoalProgress reports how far a goal is from completion.
+type GoalProgress struct {
+	Year      int `json:"year" example:"2024"`
+	Target    int `json:"target" example:"24"`
+	Completed int `json:"completed" example:"10"`
+	Remaining int `json:"remaining" example:"14"`
+	// PercentComplete is Completed as a percentage of Target.
+	PercentComplete float64 `json:"percentComplete" example:"41.7"`
+	// ProjectedTotal is the number of books that will be finished by the end
+	// of the year at the current pace.
+	ProjectedTotal int `json:"projectedTotal" example:"22"`
+	// ProjectedCompletion is when the target will be reached at the current
+	// pace. It is omitted when the target will not be reached within the year.
+	ProjectedCompletion *time.Time `json:"projectedCompletion,omitempty" example:"2024-11-20T00:00:00Z"`
+	OnTrack             bool       `json:"onTrack" example:"false"`
+}
+
+type GoalRepository interface {
+	// Put creates or replaces the goal for the year.
+	Put(ctx context.Context, goal Goal) (Goal, error)
+	List(ctx context.Context) ([]Goal, error)
+	GetByYear(ctx context.Context, year int) (Goal, error)
+	DeleteByYear(ctx context.Context, year int) (Goal, error)
+}
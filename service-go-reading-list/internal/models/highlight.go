@@ -0,0 +1,81 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Highlight is a passage saved from a book, with the note of the reader.
+type Highlight struct {
+	// Id and BookId are maintained by the service.
+	Id     string `json:"id" example:"3f1c0b9e-6a2d-4b8e-9d3f-2a7c5e1b4d6f"`
+	BookId string `json:"bookId" example:"dune"`
+	Text   string `json:"text" example:"I must not fear. Fear is the mind-killer."`
+	// Page is the page of the passage, when the book has pages.
+	Page int `json:"page,omitempty" example:"12" minimum:"0"`
+	// Location is where the passage is when the book has no pages, such as
+	// the location of an e-book or a chapter.
+	Location string   `json:"location,omitempty" example:"Chapter 1"`
+	Note     string   `json:"note,omitempty" example:"The litany against fear."`
+	Tags     []string `json:"tags,omitempty" example:"fear,litany"`
+	// CreatedAt and UpdatedAt are maintained by the service.
+	CreatedAt *time.Time `json:"createdAt,omitempty" example:"2024-01-02T15:04:05Z"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty" example:"2024-01-02T15:04:05Z"`
+}
+
+// HighlightFilter selects the highlights of a search. The empty fields match
+// all the highlights.
+type HighlightFilter struct {
+	BookId string
+	// Query matches the highlights whose text or note contain it, regardless
+	// of case.
+	Query string
+	Tag   string
+}
+
+// Matches reports whether the highlight is selected by the filter.
+func (f HighlightFilter) Matches(highlight Highlight) bool {
+	if f.BookId != "" && highlight.BookId != f.BookId {
+		return false
+	}
+	if f.Tag != "" && !slices.Contains(highlight.Tags, f.Tag) {
+		return false
+	}
+	if query := strings.ToLower(strings.TrimSpace(f.Query)); query != "" &&
+		!strings.Contains(strings.ToLower(highlight.Text), query) &&
+		!strings.Contains(strings.ToLower(highlight.Note), query) {
+		return false
+	}
+	return true
+}
+
+// ReadBefore orders the highlights of a book in reading order: by page, the
+// highlights without a page last, then by when they were added.
+func (h Highlight) ReadBefore(other Highlight) bool {
+	if h.Page != other.Page {
+		return other.Page == 0 || (h.Page != 0 && h.Page < other.Page)
+	}
+	if h.CreatedAt != nil && other.CreatedAt != nil && !h.CreatedAt.Equal(*other.CreatedAt) {
+		return h.CreatedAt.Before(*other.CreatedAt)
+	}
+	return h.Id < other.Id
+}
+
+type HighlightRepository interface {
+	// Add stores the highlight with a new id.
+	Add(ctx context.Context, highlight Highlight) (Highlight, error)
+	Update(ctx context.Context, highlight Highlight) (Highlight, error)
+	GetById(ctx context.Context, id string) (Highlight, error)
+	DeleteById(ctx context.Context, id string) (Highlight, error)
+	// List returns the highlights selected by the filter, grouped by book in
+	// reading order.
+	List(ctx context.Context, filter HighlightFilter) ([]Highlight, error)
+	// DeleteByBook removes the highlights of the book and returns how many
+	// there were.
+	DeleteByBook(ctx context.Context, bookId string) (int, error)
+}
@@ -0,0 +1,22 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+type RecommendationSource string
+
+const (
+	RecommendationSourceReadingList RecommendationSource = "reading_list"
+	RecommendationSourceCatalogue   RecommendationSource = "catalogue"
+)
+
+// Recommendation is a book suggested to read next.
+type Recommendation struct {
+	Book Book `json:"book"`
+	// Score ranks the recommendations, the higher the better.
+	Score float64 `json:"score" example:"3.5"`
+	// Source tells whether the book is on the reading list or comes from the catalogue.
+	Source RecommendationSource `json:"source" example:"reading_list" enums:"reading_list,catalogue"`
+	// Reasons explain the score.
+	Reasons []string `json:"reasons" example:"by J. R. R. Tolkien, the author of [The Hobbit]"`
+}
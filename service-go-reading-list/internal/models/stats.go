@@ -0,0 +1,23 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+type AuthorCount struct {
+	Author string `json:"author" example:"J. R. R. Tolkien"`
+	Books  int    `json:"books" example:"2"`
+}
+
+type ReadingStats struct {
+	TotalBooks int `json:"totalBooks" example:"4"`
+	// BooksByStatus is the number of books per read status.
+	BooksByStatus map[ReadStatus]int `json:"booksByStatus"`
+	// FinishedByYear is the number of books finished per year, keyed by "2006".
+	FinishedByYear map[string]int `json:"finishedByYear"`
+	// FinishedByMonth is the number of books finished per month, keyed by "2006-01".
+	FinishedByMonth map[string]int `json:"finishedByMonth"`
+	TopAuthors      []AuthorCount  `json:"topAuthors"`
+	// AverageDaysToFinish is the average number of days between starting and
+	// finishing a book. It is omitted when no finished book has a start date.
+	AverageDaysToFinish *float64 `json:"averageDaysToFinish,omitempty" example:"12.5"`
+}
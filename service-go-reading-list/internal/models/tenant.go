@@ -0,0 +1,36 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+)
+
+// Tenant is a team sharing the service, whose books are isolated from the
+// books of the other tenants.
+type Tenant struct {
+	Id string `json:"id" example:"acme"`
+	// Books is the number of books on the reading list of the tenant and
+	// Trashed the number of its books in the trash.
+	Books   int `json:"books" example:"12"`
+	Trashed int `json:"trashed" example:"1"`
+	// Quota is how many books, including the ones in the trash, the tenant
+	// can have. The books are not limited when it is zero.
+	Quota int `json:"quota" example:"100"`
+}
+
+// TenantRepository is implemented by the repositories that namespace their
+// data by the tenant of the context.
+type TenantRepository interface {
+	ListTenants(ctx context.Context) ([]Tenant, error)
+	// DeleteTenant permanently removes all the data of the tenant.
+	DeleteTenant(ctx context.Context, id string) (Tenant, error)
+}
+
+// TenantPurger is implemented by the repositories that namespace their data
+// by the tenant of the context and are emptied when a tenant is deleted.
+type TenantPurger interface {
+	// PurgeTenant permanently removes all the data of the tenant.
+	PurgeTenant(ctx context.Context, id string) error
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1
+
+import (
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+)
+
+type BatchOperation struct {
+	Op models.BatchOperationType `json:"op" example:"update" enums:"create,update,delete"`
+	// Id is the book to update or delete.
+	Id string `json:"id,omitempty" example:"fe2594d0-ccea-42a2-97ac-0487458b5642"`
+	// Book is the book to create or the updated book details.
+	Book *Book `json:"book,omitempty"`
+}
+
+type BatchRequest struct {
+	Operations []BatchOperation `json:"operations"`
+}
+
+type BatchOperationResult struct {
+	Op     models.BatchOperationType `json:"op" example:"update"`
+	Status int                       `json:"status" example:"200"`
+	Book   *Book                     `json:"book,omitempty"`
+	Error  string                    `json:"error,omitempty" example:"the book id [fe2594d0-ccea-42a2-97ac-0487458b5642] is not found"`
+}
+
+type BatchResponse struct {
+	Message string                 `json:"message,omitempty" example:"operation 1 failed: book title is required"`
+	Results []BatchOperationResult `json:"results"`
+}
+
+// Model returns the operation as a models.BatchOperation. The fields of the
+// book that version 1 does not have are carried over from the current book
+// when it is updated.
+func (o BatchOperation) Model() models.BatchOperation {
+	operation := models.BatchOperation{Op: o.Op, Id: o.Id}
+	if o.Book != nil {
+		book := o.Book.Model(models.Book{})
+		operation.Book = &book
+		operation.Merge = o.Book.Model
+	}
+	return operation
+}
+
+// NewBatchOperationResults returns the version 1 representations of the results.
+func NewBatchOperationResults(results []models.BatchOperationResult) []BatchOperationResult {
+	v1Results := make([]BatchOperationResult, len(results))
+	for i, result := range results {
+		v1Results[i] = BatchOperationResult{Op: result.Op, Status: result.Status, Error: result.Error}
+		if result.Book != nil {
+			book := NewBook(*result.Book)
+			v1Results[i].Book = &book
+		}
+	}
+	return v1Results
+}
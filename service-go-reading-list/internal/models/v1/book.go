@@ -0,0 +1,71 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package v1 holds the representations of the books in the version 1 API.
+// The controllers work with the models package, so the version 1 routes
+// translate from and to it, keeping the shape of version 1 unchanged as
+// fields are added to the models.
+package v1
+
+import (
+	"time"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+)
+
+type Book struct {
+	Id     string            `json:"id" xml:"id" example:"fe2594d0-ccea-42a2-97ac-0487458b5642"`
+	Title  string            `json:"title" xml:"title" example:"The Lord of the Rings"`
+	Author string            `json:"author" xml:"author" example:"J. R. R. Tolkien"`
+	Status models.ReadStatus `json:"status" xml:"status" example:"to_read" enums:"to_read,reading,read"`
+	// CreatedAt and UpdatedAt are maintained by the service.
+	CreatedAt *time.Time `json:"createdAt,omitempty" xml:"createdAt,omitempty" example:"2024-01-02T15:04:05Z"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty" xml:"updatedAt,omitempty" example:"2024-01-02T15:04:05Z"`
+	// StartedAt is set when the status changes to reading.
+	StartedAt *time.Time `json:"startedAt,omitempty" xml:"startedAt,omitempty" example:"2024-01-02T15:04:05Z"`
+	// FinishedAt is set when the status changes to read.
+	FinishedAt *time.Time `json:"finishedAt,omitempty" xml:"finishedAt,omitempty" example:"2024-01-02T15:04:05Z"`
+	// DeletedAt is set when the book is moved to the trash.
+	DeletedAt *time.Time `json:"deletedAt,omitempty" xml:"deletedAt,omitempty" example:"2024-01-02T15:04:05Z"`
+}
+
+// NewBook returns the version 1 representation of the book.
+func NewBook(book models.Book) Book {
+	return Book{
+		Id:         book.Id,
+		Title:      book.Title,
+		Author:     book.Author,
+		Status:     book.Status,
+		CreatedAt:  book.CreatedAt,
+		UpdatedAt:  book.UpdatedAt,
+		StartedAt:  book.StartedAt,
+		FinishedAt: book.FinishedAt,
+		DeletedAt:  book.DeletedAt,
+	}
+}
+
+// NewBooks returns the version 1 representations of the books.
+func NewBooks(books []models.Book) []Book {
+	v1Books := make([]Book, len(books))
+	for i, book := range books {
+		v1Books[i] = NewBook(book)
+	}
+	return v1Books
+}
+
+// Model returns the book as a models.Book. The fields that version 1 does not
+// have are carried over from current, so that a version 1 client does not
+// clear them when it updates a book.
+func (b Book) Model(current models.Book) models.Book {
+	book := current
+	book.Id = b.Id
+	book.Title = b.Title
+	book.Author = b.Author
+	book.Status = b.Status
+	book.CreatedAt = b.CreatedAt
+	book.UpdatedAt = b.UpdatedAt
+	book.StartedAt = b.StartedAt
+	book.FinishedAt = b.FinishedAt
+	book.DeletedAt = b.DeletedAt
+	return book
+}
@@ -0,0 +1,21 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1
+
+import (
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+)
+
+type DuplicateCluster struct {
+	Books []Book `json:"books"`
+}
+
+// NewDuplicateClusters returns the version 1 representations of the duplicate clusters.
+func NewDuplicateClusters(clusters []models.DuplicateCluster) []DuplicateCluster {
+	v1Clusters := make([]DuplicateCluster, len(clusters))
+	for i, cluster := range clusters {
+		v1Clusters[i] = DuplicateCluster{Books: NewBooks(cluster.Books)}
+	}
+	return v1Clusters
+}
@@ -0,0 +1,32 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1
+
+import (
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+)
+
+type Recommendation struct {
+	Book Book `json:"book"`
+	// Score ranks the recommendations, the higher the better.
+	Score float64 `json:"score" example:"3.5"`
+	// Source tells whether the book is on the reading list or comes from the catalogue.
+	Source models.RecommendationSource `json:"source" example:"reading_list" enums:"reading_list,catalogue"`
+	// Reasons explain the score.
+	Reasons []string `json:"reasons" example:"by J. R. R. Tolkien, the author of [The Hobbit]"`
+}
+
+// NewRecommendations returns the version 1 representations of the recommendations.
+func NewRecommendations(recommendations []models.Recommendation) []Recommendation {
+	v1Recommendations := make([]Recommendation, len(recommendations))
+	for i, recommendation := range recommendations {
+		v1Recommendations[i] = Recommendation{
+			Book:    NewBook(recommendation.Book),
+			Score:   recommendation.Score,
+			Source:  recommendation.Source,
+			Reasons: recommendation.Reasons,
+		}
+	}
+	return v1Recommendations
+}
@@ -0,0 +1,232 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package openapi matches requests against the operations declared in the
+// OpenAPI definition of the service.
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Spec is a loaded OpenAPI definition with its operations indexed for lookup
+// by request method and path.
+type Spec struct {
+	Doc *openapi3.T
+	// BasePath is the path of the first server URL, which prefixes every path in the definition.
+	BasePath string
+	routes   []*Route
+}
+
+// Route is an operation declared in the definition.
+type Route struct {
+	Method string
+	// Path is the path template relative to the base path, e.g. /books/{id}.
+	Path      string
+	PathItem  *openapi3.PathItem
+	Operation *openapi3.Operation
+	segments  []string
+}
+
+// Load parses and validates an OpenAPI 3 definition.
+func Load(data []byte) (*Spec, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the OpenAPI definition: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI definition: %w", err)
+	}
+	spec := &Spec{Doc: doc}
+	if len(doc.Servers) > 0 {
+		serverURL, err := url.Parse(doc.Servers[0].URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid server URL [%s]: %w", doc.Servers[0].URL, err)
+		}
+		spec.BasePath = strings.TrimRight(serverURL.Path, "/")
+	}
+	for path, pathItem := range doc.Paths.Map() {
+		for method, operation := range pathItem.Operations() {
+			spec.routes = append(spec.routes, &Route{
+				Method:    method,
+				Path:      path,
+				PathItem:  pathItem,
+				Operation: operation,
+				segments:  splitPath(path),
+			})
+		}
+	}
+	// Prefer the routes with more literal segments, so that /books/trash
+	// wins over /books/{id}.
+	sort.SliceStable(spec.routes, func(i, j int) bool {
+		return literalSegments(spec.routes[i].segments) > literalSegments(spec.routes[j].segments)
+	})
+	return spec, nil
+}
+
+// Routes returns all the operations in the definition.
+func (s *Spec) Routes() []*Route {
+	return s.routes
+}
+
+// FindRoute returns the operation matching the method and the full request
+// path, including the base path, together with the path parameters. The
+// literal segments are compared regardless of case, like the routes of
+// Fiber, so that a request is matched to the operation of the route that
+// serves it.
+func (s *Spec) FindRoute(method, path string) (*Route, map[string]string, bool) {
+	relativePath, ok := s.relativePath(path)
+	if !ok {
+		return nil, nil, false
+	}
+	segments := splitPath(relativePath)
+	for _, route := range s.routes {
+		if route.Method != strings.ToUpper(method) {
+			continue
+		}
+		if params, ok := route.match(segments); ok {
+			return route, params, true
+		}
+	}
+	return nil, nil, false
+}
+
+// HasPath reports whether the full request path is under the base path, so
+// that it is served by the operations of the definition.
+func (s *Spec) HasPath(path string) bool {
+	_, ok := s.relativePath(path)
+	return ok
+}
+
+// relativePath returns the path relative to the base path, which is matched
+// regardless of case.
+func (s *Spec) relativePath(path string) (string, bool) {
+	if len(path) < len(s.BasePath) || !strings.EqualFold(path[:len(s.BasePath)], s.BasePath) {
+		return "", false
+	}
+	relativePath := path[len(s.BasePath):]
+	if relativePath != "" && relativePath[0] != '/' {
+		return "", false
+	}
+	return relativePath, true
+}
+
+// SecurityRequirements returns the security requirements of the operation,
+// falling back to the top-level requirements of the definition. The second
+// value is false when neither declares any, which is different from an
+// explicitly empty list that makes the operation public.
+func (s *Spec) SecurityRequirements(route *Route) (openapi3.SecurityRequirements, bool) {
+	if route.Operation.Security != nil {
+		return *route.Operation.Security, true
+	}
+	if s.Doc.Security != nil {
+		return s.Doc.Security, true
+	}
+	return nil, false
+}
+
+// DisallowAdditionalProperties makes the object schemas of the definition
+// reject the properties they do not declare, unless they already say whether
+// additional properties are allowed. It modifies the loaded definition.
+func (s *Spec) DisallowAdditionalProperties() {
+	visited := make(map[*openapi3.Schema]bool)
+	for _, schema := range s.Doc.Components.Schemas {
+		disallowAdditionalProperties(schema, visited)
+	}
+}
+
+func disallowAdditionalProperties(ref *openapi3.SchemaRef, visited map[*openapi3.Schema]bool) {
+	if ref == nil || ref.Value == nil || visited[ref.Value] {
+		return
+	}
+	schema := ref.Value
+	visited[schema] = true
+	if schema.Type.Is(openapi3.TypeObject) && schema.AdditionalProperties.Has == nil && schema.AdditionalProperties.Schema == nil {
+		schema.AdditionalProperties.Has = openapi3.BoolPtr(false)
+	}
+	for _, property := range schema.Properties {
+		disallowAdditionalProperties(property, visited)
+	}
+	for _, subSchema := range schema.AllOf {
+		disallowAdditionalProperties(subSchema, visited)
+	}
+	disallowAdditionalProperties(schema.Items, visited)
+	disallowAdditionalProperties(schema.AdditionalProperties.Schema, visited)
+}
+
+func (r *Route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, segment := range r.segments {
+		if name, ok := pathParameterName(segment); ok {
+			if segments[i] == "" {
+				return nil, false
+			}
+			value, err := url.PathUnescape(segments[i])
+			if err != nil {
+				return nil, false
+			}
+			params[name] = value
+		} else if !strings.EqualFold(segment, segments[i]) {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// TemplateFromFiberPath converts a Fiber route path such as
+// /api/v1/reading-list/books/:id into the matching path template relative to
+// the base path, e.g. /books/{id}. It returns false when the path is not
+// under the base path.
+func (s *Spec) TemplateFromFiberPath(fiberPath string) (string, bool) {
+	fiberPath = strings.ReplaceAll(fiberPath, "\\:", "\x00")
+	relativePath, ok := strings.CutPrefix(fiberPath, s.BasePath)
+	if !ok || (relativePath != "" && relativePath[0] != '/') {
+		return "", false
+	}
+	segments := splitPath(relativePath)
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + strings.TrimSuffix(segment[1:], "?") + "}"
+		}
+		segments[i] = strings.ReplaceAll(segments[i], "\x00", ":")
+	}
+	if len(segments) == 0 {
+		return "/", true
+	}
+	return "/" + strings.Join(segments, "/"), true
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func pathParameterName(segment string) (string, bool) {
+	if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+		return segment[1 : len(segment)-1], true
+	}
+	return "", false
+}
+
+func literalSegments(segments []string) int {
+	n := 0
+	for _, segment := range segments {
+		if _, ok := pathParameterName(segment); !ok {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/docs"
+)
+
+func TestSpec(t *testing.T) {
+	spec, err := Load(docs.OpenAPISpec)
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/reading-list", spec.BasePath)
+
+	t.Run("FindRoute", func(t *testing.T) {
+		route, params, ok := spec.FindRoute("GET", "/api/v1/reading-list/books/42")
+		assert.True(t, ok)
+		assert.Equal(t, "/books/{id}", route.Path)
+		assert.Equal(t, map[string]string{"id": "42"}, params)
+
+		// Literal segments take precedence over parameters.
+		route, _, ok = spec.FindRoute("GET", "/api/v1/reading-list/books/trash")
+		assert.True(t, ok)
+		assert.Equal(t, "/books/trash", route.Path)
+
+		route, _, ok = spec.FindRoute("POST", "/api/v1/reading-list/books:batch")
+		assert.True(t, ok)
+		assert.Equal(t, "/books:batch", route.Path)
+
+		// The literal segments are matched regardless of case, like the
+		// routes of Fiber, and the parameters keep their case.
+		route, params, ok = spec.FindRoute("GET", "/API/V1/Reading-List/BOOKS/Dune")
+		assert.True(t, ok)
+		assert.Equal(t, "/books/{id}", route.Path)
+		assert.Equal(t, map[string]string{"id": "Dune"}, params)
+
+		_, _, ok = spec.FindRoute("PATCH", "/api/v1/reading-list/books/42")
+		assert.False(t, ok)
+		_, _, ok = spec.FindRoute("GET", "/healthz")
+		assert.False(t, ok)
+		_, _, ok = spec.FindRoute("GET", "/api/v1/reading-list-2/books")
+		assert.False(t, ok)
+	})
+
+	t.Run("HasPath", func(t *testing.T) {
+		assert.True(t, spec.HasPath("/api/v1/reading-list/unknown"))
+		assert.True(t, spec.HasPath("/API/v1/reading-list"))
+		assert.False(t, spec.HasPath("/api/v1/reading-list-2/books"))
+		assert.False(t, spec.HasPath("/healthz"))
+	})
+
+	t.Run("TemplateFromFiberPath", func(t *testing.T) {
+		template, ok := spec.TemplateFromFiberPath("/api/v1/reading-list/books/:id/restore")
+		assert.True(t, ok)
+		assert.Equal(t, "/books/{id}/restore", template)
+
+		template, ok = spec.TemplateFromFiberPath("/api/v1/reading-list/books\\:batch")
+		assert.True(t, ok)
+		assert.Equal(t, "/books:batch", template)
+
+		_, ok = spec.TemplateFromFiberPath("/api/v1/reading-listing")
+		assert.False(t, ok)
+	})
+
+	t.Run("SecurityRequirements", func(t *testing.T) {
+		route, _, _ := spec.FindRoute("GET", "/api/v1/reading-list/books/42")
+		requirements, ok := spec.SecurityRequirements(route)
+		assert.True(t, ok)
+		assert.Equal(t, []string{"read:books"}, requirements[0]["default"])
+	})
+}
@@ -0,0 +1,243 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultPoolSize    = 10
+	DefaultDialTimeout = 5 * time.Second
+)
+
+// ErrClosed is returned by a closed client.
+var ErrClosed = errors.New("redis: client is closed")
+
+type Options struct {
+	// Addr is the host:port of the server.
+	Addr     string
+	Password string
+	// DB is the database selected on every connection.
+	DB int
+	// PoolSize is the number of idle connections kept for reuse.
+	PoolSize    int
+	DialTimeout time.Duration
+}
+
+// ParseURL returns the options of a redis://[:password@]host[:port][/db] URL.
+func ParseURL(rawURL string) (Options, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Options{}, err
+	}
+	if u.Scheme != "redis" {
+		return Options{}, fmt.Errorf("redis: the URL scheme should be [redis], got [%s]", u.Scheme)
+	}
+	opts := Options{Addr: u.Host}
+	if u.Port() == "" {
+		opts.Addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if password, ok := u.User.Password(); ok {
+		opts.Password = password
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		if opts.DB, err = strconv.Atoi(db); err != nil {
+			return Options{}, fmt.Errorf("redis: the database [%s] should be a number", db)
+		}
+	}
+	return opts, nil
+}
+
+// Client is a pool of connections to a server. It is safe for concurrent use.
+type Client struct {
+	opts   Options
+	lock   sync.Mutex
+	idle   []*Conn
+	closed bool
+}
+
+func NewClient(opts Options) *Client {
+	if opts.PoolSize == 0 {
+		opts.PoolSize = DefaultPoolSize
+	}
+	if opts.DialTimeout == 0 {
+		opts.DialTimeout = DefaultDialTimeout
+	}
+	return &Client{opts: opts}
+}
+
+// Do runs a command on a pooled connection.
+func (c *Client) Do(ctx context.Context, args ...string) (interface{}, error) {
+	conn, err := c.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return conn.Do(ctx, args...)
+}
+
+// Conn returns a connection of the pool for the commands that depend on the
+// state of the connection, such as WATCH and MULTI. It must be closed to
+// return it to the pool.
+func (c *Client) Conn(ctx context.Context) (*Conn, error) {
+	c.lock.Lock()
+	if c.closed {
+		c.lock.Unlock()
+		return nil, ErrClosed
+	}
+	if n := len(c.idle); n > 0 {
+		conn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.lock.Unlock()
+		return conn, nil
+	}
+	c.lock.Unlock()
+	return c.dial(ctx)
+}
+
+// Close closes the idle connections. The connections in use are closed
+// when they are returned.
+func (c *Client) Close() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.closed = true
+	var errs []error
+	for _, conn := range c.idle {
+		errs = append(errs, conn.conn.Close())
+	}
+	c.idle = nil
+	return errors.Join(errs...)
+}
+
+func (c *Client) dial(ctx context.Context) (*Conn, error) {
+	dialer := net.Dialer{Timeout: c.opts.DialTimeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", c.opts.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("redis: failed to connect to [%s]: %w", c.opts.Addr, err)
+	}
+	conn := &Conn{
+		client: c,
+		conn:   netConn,
+		reader: bufio.NewReader(netConn),
+		writer: bufio.NewWriter(netConn),
+	}
+	if c.opts.Password != "" {
+		if _, err := conn.Do(ctx, "AUTH", c.opts.Password); err != nil {
+			_ = netConn.Close()
+			return nil, err
+		}
+	}
+	if c.opts.DB != 0 {
+		if _, err := conn.Do(ctx, "SELECT", strconv.Itoa(c.opts.DB)); err != nil {
+			_ = netConn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+func (c *Client) release(conn *Conn) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed || conn.broken || len(c.idle) >= c.opts.PoolSize {
+		_ = conn.conn.Close()
+		return
+	}
+	c.idle = append(c.idle, conn)
+}
+
+// Conn is a connection to a server. It is not safe for concurrent use.
+type Conn struct {
+	client *Client
+	conn   net.Conn
+	reader *bufio.Reader
+	writer *bufio.Writer
+	// broken is set when the connection is in an unknown state, e.g. after
+	// an I/O error, so that it is not reused.
+	broken bool
+	// watching is set between WATCH and EXEC, DISCARD or UNWATCH, and
+	// queuing between MULTI and EXEC or DISCARD.
+	watching bool
+	queuing  bool
+}
+
+// Do sends a command and reads its reply, returning the error replies as
+// errors of type Error. The deadline of the context applies to both.
+func (c *Conn) Do(ctx context.Context, args ...string) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	deadline, _ := ctx.Deadline()
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		c.broken = true
+		return nil, err
+	}
+	stop := context.AfterFunc(ctx, func() {
+		// Interrupts the pending I/O when the context is canceled.
+		_ = c.conn.SetDeadline(time.Unix(1, 0))
+	})
+	defer stop()
+	if err := WriteCommand(c.writer, args...); err != nil {
+		c.broken = true
+		return nil, c.ioError(ctx, err)
+	}
+	if err := c.writer.Flush(); err != nil {
+		c.broken = true
+		return nil, c.ioError(ctx, err)
+	}
+	reply, err := ReadReply(c.reader)
+	if err != nil {
+		c.broken = true
+		return nil, c.ioError(ctx, err)
+	}
+	switch strings.ToUpper(args[0]) {
+	case "WATCH":
+		c.watching = true
+	case "MULTI":
+		c.queuing = true
+	case "EXEC", "DISCARD":
+		c.watching, c.queuing = false, false
+	case "UNWATCH":
+		c.watching = false
+	}
+	if err, ok := reply.(Error); ok {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// Close returns the connection to the pool, discarding the transaction and
+// the watched keys left by the caller.
+func (c *Conn) Close() error {
+	reset := ""
+	if c.queuing {
+		reset = "DISCARD"
+	} else if c.watching {
+		reset = "UNWATCH"
+	}
+	if reset != "" && !c.broken {
+		if _, err := c.Do(context.Background(), reset); err != nil {
+			c.broken = true
+		}
+	}
+	c.client.release(c)
+	return nil
+}
+
+func (c *Conn) ioError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return fmt.Errorf("redis: %w", err)
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package redis_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/redis"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/redis/redistest"
+)
+
+func TestParseURL(t *testing.T) {
+	opts, err := redis.ParseURL("redis://:secret@cache.example.com/2")
+	assert.NoError(t, err)
+	assert.Equal(t, redis.Options{Addr: "cache.example.com:6379", Password: "secret", DB: 2}, opts)
+
+	opts, err = redis.ParseURL("redis://localhost:6380")
+	assert.NoError(t, err)
+	assert.Equal(t, redis.Options{Addr: "localhost:6380"}, opts)
+
+	_, err = redis.ParseURL("http://localhost:6379")
+	assert.Error(t, err)
+	_, err = redis.ParseURL("redis://localhost:6379/first")
+	assert.Error(t, err)
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	server, err := redistest.NewServerWithPassword("secret")
+	require.NoError(t, err)
+	defer server.Close()
+
+	t.Run("Auth", func(t *testing.T) {
+		client := redis.NewClient(redis.Options{Addr: server.Addr()})
+		defer client.Close()
+		_, err := client.Do(ctx, "PING")
+		assert.Equal(t, redis.Error("NOAUTH Authentication required."), err)
+
+		opts, err := redis.ParseURL(server.URL())
+		require.NoError(t, err)
+		client = redis.NewClient(opts)
+		defer client.Close()
+		reply, err := redis.String(client.Do(ctx, "PING"))
+		assert.NoError(t, err)
+		assert.Equal(t, "PONG", reply)
+	})
+
+	t.Run("Select", func(t *testing.T) {
+		client := redis.NewClient(redis.Options{Addr: server.Addr(), Password: "secret", DB: 3})
+		defer client.Close()
+		_, err := client.Do(ctx, "SET", "key", "value")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"key"}, server.Keys(3))
+		assert.Empty(t, server.Keys(0))
+	})
+
+	t.Run("Replies", func(t *testing.T) {
+		client := redis.NewClient(redis.Options{Addr: server.Addr(), Password: "secret"})
+		defer client.Close()
+		n, err := redis.Int(client.Do(ctx, "HSET", "hash", "a", "1", "b", "2"))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+		fields, err := redis.StringMap(client.Do(ctx, "HGETALL", "hash"))
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"a": "1", "b": "2"}, fields)
+		_, err = redis.String(client.Do(ctx, "GET", "missing"))
+		assert.ErrorIs(t, err, redis.ErrNil)
+		_, err = client.Do(ctx, "GET", "hash")
+		assert.ErrorContains(t, err, "WRONGTYPE")
+	})
+
+	t.Run("Watch", func(t *testing.T) {
+		client := redis.NewClient(redis.Options{Addr: server.Addr(), Password: "secret"})
+		defer client.Close()
+		conn, err := client.Conn(ctx)
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = conn.Do(ctx, "WATCH", "counter")
+		require.NoError(t, err)
+		// Another client changes the watched key.
+		_, err = client.Do(ctx, "INCR", "counter")
+		require.NoError(t, err)
+		_, err = conn.Do(ctx, "MULTI")
+		require.NoError(t, err)
+		_, err = conn.Do(ctx, "INCR", "counter")
+		require.NoError(t, err)
+		reply, err := conn.Do(ctx, "EXEC")
+		assert.NoError(t, err)
+		assert.Nil(t, reply)
+
+		reply, err = conn.Do(ctx, "MULTI")
+		require.NoError(t, err)
+		_, err = conn.Do(ctx, "INCR", "counter")
+		require.NoError(t, err)
+		replies, err := conn.Do(ctx, "EXEC")
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{int64(2)}, replies)
+		assert.Equal(t, "OK", reply)
+	})
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package redis is a minimal client of the Redis serialization protocol
+// (RESP2), enough for the book repository to run against Redis or any
+// server speaking its protocol.
+package redis
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ErrNil is returned by the reply helpers for a null reply, e.g. the reply
+// to an EXEC aborted because a watched key changed.
+var ErrNil = errors.New("redis: nil reply")
+
+// Error is an error reply of the server.
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+// WriteCommand writes the command as an array of bulk strings.
+func WriteCommand(w *bufio.Writer, args ...string) error {
+	if _, err := fmt.Fprintf(w, "*%d\r\n", len(args)); err != nil {
+		return err
+	}
+	for _, arg := range args {
+		if _, err := fmt.Fprintf(w, "$%d\r\n%s\r\n", len(arg), arg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadReply reads a reply, which is a string for the simple and bulk
+// strings, an int64 for the integers, a []interface{} for the arrays, nil
+// for the null bulk strings and arrays, and an Error for the errors.
+func ReadReply(r *bufio.Reader) (interface{}, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, fmt.Errorf("redis: empty reply line")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return Error(line[1:]), nil
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		values := make([]interface{}, n)
+		for i := range values {
+			if values[i], err = ReadReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply type [%c]", line[0])
+	}
+}
+
+// ReadCommand reads a command sent as an array of bulk strings.
+func ReadCommand(r *bufio.Reader) ([]string, error) {
+	reply, err := ReadReply(r)
+	if err != nil {
+		return nil, err
+	}
+	values, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("redis: a command should be an array of bulk strings")
+	}
+	args := make([]string, len(values))
+	for i, value := range values {
+		if args[i], ok = value.(string); !ok {
+			return nil, fmt.Errorf("redis: a command should be an array of bulk strings")
+		}
+	}
+	return args, nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", fmt.Errorf("redis: malformed line [%q]", line)
+	}
+	return line[:len(line)-2], nil
+}
+
+// String returns the string reply.
+func String(reply interface{}, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	switch reply := reply.(type) {
+	case string:
+		return reply, nil
+	case nil:
+		return "", ErrNil
+	default:
+		return "", fmt.Errorf("redis: unexpected reply [%T] for a string", reply)
+	}
+}
+
+// Int returns the integer reply.
+func Int(reply interface{}, err error) (int64, error) {
+	if err != nil {
+		return 0, err
+	}
+	switch reply := reply.(type) {
+	case int64:
+		return reply, nil
+	case nil:
+		return 0, ErrNil
+	default:
+		return 0, fmt.Errorf("redis: unexpected reply [%T] for an integer", reply)
+	}
+}
+
+// Strings returns the array reply of strings.
+func Strings(reply interface{}, err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	switch reply := reply.(type) {
+	case []interface{}:
+		values := make([]string, len(reply))
+		for i, value := range reply {
+			s, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("redis: unexpected reply [%T] in an array of strings", value)
+			}
+			values[i] = s
+		}
+		return values, nil
+	case nil:
+		return nil, ErrNil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply [%T] for an array", reply)
+	}
+}
+
+// StringMap returns the array reply of field and value pairs, e.g. of
+// HGETALL, as a map.
+func StringMap(reply interface{}, err error) (map[string]string, error) {
+	values, err := Strings(reply, err)
+	if err != nil {
+		return nil, err
+	}
+	if len(values)%2 != 0 {
+		return nil, fmt.Errorf("redis: odd number of values in a map reply")
+	}
+	m := make(map[string]string, len(values)/2)
+	for i := 0; i < len(values); i += 2 {
+		m[values[i]] = values[i+1]
+	}
+	return m, nil
+}
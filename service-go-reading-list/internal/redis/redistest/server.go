@@ -0,0 +1,783 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package redistest provides an in-process server of the Redis protocol for
+// the tests, so that they do not depend on an external Redis. It supports
+// the commands of the book repository on strings, hashes and sorted sets,
+// including the transactions with WATCH, MULTI and EXEC, and keeps its data
+// in memory.
+package redistest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/redis"
+)
+
+const (
+	errWrongType   = redis.Error("WRONGTYPE Operation against a key holding the wrong kind of value")
+	errNotInteger  = redis.Error("ERR value is not an integer or out of range")
+	errNotFloat    = redis.Error("ERR value is not a valid float")
+	errSyntax      = redis.Error("ERR syntax error")
+	errNoAuth      = redis.Error("NOAUTH Authentication required.")
+	errInvalidPass = redis.Error("WRONGPASS invalid username-password pair or user is disabled.")
+)
+
+// Server is a Redis protocol server listening on a loopback port.
+type Server struct {
+	listener net.Listener
+	password string
+	lock     sync.Mutex
+	dbs      map[int]*database
+	// version is bumped on every write and recorded per key for WATCH.
+	version uint64
+	conns   map[net.Conn]bool
+	done    sync.WaitGroup
+}
+
+type database struct {
+	values   map[string]interface{}
+	versions map[string]uint64
+}
+
+// sortedSet maps the members to their scores.
+type sortedSet map[string]float64
+
+// NewServer starts a server on a random loopback port.
+func NewServer() (*Server, error) {
+	return NewServerWithPassword("")
+}
+
+// NewServerWithPassword starts a server that requires the clients to
+// authenticate with the password.
+func NewServerWithPassword(password string) (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		listener: listener,
+		password: password,
+		dbs:      make(map[int]*database),
+		conns:    make(map[net.Conn]bool),
+	}
+	s.done.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Addr returns the host:port the server listens on.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// URL returns the redis:// URL of the server.
+func (s *Server) URL() string {
+	if s.password != "" {
+		return fmt.Sprintf("redis://:%s@%s", s.password, s.Addr())
+	}
+	return "redis://" + s.Addr()
+}
+
+// Close stops the server and closes the connections of the clients.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.lock.Lock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.lock.Unlock()
+	s.done.Wait()
+	return err
+}
+
+// FlushAll removes the keys of all the databases.
+func (s *Server) FlushAll() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, db := range s.dbs {
+		for key := range db.values {
+			s.touch(db, key)
+		}
+		db.values = make(map[string]interface{})
+	}
+}
+
+// Keys returns the keys of the database, sorted.
+func (s *Server) Keys(db int) []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var keys []string
+	for key := range s.db(db).values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *Server) serve() {
+	defer s.done.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.lock.Lock()
+		s.conns[conn] = true
+		s.lock.Unlock()
+		s.done.Add(1)
+		go s.handle(conn)
+	}
+}
+
+// session is the state of a client connection.
+type session struct {
+	authenticated bool
+	db            int
+	watched       map[watchedKey]uint64
+	// queued are the commands of the transaction after MULTI, which is
+	// aborted by EXEC if a command failed to be queued.
+	queued  [][]string
+	multi   bool
+	aborted bool
+}
+
+type watchedKey struct {
+	db  int
+	key string
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer s.done.Done()
+	defer func() {
+		s.lock.Lock()
+		delete(s.conns, conn)
+		s.lock.Unlock()
+		_ = conn.Close()
+	}()
+	reader := bufio.NewReader(conn)
+	writer := bufio.NewWriter(conn)
+	sess := &session{authenticated: s.password == ""}
+	for {
+		args, err := redis.ReadCommand(reader)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				writeReply(writer, redis.Error("ERR Protocol error: "+err.Error()))
+				_ = writer.Flush()
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+		name := strings.ToUpper(args[0])
+		writeReply(writer, s.dispatch(sess, name, args[1:]))
+		if err := writer.Flush(); err != nil || name == "QUIT" {
+			return
+		}
+	}
+}
+
+// dispatch runs the connection commands and queues or runs the data commands.
+func (s *Server) dispatch(sess *session, name string, args []string) interface{} {
+	if name == "AUTH" {
+		if len(args) != 1 {
+			return wrongArgs(name)
+		}
+		if s.password == "" {
+			return redis.Error("ERR AUTH <password> called without any password configured for the default user")
+		}
+		if args[0] != s.password {
+			return errInvalidPass
+		}
+		sess.authenticated = true
+		return "OK"
+	}
+	if !sess.authenticated {
+		return errNoAuth
+	}
+	switch name {
+	case "PING":
+		if len(args) == 1 {
+			return args[0]
+		}
+		return "PONG"
+	case "QUIT":
+		return "OK"
+	case "SELECT":
+		if len(args) != 1 {
+			return wrongArgs(name)
+		}
+		db, err := strconv.Atoi(args[0])
+		if err != nil || db < 0 || db > 15 {
+			return redis.Error("ERR DB index is out of range")
+		}
+		sess.db = db
+		return "OK"
+	case "MULTI":
+		if sess.multi {
+			return redis.Error("ERR MULTI calls can not be nested")
+		}
+		sess.multi = true
+		return "OK"
+	case "DISCARD":
+		if !sess.multi {
+			return redis.Error("ERR DISCARD without MULTI")
+		}
+		sess.reset()
+		return "OK"
+	case "EXEC":
+		if !sess.multi {
+			return redis.Error("ERR EXEC without MULTI")
+		}
+		return s.exec(sess)
+	case "WATCH":
+		if sess.multi {
+			return redis.Error("ERR WATCH inside MULTI is not allowed")
+		}
+		if len(args) == 0 {
+			return wrongArgs(name)
+		}
+		s.watch(sess, args)
+		return "OK"
+	case "UNWATCH":
+		sess.watched = nil
+		return "OK"
+	}
+	if _, ok := commands[name]; !ok {
+		if sess.multi {
+			sess.aborted = true
+		}
+		return redis.Error(fmt.Sprintf("ERR unknown command '%s'", strings.ToLower(name)))
+	}
+	if sess.multi {
+		sess.queued = append(sess.queued, append([]string{name}, args...))
+		return "QUEUED"
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.run(sess.db, name, args)
+}
+
+func (s *Server) watch(sess *session, keys []string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if sess.watched == nil {
+		sess.watched = make(map[watchedKey]uint64)
+	}
+	db := s.db(sess.db)
+	for _, key := range keys {
+		k := watchedKey{db: sess.db, key: key}
+		if _, ok := sess.watched[k]; !ok {
+			sess.watched[k] = db.versions[key]
+		}
+	}
+}
+
+// exec runs the queued commands atomically, unless a watched key changed.
+func (s *Server) exec(sess *session) interface{} {
+	defer sess.reset()
+	if sess.aborted {
+		return redis.Error("EXECABORT Transaction discarded because of previous errors.")
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for k, version := range sess.watched {
+		if s.db(k.db).versions[k.key] != version {
+			return nil
+		}
+	}
+	replies := make([]interface{}, len(sess.queued))
+	for i, command := range sess.queued {
+		replies[i] = s.run(sess.db, command[0], command[1:])
+	}
+	return replies
+}
+
+func (sess *session) reset() {
+	sess.multi = false
+	sess.aborted = false
+	sess.queued = nil
+	sess.watched = nil
+}
+
+// command runs a data command with the lock of the server held.
+type command func(s *Server, db *database, args []string) interface{}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"FLUSHDB":       flushDB,
+		"DEL":           del,
+		"EXISTS":        exists,
+		"GET":           get,
+		"SET":           set,
+		"INCR":          incr,
+		"HSET":          hset,
+		"HGET":          hget,
+		"HGETALL":       hgetall,
+		"HDEL":          hdel,
+		"ZADD":          zadd,
+		"ZREM":          zrem,
+		"ZCARD":         zcard,
+		"ZSCORE":        zscore,
+		"ZRANGE":        zrange,
+		"ZRANGEBYSCORE": zrangeByScore,
+	}
+}
+
+func (s *Server) run(db int, name string, args []string) interface{} {
+	return commands[name](s, s.db(db), args)
+}
+
+func (s *Server) db(index int) *database {
+	db, ok := s.dbs[index]
+	if !ok {
+		db = &database{values: make(map[string]interface{}), versions: make(map[string]uint64)}
+		s.dbs[index] = db
+	}
+	return db
+}
+
+// touch records a write to the key for the transactions watching it.
+func (s *Server) touch(db *database, key string) {
+	s.version++
+	db.versions[key] = s.version
+}
+
+func flushDB(s *Server, db *database, args []string) interface{} {
+	for key := range db.values {
+		s.touch(db, key)
+	}
+	db.values = make(map[string]interface{})
+	return "OK"
+}
+
+func del(s *Server, db *database, args []string) interface{} {
+	if len(args) == 0 {
+		return wrongArgs("DEL")
+	}
+	var n int64
+	for _, key := range args {
+		if _, ok := db.values[key]; ok {
+			delete(db.values, key)
+			s.touch(db, key)
+			n++
+		}
+	}
+	return n
+}
+
+func exists(s *Server, db *database, args []string) interface{} {
+	if len(args) == 0 {
+		return wrongArgs("EXISTS")
+	}
+	var n int64
+	for _, key := range args {
+		if _, ok := db.values[key]; ok {
+			n++
+		}
+	}
+	return n
+}
+
+func get(s *Server, db *database, args []string) interface{} {
+	if len(args) != 1 {
+		return wrongArgs("GET")
+	}
+	switch value := db.values[args[0]].(type) {
+	case nil:
+		return nil
+	case string:
+		return value
+	default:
+		return errWrongType
+	}
+}
+
+// set supports the NX and XX options.
+func set(s *Server, db *database, args []string) interface{} {
+	if len(args) < 2 {
+		return wrongArgs("SET")
+	}
+	key := args[0]
+	_, found := db.values[key]
+	for _, option := range args[2:] {
+		switch strings.ToUpper(option) {
+		case "NX":
+			if found {
+				return nil
+			}
+		case "XX":
+			if !found {
+				return nil
+			}
+		default:
+			return errSyntax
+		}
+	}
+	db.values[key] = args[1]
+	s.touch(db, key)
+	return "OK"
+}
+
+func incr(s *Server, db *database, args []string) interface{} {
+	if len(args) != 1 {
+		return wrongArgs("INCR")
+	}
+	var n int64
+	switch value := db.values[args[0]].(type) {
+	case nil:
+	case string:
+		var err error
+		if n, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return errNotInteger
+		}
+	default:
+		return errWrongType
+	}
+	n++
+	db.values[args[0]] = strconv.FormatInt(n, 10)
+	s.touch(db, args[0])
+	return n
+}
+
+// hash returns the hash at the key, creating it if asked to.
+func hash(db *database, key string, create bool) (map[string]string, interface{}) {
+	switch value := db.values[key].(type) {
+	case nil:
+		if !create {
+			return nil, nil
+		}
+		h := make(map[string]string)
+		db.values[key] = h
+		return h, nil
+	case map[string]string:
+		return value, nil
+	default:
+		return nil, errWrongType
+	}
+}
+
+func hset(s *Server, db *database, args []string) interface{} {
+	if len(args) < 3 || len(args)%2 != 1 {
+		return wrongArgs("HSET")
+	}
+	h, errReply := hash(db, args[0], true)
+	if errReply != nil {
+		return errReply
+	}
+	var n int64
+	for i := 1; i < len(args); i += 2 {
+		if _, ok := h[args[i]]; !ok {
+			n++
+		}
+		h[args[i]] = args[i+1]
+	}
+	s.touch(db, args[0])
+	return n
+}
+
+func hget(s *Server, db *database, args []string) interface{} {
+	if len(args) != 2 {
+		return wrongArgs("HGET")
+	}
+	h, errReply := hash(db, args[0], false)
+	if errReply != nil {
+		return errReply
+	}
+	if value, ok := h[args[1]]; ok {
+		return value
+	}
+	return nil
+}
+
+func hgetall(s *Server, db *database, args []string) interface{} {
+	if len(args) != 1 {
+		return wrongArgs("HGETALL")
+	}
+	h, errReply := hash(db, args[0], false)
+	if errReply != nil {
+		return errReply
+	}
+	fields := make([]string, 0, len(h))
+	for field := range h {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	reply := make([]interface{}, 0, 2*len(h))
+	for _, field := range fields {
+		reply = append(reply, field, h[field])
+	}
+	return reply
+}
+
+func hdel(s *Server, db *database, args []string) interface{} {
+	if len(args) < 2 {
+		return wrongArgs("HDEL")
+	}
+	h, errReply := hash(db, args[0], false)
+	if errReply != nil {
+		return errReply
+	}
+	var n int64
+	for _, field := range args[1:] {
+		if _, ok := h[field]; ok {
+			delete(h, field)
+			n++
+		}
+	}
+	if n > 0 {
+		if len(h) == 0 {
+			delete(db.values, args[0])
+		}
+		s.touch(db, args[0])
+	}
+	return n
+}
+
+// sortedSetAt returns the sorted set at the key, creating it if asked to.
+func sortedSetAt(db *database, key string, create bool) (sortedSet, interface{}) {
+	switch value := db.values[key].(type) {
+	case nil:
+		if !create {
+			return nil, nil
+		}
+		z := make(sortedSet)
+		db.values[key] = z
+		return z, nil
+	case sortedSet:
+		return value, nil
+	default:
+		return nil, errWrongType
+	}
+}
+
+// zadd supports the NX and XX options.
+func zadd(s *Server, db *database, args []string) interface{} {
+	if len(args) < 3 {
+		return wrongArgs("ZADD")
+	}
+	key, args := args[0], args[1:]
+	nx, xx := false, false
+	for len(args) > 0 {
+		option := strings.ToUpper(args[0])
+		if option == "NX" {
+			nx = true
+		} else if option == "XX" {
+			xx = true
+		} else {
+			break
+		}
+		args = args[1:]
+	}
+	if len(args) == 0 || len(args)%2 != 0 || (nx && xx) {
+		return errSyntax
+	}
+	scores := make([]float64, 0, len(args)/2)
+	for i := 0; i < len(args); i += 2 {
+		score, err := strconv.ParseFloat(args[i], 64)
+		if err != nil || math.IsNaN(score) {
+			return errNotFloat
+		}
+		scores = append(scores, score)
+	}
+	z, errReply := sortedSetAt(db, key, !xx)
+	if errReply != nil {
+		return errReply
+	}
+	if z == nil {
+		return int64(0)
+	}
+	var added int64
+	for i, score := range scores {
+		member := args[2*i+1]
+		if _, ok := z[member]; ok {
+			if !nx {
+				z[member] = score
+			}
+		} else if !xx {
+			z[member] = score
+			added++
+		}
+	}
+	if len(z) == 0 {
+		delete(db.values, key)
+	}
+	s.touch(db, key)
+	return added
+}
+
+func zrem(s *Server, db *database, args []string) interface{} {
+	if len(args) < 2 {
+		return wrongArgs("ZREM")
+	}
+	z, errReply := sortedSetAt(db, args[0], false)
+	if errReply != nil {
+		return errReply
+	}
+	var n int64
+	for _, member := range args[1:] {
+		if _, ok := z[member]; ok {
+			delete(z, member)
+			n++
+		}
+	}
+	if n > 0 {
+		if len(z) == 0 {
+			delete(db.values, args[0])
+		}
+		s.touch(db, args[0])
+	}
+	return n
+}
+
+func zcard(s *Server, db *database, args []string) interface{} {
+	if len(args) != 1 {
+		return wrongArgs("ZCARD")
+	}
+	z, errReply := sortedSetAt(db, args[0], false)
+	if errReply != nil {
+		return errReply
+	}
+	return int64(len(z))
+}
+
+func zscore(s *Server, db *database, args []string) interface{} {
+	if len(args) != 2 {
+		return wrongArgs("ZSCORE")
+	}
+	z, errReply := sortedSetAt(db, args[0], false)
+	if errReply != nil {
+		return errReply
+	}
+	if score, ok := z[args[1]]; ok {
+		return strconv.FormatFloat(score, 'f', -1, 64)
+	}
+	return nil
+}
+
+// zrange supports the ranks of the members, without the other options.
+func zrange(s *Server, db *database, args []string) interface{} {
+	if len(args) != 3 {
+		return wrongArgs("ZRANGE")
+	}
+	z, errReply := sortedSetAt(db, args[0], false)
+	if errReply != nil {
+		return errReply
+	}
+	start, err1 := strconv.Atoi(args[1])
+	stop, err2 := strconv.Atoi(args[2])
+	if err1 != nil || err2 != nil {
+		return errNotInteger
+	}
+	members := z.members()
+	n := len(members)
+	if start < 0 {
+		start = max(n+start, 0)
+	}
+	if stop < 0 {
+		stop = n + stop
+	}
+	stop = min(stop, n-1)
+	reply := make([]interface{}, 0)
+	for i := start; i <= stop; i++ {
+		reply = append(reply, members[i])
+	}
+	return reply
+}
+
+// zrangeByScore supports the exclusive and infinite bounds, without the
+// other options.
+func zrangeByScore(s *Server, db *database, args []string) interface{} {
+	if len(args) != 3 {
+		return wrongArgs("ZRANGEBYSCORE")
+	}
+	z, errReply := sortedSetAt(db, args[0], false)
+	if errReply != nil {
+		return errReply
+	}
+	minScore, minExclusive, ok1 := parseScoreBound(args[1])
+	maxScore, maxExclusive, ok2 := parseScoreBound(args[2])
+	if !ok1 || !ok2 {
+		return redis.Error("ERR min or max is not a float")
+	}
+	reply := make([]interface{}, 0)
+	for _, member := range z.members() {
+		score := z[member]
+		if score < minScore || (minExclusive && score == minScore) {
+			continue
+		}
+		if score > maxScore || (maxExclusive && score == maxScore) {
+			continue
+		}
+		reply = append(reply, member)
+	}
+	return reply
+}
+
+func parseScoreBound(bound string) (float64, bool, bool) {
+	exclusive := strings.HasPrefix(bound, "(")
+	bound = strings.TrimPrefix(bound, "(")
+	switch strings.ToLower(bound) {
+	case "-inf":
+		return math.Inf(-1), exclusive, true
+	case "+inf", "inf":
+		return math.Inf(1), exclusive, true
+	}
+	score, err := strconv.ParseFloat(bound, 64)
+	return score, exclusive, err == nil
+}
+
+// members returns the members ordered by score, then lexicographically.
+func (z sortedSet) members() []string {
+	members := make([]string, 0, len(z))
+	for member := range z {
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if z[members[i]] != z[members[j]] {
+			return z[members[i]] < z[members[j]]
+		}
+		return members[i] < members[j]
+	})
+	return members
+}
+
+func wrongArgs(name string) redis.Error {
+	return redis.Error(fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(name)))
+}
+
+func writeReply(w *bufio.Writer, reply interface{}) {
+	switch reply := reply.(type) {
+	case nil:
+		_, _ = w.WriteString("$-1\r\n")
+	case redis.Error:
+		_, _ = fmt.Fprintf(w, "-%s\r\n", reply)
+	case int64:
+		_, _ = fmt.Fprintf(w, ":%d\r\n", reply)
+	case string:
+		if reply == "OK" || reply == "QUEUED" || reply == "PONG" {
+			_, _ = fmt.Fprintf(w, "+%s\r\n", reply)
+		} else {
+			_, _ = fmt.Fprintf(w, "$%d\r\n%s\r\n", len(reply), reply)
+		}
+	case []interface{}:
+		_, _ = fmt.Fprintf(w, "*%d\r\n", len(reply))
+		for _, value := range reply {
+			writeReply(w, value)
+		}
+	default:
+		panic(fmt.Sprintf("redistest: unsupported reply [%T]", reply))
+	}
+}
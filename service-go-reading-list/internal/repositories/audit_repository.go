@@ -0,0 +1,152 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+)
+
+type auditRepository struct {
+	entries []models.AuditEntry
+	lock    sync.RWMutex
+	// file, when set, receives every appended entry as a JSON line.
+	file *os.File
+}
+
+// NewAuditRepository returns an audit log that is kept in memory.
+func NewAuditRepository() models.AuditRepository {
+	return &auditRepository{lock: sync.RWMutex{}}
+}
+
+func (r *auditRepository) Append(ctx context.Context, entries ...models.AuditEntry) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("auditRepository:Append: %w", err)
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	appended := make([]models.AuditEntry, len(entries))
+	var lines bytes.Buffer
+	for i, entry := range entries {
+		entry.Id = int64(len(r.entries) + i + 1)
+		appended[i] = entry
+		if r.file != nil {
+			line, err := json.Marshal(entry)
+			if err != nil {
+				return fmt.Errorf("auditRepository:Append: %w", err)
+			}
+			lines.Write(append(line, '\n'))
+		}
+	}
+	if r.file != nil {
+		if _, err := r.file.Write(lines.Bytes()); err != nil {
+			return fmt.Errorf("auditRepository:Append: %w", err)
+		}
+		if err := r.file.Sync(); err != nil {
+			return fmt.Errorf("auditRepository:Append: %w", err)
+		}
+	}
+	r.entries = append(r.entries, appended...)
+	return nil
+}
+
+func (r *auditRepository) List(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("auditRepository:List: %w", err)
+	}
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	entries := make([]models.AuditEntry, 0)
+	for _, entry := range r.entries {
+		if filter.Tenant != "" && entry.Tenant != filter.Tenant {
+			continue
+		}
+		if filter.BookId != "" && entry.BookId != filter.BookId {
+			continue
+		}
+		if !filter.Since.IsZero() && entry.Timestamp.Before(filter.Since) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// PersistentAuditRepository is an audit log that is also appended to a file
+// of JSON lines, from which it is restored on startup.
+type PersistentAuditRepository struct {
+	*auditRepository
+}
+
+// NewPersistentAuditRepository opens the audit log at path, creating it if
+// needed. An incomplete line at the end of the file is the result of a crash
+// during append; it is discarded.
+func NewPersistentAuditRepository(path string) (*PersistentAuditRepository, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	entries, validSize, err := readAuditLog(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if validSize < info.Size() {
+		logrus.WithFields(logrus.Fields{"path": path, "discardedBytes": info.Size() - validSize}).
+			Warn("discarding an incomplete entry at the end of the audit log")
+		if err := file.Truncate(validSize); err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+	}
+	if _, err := file.Seek(validSize, io.SeekStart); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &PersistentAuditRepository{&auditRepository{entries: entries, lock: sync.RWMutex{}, file: file}}, nil
+}
+
+// Close closes the file of the audit log.
+func (r *PersistentAuditRepository) Close() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.file.Close()
+}
+
+// readAuditLog reads the complete lines of the file and returns their entries
+// together with the offset just after the last complete line.
+func readAuditLog(file *os.File) ([]models.AuditEntry, int64, error) {
+	var entries []models.AuditEntry
+	var offset int64
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			return entries, offset, nil
+		} else if err != nil {
+			return nil, 0, err
+		}
+		var entry models.AuditEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, 0, fmt.Errorf("%w: entry at offset %d: %s", ErrCorruptedAuditLog, offset, err)
+		}
+		entries = append(entries, entry)
+		offset += int64(len(line))
+	}
+}
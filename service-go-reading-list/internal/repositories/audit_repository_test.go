@@ -0,0 +1,88 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+)
+
+func TestAuditRepository(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []models.AuditEntry{
+		{Timestamp: start, Actor: "alice", Operation: models.AuditOperationAdd, BookId: "1"},
+		{Timestamp: start.Add(time.Hour), Actor: "bob", Operation: models.AuditOperationAdd, BookId: "2"},
+		{Timestamp: start.Add(2 * time.Hour), Actor: "alice", Operation: models.AuditOperationUpdate, BookId: "1",
+			Changes: map[string]models.AuditChange{"status": {Before: "to_read", After: "read"}}},
+	}
+	ids := func(entries []models.AuditEntry) []int64 {
+		var ids []int64
+		for _, entry := range entries {
+			ids = append(ids, entry.Id)
+		}
+		return ids
+	}
+
+	t.Run("List", func(t *testing.T) {
+		repo := NewAuditRepository()
+		require.NoError(t, repo.Append(ctx, entries[:2]...))
+		require.NoError(t, repo.Append(ctx, entries[2]))
+
+		all, err := repo.List(ctx, models.AuditFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1, 2, 3}, ids(all))
+		byBook, err := repo.List(ctx, models.AuditFilter{BookId: "1"})
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1, 3}, ids(byBook))
+		since, err := repo.List(ctx, models.AuditFilter{Since: start.Add(time.Hour)})
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{2, 3}, ids(since))
+		none, err := repo.List(ctx, models.AuditFilter{BookId: "missing"})
+		assert.NoError(t, err)
+		assert.Empty(t, none)
+	})
+
+	t.Run("Persistence", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.jsonl")
+		repo, err := NewPersistentAuditRepository(path)
+		require.NoError(t, err)
+		require.NoError(t, repo.Append(ctx, entries...))
+		require.NoError(t, repo.Close())
+
+		// An entry torn by a crash is discarded and the ids carry on.
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+		require.NoError(t, err)
+		_, err = file.WriteString(`{"id":4,"actor":"ali`)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		reopened, err := NewPersistentAuditRepository(path)
+		require.NoError(t, err)
+		defer reopened.Close()
+		all, err := reopened.List(ctx, models.AuditFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1, 2, 3}, ids(all))
+		assert.Equal(t, models.AuditChange{Before: "to_read", After: "read"}, all[2].Changes["status"])
+		require.NoError(t, reopened.Append(ctx, models.AuditEntry{Operation: models.AuditOperationDelete, BookId: "2"}))
+		all, err = reopened.List(ctx, models.AuditFilter{BookId: "2"})
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{2, 4}, ids(all))
+	})
+
+	t.Run("Corrupted", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.jsonl")
+		require.NoError(t, os.WriteFile(path, []byte("not json\n{}\n"), 0o600))
+		_, err := NewPersistentAuditRepository(path)
+		assert.True(t, errors.Is(err, ErrCorruptedAuditLog))
+	})
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/redis"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/redis/redistest"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories/repositorytest"
+)
+
+func BenchmarkBookRepository(b *testing.B) {
+	// The storages log when they are opened, which they are for every
+	// benchmark.
+	logrus.SetLevel(logrus.WarnLevel)
+	b.Run("Memory", func(b *testing.B) {
+		repositorytest.BenchmarkBookRepository(b, func(b *testing.B, initialData []models.Book) models.BookRepository {
+			return repositories.NewBookRepository(initialData)
+		})
+	})
+
+	// The baseline of the in-memory repository, which guards all the books
+	// with a single lock as it used to.
+	b.Run("SingleLock", func(b *testing.B) {
+		repositorytest.BenchmarkBookRepository(b, func(b *testing.B, initialData []models.Book) models.BookRepository {
+			return newSingleLockBookRepository(initialData)
+		})
+	})
+
+	b.Run("WriteAheadLog", func(b *testing.B) {
+		repositorytest.BenchmarkBookRepository(b, func(b *testing.B, initialData []models.Book) models.BookRepository {
+			repo, err := repositories.NewPersistentBookRepository(b.TempDir(), initialData)
+			require.NoError(b, err)
+			b.Cleanup(func() { _ = repo.Close() })
+			return repo
+		})
+	})
+
+	b.Run("Bolt", func(b *testing.B) {
+		repositorytest.BenchmarkBookRepository(b, func(b *testing.B, initialData []models.Book) models.BookRepository {
+			repo, err := repositories.NewBoltBookRepository(b.TempDir(), initialData)
+			require.NoError(b, err)
+			b.Cleanup(func() { _ = repo.Close() })
+			return repo
+		})
+	})
+
+	b.Run("Redis", func(b *testing.B) {
+		repositorytest.BenchmarkBookRepository(b, func(b *testing.B, initialData []models.Book) models.BookRepository {
+			server, err := redistest.NewServer()
+			require.NoError(b, err)
+			b.Cleanup(func() { _ = server.Close() })
+			repo, err := repositories.NewRedisBookRepository(redis.NewClient(redis.Options{Addr: server.Addr()}), "reading-list:", initialData)
+			require.NoError(b, err)
+			b.Cleanup(func() { _ = repo.Close() })
+			return repo
+		})
+	})
+
+	b.Run("Tenant", func(b *testing.B) {
+		repositorytest.BenchmarkBookRepository(b, func(b *testing.B, initialData []models.Book) models.BookRepository {
+			repo, err := repositories.NewTenantBookRepository(repositories.NewBookRepository(initialData), repositories.TenantBookRepositoryConfig{})
+			require.NoError(b, err)
+			return repo
+		})
+	})
+}
+
+// singleLockBookRepository keeps the books in a map guarded by a single
+// lock, and lists them by reading the whole map.
+type singleLockBookRepository struct {
+	lock  sync.RWMutex
+	store map[string]models.Book
+}
+
+func newSingleLockBookRepository(initialData []models.Book) *singleLockBookRepository {
+	store := make(map[string]models.Book, len(initialData))
+	for _, book := range initialData {
+		store[book.Id] = book
+	}
+	return &singleLockBookRepository{store: store}
+}
+
+func (r *singleLockBookRepository) Add(_ context.Context, book models.Book) (models.Book, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.store[book.Id]; ok {
+		return models.Book{}, repositories.ErrRecordAlreadyExists
+	}
+	r.store[book.Id] = book
+	return book, nil
+}
+
+func (r *singleLockBookRepository) Update(_ context.Context, book models.Book) (models.Book, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if stored, ok := r.store[book.Id]; !ok || stored.IsDeleted() {
+		return models.Book{}, repositories.ErrRecordNotFound
+	}
+	r.store[book.Id] = book
+	return book, nil
+}
+
+func (r *singleLockBookRepository) List(context.Context) ([]models.Book, error) {
+	return r.list(false), nil
+}
+
+func (r *singleLockBookRepository) ListDeleted(context.Context) ([]models.Book, error) {
+	return r.list(true), nil
+}
+
+func (r *singleLockBookRepository) list(deleted bool) []models.Book {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	var books []models.Book
+	for _, book := range r.store {
+		if book.IsDeleted() == deleted {
+			books = append(books, book)
+		}
+	}
+	return books
+}
+
+func (r *singleLockBookRepository) GetById(_ context.Context, id string) (models.Book, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	book, ok := r.store[id]
+	if !ok || book.IsDeleted() {
+		return models.Book{}, repositories.ErrRecordNotFound
+	}
+	return book, nil
+}
+
+func (r *singleLockBookRepository) DeleteById(_ context.Context, id string) (models.Book, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	book, ok := r.store[id]
+	if !ok || book.IsDeleted() {
+		return models.Book{}, repositories.ErrRecordNotFound
+	}
+	deletedAt := time.Now().UTC()
+	book.DeletedAt = &deletedAt
+	r.store[id] = book
+	return book, nil
+}
+
+func (r *singleLockBookRepository) RestoreById(_ context.Context, id string) (models.Book, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	book, ok := r.store[id]
+	if !ok || !book.IsDeleted() {
+		return models.Book{}, repositories.ErrRecordNotFound
+	}
+	book.DeletedAt = nil
+	r.store[id] = book
+	return book, nil
+}
+
+func (r *singleLockBookRepository) PurgeById(_ context.Context, id string) (models.Book, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	book, ok := r.store[id]
+	if !ok {
+		return models.Book{}, repositories.ErrRecordNotFound
+	}
+	delete(r.store, id)
+	return book, nil
+}
+
+func (r *singleLockBookRepository) PurgeDeletedBefore(_ context.Context, before time.Time) ([]models.Book, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	var purged []models.Book
+	for id, book := range r.store {
+		if book.IsDeleted() && book.DeletedAt.Before(before) {
+			delete(r.store, id)
+			purged = append(purged, book)
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,230 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+)
+
+type blobRepository struct {
+	lock  sync.RWMutex
+	blobs map[string]models.Blob
+}
+
+// NewBlobRepository returns a blob repository that is kept in memory.
+func NewBlobRepository() models.BlobRepository {
+	return &blobRepository{blobs: make(map[string]models.Blob)}
+}
+
+func (r *blobRepository) Put(ctx context.Context, key string, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("blobRepository:Put: %w", err)
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.blobs[key] = models.Blob{Data: bytes.Clone(data), ModifiedAt: time.Now().UTC()}
+	return nil
+}
+
+func (r *blobRepository) Get(ctx context.Context, key string) (models.Blob, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Blob{}, fmt.Errorf("blobRepository:Get: %w", err)
+	}
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	blob, ok := r.blobs[key]
+	if !ok {
+		return models.Blob{}, fmt.Errorf("blobRepository:Get: %w", ErrRecordNotFound)
+	}
+	// The blobs are not changed once stored, so the data is shared.
+	return blob, nil
+}
+
+func (r *blobRepository) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("blobRepository:Delete: %w", err)
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.blobs, key)
+	return nil
+}
+
+func (r *blobRepository) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, fmt.Errorf("blobRepository:DeletePrefix: %w", err)
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	deleted := 0
+	for key := range r.blobs {
+		if strings.HasPrefix(key, prefix) {
+			delete(r.blobs, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// FileBlobRepository stores every blob in a file under its directory, whose
+// path is made of the escaped segments of the key.
+type FileBlobRepository struct {
+	dir string
+}
+
+// NewFileBlobRepository returns a blob repository storing the blobs under
+// dir, which is created when it does not exist.
+func NewFileBlobRepository(dir string) (*FileBlobRepository, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create the blob directory [%s]: %w", dir, err)
+	}
+	return &FileBlobRepository{dir: dir}, nil
+}
+
+func (r *FileBlobRepository) Put(ctx context.Context, key string, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("FileBlobRepository:Put: %w", err)
+	}
+	path, err := r.path(key)
+	if err != nil {
+		return fmt.Errorf("FileBlobRepository:Put: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("FileBlobRepository:Put: %w", err)
+	}
+	if err := writeFile(path, data); err != nil {
+		return fmt.Errorf("FileBlobRepository:Put: %w", err)
+	}
+	return nil
+}
+
+func (r *FileBlobRepository) Get(ctx context.Context, key string) (models.Blob, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Blob{}, fmt.Errorf("FileBlobRepository:Get: %w", err)
+	}
+	path, err := r.path(key)
+	if err != nil {
+		return models.Blob{}, fmt.Errorf("FileBlobRepository:Get: %w", err)
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return models.Blob{}, fmt.Errorf("FileBlobRepository:Get: %w", ErrRecordNotFound)
+	} else if err != nil {
+		return models.Blob{}, fmt.Errorf("FileBlobRepository:Get: %w", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return models.Blob{}, fmt.Errorf("FileBlobRepository:Get: %w", err)
+	}
+	data := make([]byte, info.Size())
+	if _, err := file.ReadAt(data, 0); err != nil {
+		return models.Blob{}, fmt.Errorf("FileBlobRepository:Get: %w", err)
+	}
+	return models.Blob{Data: data, ModifiedAt: info.ModTime().UTC()}, nil
+}
+
+func (r *FileBlobRepository) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("FileBlobRepository:Delete: %w", err)
+	}
+	path, err := r.path(key)
+	if err != nil {
+		return fmt.Errorf("FileBlobRepository:Delete: %w", err)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("FileBlobRepository:Delete: %w", err)
+	}
+	return nil
+}
+
+func (r *FileBlobRepository) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, fmt.Errorf("FileBlobRepository:DeletePrefix: %w", err)
+	}
+	// The prefix is a directory, whose blobs are counted before it is removed.
+	dir, err := r.path(strings.TrimSuffix(prefix, "/"))
+	if err != nil {
+		return 0, fmt.Errorf("FileBlobRepository:DeletePrefix: %w", err)
+	}
+	deleted := 0
+	err = filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			deleted++
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("FileBlobRepository:DeletePrefix: %w", err)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return 0, fmt.Errorf("FileBlobRepository:DeletePrefix: %w", err)
+	}
+	return deleted, nil
+}
+
+// path returns the path of the file of the key. The segments of the key are
+// escaped so that they cannot name another directory.
+func (r *FileBlobRepository) path(key string) (string, error) {
+	segments := strings.Split(key, "/")
+	elems := make([]string, 0, len(segments)+1)
+	elems = append(elems, r.dir)
+	for _, segment := range segments {
+		if segment == "" {
+			return "", ErrInvalidId
+		}
+		escaped := url.PathEscape(segment)
+		if strings.HasPrefix(escaped, ".") {
+			escaped = "%2E" + escaped[1:]
+		}
+		elems = append(elems, escaped)
+	}
+	return filepath.Join(elems...), nil
+}
+
+// writeFile atomically replaces the file at path with data.
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+)
+
+func TestBlobRepository(t *testing.T) {
+	repositories := map[string]func(t *testing.T) models.BlobRepository{
+		"Memory": func(t *testing.T) models.BlobRepository {
+			return NewBlobRepository()
+		},
+		"File": func(t *testing.T) models.BlobRepository {
+			repo, err := NewFileBlobRepository(filepath.Join(t.TempDir(), "blobs"))
+			require.NoError(t, err)
+			return repo
+		},
+	}
+	for name, newRepository := range repositories {
+		t.Run(name, func(t *testing.T) {
+			testBlobRepository(t, newRepository(t))
+		})
+	}
+}
+
+func testBlobRepository(t *testing.T, repo models.BlobRepository) {
+	ctx := context.Background()
+	start := time.Now().Add(-time.Second)
+	require.NoError(t, repo.Put(ctx, "acme/dune/original", []byte("cover")))
+	require.NoError(t, repo.Put(ctx, "acme/dune/small", []byte("thumbnail")))
+	require.NoError(t, repo.Put(ctx, "acme/dune-2/original", []byte("other cover")))
+	require.NoError(t, repo.Put(ctx, "globex/dune/original", []byte("globex cover")))
+
+	blob, err := repo.Get(ctx, "acme/dune/original")
+	require.NoError(t, err)
+	assert.Equal(t, "cover", string(blob.Data))
+	assert.True(t, blob.ModifiedAt.After(start), blob.ModifiedAt)
+	_, err = repo.Get(ctx, "acme/dune/large")
+	assert.ErrorIs(t, err, ErrRecordNotFound)
+
+	// Put replaces the blob.
+	require.NoError(t, repo.Put(ctx, "acme/dune/original", []byte("new cover")))
+	blob, err = repo.Get(ctx, "acme/dune/original")
+	require.NoError(t, err)
+	assert.Equal(t, "new cover", string(blob.Data))
+
+	// Delete removes the blob of the key only, if there is one.
+	require.NoError(t, repo.Put(ctx, "acme/dune/large", []byte("thumbnail")))
+	require.NoError(t, repo.Delete(ctx, "acme/dune/large"))
+	_, err = repo.Get(ctx, "acme/dune/large")
+	assert.ErrorIs(t, err, ErrRecordNotFound)
+	require.NoError(t, repo.Delete(ctx, "acme/dune/large"))
+	_, err = repo.Get(ctx, "acme/dune/small")
+	assert.NoError(t, err)
+
+	// The keys that share the prefix but not its segments are kept.
+	deleted, err := repo.DeletePrefix(ctx, "acme/dune/")
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+	_, err = repo.Get(ctx, "acme/dune/original")
+	assert.ErrorIs(t, err, ErrRecordNotFound)
+	_, err = repo.Get(ctx, "acme/dune-2/original")
+	assert.NoError(t, err)
+	deleted, err = repo.DeletePrefix(ctx, "acme/dune/")
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	deleted, err = repo.DeletePrefix(ctx, "acme/")
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	_, err = repo.Get(ctx, "globex/dune/original")
+	assert.NoError(t, err)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, repo.Put(cancelled, "acme/dune/original", []byte("cover")), context.Canceled)
+}
+
+func TestFileBlobRepositoryPaths(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "blobs")
+	repo, err := NewFileBlobRepository(dir)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// The segments cannot name another directory.
+	require.NoError(t, repo.Put(ctx, "acme/../original", []byte("cover")))
+	require.NoError(t, repo.Put(ctx, "acme/a%2Fb/original", []byte("cover")))
+	_, err = os.Stat(filepath.Join(root, "original"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	blob, err := repo.Get(ctx, "acme/../original")
+	require.NoError(t, err)
+	assert.Equal(t, "cover", string(blob.Data))
+	_, err = repo.Get(ctx, "acme/a/b/original")
+	assert.ErrorIs(t, err, ErrRecordNotFound)
+	assert.ErrorIs(t, repo.Put(ctx, "acme//original", []byte("cover")), ErrInvalidId)
+
+	// The blobs are kept across restarts.
+	repo, err = NewFileBlobRepository(dir)
+	require.NoError(t, err)
+	_, err = repo.Get(ctx, "acme/a%2Fb/original")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,684 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+)
+
+// BoltFileName is the file of the books in the data directory.
+const BoltFileName = "books.db"
+
+// boltOpenTimeout bounds how long the store waits for the lock of the file,
+// which is held by another process using the same data directory.
+const boltOpenTimeout = 5 * time.Second
+
+var (
+	booksBucket         = []byte("books")
+	booksByStatusBucket = []byte("books_by_status")
+	booksByAuthorBucket = []byte("books_by_author")
+	// tenantsBucket holds a bucket per tenant other than the default one,
+	// with the buckets of its books.
+	tenantsBucket = []byte("tenants")
+)
+
+// indexSeparator separates the indexed value from the id in the keys of the
+// indexes, so that the keys of a value are contiguous.
+const indexSeparator = 0
+
+// BoltBookRepository stores the books in a bbolt file in the data directory,
+// a single-file key-value store embedded in the service. The books are kept
+// as JSON by id, and indexed by status and by author in buckets whose keys
+// are the indexed value and the id of the book. Every method runs in its own
+// transaction of the store. The books of the other tenants are kept in the
+// same buckets, nested in the bucket of the tenant.
+type BoltBookRepository struct {
+	db *bolt.DB
+	// tenant is the tenant whose books are those of the repository, or
+	// empty for the default tenant.
+	tenant string
+}
+
+// NewBoltBookRepository opens the store of dir, creating it with the initial
+// data if it does not exist.
+func NewBoltBookRepository(dir string, initialData []models.Book) (*BoltBookRepository, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, BoltFileName)
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: boltOpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("boltBookRepository:New: %w", err)
+	}
+	err = db.Update(func(btx *bolt.Tx) error {
+		if btx.Bucket(booksBucket) != nil {
+			return nil
+		}
+		return createBoltBooks(&boltBookTx{tx: btx}, btx, initialData)
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("boltBookRepository:New: %w", err)
+	}
+	logrus.WithFields(logrus.Fields{"path": path}).Info("opened the book store")
+	return &BoltBookRepository{db: db}, nil
+}
+
+// createBoltBooks creates the buckets of the books in the parent, which is
+// the store or the bucket of a tenant, and adds the initial data to them.
+func createBoltBooks(tx *boltBookTx, parent interface {
+	CreateBucket(name []byte) (*bolt.Bucket, error)
+}, initialData []models.Book) error {
+	for _, name := range [][]byte{booksBucket, booksByStatusBucket, booksByAuthorBucket} {
+		if _, err := parent.CreateBucket(name); err != nil {
+			return err
+		}
+	}
+	for _, book := range initialData {
+		if _, err := tx.Add(context.Background(), book); err != nil && !errors.Is(err, ErrRecordAlreadyExists) {
+			return err
+		}
+	}
+	return nil
+}
+
+// RestoreBoltBackup copies the backup into dir as the store of the books,
+// unless dir already has one, in which case the backup is ignored so that
+// restarting the service does not discard the changes since the restore.
+// It reports whether the backup was restored.
+func RestoreBoltBackup(dir, backupPath string) (bool, error) {
+	path := filepath.Join(dir, BoltFileName)
+	if _, err := os.Stat(path); err == nil {
+		return false, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+	if err := checkBoltBackup(backupPath); err != nil {
+		return false, err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return false, err
+	}
+	src, err := os.Open(backupPath)
+	if err != nil {
+		return false, err
+	}
+	defer src.Close()
+	// The backup is copied next to the store and renamed, so that a failed
+	// copy does not leave a partial store behind.
+	tmp, err := os.CreateTemp(dir, BoltFileName+".*.tmp")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, src); err != nil {
+		_ = tmp.Close()
+		return false, err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return false, err
+	}
+	if err := tmp.Close(); err != nil {
+		return false, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// checkBoltBackup checks that the file is a store of the books.
+func checkBoltBackup(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{ReadOnly: true, Timeout: boltOpenTimeout})
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidBackup, err)
+	}
+	defer db.Close()
+	return db.View(func(btx *bolt.Tx) error {
+		for _, name := range [][]byte{booksBucket, booksByStatusBucket, booksByAuthorBucket} {
+			if btx.Bucket(name) == nil {
+				return fmt.Errorf("%w: the bucket [%s] is missing", ErrInvalidBackup, name)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *BoltBookRepository) Add(ctx context.Context, book models.Book) (models.Book, error) {
+	var added models.Book
+	err := r.update(ctx, func(tx *boltBookTx) (err error) {
+		added, err = tx.Add(ctx, book)
+		return err
+	})
+	if err != nil {
+		return models.Book{}, fmt.Errorf("boltBookRepository:Add: %w", err)
+	}
+	return added, nil
+}
+
+func (r *BoltBookRepository) Update(ctx context.Context, updatedBook models.Book) (models.Book, error) {
+	var updated models.Book
+	err := r.update(ctx, func(tx *boltBookTx) (err error) {
+		updated, err = tx.Update(ctx, updatedBook)
+		return err
+	})
+	if err != nil {
+		return models.Book{}, fmt.Errorf("boltBookRepository:Update: %w", err)
+	}
+	return updated, nil
+}
+
+// UpdateById updates the book in a write transaction of the store.
+func (r *BoltBookRepository) UpdateById(ctx context.Context, id string, fn func(current models.Book) (models.Book, error)) (models.Book, error) {
+	var updated models.Book
+	err := r.update(ctx, func(tx *boltBookTx) (err error) {
+		updated, err = updateById(ctx, tx, id, fn)
+		return err
+	})
+	if err != nil {
+		return models.Book{}, fmt.Errorf("boltBookRepository:UpdateById: %w", err)
+	}
+	return updated, nil
+}
+
+func (r *BoltBookRepository) List(ctx context.Context) ([]models.Book, error) {
+	var books []models.Book
+	err := r.view(ctx, func(tx *boltBookTx) (err error) {
+		books, err = tx.List(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("boltBookRepository:List: %w", err)
+	}
+	return books, nil
+}
+
+func (r *BoltBookRepository) GetById(ctx context.Context, id string) (models.Book, error) {
+	var book models.Book
+	err := r.view(ctx, func(tx *boltBookTx) (err error) {
+		book, err = tx.GetById(ctx, id)
+		return err
+	})
+	if err != nil {
+		return models.Book{}, fmt.Errorf("boltBookRepository:GetById: %w", err)
+	}
+	return book, nil
+}
+
+func (r *BoltBookRepository) DeleteById(ctx context.Context, id string) (models.Book, error) {
+	var deleted models.Book
+	err := r.update(ctx, func(tx *boltBookTx) (err error) {
+		deleted, err = tx.DeleteById(ctx, id)
+		return err
+	})
+	if err != nil {
+		return models.Book{}, fmt.Errorf("boltBookRepository:DeleteById: %w", err)
+	}
+	return deleted, nil
+}
+
+func (r *BoltBookRepository) ListDeleted(ctx context.Context) ([]models.Book, error) {
+	var books []models.Book
+	err := r.view(ctx, func(tx *boltBookTx) (err error) {
+		books, err = tx.ListDeleted(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("boltBookRepository:ListDeleted: %w", err)
+	}
+	return books, nil
+}
+
+func (r *BoltBookRepository) RestoreById(ctx context.Context, id string) (models.Book, error) {
+	var restored models.Book
+	err := r.update(ctx, func(tx *boltBookTx) (err error) {
+		restored, err = tx.RestoreById(ctx, id)
+		return err
+	})
+	if err != nil {
+		return models.Book{}, fmt.Errorf("boltBookRepository:RestoreById: %w", err)
+	}
+	return restored, nil
+}
+
+func (r *BoltBookRepository) PurgeById(ctx context.Context, id string) (models.Book, error) {
+	var purged models.Book
+	err := r.update(ctx, func(tx *boltBookTx) (err error) {
+		purged, err = tx.PurgeById(ctx, id)
+		return err
+	})
+	if err != nil {
+		return models.Book{}, fmt.Errorf("boltBookRepository:PurgeById: %w", err)
+	}
+	return purged, nil
+}
+
+func (r *BoltBookRepository) PurgeDeletedBefore(ctx context.Context, before time.Time) ([]models.Book, error) {
+	var purged []models.Book
+	err := r.update(ctx, func(tx *boltBookTx) (err error) {
+		purged, err = tx.PurgeDeletedBefore(ctx, before)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("boltBookRepository:PurgeDeletedBefore: %w", err)
+	}
+	return purged, nil
+}
+
+func (r *BoltBookRepository) ListByStatus(ctx context.Context, status models.ReadStatus) ([]models.Book, error) {
+	var books []models.Book
+	err := r.view(ctx, func(tx *boltBookTx) (err error) {
+		books, err = tx.ListByStatus(ctx, status)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("boltBookRepository:ListByStatus: %w", err)
+	}
+	return books, nil
+}
+
+func (r *BoltBookRepository) ListByAuthor(ctx context.Context, author string) ([]models.Book, error) {
+	var books []models.Book
+	err := r.view(ctx, func(tx *boltBookTx) (err error) {
+		books, err = tx.ListByAuthor(ctx, author)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("boltBookRepository:ListByAuthor: %w", err)
+	}
+	return books, nil
+}
+
+// RunInTransaction runs fn in a write transaction of the store, which is
+// committed if fn returns nil and rolled back otherwise. The other writes
+// wait for it to complete.
+func (r *BoltBookRepository) RunInTransaction(ctx context.Context, fn func(tx models.BookRepository) error) error {
+	return r.update(ctx, func(tx *boltBookTx) error {
+		return fn(tx)
+	})
+}
+
+// Backup writes a consistent copy of the store, which is a store itself,
+// while the books keep being read and changed. The copy is made in a
+// temporary file of the data directory, and written to w once the read
+// transaction is over, so that a slow reader of w does not keep the store
+// from reclaiming the pages freed by the writes.
+func (r *BoltBookRepository) Backup(ctx context.Context, w io.Writer) (int64, error) {
+	n, err := r.backup(ctx, w)
+	if err != nil {
+		return n, fmt.Errorf("boltBookRepository:Backup: %w", err)
+	}
+	return n, nil
+}
+
+func (r *BoltBookRepository) backup(ctx context.Context, w io.Writer) (int64, error) {
+	snapshot, err := os.CreateTemp(filepath.Dir(r.db.Path()), BoltFileName+".backup-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(snapshot.Name())
+	defer snapshot.Close()
+	err = r.db.View(func(btx *bolt.Tx) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		_, err := btx.WriteTo(snapshot)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if _, err := snapshot.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return io.Copy(w, snapshot)
+}
+
+// Close closes the store, unless the repository holds the books of a
+// tenant, which are closed with the store.
+func (r *BoltBookRepository) Close() error {
+	if r.tenant != "" {
+		return nil
+	}
+	return r.db.Close()
+}
+
+// OpenTenant returns the books of the tenant, whose buckets are created in
+// the store when it has none.
+func (r *BoltBookRepository) OpenTenant(ctx context.Context, tenant string, initialData []models.Book) (models.BookRepository, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("boltBookRepository:OpenTenant: %w", err)
+	}
+	err := r.db.Update(func(btx *bolt.Tx) error {
+		tenants, err := btx.CreateBucketIfNotExists(tenantsBucket)
+		if err != nil {
+			return err
+		}
+		if tenants.Bucket([]byte(tenant)) != nil {
+			return nil
+		}
+		parent, err := tenants.CreateBucket([]byte(tenant))
+		if err != nil {
+			return err
+		}
+		return createBoltBooks(&boltBookTx{tx: btx, tenant: tenant}, parent, initialData)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("boltBookRepository:OpenTenant: %w", err)
+	}
+	return &BoltBookRepository{db: r.db, tenant: tenant}, nil
+}
+
+func (r *BoltBookRepository) ListTenantIds(ctx context.Context) ([]string, error) {
+	var ids []string
+	err := r.view(ctx, func(tx *boltBookTx) error {
+		tenants := tx.tx.Bucket(tenantsBucket)
+		if tenants == nil {
+			return nil
+		}
+		return tenants.ForEach(func(key, value []byte) error {
+			// The values of the nested buckets are nil.
+			if value == nil {
+				ids = append(ids, string(key))
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("boltBookRepository:ListTenantIds: %w", err)
+	}
+	return ids, nil
+}
+
+// DropTenant deletes the bucket of the tenant.
+func (r *BoltBookRepository) DropTenant(ctx context.Context, tenant string) error {
+	err := r.update(ctx, func(tx *boltBookTx) error {
+		tenants := tx.tx.Bucket(tenantsBucket)
+		if tenants == nil {
+			return nil
+		}
+		if err := tenants.DeleteBucket([]byte(tenant)); err != nil && !errors.Is(err, bolt.ErrBucketNotFound) {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("boltBookRepository:DropTenant: %w", err)
+	}
+	return nil
+}
+
+func (r *BoltBookRepository) update(ctx context.Context, fn func(tx *boltBookTx) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return r.db.Update(func(btx *bolt.Tx) error {
+		return r.run(btx, fn)
+	})
+}
+
+func (r *BoltBookRepository) view(ctx context.Context, fn func(tx *boltBookTx) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return r.db.View(func(btx *bolt.Tx) error {
+		return r.run(btx, fn)
+	})
+}
+
+// run calls fn with the books of the tenant of the repository in the
+// transaction, failing when the tenant was dropped meanwhile.
+func (r *BoltBookRepository) run(btx *bolt.Tx, fn func(tx *boltBookTx) error) error {
+	tx := &boltBookTx{tx: btx, tenant: r.tenant}
+	if r.tenant != "" && tx.parent() == nil {
+		return fmt.Errorf("the books of the tenant [%s] are dropped: %w", r.tenant, ErrRecordNotFound)
+	}
+	return fn(tx)
+}
+
+// boltBookTx runs the methods of the repository in a transaction of the
+// store. Its errors are wrapped by the methods of the repository.
+type boltBookTx struct {
+	tx     *bolt.Tx
+	tenant string
+}
+
+func (tx *boltBookTx) Add(ctx context.Context, book models.Book) (models.Book, error) {
+	if book.Id == "" {
+		book.Id = uuid.NewString()
+	}
+	existing, err := tx.get(book.Id)
+	if err != nil {
+		return models.Book{}, err
+	}
+	if existing != nil {
+		return models.Book{}, ErrRecordAlreadyExists
+	}
+	book.DeletedAt = nil
+	return book, tx.put(book, nil)
+}
+
+func (tx *boltBookTx) Update(ctx context.Context, updatedBook models.Book) (models.Book, error) {
+	existing, err := tx.get(updatedBook.Id)
+	if err != nil {
+		return models.Book{}, err
+	}
+	if existing == nil || existing.IsDeleted() {
+		return models.Book{}, ErrRecordNotFound
+	}
+	updatedBook.DeletedAt = nil
+	return updatedBook, tx.put(updatedBook, existing)
+}
+
+func (tx *boltBookTx) List(ctx context.Context) ([]models.Book, error) {
+	return tx.list(func(book models.Book) bool { return !book.IsDeleted() })
+}
+
+func (tx *boltBookTx) GetById(ctx context.Context, id string) (models.Book, error) {
+	book, err := tx.get(id)
+	if err != nil {
+		return models.Book{}, err
+	}
+	if book == nil || book.IsDeleted() {
+		return models.Book{}, ErrRecordNotFound
+	}
+	return *book, nil
+}
+
+func (tx *boltBookTx) DeleteById(ctx context.Context, id string) (models.Book, error) {
+	book, err := tx.GetById(ctx, id)
+	if err != nil {
+		return models.Book{}, err
+	}
+	previous := book
+	deletedAt := time.Now().UTC()
+	book.DeletedAt = &deletedAt
+	return book, tx.put(book, &previous)
+}
+
+func (tx *boltBookTx) ListDeleted(ctx context.Context) ([]models.Book, error) {
+	return tx.list(func(book models.Book) bool { return book.IsDeleted() })
+}
+
+func (tx *boltBookTx) RestoreById(ctx context.Context, id string) (models.Book, error) {
+	book, err := tx.get(id)
+	if err != nil {
+		return models.Book{}, err
+	}
+	if book == nil || !book.IsDeleted() {
+		return models.Book{}, ErrRecordNotFound
+	}
+	restored := *book
+	restored.DeletedAt = nil
+	return restored, tx.put(restored, book)
+}
+
+func (tx *boltBookTx) PurgeById(ctx context.Context, id string) (models.Book, error) {
+	book, err := tx.get(id)
+	if err != nil {
+		return models.Book{}, err
+	}
+	if book == nil {
+		return models.Book{}, ErrRecordNotFound
+	}
+	return *book, tx.remove(*book)
+}
+
+func (tx *boltBookTx) PurgeDeletedBefore(ctx context.Context, before time.Time) ([]models.Book, error) {
+	expired, err := tx.list(func(book models.Book) bool {
+		return book.IsDeleted() && book.DeletedAt.Before(before)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, book := range expired {
+		if err := tx.remove(book); err != nil {
+			return nil, err
+		}
+	}
+	return expired, nil
+}
+
+func (tx *boltBookTx) ListByStatus(ctx context.Context, status models.ReadStatus) ([]models.Book, error) {
+	return tx.listIndexed(booksByStatusBucket, string(status))
+}
+
+func (tx *boltBookTx) ListByAuthor(ctx context.Context, author string) ([]models.Book, error) {
+	return tx.listIndexed(booksByAuthorBucket, models.NormalizeAuthor(author))
+}
+
+// parent returns the bucket of the tenant, or nil when it has none. The
+// buckets of the default tenant are in the root of the store.
+func (tx *boltBookTx) parent() *bolt.Bucket {
+	tenants := tx.tx.Bucket(tenantsBucket)
+	if tenants == nil {
+		return nil
+	}
+	return tenants.Bucket([]byte(tx.tenant))
+}
+
+// bucket returns the bucket of the books of the tenant with the name.
+func (tx *boltBookTx) bucket(name []byte) *bolt.Bucket {
+	if tx.tenant == "" {
+		return tx.tx.Bucket(name)
+	}
+	return tx.parent().Bucket(name)
+}
+
+// get returns the book, or nil when it does not exist.
+func (tx *boltBookTx) get(id string) (*models.Book, error) {
+	contents := tx.bucket(booksBucket).Get([]byte(id))
+	if contents == nil {
+		return nil, nil
+	}
+	var book models.Book
+	if err := json.Unmarshal(contents, &book); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCorruptedRecord, err)
+	}
+	return &book, nil
+}
+
+// put stores the book and moves its index entries from those of the
+// previous version of the book, if any.
+func (tx *boltBookTx) put(book models.Book, previous *models.Book) error {
+	if previous != nil {
+		if err := tx.unindex(*previous); err != nil {
+			return err
+		}
+	}
+	contents, err := json.Marshal(book)
+	if err != nil {
+		return err
+	}
+	if err := tx.bucket(booksBucket).Put([]byte(book.Id), contents); err != nil {
+		return err
+	}
+	for bucket, value := range bookIndexValues(book) {
+		if err := tx.bucket([]byte(bucket)).Put(indexKey(value, book.Id), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tx *boltBookTx) remove(book models.Book) error {
+	if err := tx.unindex(book); err != nil {
+		return err
+	}
+	return tx.bucket(booksBucket).Delete([]byte(book.Id))
+}
+
+func (tx *boltBookTx) unindex(book models.Book) error {
+	for bucket, value := range bookIndexValues(book) {
+		if err := tx.bucket([]byte(bucket)).Delete(indexKey(value, book.Id)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tx *boltBookTx) list(matches func(book models.Book) bool) ([]models.Book, error) {
+	var books []models.Book
+	err := tx.bucket(booksBucket).ForEach(func(_, contents []byte) error {
+		var book models.Book
+		if err := json.Unmarshal(contents, &book); err != nil {
+			return fmt.Errorf("%w: %s", ErrCorruptedRecord, err)
+		}
+		if matches(book) {
+			books = append(books, book)
+		}
+		return nil
+	})
+	return books, err
+}
+
+// listIndexed returns the books, not in the trash, with the value in the index.
+func (tx *boltBookTx) listIndexed(bucket []byte, value string) ([]models.Book, error) {
+	prefix := indexKey(value, "")
+	var books []models.Book
+	cursor := tx.bucket(bucket).Cursor()
+	for key, _ := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, _ = cursor.Next() {
+		book, err := tx.get(string(key[len(prefix):]))
+		if err != nil {
+			return nil, err
+		}
+		if book != nil && !book.IsDeleted() {
+			books = append(books, *book)
+		}
+	}
+	return books, nil
+}
+
+// bookIndexValues returns the values of the book in each index bucket.
+func bookIndexValues(book models.Book) map[string]string {
+	return map[string]string{
+		string(booksByStatusBucket): string(book.Status),
+		string(booksByAuthorBucket): models.NormalizeAuthor(book.Author),
+	}
+}
+
+func indexKey(value, id string) []byte {
+	key := make([]byte, 0, len(value)+1+len(id))
+	key = append(key, value...)
+	key = append(key, indexSeparator)
+	return append(key, id...)
+}
@@ -0,0 +1,290 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+)
+
+// newBoltBookRepository returns a repository in a new directory.
+func newBoltBookRepository(t *testing.T, initialData []models.Book) (*BoltBookRepository, string) {
+	dir := t.TempDir()
+	repo, err := NewBoltBookRepository(dir, initialData)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = repo.Close() })
+	return repo, dir
+}
+
+func TestBoltBookRepository(t *testing.T) {
+	ctx := context.Background()
+	initialBook := models.Book{Id: "1", Title: "Test Book", Author: "Test Author"}
+	updatedBook := models.Book{Id: initialBook.Id, Title: "Updated Book", Author: "Updated Author", Tags: []string{"classic"}}
+	repo, dir := newBoltBookRepository(t, []models.Book{initialBook})
+
+	t.Run("Add", func(t *testing.T) {
+		addedBook, err := repo.Add(ctx, models.Book{Id: "2", Title: "New Book", Author: "New Author"})
+		assert.NoError(t, err)
+		assert.Equal(t, "2", addedBook.Id)
+		generated, err := repo.Add(ctx, models.Book{Title: "Generated Book"})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, generated.Id)
+		_, err = repo.PurgeById(ctx, generated.Id)
+		assert.NoError(t, err)
+
+		_, err = repo.Add(ctx, models.Book{Id: addedBook.Id, Title: "Duplicate Book"})
+		assert.ErrorIs(t, err, ErrRecordAlreadyExists)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		updated, err := repo.Update(ctx, updatedBook)
+		assert.NoError(t, err)
+		assert.Equal(t, updatedBook, updated)
+
+		_, err = repo.Update(ctx, models.Book{Id: "non-existing-id"})
+		assert.ErrorIs(t, err, ErrRecordNotFound)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		books, err := repo.List(ctx)
+		assert.NoError(t, err)
+		require.Len(t, books, 2)
+		// The books are listed by id.
+		assert.Equal(t, updatedBook, books[0])
+		assert.Equal(t, "New Book", books[1].Title)
+	})
+
+	t.Run("GetById", func(t *testing.T) {
+		book, err := repo.GetById(ctx, initialBook.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, updatedBook, book)
+
+		_, err = repo.GetById(ctx, "non-existing-id")
+		assert.ErrorIs(t, err, ErrRecordNotFound)
+	})
+
+	t.Run("Trash", func(t *testing.T) {
+		deletedBook, err := repo.DeleteById(ctx, initialBook.Id)
+		assert.NoError(t, err)
+		assert.True(t, deletedBook.IsDeleted())
+		_, err = repo.DeleteById(ctx, initialBook.Id)
+		assert.ErrorIs(t, err, ErrRecordNotFound)
+
+		books, err := repo.List(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, books, 1)
+		_, err = repo.GetById(ctx, initialBook.Id)
+		assert.ErrorIs(t, err, ErrRecordNotFound)
+		// Deleted books still reserve their ID.
+		_, err = repo.Add(ctx, initialBook)
+		assert.ErrorIs(t, err, ErrRecordAlreadyExists)
+
+		trashed, err := repo.ListDeleted(ctx)
+		assert.NoError(t, err)
+		require.Len(t, trashed, 1)
+		assert.Equal(t, deletedBook.Id, trashed[0].Id)
+		assert.True(t, deletedBook.DeletedAt.Equal(*trashed[0].DeletedAt))
+
+		restored, err := repo.RestoreById(ctx, initialBook.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, updatedBook, restored)
+		_, err = repo.RestoreById(ctx, initialBook.Id)
+		assert.ErrorIs(t, err, ErrRecordNotFound)
+
+		_, err = repo.DeleteById(ctx, initialBook.Id)
+		assert.NoError(t, err)
+		purged, err := repo.PurgeDeletedBefore(ctx, time.Now().Add(-time.Hour))
+		assert.NoError(t, err)
+		assert.Empty(t, purged)
+		purged, err = repo.PurgeDeletedBefore(ctx, time.Now().Add(time.Hour))
+		assert.NoError(t, err)
+		require.Len(t, purged, 1)
+		assert.Equal(t, initialBook.Id, purged[0].Id)
+		_, err = repo.PurgeById(ctx, initialBook.Id)
+		assert.ErrorIs(t, err, ErrRecordNotFound)
+	})
+
+	t.Run("Reopen", func(t *testing.T) {
+		require.NoError(t, repo.Close())
+		// The initial data is not added again to an existing store.
+		reopened, err := NewBoltBookRepository(dir, []models.Book{initialBook})
+		require.NoError(t, err)
+		defer reopened.Close()
+		books, err := reopened.List(ctx)
+		assert.NoError(t, err)
+		require.Len(t, books, 1)
+		assert.Equal(t, "New Book", books[0].Title)
+	})
+}
+
+func TestBoltBookRepositoryIndexes(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newBoltBookRepository(t, []models.Book{
+		{Id: "dune", Title: "Dune", Author: "Frank Herbert", Status: models.ReadStatusToRead},
+		{Id: "emma", Title: "Emma", Author: "Jane Austen", Status: models.ReadStatusRead},
+		{Id: "persuasion", Title: "Persuasion", Author: " jane austen", Status: models.ReadStatusToRead},
+	})
+	ids := func(books []models.Book, err error) []string {
+		require.NoError(t, err)
+		var ids []string
+		for _, book := range books {
+			ids = append(ids, book.Id)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"dune", "persuasion"}, ids(repo.ListByStatus(ctx, models.ReadStatusToRead)))
+	assert.Equal(t, []string{"emma", "persuasion"}, ids(repo.ListByAuthor(ctx, "Jane Austen")))
+	assert.Empty(t, ids(repo.ListByStatus(ctx, models.ReadStatusReading)))
+
+	// Updates move the index entries of the book.
+	_, err := repo.Update(ctx, models.Book{Id: "dune", Title: "Dune", Author: "Jane Austen", Status: models.ReadStatusReading})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"persuasion"}, ids(repo.ListByStatus(ctx, models.ReadStatusToRead)))
+	assert.Equal(t, []string{"dune"}, ids(repo.ListByStatus(ctx, models.ReadStatusReading)))
+	assert.Empty(t, ids(repo.ListByAuthor(ctx, "Frank Herbert")))
+	assert.Equal(t, []string{"dune", "emma", "persuasion"}, ids(repo.ListByAuthor(ctx, "jane austen")))
+
+	// The books in the trash are not listed, and purged books leave the indexes.
+	_, err = repo.DeleteById(ctx, "emma")
+	require.NoError(t, err)
+	assert.Empty(t, ids(repo.ListByStatus(ctx, models.ReadStatusRead)))
+	_, err = repo.PurgeById(ctx, "emma")
+	require.NoError(t, err)
+	_, err = repo.Add(ctx, models.Book{Id: "emma", Title: "Emma", Author: "Jane Austen", Status: models.ReadStatusRead})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"emma"}, ids(repo.ListByStatus(ctx, models.ReadStatusRead)))
+}
+
+func TestBoltBookRepositoryTransaction(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newBoltBookRepository(t, []models.Book{{Id: "1", Title: "Test Book", Status: models.ReadStatusToRead}})
+
+	t.Run("Commit", func(t *testing.T) {
+		err := repo.RunInTransaction(ctx, func(tx models.BookRepository) error {
+			if _, err := tx.Update(ctx, models.Book{Id: "1", Title: "Test Book", Status: models.ReadStatusRead}); err != nil {
+				return err
+			}
+			_, err := tx.Add(ctx, models.Book{Id: "2", Title: "New Book"})
+			return err
+		})
+		assert.NoError(t, err)
+		books, err := repo.List(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, books, 2)
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		errRollback := errors.New("rollback")
+		err := repo.RunInTransaction(ctx, func(tx models.BookRepository) error {
+			if _, err := tx.Update(ctx, models.Book{Id: "1", Title: "Test Book", Status: models.ReadStatusReading}); err != nil {
+				return err
+			}
+			if _, err := tx.DeleteById(ctx, "2"); err != nil {
+				return err
+			}
+			return errRollback
+		})
+		assert.ErrorIs(t, err, errRollback)
+		book, err := repo.GetById(ctx, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, models.ReadStatusRead, book.Status)
+		// The index entries are rolled back with the books.
+		books, err := repo.ListByStatus(ctx, models.ReadStatusReading)
+		assert.NoError(t, err)
+		assert.Empty(t, books)
+		_, err = repo.GetById(ctx, "2")
+		assert.NoError(t, err)
+	})
+}
+
+func TestBoltBookRepositoryBackup(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newBoltBookRepository(t, []models.Book{{Id: "dune", Title: "Dune", Author: "Frank Herbert", Status: models.ReadStatusRead}})
+	var backup bytes.Buffer
+	n, err := repo.Backup(ctx, &backup)
+	require.NoError(t, err)
+	assert.Equal(t, int64(backup.Len()), n)
+	backupPath := filepath.Join(t.TempDir(), "backup.db")
+	require.NoError(t, os.WriteFile(backupPath, backup.Bytes(), 0o600))
+
+	t.Run("Restore", func(t *testing.T) {
+		dir := t.TempDir()
+		restored, err := RestoreBoltBackup(dir, backupPath)
+		require.NoError(t, err)
+		assert.True(t, restored)
+		repo, err := NewBoltBookRepository(dir, []models.Book{{Id: "emma", Title: "Emma"}})
+		require.NoError(t, err)
+		defer repo.Close()
+		books, err := repo.List(ctx)
+		assert.NoError(t, err)
+		require.Len(t, books, 1)
+		assert.Equal(t, "dune", books[0].Id)
+		books, err = repo.ListByStatus(ctx, models.ReadStatusRead)
+		assert.NoError(t, err)
+		assert.Len(t, books, 1)
+	})
+
+	t.Run("ExistingStore", func(t *testing.T) {
+		_, dir := newBoltBookRepository(t, nil)
+		restored, err := RestoreBoltBackup(dir, backupPath)
+		assert.NoError(t, err)
+		assert.False(t, restored)
+	})
+
+	t.Run("SlowReader", func(t *testing.T) {
+		// The backup is written once the read transaction is over, and its
+		// temporary copy is removed.
+		repo, dir := newBoltBookRepository(t, nil)
+		openTxs := -1
+		_, err := repo.Backup(ctx, writerFunc(func(p []byte) (int, error) {
+			openTxs = repo.db.Stats().OpenTxN
+			return len(p), nil
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, 0, openTxs)
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, BoltFileName, entries[0].Name())
+	})
+
+	t.Run("InvalidBackup", func(t *testing.T) {
+		invalidPath := filepath.Join(t.TempDir(), "invalid.db")
+		require.NoError(t, os.WriteFile(invalidPath, []byte("not a store"), 0o600))
+		dir := t.TempDir()
+		_, err := RestoreBoltBackup(dir, invalidPath)
+		assert.ErrorIs(t, err, ErrInvalidBackup)
+		assert.NoFileExists(t, filepath.Join(dir, BoltFileName))
+
+		_, err = RestoreBoltBackup(dir, filepath.Join(dir, "missing.db"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestBoltBookRepositoryContext(t *testing.T) {
+	repo, _ := newBoltBookRepository(t, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := repo.Add(ctx, models.Book{Title: "Canceled Book"})
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = repo.List(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
@@ -6,81 +6,542 @@ package repositories
 import (
 	"context"
 	"fmt"
+	"hash/maphash"
+	"slices"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
 )
 
+// bookShards is the number of shards of a bookRepository, a power of two so
+// that the shard of a book is picked by masking the hash of its id.
+const bookShards = 32
+
+// bookRepository keeps the books in memory. The books are spread over shards
+// by their ids, each with its own lock, so that the changes to different
+// books do not wait for each other. The books not in the trash are also kept
+// sorted in an index, so that they are listed in a stable order without
+// reading the shards. The index is only locked to add or remove a book, or
+// to move one that is updated with another time of creation. The locks are
+// taken shard first, then the index, and the operations that span the
+// shards take all of them in order.
 type bookRepository struct {
-	store map[string]models.Book
+	seed   maphash.Seed
+	shards [bookShards]bookShard
+	index  sortedBooks
+	// journal, when set, records every mutation before it is applied to the store.
+	journal journal
+	// journalLock serializes the appends to the journal, which come from all
+	// the shards.
+	journalLock sync.Mutex
+	// tenants are the books of the other tenants, each in a repository of
+	// its own.
+	tenants     map[string]*bookRepository
+	tenantsLock sync.Mutex
+}
+
+type bookShard struct {
 	lock  sync.RWMutex
+	books map[string]*bookEntry
+}
+
+// bookEntry holds the current version of a book, which is replaced under
+// the lock of the shard of the book and read without it, from the index.
+type bookEntry struct {
+	book atomic.Pointer[models.Book]
+}
+
+func newBookEntry(book models.Book) *bookEntry {
+	entry := &bookEntry{}
+	entry.book.Store(&book)
+	return entry
+}
+
+// sortedBooks are the entries of the books in the order of
+// models.Book.AddedBefore.
+type sortedBooks struct {
+	lock    sync.RWMutex
+	entries []*bookEntry
 }
 
 func NewBookRepository(initialData []models.Book) models.BookRepository {
-	m := make(map[string]models.Book, 0)
-	if len(initialData) > 0 {
-		for _, book := range initialData {
-			m[book.Id] = book
-		}
+	m := make(map[string]models.Book, len(initialData))
+	for _, book := range initialData {
+		m[book.Id] = book
+	}
+	return newBookRepository(m)
+}
+
+// newBookRepository returns a repository holding the books of the store,
+// which are keyed by their ids.
+func newBookRepository(store map[string]models.Book) *bookRepository {
+	r := &bookRepository{seed: maphash.MakeSeed()}
+	for i := range r.shards {
+		r.shards[i].books = make(map[string]*bookEntry, len(store)/bookShards)
 	}
-	return &bookRepository{
-		store: m,
-		lock:  sync.RWMutex{},
+	for id, book := range store {
+		entry := newBookEntry(book)
+		r.shard(id).books[id] = entry
+		if !book.IsDeleted() {
+			r.index.entries = append(r.index.entries, entry)
+		}
 	}
+	r.index.sort()
+	return r
+}
+
+func (r *bookRepository) shard(id string) *bookShard {
+	return &r.shards[maphash.String(r.seed, id)&(bookShards-1)]
 }
 
 func (r *bookRepository) Add(ctx context.Context, book models.Book) (models.Book, error) {
-	r.lock.Lock()
-	defer r.lock.Unlock()
+	if err := ctx.Err(); err != nil {
+		return models.Book{}, fmt.Errorf("bookRepository:Add: %w", err)
+	}
 	if book.Id == "" {
 		book.Id = uuid.NewString()
 	}
-	if _, ok := r.store[book.Id]; ok {
+	shard := r.shard(book.Id)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+	if _, ok := shard.books[book.Id]; ok {
 		return models.Book{}, fmt.Errorf("bookRepository:Add: %w", ErrRecordAlreadyExists)
 	}
-	r.store[book.Id] = book
-	return r.store[book.Id], nil
+	book.DeletedAt = nil
+	if err := r.record(putEntry(book)); err != nil {
+		return models.Book{}, fmt.Errorf("bookRepository:Add: %w", err)
+	}
+	entry := newBookEntry(book)
+	shard.books[book.Id] = entry
+	r.index.insert(entry)
+	return book, nil
 }
 
 func (r *bookRepository) Update(ctx context.Context, updatedBook models.Book) (models.Book, error) {
-	r.lock.Lock()
-	defer r.lock.Unlock()
-	if _, ok := r.store[updatedBook.Id]; !ok {
+	if err := ctx.Err(); err != nil {
+		return models.Book{}, fmt.Errorf("bookRepository:Update: %w", err)
+	}
+	shard := r.shard(updatedBook.Id)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+	entry, ok := shard.books[updatedBook.Id]
+	if !ok || entry.book.Load().IsDeleted() {
 		return models.Book{}, fmt.Errorf("bookRepository:Update: %w", ErrRecordNotFound)
 	}
-	r.store[updatedBook.Id] = updatedBook
-	return r.store[updatedBook.Id], nil
+	book, err := r.replace(entry, updatedBook)
+	if err != nil {
+		return models.Book{}, fmt.Errorf("bookRepository:Update: %w", err)
+	}
+	return book, nil
+}
+
+// UpdateById updates the book with the lock of its shard held while fn runs.
+func (r *bookRepository) UpdateById(ctx context.Context, id string, fn func(current models.Book) (models.Book, error)) (models.Book, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Book{}, fmt.Errorf("bookRepository:UpdateById: %w", err)
+	}
+	shard := r.shard(id)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+	entry, ok := shard.books[id]
+	if !ok || entry.book.Load().IsDeleted() {
+		return models.Book{}, fmt.Errorf("bookRepository:UpdateById: %w", ErrRecordNotFound)
+	}
+	updatedBook, err := fn(*entry.book.Load())
+	if err != nil {
+		return models.Book{}, fmt.Errorf("bookRepository:UpdateById: %w", err)
+	}
+	book, err := r.replace(entry, updatedBook)
+	if err != nil {
+		return models.Book{}, fmt.Errorf("bookRepository:UpdateById: %w", err)
+	}
+	return book, nil
+}
+
+// replace replaces the book of the entry, which is only done under the lock
+// of its shard.
+func (r *bookRepository) replace(entry *bookEntry, updatedBook models.Book) (models.Book, error) {
+	book := entry.book.Load()
+	// The book keeps its own id, as updatedBook.Id may point into a request
+	// buffer that is reused.
+	updatedBook.Id = book.Id
+	updatedBook.DeletedAt = nil
+	if err := r.record(putEntry(updatedBook)); err != nil {
+		return models.Book{}, err
+	}
+	if book.AddedBefore(updatedBook) || updatedBook.AddedBefore(*book) {
+		r.index.move(entry, updatedBook)
+	} else {
+		entry.book.Store(&updatedBook)
+	}
+	return updatedBook, nil
 }
 
 func (r *bookRepository) List(ctx context.Context) ([]models.Book, error) {
-	r.lock.RLock()
-	defer r.lock.RUnlock()
-	var books []models.Book
-	for _, book := range r.store {
-		books = append(books, book)
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("bookRepository:List: %w", err)
 	}
-	return books, nil
+	r.index.lock.RLock()
+	defer r.index.lock.RUnlock()
+	return r.index.copy(0, len(r.index.entries)), nil
+}
+
+// ListPage returns a page of the books not in the trash, in the order they
+// were added, which is read from the index without reading all the books.
+func (r *bookRepository) ListPage(ctx context.Context, offset, limit int) ([]models.Book, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, fmt.Errorf("bookRepository:ListPage: %w", err)
+	}
+	r.index.lock.RLock()
+	defer r.index.lock.RUnlock()
+	total := len(r.index.entries)
+	start := min(max(offset, 0), total)
+	return r.index.copy(start, min(start+max(limit, 0), total)), total, nil
 }
 
 func (r *bookRepository) GetById(ctx context.Context, id string) (models.Book, error) {
-	r.lock.RLock()
-	defer r.lock.RUnlock()
-	if _, ok := r.store[id]; !ok {
+	if err := ctx.Err(); err != nil {
+		return models.Book{}, fmt.Errorf("bookRepository:GetById: %w", err)
+	}
+	shard := r.shard(id)
+	shard.lock.RLock()
+	entry, ok := shard.books[id]
+	shard.lock.RUnlock()
+	if !ok {
 		return models.Book{}, fmt.Errorf("bookRepository:GetById: %w", ErrRecordNotFound)
 	}
-	return r.store[id], nil
+	book := entry.book.Load()
+	if book.IsDeleted() {
+		return models.Book{}, fmt.Errorf("bookRepository:GetById: %w", ErrRecordNotFound)
+	}
+	return *book, nil
 }
 
 func (r *bookRepository) DeleteById(ctx context.Context, id string) (models.Book, error) {
-	r.lock.RLock()
-	defer r.lock.RUnlock()
-
-	if _, ok := r.store[id]; !ok {
+	if err := ctx.Err(); err != nil {
+		return models.Book{}, fmt.Errorf("bookRepository:DeleteById: %w", err)
+	}
+	shard := r.shard(id)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+	entry, ok := shard.books[id]
+	if !ok || entry.book.Load().IsDeleted() {
 		return models.Book{}, fmt.Errorf("bookRepository:DeleteById: %w", ErrRecordNotFound)
 	}
-	book := r.store[id]
-	delete(r.store, id)
+	book := *entry.book.Load()
+	deletedAt := time.Now().UTC()
+	book.DeletedAt = &deletedAt
+	if err := r.record(putEntry(book)); err != nil {
+		return models.Book{}, fmt.Errorf("bookRepository:DeleteById: %w", err)
+	}
+	r.index.remove(entry)
+	entry.book.Store(&book)
+	return book, nil
+}
+
+func (r *bookRepository) ListDeleted(ctx context.Context) ([]models.Book, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("bookRepository:ListDeleted: %w", err)
+	}
+	var books []models.Book
+	for i := range r.shards {
+		shard := &r.shards[i]
+		shard.lock.RLock()
+		for _, entry := range shard.books {
+			if book := entry.book.Load(); book.IsDeleted() {
+				books = append(books, *book)
+			}
+		}
+		shard.lock.RUnlock()
+	}
+	sort.Slice(books, func(i, j int) bool {
+		return books[i].AddedBefore(books[j])
+	})
+	return books, nil
+}
+
+func (r *bookRepository) RestoreById(ctx context.Context, id string) (models.Book, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Book{}, fmt.Errorf("bookRepository:RestoreById: %w", err)
+	}
+	shard := r.shard(id)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+	entry, ok := shard.books[id]
+	if !ok || !entry.book.Load().IsDeleted() {
+		return models.Book{}, fmt.Errorf("bookRepository:RestoreById: %w", ErrRecordNotFound)
+	}
+	book := *entry.book.Load()
+	book.DeletedAt = nil
+	if err := r.record(putEntry(book)); err != nil {
+		return models.Book{}, fmt.Errorf("bookRepository:RestoreById: %w", err)
+	}
+	entry.book.Store(&book)
+	r.index.insert(entry)
 	return book, nil
 }
+
+func (r *bookRepository) PurgeById(ctx context.Context, id string) (models.Book, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Book{}, fmt.Errorf("bookRepository:PurgeById: %w", err)
+	}
+	shard := r.shard(id)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+	entry, ok := shard.books[id]
+	if !ok {
+		return models.Book{}, fmt.Errorf("bookRepository:PurgeById: %w", ErrRecordNotFound)
+	}
+	if err := r.record(purgeEntry(id)); err != nil {
+		return models.Book{}, fmt.Errorf("bookRepository:PurgeById: %w", err)
+	}
+	delete(shard.books, id)
+	book := *entry.book.Load()
+	if !book.IsDeleted() {
+		r.index.remove(entry)
+	}
+	return book, nil
+}
+
+// PurgeDeletedBefore purges the books of a shard at a time, so that the
+// other shards are not locked meanwhile. The books in the trash are not in
+// the index.
+func (r *bookRepository) PurgeDeletedBefore(ctx context.Context, before time.Time) ([]models.Book, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("bookRepository:PurgeDeletedBefore: %w", err)
+	}
+	var purged []models.Book
+	for i := range r.shards {
+		books, err := r.purgeDeletedBefore(&r.shards[i], before)
+		purged = append(purged, books...)
+		if err != nil {
+			return purged, fmt.Errorf("bookRepository:PurgeDeletedBefore: %w", err)
+		}
+	}
+	return purged, nil
+}
+
+func (r *bookRepository) purgeDeletedBefore(shard *bookShard, before time.Time) ([]models.Book, error) {
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+	var entries []journalEntry
+	var books []models.Book
+	for id, entry := range shard.books {
+		if book := entry.book.Load(); book.IsDeleted() && book.DeletedAt.Before(before) {
+			entries = append(entries, purgeEntry(id))
+			books = append(books, *book)
+		}
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	if err := r.record(entries...); err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		delete(shard.books, entry.Id)
+	}
+	return books, nil
+}
+
+// OpenTenant returns the books of the tenant, which are kept in memory
+// with the ones of the repository.
+func (r *bookRepository) OpenTenant(ctx context.Context, tenant string, initialData []models.Book) (models.BookRepository, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("bookRepository:OpenTenant: %w", err)
+	}
+	r.tenantsLock.Lock()
+	defer r.tenantsLock.Unlock()
+	books, ok := r.tenants[tenant]
+	if !ok {
+		if r.tenants == nil {
+			r.tenants = make(map[string]*bookRepository)
+		}
+		books = NewBookRepository(initialData).(*bookRepository)
+		r.tenants[tenant] = books
+	}
+	return books, nil
+}
+
+func (r *bookRepository) ListTenantIds(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("bookRepository:ListTenantIds: %w", err)
+	}
+	r.tenantsLock.Lock()
+	defer r.tenantsLock.Unlock()
+	ids := make([]string, 0, len(r.tenants))
+	for id := range r.tenants {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (r *bookRepository) DropTenant(ctx context.Context, tenant string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("bookRepository:DropTenant: %w", err)
+	}
+	r.tenantsLock.Lock()
+	defer r.tenantsLock.Unlock()
+	delete(r.tenants, tenant)
+	return nil
+}
+
+// record writes the entries to the journal, if any. Callers must hold the
+// write lock of the shards of the entries.
+func (r *bookRepository) record(entries ...journalEntry) error {
+	if r.journal == nil {
+		return nil
+	}
+	r.journalLock.Lock()
+	defer r.journalLock.Unlock()
+	return r.journal.append(entries...)
+}
+
+// lock takes the write locks of all the shards and of the index, which
+// stops all the other operations.
+func (r *bookRepository) lock() {
+	for i := range r.shards {
+		r.shards[i].lock.Lock()
+	}
+	r.index.lock.Lock()
+}
+
+func (r *bookRepository) unlock() {
+	r.index.lock.Unlock()
+	for i := range r.shards {
+		r.shards[i].lock.Unlock()
+	}
+}
+
+// RunInTransaction runs fn against a copy of the store and swaps the copy in
+// only if fn succeeds. All the locks are held for the whole transaction, so
+// concurrent requests never observe a partially applied transaction.
+func (r *bookRepository) RunInTransaction(ctx context.Context, fn func(tx models.BookRepository) error) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("bookRepository:RunInTransaction: %w", err)
+	}
+	r.lock()
+	defer r.unlock()
+	buffer := &journalBuffer{}
+	// The copy has the seed of the repository, so that its shards hold the
+	// same books and can be swapped in.
+	tx := &bookRepository{seed: r.seed, journal: buffer}
+	for i := range r.shards {
+		tx.shards[i].books = make(map[string]*bookEntry, len(r.shards[i].books))
+		for id, entry := range r.shards[i].books {
+			tx.shards[i].books[id] = newBookEntry(*entry.book.Load())
+		}
+	}
+	tx.index.entries = make([]*bookEntry, len(r.index.entries))
+	for i, entry := range r.index.entries {
+		id := entry.book.Load().Id
+		tx.index.entries[i] = tx.shard(id).books[id]
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if len(buffer.entries) > 0 {
+		if err := r.record(buffer.entries...); err != nil {
+			return fmt.Errorf("bookRepository:RunInTransaction: %w", err)
+		}
+	}
+	for i := range r.shards {
+		r.shards[i].books = tx.shards[i].books
+	}
+	r.index.entries = tx.index.entries
+	return nil
+}
+
+// books returns all the books, including the ones in the trash. Callers
+// must hold the locks of all the shards.
+func (r *bookRepository) books() []models.Book {
+	var books []models.Book
+	for i := range r.shards {
+		for _, entry := range r.shards[i].books {
+			books = append(books, *entry.book.Load())
+		}
+	}
+	return books
+}
+
+func (s *sortedBooks) sort() {
+	sort.Slice(s.entries, func(i, j int) bool {
+		return s.entries[i].book.Load().AddedBefore(*s.entries[j].book.Load())
+	})
+}
+
+// search returns the position of the book in the index, or the one it
+// would be inserted at, and whether it is in the index. Callers must hold
+// the lock.
+func (s *sortedBooks) search(book *models.Book) (int, bool) {
+	return slices.BinarySearchFunc(s.entries, book, func(entry *bookEntry, book *models.Book) int {
+		switch other := entry.book.Load(); {
+		case other.AddedBefore(*book):
+			return -1
+		case book.AddedBefore(*other):
+			return 1
+		}
+		return 0
+	})
+}
+
+func (s *sortedBooks) insert(entry *bookEntry) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	i, _ := s.search(entry.book.Load())
+	s.entries = slices.Insert(s.entries, i, entry)
+}
+
+func (s *sortedBooks) remove(entry *bookEntry) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if i, found := s.search(entry.book.Load()); found {
+		s.entries = slices.Delete(s.entries, i, i+1)
+	}
+}
+
+// move replaces the book of the entry with one that was added at another
+// time, and moves the entry to its new position.
+func (s *sortedBooks) move(entry *bookEntry, book models.Book) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if i, found := s.search(entry.book.Load()); found {
+		s.entries = slices.Delete(s.entries, i, i+1)
+	}
+	entry.book.Store(&book)
+	i, _ := s.search(&book)
+	s.entries = slices.Insert(s.entries, i, entry)
+}
+
+// copy copies the books from start to end. Callers must hold the lock.
+func (s *sortedBooks) copy(start, end int) []models.Book {
+	if start >= end {
+		return nil
+	}
+	books := make([]models.Book, end-start)
+	for i, entry := range s.entries[start:end] {
+		books[i] = *entry.book.Load()
+	}
+	return books
+}
+
+// updateById updates the book of the repository with the details fn
+// returns from its current ones. It is atomic when the repository is that
+// of a transaction.
+func updateById(ctx context.Context, repo models.BookRepository, id string, fn func(current models.Book) (models.Book, error)) (models.Book, error) {
+	current, err := repo.GetById(ctx, id)
+	if err != nil {
+		return models.Book{}, err
+	}
+	updatedBook, err := fn(current)
+	if err != nil {
+		return models.Book{}, err
+	}
+	updatedBook.Id = current.Id
+	return repo.Update(ctx, updatedBook)
+}
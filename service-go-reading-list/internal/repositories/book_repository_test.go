@@ -5,69 +5,47 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
 )
 
-func TestBookRepository(t *testing.T) {
-	// Create a new repository for testing with an initial book.
-	initialBook := models.Book{Id: "1", Title: "Test Book", Author: "Test Author"}
-	updatedBook := models.Book{Id: initialBook.Id, Title: "Updated Book", Author: "Updated Author"}
-	repo := NewBookRepository([]models.Book{initialBook})
-
-	t.Run("Add", func(t *testing.T) {
-		// Test adding a new book.
-		newBook := models.Book{Title: "New Book", Author: "New Author"}
-		addedBook, err := repo.Add(context.Background(), newBook)
+func TestBookRepositoryTransaction(t *testing.T) {
+	ctx := context.Background()
+	repo := NewBookRepository([]models.Book{{Id: "1", Title: "Test Book", Status: models.ReadStatusToRead}})
+	transactor := repo.(models.BookTransactor)
+
+	t.Run("Commit", func(t *testing.T) {
+		err := transactor.RunInTransaction(ctx, func(tx models.BookRepository) error {
+			if _, err := tx.Update(ctx, models.Book{Id: "1", Title: "Test Book", Status: models.ReadStatusRead}); err != nil {
+				return err
+			}
+			_, err := tx.Add(ctx, models.Book{Id: "2", Title: "New Book"})
+			return err
+		})
 		assert.NoError(t, err)
-		assert.NotEmpty(t, addedBook.Id)
-
-		// Test adding a book with an existing ID.
-		duplicateBook := models.Book{Id: addedBook.Id, Title: "Duplicate Book", Author: "Duplicate Author"}
-		_, err = repo.Add(context.Background(), duplicateBook)
-		assert.Error(t, err)
-	})
-
-	t.Run("Update", func(t *testing.T) {
-		// Test updating an existing book.
-		updated, err := repo.Update(context.Background(), updatedBook)
+		books, err := repo.List(ctx)
 		assert.NoError(t, err)
-		assert.Equal(t, updatedBook, updated)
-
-		// Test updating a non-existing book.
-		nonExistingBook := models.Book{Id: "non-existing-id", Title: "Non-existing Book", Author: "Non-existing Author"}
-		_, err = repo.Update(context.Background(), nonExistingBook)
-		assert.Error(t, err)
+		assert.Len(t, books, 2)
 	})
 
-	t.Run("List", func(t *testing.T) {
-		// Test listing all books.
-		books, err := repo.List(context.Background())
+	t.Run("Rollback", func(t *testing.T) {
+		errRollback := errors.New("rollback")
+		err := transactor.RunInTransaction(ctx, func(tx models.BookRepository) error {
+			if _, err := tx.DeleteById(ctx, "1"); err != nil {
+				return err
+			}
+			// Changes are visible inside the transaction.
+			if _, err := tx.GetById(ctx, "1"); !errors.Is(err, ErrRecordNotFound) {
+				return err
+			}
+			return errRollback
+		})
+		assert.ErrorIs(t, err, errRollback)
+		book, err := repo.GetById(ctx, "1")
 		assert.NoError(t, err)
-		assert.Len(t, books, 2) // Includes the initial book and the one added.
-	})
-
-	t.Run("GetById", func(t *testing.T) {
-		// Test getting a book by ID.
-		book, err := repo.GetById(context.Background(), initialBook.Id)
-		assert.NoError(t, err)
-		assert.Equal(t, updatedBook, book)
-
-		// Test getting a non-existing book.
-		_, err = repo.GetById(context.Background(), "non-existing-id")
-		assert.Error(t, err)
-	})
-
-	t.Run("DeleteById", func(t *testing.T) {
-		// Test deleting a book by ID.
-		deletedBook, err := repo.DeleteById(context.Background(), initialBook.Id)
-		assert.NoError(t, err)
-		assert.Equal(t, updatedBook, deletedBook)
-
-		// Test deleting a non-existing book.
-		_, err = repo.DeleteById(context.Background(), "non-existing-id")
-		assert.Error(t, err)
+		assert.Equal(t, models.ReadStatusRead, book.Status)
 	})
 }
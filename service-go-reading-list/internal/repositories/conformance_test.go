@@ -0,0 +1,109 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/redis"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/redis/redistest"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories/repositorytest"
+)
+
+func TestBookRepositoryConformance(t *testing.T) {
+	t.Run("Memory", func(t *testing.T) {
+		repositorytest.TestBookRepository(t, func(t *testing.T, initialData []models.Book) models.BookRepository {
+			return repositories.NewBookRepository(initialData)
+		})
+	})
+
+	t.Run("WriteAheadLog", func(t *testing.T) {
+		repositorytest.TestBookRepository(t, func(t *testing.T, initialData []models.Book) models.BookRepository {
+			repo, err := repositories.NewPersistentBookRepository(t.TempDir(), initialData)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = repo.Close() })
+			return repo
+		})
+	})
+
+	t.Run("Bolt", func(t *testing.T) {
+		repositorytest.TestBookRepository(t, func(t *testing.T, initialData []models.Book) models.BookRepository {
+			repo, err := repositories.NewBoltBookRepository(t.TempDir(), initialData)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = repo.Close() })
+			return repo
+		})
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		repositorytest.TestBookRepository(t, func(t *testing.T, initialData []models.Book) models.BookRepository {
+			server, err := redistest.NewServer()
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = server.Close() })
+			repo, err := repositories.NewRedisBookRepository(redis.NewClient(redis.Options{Addr: server.Addr()}), "reading-list:", initialData)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = repo.Close() })
+			return repo
+		})
+	})
+
+	t.Run("Tenant", func(t *testing.T) {
+		// The books of the default tenant, which are the ones of a
+		// single-tenant deployment.
+		repositorytest.TestBookRepository(t, func(t *testing.T, initialData []models.Book) models.BookRepository {
+			repo, err := repositories.NewTenantBookRepository(repositories.NewBookRepository(initialData), repositories.TenantBookRepositoryConfig{})
+			require.NoError(t, err)
+			return repo
+		})
+	})
+}
+
+func TestBookTenantStoreConformance(t *testing.T) {
+	stores := map[string]func(t *testing.T) models.BookRepository{
+		"Memory": func(t *testing.T) models.BookRepository {
+			return repositories.NewBookRepository(nil)
+		},
+		"WriteAheadLog": func(t *testing.T) models.BookRepository {
+			repo, err := repositories.NewPersistentBookRepository(t.TempDir(), nil)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = repo.Close() })
+			return repo
+		},
+		"Bolt": func(t *testing.T) models.BookRepository {
+			repo, err := repositories.NewBoltBookRepository(t.TempDir(), nil)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = repo.Close() })
+			return repo
+		},
+		"Redis": func(t *testing.T) models.BookRepository {
+			server, err := redistest.NewServer()
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = server.Close() })
+			repo, err := repositories.NewRedisBookRepository(redis.NewClient(redis.Options{Addr: server.Addr()}), "reading-list:", nil)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = repo.Close() })
+			return repo
+		},
+	}
+	for name, open := range stores {
+		// The books of a tenant other than the default one, which are stored
+		// apart from those of the default tenant.
+		t.Run(name, func(t *testing.T) {
+			repositorytest.TestBookRepository(t, func(t *testing.T, initialData []models.Book) models.BookRepository {
+				store := open(t)
+				books, err := store.(models.BookTenantStore).OpenTenant(context.Background(), "acme", initialData)
+				require.NoError(t, err)
+				list, err := store.List(context.Background())
+				require.NoError(t, err)
+				require.Empty(t, list)
+				return books
+			})
+		})
+	}
+}
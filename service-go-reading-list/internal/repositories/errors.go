@@ -9,3 +9,12 @@ import (
 
 var ErrRecordNotFound = errors.New("record not found")
 var ErrRecordAlreadyExists = errors.New("record already exists")
+var ErrCorruptedLog = errors.New("write-ahead log is corrupted")
+var ErrCorruptedAuditLog = errors.New("audit log is corrupted")
+var ErrQuotaExceeded = errors.New("quota exceeded")
+var ErrInvalidId = errors.New("invalid id")
+var ErrConflict = errors.New("record was changed concurrently")
+var ErrCorruptedRecord = errors.New("record is corrupted")
+var ErrInvalidBackup = errors.New("backup is not valid")
+var ErrNotSupported = errors.New("operation is not supported by the storage")
+var ErrLocked = errors.New("data directory is used by another process")
@@ -0,0 +1,76 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+)
+
+type goalRepository struct {
+	store map[int]models.Goal
+	lock  sync.RWMutex
+}
+
+func NewGoalRepository() models.GoalRepository {
+	return &goalRepository{
+		store: make(map[int]models.Goal),
+		lock:  sync.RWMutex{},
+	}
+}
+
+func (r *goalRepository) Put(ctx context.Context, goal models.Goal) (models.Goal, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Goal{}, fmt.Errorf("goalRepository:Put: %w", err)
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.store[goal.Year] = goal
+	return goal, nil
+}
+
+func (r *goalRepository) List(ctx context.Context) ([]models.Goal, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("goalRepository:List: %w", err)
+	}
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	goals := make([]models.Goal, 0, len(r.store))
+	for _, goal := range r.store {
+		goals = append(goals, goal)
+	}
+	sort.Slice(goals, func(i, j int) bool { return goals[i].Year < goals[j].Year })
+	return goals, nil
+}
+
+func (r *goalRepository) GetByYear(ctx context.Context, year int) (models.Goal, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Goal{}, fmt.Errorf("goalRepository:GetByYear: %w", err)
+	}
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	goal, ok := r.store[year]
+	if !ok {
+		return models.Goal{}, fmt.Errorf("goalRepository:GetByYear: %w", ErrRecordNotFound)
+	}
+	return goal, nil
+}
+
+func (r *goalRepository) DeleteByYear(ctx context.Context, year int) (models.Goal, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Goal{}, fmt.Errorf("goalRepository:DeleteByYear: %w", err)
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	goal, ok := r.store[year]
+	if !ok {
+		return models.Goal{}, fmt.Errorf("goalRepository:DeleteByYear: %w", ErrRecordNotFound)
+	}
+	delete(r.store, year)
+	return goal, nil
+}
@@ -0,0 +1,258 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+)
+
+type highlightRepository struct {
+	store map[string]models.Highlight
+	lock  sync.RWMutex
+	// path, when set, is the snapshot of the highlights, and wal the log of
+	// their changes since it was written.
+	path string
+	wal  *writeAheadLog[highlightEntry]
+}
+
+// highlightEntry is a single idempotent change of the highlights: either a
+// highlight after the change or its removal.
+type highlightEntry struct {
+	Op        string            `json:"op"`
+	Highlight *models.Highlight `json:"highlight,omitempty"`
+	Id        string            `json:"id,omitempty"`
+}
+
+func putHighlightEntry(highlight models.Highlight) highlightEntry {
+	return highlightEntry{Op: journalOpPut, Highlight: &highlight}
+}
+
+func purgeHighlightEntry(id string) highlightEntry {
+	return highlightEntry{Op: journalOpPurge, Id: id}
+}
+
+// apply replays the entry on the given store.
+func (e highlightEntry) apply(store map[string]models.Highlight) {
+	switch e.Op {
+	case journalOpPut:
+		if e.Highlight != nil {
+			store[e.Highlight.Id] = *e.Highlight
+		}
+	case journalOpPurge:
+		delete(store, e.Id)
+	}
+}
+
+func NewHighlightRepository() models.HighlightRepository {
+	return &highlightRepository{
+		store: make(map[string]models.Highlight),
+		lock:  sync.RWMutex{},
+	}
+}
+
+// newPersistentHighlightRepository returns the highlights of the JSON
+// snapshot at path, with the changes of the write-ahead log at walPath
+// replayed on top of it. Every change is appended to the log, which compact
+// folds into a new snapshot. The log is locked until close is called.
+func newPersistentHighlightRepository(path, walPath string) (*highlightRepository, error) {
+	// The log is opened first, as it locks the highlights.
+	wal, records, err := openWriteAheadLog[highlightEntry](walPath)
+	if err != nil {
+		return nil, err
+	}
+	store := make(map[string]models.Highlight)
+	contents, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		_ = wal.close()
+		return nil, err
+	}
+	if err == nil {
+		var highlights []models.Highlight
+		if err := json.Unmarshal(contents, &highlights); err != nil {
+			_ = wal.close()
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for _, highlight := range highlights {
+			store[highlight.Id] = highlight
+		}
+	}
+	for _, entries := range records {
+		for _, entry := range entries {
+			entry.apply(store)
+		}
+	}
+	return &highlightRepository{store: store, lock: sync.RWMutex{}, path: path, wal: wal}, nil
+}
+
+func (r *highlightRepository) Add(ctx context.Context, highlight models.Highlight) (models.Highlight, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Highlight{}, fmt.Errorf("highlightRepository:Add: %w", err)
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	highlight.Id = uuid.NewString()
+	if err := r.record(putHighlightEntry(highlight)); err != nil {
+		return models.Highlight{}, fmt.Errorf("highlightRepository:Add: %w", err)
+	}
+	return highlight, nil
+}
+
+func (r *highlightRepository) Update(ctx context.Context, highlight models.Highlight) (models.Highlight, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Highlight{}, fmt.Errorf("highlightRepository:Update: %w", err)
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.store[highlight.Id]; !ok {
+		return models.Highlight{}, fmt.Errorf("highlightRepository:Update: %w", ErrRecordNotFound)
+	}
+	if err := r.record(putHighlightEntry(highlight)); err != nil {
+		return models.Highlight{}, fmt.Errorf("highlightRepository:Update: %w", err)
+	}
+	return highlight, nil
+}
+
+func (r *highlightRepository) GetById(ctx context.Context, id string) (models.Highlight, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Highlight{}, fmt.Errorf("highlightRepository:GetById: %w", err)
+	}
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	highlight, ok := r.store[id]
+	if !ok {
+		return models.Highlight{}, fmt.Errorf("highlightRepository:GetById: %w", ErrRecordNotFound)
+	}
+	return highlight, nil
+}
+
+func (r *highlightRepository) DeleteById(ctx context.Context, id string) (models.Highlight, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Highlight{}, fmt.Errorf("highlightRepository:DeleteById: %w", err)
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	highlight, ok := r.store[id]
+	if !ok {
+		return models.Highlight{}, fmt.Errorf("highlightRepository:DeleteById: %w", ErrRecordNotFound)
+	}
+	if err := r.record(purgeHighlightEntry(id)); err != nil {
+		return models.Highlight{}, fmt.Errorf("highlightRepository:DeleteById: %w", err)
+	}
+	return highlight, nil
+}
+
+func (r *highlightRepository) List(ctx context.Context, filter models.HighlightFilter) ([]models.Highlight, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("highlightRepository:List: %w", err)
+	}
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	highlights := make([]models.Highlight, 0)
+	for _, highlight := range r.store {
+		if filter.Matches(highlight) {
+			highlights = append(highlights, highlight)
+		}
+	}
+	sortHighlights(highlights)
+	return highlights, nil
+}
+
+// sortHighlights orders the highlights by book, and in reading order.
+func sortHighlights(highlights []models.Highlight) {
+	sort.Slice(highlights, func(i, j int) bool {
+		if highlights[i].BookId != highlights[j].BookId {
+			return highlights[i].BookId < highlights[j].BookId
+		}
+		return highlights[i].ReadBefore(highlights[j])
+	})
+}
+
+func (r *highlightRepository) DeleteByBook(ctx context.Context, bookId string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, fmt.Errorf("highlightRepository:DeleteByBook: %w", err)
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	var entries []highlightEntry
+	for id, highlight := range r.store {
+		if highlight.BookId == bookId {
+			entries = append(entries, purgeHighlightEntry(id))
+		}
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+	if err := r.record(entries...); err != nil {
+		return 0, fmt.Errorf("highlightRepository:DeleteByBook: %w", err)
+	}
+	return len(entries), nil
+}
+
+// record appends the entries to the log, when the highlights are
+// persisted, before it applies them, so that a failed write leaves the
+// highlights unchanged. The caller holds the lock.
+func (r *highlightRepository) record(entries ...highlightEntry) error {
+	if r.wal != nil {
+		if err := r.wal.append(entries...); err != nil {
+			return err
+		}
+	}
+	for _, entry := range entries {
+		entry.apply(r.store)
+	}
+	return nil
+}
+
+// pendingRecords returns the number of records appended to the log since
+// the last snapshot.
+func (r *highlightRepository) pendingRecords() int {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.wal.records
+}
+
+// compact writes the highlights to a new snapshot and truncates the log.
+func (r *highlightRepository) compact() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	highlights := make([]models.Highlight, 0, len(r.store))
+	for _, highlight := range r.store {
+		highlights = append(highlights, highlight)
+	}
+	sort.Slice(highlights, func(i, j int) bool { return highlights[i].Id < highlights[j].Id })
+	data, err := json.Marshal(highlights)
+	if err != nil {
+		return fmt.Errorf("highlightRepository:Compact: %w", err)
+	}
+	if err := writeFile(r.path, data); err != nil {
+		return fmt.Errorf("highlightRepository:Compact: %w", err)
+	}
+	// Replaying the log on top of the new snapshot is harmless.
+	if err := r.wal.reset(); err != nil {
+		return fmt.Errorf("highlightRepository:Compact: %w", err)
+	}
+	return nil
+}
+
+// close compacts the log and releases it.
+func (r *highlightRepository) close() error {
+	return errors.Join(r.compact(), r.release())
+}
+
+// release releases the log, which fails the changes made after it.
+func (r *highlightRepository) release() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.wal.close()
+}
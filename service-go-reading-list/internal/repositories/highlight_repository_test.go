@@ -0,0 +1,216 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/redis"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/redis/redistest"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/tenancy"
+)
+
+func TestHighlightRepository(t *testing.T) {
+	t.Run("Memory", func(t *testing.T) {
+		testTenantHighlightRepository(t, NewTenantHighlightRepository())
+	})
+	t.Run("Redis", func(t *testing.T) {
+		server, err := redistest.NewServer()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = server.Close() })
+		repo := NewRedisTenantHighlightRepository(redis.NewClient(redis.Options{Addr: server.Addr()}), "reading-list:")
+		t.Cleanup(func() { _ = repo.Close() })
+		testTenantHighlightRepository(t, repo)
+		// The highlights of the purged tenant were removed.
+		assert.ElementsMatch(t, []string{"reading-list:highlights"}, server.Keys(0))
+	})
+}
+
+func testTenantHighlightRepository(t *testing.T, repo *TenantHighlightRepository) {
+	ctx := context.Background()
+	add := func(ctx context.Context, bookId, text string, page int) models.Highlight {
+		// The time is the one read back from JSON.
+		now := time.Now().UTC().Round(0)
+		highlight, err := repo.Add(ctx, models.Highlight{BookId: bookId, Text: text, Page: page, CreatedAt: &now})
+		require.NoError(t, err)
+		require.NotEmpty(t, highlight.Id)
+		return highlight
+	}
+	fear := add(ctx, "dune", "I must not fear.", 12)
+	spice := add(ctx, "dune", "The spice must flow.", 0)
+	sand := add(ctx, "dune", "The sand is the desert.", 3)
+	emma := add(ctx, "emma", "Emma Woodhouse, handsome, clever, and rich.", 1)
+	other := add(tenancy.WithTenant(ctx, "acme"), "dune", "I must not fear.", 12)
+
+	t.Run("List", func(t *testing.T) {
+		// The highlights are grouped by book in reading order, the ones
+		// without a page last.
+		highlights, err := repo.List(ctx, models.HighlightFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, []models.Highlight{sand, fear, spice, emma}, highlights)
+
+		highlights, err = repo.List(ctx, models.HighlightFilter{BookId: "dune", Query: "MUST"})
+		require.NoError(t, err)
+		assert.Equal(t, []models.Highlight{fear, spice}, highlights)
+		highlights, err = repo.List(ctx, models.HighlightFilter{BookId: "persuasion"})
+		require.NoError(t, err)
+		assert.Empty(t, highlights)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		fear.Note = "The litany against fear."
+		updated, err := repo.Update(ctx, fear)
+		require.NoError(t, err)
+		assert.Equal(t, fear, updated)
+		highlight, err := repo.GetById(ctx, fear.Id)
+		require.NoError(t, err)
+		assert.Equal(t, fear, highlight)
+		_, err = repo.Update(ctx, models.Highlight{Id: "missing", BookId: "dune", Text: "Missing"})
+		assert.ErrorIs(t, err, ErrRecordNotFound)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		deleted, err := repo.DeleteById(ctx, spice.Id)
+		require.NoError(t, err)
+		assert.Equal(t, spice, deleted)
+		_, err = repo.GetById(ctx, spice.Id)
+		assert.ErrorIs(t, err, ErrRecordNotFound)
+		_, err = repo.DeleteById(ctx, spice.Id)
+		assert.ErrorIs(t, err, ErrRecordNotFound)
+
+		count, err := repo.DeleteByBook(ctx, "dune")
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		highlights, err := repo.List(ctx, models.HighlightFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, []models.Highlight{emma}, highlights)
+	})
+
+	t.Run("Tenants", func(t *testing.T) {
+		acme := tenancy.WithTenant(ctx, "acme")
+		_, err := repo.GetById(ctx, other.Id)
+		assert.ErrorIs(t, err, ErrRecordNotFound)
+		highlight, err := repo.GetById(acme, other.Id)
+		require.NoError(t, err)
+		assert.Equal(t, other, highlight)
+
+		require.NoError(t, repo.PurgeTenant(ctx, "acme"))
+		_, err = repo.GetById(acme, other.Id)
+		assert.ErrorIs(t, err, ErrRecordNotFound)
+		_, err = repo.GetById(ctx, emma.Id)
+		assert.NoError(t, err)
+	})
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err := repo.Add(cancelled, models.Highlight{BookId: "dune", Text: "I must not fear."})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestPersistentHighlightRepository(t *testing.T) {
+	ctx := context.Background()
+	acme := tenancy.WithTenant(ctx, "acme")
+	dir := t.TempDir()
+	repo, err := NewPersistentTenantHighlightRepository(dir)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = repo.Close() })
+	fear, err := repo.Add(ctx, models.Highlight{BookId: "dune", Text: "I must not fear.", Page: 12})
+	require.NoError(t, err)
+	spice, err := repo.Add(ctx, models.Highlight{BookId: "dune", Text: "The spice must flow."})
+	require.NoError(t, err)
+	_, err = repo.Add(ctx, models.Highlight{BookId: "emma", Text: "Emma Woodhouse, handsome, clever, and rich."})
+	require.NoError(t, err)
+	other, err := repo.Add(acme, models.Highlight{BookId: "dune", Text: "I must not fear."})
+	require.NoError(t, err)
+	fear.Note = "The litany against fear."
+	_, err = repo.Update(ctx, fear)
+	require.NoError(t, err)
+	_, err = repo.DeleteById(ctx, spice.Id)
+	require.NoError(t, err)
+	_, err = repo.DeleteByBook(ctx, "emma")
+	require.NoError(t, err)
+
+	// The highlights of every tenant are replayed from their logs as they
+	// were left.
+	reopen := func() {
+		t.Helper()
+		for _, highlights := range repo.persisted() {
+			require.NoError(t, highlights.release())
+		}
+		repo, err = NewPersistentTenantHighlightRepository(dir)
+		require.NoError(t, err)
+	}
+	reopen()
+	highlights, err := repo.List(ctx, models.HighlightFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []models.Highlight{fear}, highlights)
+	highlights, err = repo.List(acme, models.HighlightFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []models.Highlight{other}, highlights)
+
+	t.Run("Compact", func(t *testing.T) {
+		// The logs are folded into the snapshots on Close.
+		require.NoError(t, repo.Close())
+		info, err := os.Stat(filepath.Join(dir, "default.wal"))
+		require.NoError(t, err)
+		assert.Zero(t, info.Size())
+		assert.FileExists(t, filepath.Join(dir, "default.json"))
+		repo, err = NewPersistentTenantHighlightRepository(dir)
+		require.NoError(t, err)
+		highlights, err := repo.List(ctx, models.HighlightFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, []models.Highlight{fear}, highlights)
+		highlights, err = repo.List(acme, models.HighlightFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, []models.Highlight{other}, highlights)
+	})
+
+	t.Run("Locked", func(t *testing.T) {
+		locked, err := NewPersistentTenantHighlightRepository(dir)
+		require.NoError(t, err)
+		_, err = locked.List(ctx, models.HighlightFilter{})
+		assert.ErrorIs(t, err, ErrLocked)
+	})
+
+	t.Run("PurgeTenant", func(t *testing.T) {
+		require.NoError(t, repo.PurgeTenant(ctx, "acme"))
+		assert.NoFileExists(t, filepath.Join(dir, "acme.json"))
+		assert.NoFileExists(t, filepath.Join(dir, "acme.wal"))
+		reopen()
+		highlights, err := repo.List(acme, models.HighlightFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, highlights)
+	})
+
+	t.Run("FailedWrite", func(t *testing.T) {
+		// The highlights are unchanged when they cannot be written.
+		_, err := repo.GetById(ctx, fear.Id)
+		require.NoError(t, err)
+		require.NoError(t, repo.persisted()[tenancy.DefaultTenant].release())
+		_, err = repo.Add(ctx, models.Highlight{BookId: "dune", Text: "The spice must flow."})
+		assert.Error(t, err)
+		_, err = repo.DeleteById(ctx, fear.Id)
+		assert.Error(t, err)
+		highlights, err := repo.List(ctx, models.HighlightFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, []models.Highlight{fear}, highlights)
+	})
+
+	t.Run("CorruptedFile", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "default.json"), []byte("[{"), 0o600))
+		repo, err := NewPersistentTenantHighlightRepository(dir)
+		require.NoError(t, err)
+		_, err = repo.List(ctx, models.HighlightFilter{})
+		assert.ErrorContains(t, err, "failed to read the highlights of the tenant [default]")
+	})
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories
+
+import (
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+)
+
+const (
+	journalOpPut   = "put"
+	journalOpPurge = "purge"
+)
+
+// journal records the mutations of a bookRepository before they are applied.
+// The entries passed to a single append call must be persisted atomically.
+type journal interface {
+	append(entries ...journalEntry) error
+}
+
+// journalEntry is a single idempotent mutation: either the full state of a
+// book after the change or the permanent removal of a book.
+type journalEntry struct {
+	Op   string       `json:"op"`
+	Book *models.Book `json:"book,omitempty"`
+	Id   string       `json:"id,omitempty"`
+}
+
+func putEntry(book models.Book) journalEntry {
+	return journalEntry{Op: journalOpPut, Book: &book}
+}
+
+func purgeEntry(id string) journalEntry {
+	return journalEntry{Op: journalOpPurge, Id: id}
+}
+
+// apply replays the entry on the given store.
+func (e journalEntry) apply(store map[string]models.Book) {
+	switch e.Op {
+	case journalOpPut:
+		if e.Book != nil {
+			store[e.Book.Id] = *e.Book
+		}
+	case journalOpPurge:
+		delete(store, e.Id)
+	}
+}
+
+// journalBuffer collects the entries of a transaction until it is committed.
+type journalBuffer struct {
+	entries []journalEntry
+}
+
+func (b *journalBuffer) append(entries ...journalEntry) error {
+	b.entries = append(b.entries, entries...)
+	return nil
+}
@@ -0,0 +1,14 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !unix
+
+package repositories
+
+import "os"
+
+// lockFile does not lock the file on the platforms without flock, where the
+// data directory must not be opened by two processes at once.
+func lockFile(*os.File) error {
+	return nil
+}
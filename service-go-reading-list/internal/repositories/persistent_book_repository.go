@@ -0,0 +1,278 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+)
+
+const (
+	snapshotFileName = "books.snapshot.json"
+	walFileName      = "books.wal"
+)
+
+// TenantsDirName is the directory of the data directory holding the books of
+// the other tenants, a data directory of its own per tenant.
+const TenantsDirName = "tenants"
+
+// PersistentBookRepository is the in-memory book repository backed by a
+// snapshot and a write-ahead log in a data directory. Every mutation is
+// appended to the log before it is applied, and Compact periodically folds
+// the log into a new snapshot.
+type PersistentBookRepository struct {
+	*bookRepository
+	dir string
+	wal *writeAheadLog[journalEntry]
+	// tenantRepos are the books of the other tenants that were opened,
+	// guarded by tenantsLock.
+	tenantRepos map[string]*PersistentBookRepository
+}
+
+type snapshot struct {
+	Books []models.Book `json:"books"`
+}
+
+// NewPersistentBookRepository restores the repository from the snapshot and
+// write-ahead log in dir. The initial data is only used when dir does not
+// contain any previous state. It fails with ErrLocked when another process
+// has the repository of dir open.
+func NewPersistentBookRepository(dir string, initialData []models.Book) (*PersistentBookRepository, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	// The log is opened first, as it locks dir.
+	wal, records, err := openWriteAheadLog[journalEntry](filepath.Join(dir, walFileName))
+	if err != nil {
+		return nil, err
+	}
+	store, found, err := readSnapshot(filepath.Join(dir, snapshotFileName))
+	if err != nil {
+		_ = wal.close()
+		return nil, err
+	}
+	if !found && len(records) == 0 {
+		for _, book := range initialData {
+			store[book.Id] = book
+		}
+	}
+	for _, entries := range records {
+		for _, entry := range entries {
+			entry.apply(store)
+		}
+	}
+	r := &PersistentBookRepository{
+		bookRepository: newBookRepository(store),
+		dir:            dir,
+		wal:            wal,
+	}
+	r.journal = wal
+	if !found {
+		if err := r.compact(); err != nil {
+			_ = wal.close()
+			return nil, err
+		}
+	}
+	logrus.WithFields(logrus.Fields{"dir": dir, "books": len(store), "replayedRecords": len(records)}).
+		Info("restored the book repository")
+	return r, nil
+}
+
+// Compact writes the current state to a new snapshot and truncates the
+// write-ahead log, and does the same for the tenants that were opened.
+func (r *PersistentBookRepository) Compact() error {
+	errs := []error{r.compact()}
+	for _, tenant := range r.openedTenants() {
+		errs = append(errs, tenant.compact())
+	}
+	return errors.Join(errs...)
+}
+
+func (r *PersistentBookRepository) compact() error {
+	r.lock()
+	defer r.unlock()
+	s := snapshot{Books: r.books()}
+	if s.Books == nil {
+		s.Books = make([]models.Book, 0)
+	}
+	if err := writeSnapshot(filepath.Join(r.dir, snapshotFileName), s); err != nil {
+		return fmt.Errorf("PersistentBookRepository:Compact: %w", err)
+	}
+	// Replaying the log on top of the new snapshot is harmless, so a crash
+	// before the log is truncated does not lose or duplicate anything.
+	if err := r.wal.reset(); err != nil {
+		return fmt.Errorf("PersistentBookRepository:Compact: %w", err)
+	}
+	return nil
+}
+
+// RunCompaction compacts the write-ahead log every interval until the context is cancelled.
+func (r *PersistentBookRepository) RunCompaction(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, repo := range append(r.openedTenants(), r) {
+				if repo.pendingRecords() == 0 {
+					continue
+				}
+				if err := repo.compact(); err != nil {
+					logrus.WithFields(logrus.Fields{"dir": repo.dir}).Errorf("failed to compact the write-ahead log: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// Close compacts the write-ahead logs and releases the underlying files,
+// including those of the tenants that were opened.
+func (r *PersistentBookRepository) Close() error {
+	var errs []error
+	for _, repo := range append(r.openedTenants(), r) {
+		errs = append(errs, repo.close())
+	}
+	return errors.Join(errs...)
+}
+
+func (r *PersistentBookRepository) close() error {
+	err := r.compact()
+	r.journalLock.Lock()
+	defer r.journalLock.Unlock()
+	return errors.Join(err, r.wal.close())
+}
+
+// OpenTenant returns the books of the tenant, which are persisted in a
+// directory of TenantsDirName.
+func (r *PersistentBookRepository) OpenTenant(ctx context.Context, tenant string, initialData []models.Book) (models.BookRepository, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("PersistentBookRepository:OpenTenant: %w", err)
+	}
+	r.tenantsLock.Lock()
+	defer r.tenantsLock.Unlock()
+	if repo, ok := r.tenantRepos[tenant]; ok {
+		return repo, nil
+	}
+	repo, err := NewPersistentBookRepository(r.tenantDir(tenant), initialData)
+	if err != nil {
+		return nil, fmt.Errorf("PersistentBookRepository:OpenTenant: %w", err)
+	}
+	if r.tenantRepos == nil {
+		r.tenantRepos = make(map[string]*PersistentBookRepository)
+	}
+	r.tenantRepos[tenant] = repo
+	return repo, nil
+}
+
+func (r *PersistentBookRepository) ListTenantIds(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("PersistentBookRepository:ListTenantIds: %w", err)
+	}
+	entries, err := os.ReadDir(filepath.Join(r.dir, TenantsDirName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("PersistentBookRepository:ListTenantIds: %w", err)
+	}
+	var ids []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if id, err := url.PathUnescape(entry.Name()); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// DropTenant closes the books of the tenant and removes their directory.
+func (r *PersistentBookRepository) DropTenant(ctx context.Context, tenant string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("PersistentBookRepository:DropTenant: %w", err)
+	}
+	r.tenantsLock.Lock()
+	defer r.tenantsLock.Unlock()
+	if repo, ok := r.tenantRepos[tenant]; ok {
+		repo.journalLock.Lock()
+		err := repo.wal.close()
+		repo.journalLock.Unlock()
+		if err != nil {
+			return fmt.Errorf("PersistentBookRepository:DropTenant: %w", err)
+		}
+		delete(r.tenantRepos, tenant)
+	}
+	if err := os.RemoveAll(r.tenantDir(tenant)); err != nil {
+		return fmt.Errorf("PersistentBookRepository:DropTenant: %w", err)
+	}
+	return nil
+}
+
+// openedTenants returns the books of the tenants that were opened.
+func (r *PersistentBookRepository) openedTenants() []*PersistentBookRepository {
+	r.tenantsLock.Lock()
+	defer r.tenantsLock.Unlock()
+	repos := make([]*PersistentBookRepository, 0, len(r.tenantRepos))
+	for _, repo := range r.tenantRepos {
+		repos = append(repos, repo)
+	}
+	return repos
+}
+
+// tenantDir returns the data directory of the tenant, whose name is escaped
+// as the tenant is taken from the token.
+func (r *PersistentBookRepository) tenantDir(tenant string) string {
+	return filepath.Join(r.dir, TenantsDirName, url.PathEscape(tenant))
+}
+
+// pendingRecords returns the number of records to compact. A log left
+// unwritable by a failed append counts one more, so that it is recovered by
+// the next compaction.
+func (r *PersistentBookRepository) pendingRecords() int {
+	r.journalLock.Lock()
+	defer r.journalLock.Unlock()
+	if r.wal.err != nil {
+		return r.wal.records + 1
+	}
+	return r.wal.records
+}
+
+func readSnapshot(path string) (map[string]models.Book, bool, error) {
+	store := make(map[string]models.Book)
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	var s snapshot
+	if err := json.Unmarshal(contents, &s); err != nil {
+		return nil, false, fmt.Errorf("failed to read the snapshot at [%s]: %w", path, err)
+	}
+	for _, book := range s.Books {
+		store[book.Id] = book
+	}
+	return store, true, nil
+}
+
+// writeSnapshot atomically replaces the snapshot at path.
+func writeSnapshot(path string, s snapshot) error {
+	contents, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, contents)
+}
@@ -0,0 +1,186 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+)
+
+func TestPersistentBookRepository(t *testing.T) {
+	ctx := context.Background()
+	initialBook := models.Book{Id: "1", Title: "Test Book", Author: "Test Author", Status: models.ReadStatusToRead}
+
+	t.Run("Replay", func(t *testing.T) {
+		dir := t.TempDir()
+		repo, err := NewPersistentBookRepository(dir, []models.Book{initialBook})
+		require.NoError(t, err)
+
+		// Mutate the repository without compacting, then reopen it from the log.
+		added, err := repo.Add(ctx, models.Book{Title: "New Book", Author: "New Author"})
+		require.NoError(t, err)
+		_, err = repo.Update(ctx, models.Book{Id: "1", Title: "Updated Book", Status: models.ReadStatusRead})
+		require.NoError(t, err)
+		_, err = repo.DeleteById(ctx, added.Id)
+		require.NoError(t, err)
+		require.NoError(t, repo.wal.close())
+
+		reopened, err := NewPersistentBookRepository(dir, nil)
+		require.NoError(t, err)
+		defer reopened.Close()
+		book, err := reopened.GetById(ctx, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, "Updated Book", book.Title)
+		trashed, err := reopened.ListDeleted(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, trashed, 1)
+	})
+
+	t.Run("Compact", func(t *testing.T) {
+		dir := t.TempDir()
+		repo, err := NewPersistentBookRepository(dir, []models.Book{initialBook})
+		require.NoError(t, err)
+		_, err = repo.PurgeById(ctx, "1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, repo.pendingRecords())
+
+		require.NoError(t, repo.Close())
+		info, err := os.Stat(filepath.Join(dir, walFileName))
+		require.NoError(t, err)
+		assert.Zero(t, info.Size())
+
+		// The initial data must not be loaded again once the directory has state.
+		reopened, err := NewPersistentBookRepository(dir, []models.Book{initialBook})
+		require.NoError(t, err)
+		defer reopened.Close()
+		books, err := reopened.List(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, books)
+	})
+
+	t.Run("TruncatedRecord", func(t *testing.T) {
+		dir := t.TempDir()
+		repo, err := NewPersistentBookRepository(dir, nil)
+		require.NoError(t, err)
+		_, err = repo.Add(ctx, models.Book{Id: "1", Title: "Kept"})
+		require.NoError(t, err)
+		_, err = repo.Add(ctx, models.Book{Id: "2", Title: "Torn"})
+		require.NoError(t, err)
+		require.NoError(t, repo.wal.close())
+
+		// Simulate a crash in the middle of writing the last record.
+		walPath := filepath.Join(dir, walFileName)
+		info, err := os.Stat(walPath)
+		require.NoError(t, err)
+		require.NoError(t, os.Truncate(walPath, info.Size()-3))
+
+		reopened, err := NewPersistentBookRepository(dir, nil)
+		require.NoError(t, err)
+		_, err = reopened.GetById(ctx, "1")
+		assert.NoError(t, err)
+		_, err = reopened.GetById(ctx, "2")
+		assert.ErrorIs(t, err, ErrRecordNotFound)
+
+		// New records are appended after the last complete record.
+		_, err = reopened.Add(ctx, models.Book{Id: "3", Title: "After Recovery"})
+		require.NoError(t, err)
+		require.NoError(t, reopened.wal.close())
+		recovered, err := NewPersistentBookRepository(dir, nil)
+		require.NoError(t, err)
+		defer recovered.Close()
+		books, err := recovered.List(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, books, 2)
+	})
+
+	t.Run("CorruptedRecord", func(t *testing.T) {
+		dir := t.TempDir()
+		repo, err := NewPersistentBookRepository(dir, nil)
+		require.NoError(t, err)
+		_, err = repo.Add(ctx, models.Book{Id: "1", Title: "First"})
+		require.NoError(t, err)
+		_, err = repo.Add(ctx, models.Book{Id: "2", Title: "Second"})
+		require.NoError(t, err)
+		require.NoError(t, repo.wal.close())
+
+		// Flip a byte in the payload of the first record.
+		walPath := filepath.Join(dir, walFileName)
+		contents, err := os.ReadFile(walPath)
+		require.NoError(t, err)
+		contents[walHeaderSize+1] ^= 0xff
+		require.NoError(t, os.WriteFile(walPath, contents, 0o600))
+
+		_, err = NewPersistentBookRepository(dir, nil)
+		assert.ErrorIs(t, err, ErrCorruptedLog)
+	})
+
+	t.Run("CorruptedLength", func(t *testing.T) {
+		dir := t.TempDir()
+		repo, err := NewPersistentBookRepository(dir, nil)
+		require.NoError(t, err)
+		_, err = repo.Add(ctx, models.Book{Id: "1", Title: "First"})
+		require.NoError(t, err)
+		_, err = repo.Add(ctx, models.Book{Id: "2", Title: "Second"})
+		require.NoError(t, err)
+		require.NoError(t, repo.wal.close())
+
+		// A damaged length runs past the end of the file, but the records
+		// after it are not discarded as a torn write.
+		walPath := filepath.Join(dir, walFileName)
+		contents, err := os.ReadFile(walPath)
+		require.NoError(t, err)
+		contents[3] = 0x7f
+		require.NoError(t, os.WriteFile(walPath, contents, 0o600))
+
+		_, err = NewPersistentBookRepository(dir, nil)
+		assert.ErrorIs(t, err, ErrCorruptedLog)
+		info, err := os.Stat(walPath)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(contents)), info.Size())
+	})
+
+	t.Run("Locked", func(t *testing.T) {
+		dir := t.TempDir()
+		repo, err := NewPersistentBookRepository(dir, nil)
+		require.NoError(t, err)
+
+		// The directory is not opened again until the repository is closed.
+		_, err = NewPersistentBookRepository(dir, nil)
+		assert.ErrorIs(t, err, ErrLocked)
+		require.NoError(t, repo.Close())
+		reopened, err := NewPersistentBookRepository(dir, nil)
+		require.NoError(t, err)
+		assert.NoError(t, reopened.Close())
+	})
+}
+
+func TestWriteAheadLogRollback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal.log")
+	wal, _, err := openWriteAheadLog[journalEntry](path)
+	require.NoError(t, err)
+	require.NoError(t, wal.append(putEntry(models.Book{Id: "1", Title: "Dune"})))
+
+	// The bytes of an append that failed part-way, e.g. on a full disk, are
+	// removed, so that the next record can be read back.
+	end, err := wal.file.Seek(0, io.SeekCurrent)
+	require.NoError(t, err)
+	_, err = wal.file.Write([]byte{0xff, 0xff, 0, 0, 1})
+	require.NoError(t, err)
+	assert.ErrorIs(t, wal.rollback(end, os.ErrDeadlineExceeded), os.ErrDeadlineExceeded)
+	require.NoError(t, wal.append(putEntry(models.Book{Id: "2", Title: "Emma"})))
+	require.NoError(t, wal.close())
+
+	wal, records, err := openWriteAheadLog[journalEntry](path)
+	require.NoError(t, err)
+	defer wal.close()
+	require.Len(t, records, 2)
+	assert.Equal(t, "Emma", records[1][0].Book.Title)
+}
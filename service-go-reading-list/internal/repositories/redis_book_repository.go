@@ -0,0 +1,675 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/redis"
+)
+
+// redisCommitAttempts bounds how many times a mutation is retried when
+// another client changes the books it read before it is committed.
+const redisCommitAttempts = 10
+
+// errRedisConflict is returned by commit when a watched key changed.
+var errRedisConflict = errors.New("a watched key changed")
+
+// RedisBookRepository stores the books in Redis, or any server of its
+// protocol, so that the replicas of the service share them. Each book is a
+// hash of its JSON fields at <prefix>book:<id>. The ids of all the books are
+// in the <prefix>books sorted set, ordered by when the books were added,
+// and those in the trash in the <prefix>trash sorted set, ordered by when
+// they were deleted. The books of the other tenants are stored the same way
+// under <prefix>tenants:<tenant>:, and the tenants in the <prefix>tenants
+// sorted set.
+//
+// The mutations read the books with WATCH and apply their changes with
+// MULTI and EXEC, so that they are retried when another replica changes the
+// same books concurrently.
+type RedisBookRepository struct {
+	client *redis.Client
+	prefix string
+	// tenant is set for the books of a tenant, which share the client of
+	// the repository of the default tenant.
+	tenant bool
+}
+
+// NewRedisBookRepository stores the books under the key prefix. The initial
+// data is only added by the first replica that starts with the prefix.
+func NewRedisBookRepository(client *redis.Client, prefix string, initialData []models.Book) (*RedisBookRepository, error) {
+	r := &RedisBookRepository{client: client, prefix: prefix}
+	ctx := context.Background()
+	if _, err := client.Do(ctx, "PING"); err != nil {
+		return nil, fmt.Errorf("redisBookRepository:New: %w", err)
+	}
+	// The marker is set together with the initial data, so that they are
+	// added only once even when several replicas start at the same time.
+	err := r.update(ctx, func(tx *redisBookTx) error {
+		if _, err := tx.watch(ctx, r.initializedKey()); err != nil {
+			return err
+		}
+		initialized, err := redis.Int(tx.conn.Do(ctx, "EXISTS", r.initializedKey()))
+		if err != nil || initialized > 0 {
+			return err
+		}
+		tx.markers = append(tx.markers, r.initializedKey())
+		for _, book := range initialData {
+			if _, err := tx.Add(ctx, book); err != nil && !errors.Is(err, ErrRecordAlreadyExists) {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("redisBookRepository:New: %w", err)
+	}
+	return r, nil
+}
+
+func (r *RedisBookRepository) Add(ctx context.Context, book models.Book) (models.Book, error) {
+	var added models.Book
+	err := r.update(ctx, func(tx *redisBookTx) (err error) {
+		added, err = tx.Add(ctx, book)
+		return err
+	})
+	if err != nil {
+		return models.Book{}, fmt.Errorf("redisBookRepository:Add: %w", err)
+	}
+	return added, nil
+}
+
+func (r *RedisBookRepository) Update(ctx context.Context, updatedBook models.Book) (models.Book, error) {
+	var updated models.Book
+	err := r.update(ctx, func(tx *redisBookTx) (err error) {
+		updated, err = tx.Update(ctx, updatedBook)
+		return err
+	})
+	if err != nil {
+		return models.Book{}, fmt.Errorf("redisBookRepository:Update: %w", err)
+	}
+	return updated, nil
+}
+
+// UpdateById updates the book in a transaction, which is retried, calling
+// fn again, when another client changed the book meanwhile.
+func (r *RedisBookRepository) UpdateById(ctx context.Context, id string, fn func(current models.Book) (models.Book, error)) (models.Book, error) {
+	var updated models.Book
+	err := r.update(ctx, func(tx *redisBookTx) (err error) {
+		updated, err = updateById(ctx, tx, id, fn)
+		return err
+	})
+	if err != nil {
+		return models.Book{}, fmt.Errorf("redisBookRepository:UpdateById: %w", err)
+	}
+	return updated, nil
+}
+
+func (r *RedisBookRepository) List(ctx context.Context) ([]models.Book, error) {
+	books, err := r.list(ctx, r.booksKey(), false)
+	if err != nil {
+		return nil, fmt.Errorf("redisBookRepository:List: %w", err)
+	}
+	return books, nil
+}
+
+func (r *RedisBookRepository) GetById(ctx context.Context, id string) (models.Book, error) {
+	book, err := r.get(ctx, id)
+	if err != nil {
+		return models.Book{}, fmt.Errorf("redisBookRepository:GetById: %w", err)
+	}
+	if book == nil || book.IsDeleted() {
+		return models.Book{}, fmt.Errorf("redisBookRepository:GetById: %w", ErrRecordNotFound)
+	}
+	return *book, nil
+}
+
+func (r *RedisBookRepository) DeleteById(ctx context.Context, id string) (models.Book, error) {
+	var deleted models.Book
+	err := r.update(ctx, func(tx *redisBookTx) (err error) {
+		deleted, err = tx.DeleteById(ctx, id)
+		return err
+	})
+	if err != nil {
+		return models.Book{}, fmt.Errorf("redisBookRepository:DeleteById: %w", err)
+	}
+	return deleted, nil
+}
+
+func (r *RedisBookRepository) ListDeleted(ctx context.Context) ([]models.Book, error) {
+	books, err := r.list(ctx, r.trashKey(), true)
+	if err != nil {
+		return nil, fmt.Errorf("redisBookRepository:ListDeleted: %w", err)
+	}
+	return books, nil
+}
+
+func (r *RedisBookRepository) RestoreById(ctx context.Context, id string) (models.Book, error) {
+	var restored models.Book
+	err := r.update(ctx, func(tx *redisBookTx) (err error) {
+		restored, err = tx.RestoreById(ctx, id)
+		return err
+	})
+	if err != nil {
+		return models.Book{}, fmt.Errorf("redisBookRepository:RestoreById: %w", err)
+	}
+	return restored, nil
+}
+
+func (r *RedisBookRepository) PurgeById(ctx context.Context, id string) (models.Book, error) {
+	var purged models.Book
+	err := r.update(ctx, func(tx *redisBookTx) (err error) {
+		purged, err = tx.PurgeById(ctx, id)
+		return err
+	})
+	if err != nil {
+		return models.Book{}, fmt.Errorf("redisBookRepository:PurgeById: %w", err)
+	}
+	return purged, nil
+}
+
+func (r *RedisBookRepository) PurgeDeletedBefore(ctx context.Context, before time.Time) ([]models.Book, error) {
+	var purged []models.Book
+	err := r.update(ctx, func(tx *redisBookTx) (err error) {
+		purged, err = tx.PurgeDeletedBefore(ctx, before)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("redisBookRepository:PurgeDeletedBefore: %w", err)
+	}
+	return purged, nil
+}
+
+// RunInTransaction runs fn with a repository that stages its changes and
+// commits them at once if fn succeeds. It fails with ErrConflict instead of
+// retrying fn when another client changed the books fn read.
+func (r *RedisBookRepository) RunInTransaction(ctx context.Context, fn func(tx models.BookRepository) error) error {
+	conn, err := r.client.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("redisBookRepository:RunInTransaction: %w", err)
+	}
+	defer conn.Close()
+	tx := newRedisBookTx(r, conn)
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.commit(ctx); errors.Is(err, errRedisConflict) {
+		return fmt.Errorf("redisBookRepository:RunInTransaction: %w", ErrConflict)
+	} else if err != nil {
+		return fmt.Errorf("redisBookRepository:RunInTransaction: %w", err)
+	}
+	return nil
+}
+
+// Close closes the connections to Redis, unless the repository holds the
+// books of a tenant, whose connections are closed with the store.
+func (r *RedisBookRepository) Close() error {
+	if r.tenant {
+		return nil
+	}
+	return r.client.Close()
+}
+
+// OpenTenant returns the books of the tenant, under a key prefix of its own.
+func (r *RedisBookRepository) OpenTenant(ctx context.Context, tenant string, initialData []models.Book) (models.BookRepository, error) {
+	if _, err := r.client.Do(ctx, "ZADD", r.tenantsKey(), "NX", "0", tenant); err != nil {
+		return nil, fmt.Errorf("redisBookRepository:OpenTenant: %w", err)
+	}
+	repo, err := NewRedisBookRepository(r.client, r.tenantPrefix(tenant), initialData)
+	if err != nil {
+		return nil, fmt.Errorf("redisBookRepository:OpenTenant: %w", err)
+	}
+	repo.tenant = true
+	return repo, nil
+}
+
+func (r *RedisBookRepository) ListTenantIds(ctx context.Context) ([]string, error) {
+	ids, err := redis.Strings(r.client.Do(ctx, "ZRANGE", r.tenantsKey(), "0", "-1"))
+	if err != nil {
+		return nil, fmt.Errorf("redisBookRepository:ListTenantIds: %w", err)
+	}
+	return ids, nil
+}
+
+// DropTenant removes the books of the tenant and the tenant.
+func (r *RedisBookRepository) DropTenant(ctx context.Context, tenant string) error {
+	repo := &RedisBookRepository{client: r.client, prefix: r.tenantPrefix(tenant), tenant: true}
+	err := repo.update(ctx, func(tx *redisBookTx) error {
+		ids, err := tx.listIds(ctx, repo.booksKey())
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			tx.put(id, nil)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("redisBookRepository:DropTenant: %w", err)
+	}
+	if _, err := r.client.Do(ctx, "DEL", repo.initializedKey()); err != nil {
+		return fmt.Errorf("redisBookRepository:DropTenant: %w", err)
+	}
+	if _, err := r.client.Do(ctx, "ZREM", r.tenantsKey(), tenant); err != nil {
+		return fmt.Errorf("redisBookRepository:DropTenant: %w", err)
+	}
+	return nil
+}
+
+// update runs fn in a transaction, retrying it when a watched key changed.
+func (r *RedisBookRepository) update(ctx context.Context, fn func(tx *redisBookTx) error) error {
+	conn, err := r.client.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	for attempt := 0; attempt < redisCommitAttempts; attempt++ {
+		tx := newRedisBookTx(r, conn)
+		if err := fn(tx); err != nil {
+			return err
+		}
+		if err := tx.commit(ctx); !errors.Is(err, errRedisConflict) {
+			return err
+		}
+	}
+	return ErrConflict
+}
+
+func (r *RedisBookRepository) get(ctx context.Context, id string) (*models.Book, error) {
+	fields, err := redis.StringMap(r.client.Do(ctx, "HGETALL", r.bookKey(id)))
+	if err != nil {
+		return nil, err
+	}
+	return decodeRedisBook(fields)
+}
+
+// list returns the books of the set that are, or are not, in the trash.
+// The books are read in a single MULTI, so that they are consistent.
+func (r *RedisBookRepository) list(ctx context.Context, setKey string, deleted bool) ([]models.Book, error) {
+	conn, err := r.client.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	ids, err := redis.Strings(conn.Do(ctx, "ZRANGE", setKey, "0", "-1"))
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	if _, err := conn.Do(ctx, "MULTI"); err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if _, err := conn.Do(ctx, "HGETALL", r.bookKey(id)); err != nil {
+			return nil, err
+		}
+	}
+	replies, err := conn.Do(ctx, "EXEC")
+	if err != nil {
+		return nil, err
+	}
+	values, ok := replies.([]interface{})
+	if !ok || len(values) != len(ids) {
+		return nil, fmt.Errorf("unexpected reply to EXEC")
+	}
+	var books []models.Book
+	for _, value := range values {
+		fields, err := redis.StringMap(value, nil)
+		if err != nil {
+			return nil, err
+		}
+		// The book is nil when it was purged after its id was read.
+		book, err := decodeRedisBook(fields)
+		if err != nil {
+			return nil, err
+		}
+		if book != nil && book.IsDeleted() == deleted {
+			books = append(books, *book)
+		}
+	}
+	return books, nil
+}
+
+func (r *RedisBookRepository) bookKey(id string) string {
+	return r.prefix + "book:" + id
+}
+
+func (r *RedisBookRepository) booksKey() string {
+	return r.prefix + "books"
+}
+
+func (r *RedisBookRepository) trashKey() string {
+	return r.prefix + "trash"
+}
+
+func (r *RedisBookRepository) initializedKey() string {
+	return r.prefix + "initialized"
+}
+
+func (r *RedisBookRepository) tenantsKey() string {
+	return r.prefix + "tenants"
+}
+
+func (r *RedisBookRepository) tenantPrefix(tenant string) string {
+	return r.prefix + "tenants:" + tenant + ":"
+}
+
+// redisBookTx stages the changes of a transaction in memory. The books it
+// reads are watched, so that its commit fails if they changed meanwhile. Its
+// errors are wrapped by the methods of the repository.
+type redisBookTx struct {
+	repo *RedisBookRepository
+	conn *redis.Conn
+	// books are the books read or changed by the transaction, nil for the
+	// ones that do not exist.
+	books map[string]*models.Book
+	// changed are the ids of the books changed by the transaction, in the
+	// order they were first changed.
+	changed []string
+	// markers are the keys set to 1 on commit.
+	markers []string
+	// listed is set once the sets of ids are watched.
+	listed bool
+}
+
+func newRedisBookTx(repo *RedisBookRepository, conn *redis.Conn) *redisBookTx {
+	return &redisBookTx{repo: repo, conn: conn, books: make(map[string]*models.Book)}
+}
+
+func (tx *redisBookTx) Add(ctx context.Context, book models.Book) (models.Book, error) {
+	if book.Id == "" {
+		book.Id = uuid.NewString()
+	}
+	existing, err := tx.get(ctx, book.Id)
+	if err != nil {
+		return models.Book{}, err
+	}
+	if existing != nil {
+		return models.Book{}, ErrRecordAlreadyExists
+	}
+	book.DeletedAt = nil
+	tx.put(book.Id, &book)
+	return book, nil
+}
+
+func (tx *redisBookTx) Update(ctx context.Context, updatedBook models.Book) (models.Book, error) {
+	existing, err := tx.get(ctx, updatedBook.Id)
+	if err != nil {
+		return models.Book{}, err
+	}
+	if existing == nil || existing.IsDeleted() {
+		return models.Book{}, ErrRecordNotFound
+	}
+	updatedBook.DeletedAt = nil
+	tx.put(updatedBook.Id, &updatedBook)
+	return updatedBook, nil
+}
+
+func (tx *redisBookTx) List(ctx context.Context) ([]models.Book, error) {
+	ids, err := tx.listIds(ctx, tx.repo.booksKey())
+	if err != nil {
+		return nil, err
+	}
+	return tx.getBooks(ctx, ids, false)
+}
+
+func (tx *redisBookTx) GetById(ctx context.Context, id string) (models.Book, error) {
+	book, err := tx.get(ctx, id)
+	if err != nil {
+		return models.Book{}, err
+	}
+	if book == nil || book.IsDeleted() {
+		return models.Book{}, ErrRecordNotFound
+	}
+	return *book, nil
+}
+
+func (tx *redisBookTx) DeleteById(ctx context.Context, id string) (models.Book, error) {
+	book, err := tx.GetById(ctx, id)
+	if err != nil {
+		return models.Book{}, err
+	}
+	deletedAt := time.Now().UTC()
+	book.DeletedAt = &deletedAt
+	tx.put(id, &book)
+	return book, nil
+}
+
+func (tx *redisBookTx) ListDeleted(ctx context.Context) ([]models.Book, error) {
+	ids, err := tx.listIds(ctx, tx.repo.trashKey())
+	if err != nil {
+		return nil, err
+	}
+	return tx.getBooks(ctx, ids, true)
+}
+
+func (tx *redisBookTx) RestoreById(ctx context.Context, id string) (models.Book, error) {
+	book, err := tx.get(ctx, id)
+	if err != nil {
+		return models.Book{}, err
+	}
+	if book == nil || !book.IsDeleted() {
+		return models.Book{}, ErrRecordNotFound
+	}
+	restored := *book
+	restored.DeletedAt = nil
+	tx.put(id, &restored)
+	return restored, nil
+}
+
+func (tx *redisBookTx) PurgeById(ctx context.Context, id string) (models.Book, error) {
+	book, err := tx.get(ctx, id)
+	if err != nil {
+		return models.Book{}, err
+	}
+	if book == nil {
+		return models.Book{}, ErrRecordNotFound
+	}
+	tx.put(id, nil)
+	return *book, nil
+}
+
+func (tx *redisBookTx) PurgeDeletedBefore(ctx context.Context, before time.Time) ([]models.Book, error) {
+	deleted, err := tx.ListDeleted(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var purged []models.Book
+	for _, book := range deleted {
+		if book.DeletedAt.Before(before) {
+			tx.put(book.Id, nil)
+			purged = append(purged, book)
+		}
+	}
+	return purged, nil
+}
+
+// watch watches the keys, so that the commit fails if they change.
+func (tx *redisBookTx) watch(ctx context.Context, keys ...string) (interface{}, error) {
+	return tx.conn.Do(ctx, append([]string{"WATCH"}, keys...)...)
+}
+
+// get returns the book, or nil when it does not exist.
+func (tx *redisBookTx) get(ctx context.Context, id string) (*models.Book, error) {
+	if book, ok := tx.books[id]; ok {
+		return book, nil
+	}
+	key := tx.repo.bookKey(id)
+	if _, err := tx.watch(ctx, key); err != nil {
+		return nil, err
+	}
+	fields, err := redis.StringMap(tx.conn.Do(ctx, "HGETALL", key))
+	if err != nil {
+		return nil, err
+	}
+	book, err := decodeRedisBook(fields)
+	if err != nil {
+		return nil, err
+	}
+	tx.books[id] = book
+	return book, nil
+}
+
+func (tx *redisBookTx) put(id string, book *models.Book) {
+	if !tx.isChanged(id) {
+		tx.changed = append(tx.changed, id)
+	}
+	tx.books[id] = book
+}
+
+func (tx *redisBookTx) isChanged(id string) bool {
+	for _, changed := range tx.changed {
+		if changed == id {
+			return true
+		}
+	}
+	return false
+}
+
+// listIds returns the ids of the set, watching both sets so that the
+// transaction fails if a book is added or moved meanwhile. The books
+// changed by the transaction are added to the ids.
+func (tx *redisBookTx) listIds(ctx context.Context, setKey string) ([]string, error) {
+	if !tx.listed {
+		if _, err := tx.watch(ctx, tx.repo.booksKey(), tx.repo.trashKey()); err != nil {
+			return nil, err
+		}
+		tx.listed = true
+	}
+	ids, err := redis.Strings(tx.conn.Do(ctx, "ZRANGE", setKey, "0", "-1"))
+	if err != nil {
+		return nil, err
+	}
+	listed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		listed[id] = true
+	}
+	for _, id := range tx.changed {
+		if !listed[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// getBooks returns the books with the ids that are, or are not, in the trash.
+func (tx *redisBookTx) getBooks(ctx context.Context, ids []string, deleted bool) ([]models.Book, error) {
+	var books []models.Book
+	for _, id := range ids {
+		book, err := tx.get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if book != nil && book.IsDeleted() == deleted {
+			books = append(books, *book)
+		}
+	}
+	return books, nil
+}
+
+// commit applies the changes of the transaction at once, failing with
+// errRedisConflict when a watched key changed.
+func (tx *redisBookTx) commit(ctx context.Context) error {
+	if len(tx.changed) == 0 && len(tx.markers) == 0 {
+		return nil
+	}
+	commands := make([][]string, 0, 3*len(tx.changed)+len(tx.markers))
+	// The books added by the transaction are ordered after the existing ones
+	// and in the order they were added.
+	score := time.Now().UnixMicro()
+	for _, id := range tx.changed {
+		key := tx.repo.bookKey(id)
+		commands = append(commands, []string{"DEL", key})
+		book := tx.books[id]
+		if book == nil {
+			commands = append(commands,
+				[]string{"ZREM", tx.repo.booksKey(), id},
+				[]string{"ZREM", tx.repo.trashKey(), id})
+			continue
+		}
+		hset, err := encodeRedisBook(key, *book)
+		if err != nil {
+			return err
+		}
+		commands = append(commands, hset,
+			[]string{"ZADD", tx.repo.booksKey(), "NX", strconv.FormatInt(score, 10), id})
+		score++
+		if book.IsDeleted() {
+			commands = append(commands, []string{"ZADD", tx.repo.trashKey(), strconv.FormatInt(book.DeletedAt.UnixMilli(), 10), id})
+		} else {
+			commands = append(commands, []string{"ZREM", tx.repo.trashKey(), id})
+		}
+	}
+	for _, key := range tx.markers {
+		commands = append(commands, []string{"SET", key, "1"})
+	}
+	if _, err := tx.conn.Do(ctx, "MULTI"); err != nil {
+		return err
+	}
+	for _, command := range commands {
+		if _, err := tx.conn.Do(ctx, command...); err != nil {
+			return err
+		}
+	}
+	replies, err := tx.conn.Do(ctx, "EXEC")
+	if err != nil {
+		return err
+	}
+	if replies == nil {
+		return errRedisConflict
+	}
+	return nil
+}
+
+// encodeRedisBook returns the HSET command storing each JSON field of the
+// book in a field of the hash.
+func encodeRedisBook(key string, book models.Book) ([]string, error) {
+	contents, err := json.Marshal(book)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(contents, &fields); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	command := []string{"HSET", key}
+	for _, name := range names {
+		command = append(command, name, string(fields[name]))
+	}
+	return command, nil
+}
+
+// decodeRedisBook returns the book of the fields of its hash, or nil when
+// there are none.
+func decodeRedisBook(fields map[string]string) (*models.Book, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	raw := make(map[string]json.RawMessage, len(fields))
+	for name, value := range fields {
+		raw[name] = json.RawMessage(value)
+	}
+	contents, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCorruptedRecord, err)
+	}
+	var book models.Book
+	if err := json.Unmarshal(contents, &book); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCorruptedRecord, err)
+	}
+	return &book, nil
+}
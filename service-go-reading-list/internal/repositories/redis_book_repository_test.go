@@ -0,0 +1,249 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/redis"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/redis/redistest"
+)
+
+// newRedisBookRepository returns a repository on a new in-process server.
+func newRedisBookRepository(t *testing.T, initialData []models.Book) (*RedisBookRepository, *redistest.Server) {
+	server, err := redistest.NewServer()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = server.Close() })
+	repo, err := NewRedisBookRepository(redis.NewClient(redis.Options{Addr: server.Addr()}), "reading-list:", initialData)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = repo.Close() })
+	return repo, server
+}
+
+func TestRedisBookRepository(t *testing.T) {
+	ctx := context.Background()
+	initialBook := models.Book{Id: "1", Title: "Test Book", Author: "Test Author"}
+	updatedBook := models.Book{Id: initialBook.Id, Title: "Updated Book", Author: "Updated Author", Tags: []string{"classic"}}
+	repo, server := newRedisBookRepository(t, []models.Book{initialBook})
+
+	t.Run("Add", func(t *testing.T) {
+		addedBook, err := repo.Add(ctx, models.Book{Title: "New Book", Author: "New Author"})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, addedBook.Id)
+
+		_, err = repo.Add(ctx, models.Book{Id: addedBook.Id, Title: "Duplicate Book"})
+		assert.ErrorIs(t, err, ErrRecordAlreadyExists)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		updated, err := repo.Update(ctx, updatedBook)
+		assert.NoError(t, err)
+		assert.Equal(t, updatedBook, updated)
+
+		_, err = repo.Update(ctx, models.Book{Id: "non-existing-id"})
+		assert.ErrorIs(t, err, ErrRecordNotFound)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		books, err := repo.List(ctx)
+		assert.NoError(t, err)
+		require.Len(t, books, 2)
+		// The books are listed in the order they were added.
+		assert.Equal(t, updatedBook, books[0])
+		assert.Equal(t, "New Book", books[1].Title)
+	})
+
+	t.Run("GetById", func(t *testing.T) {
+		book, err := repo.GetById(ctx, initialBook.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, updatedBook, book)
+
+		_, err = repo.GetById(ctx, "non-existing-id")
+		assert.ErrorIs(t, err, ErrRecordNotFound)
+	})
+
+	t.Run("Trash", func(t *testing.T) {
+		deletedBook, err := repo.DeleteById(ctx, initialBook.Id)
+		assert.NoError(t, err)
+		assert.True(t, deletedBook.IsDeleted())
+		_, err = repo.DeleteById(ctx, initialBook.Id)
+		assert.ErrorIs(t, err, ErrRecordNotFound)
+
+		books, err := repo.List(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, books, 1)
+		_, err = repo.GetById(ctx, initialBook.Id)
+		assert.ErrorIs(t, err, ErrRecordNotFound)
+		// Deleted books still reserve their ID.
+		_, err = repo.Add(ctx, initialBook)
+		assert.ErrorIs(t, err, ErrRecordAlreadyExists)
+
+		trashed, err := repo.ListDeleted(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Book{deletedBook}, trashed)
+
+		restored, err := repo.RestoreById(ctx, initialBook.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, updatedBook, restored)
+		_, err = repo.RestoreById(ctx, initialBook.Id)
+		assert.ErrorIs(t, err, ErrRecordNotFound)
+
+		_, err = repo.DeleteById(ctx, initialBook.Id)
+		assert.NoError(t, err)
+		purged, err := repo.PurgeDeletedBefore(ctx, time.Now().Add(-time.Hour))
+		assert.NoError(t, err)
+		assert.Empty(t, purged)
+		purged, err = repo.PurgeDeletedBefore(ctx, time.Now().Add(time.Hour))
+		assert.NoError(t, err)
+		require.Len(t, purged, 1)
+		assert.Equal(t, initialBook.Id, purged[0].Id)
+		_, err = repo.PurgeById(ctx, initialBook.Id)
+		assert.ErrorIs(t, err, ErrRecordNotFound)
+	})
+
+	t.Run("Keys", func(t *testing.T) {
+		books, err := repo.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, books, 1)
+		assert.Equal(t, []string{
+			"reading-list:book:" + books[0].Id,
+			"reading-list:books",
+			"reading-list:initialized",
+		}, server.Keys(0))
+	})
+
+	t.Run("InitialData", func(t *testing.T) {
+		// Another replica does not add the initial data again.
+		replica, err := NewRedisBookRepository(redis.NewClient(redis.Options{Addr: server.Addr()}), "reading-list:", []models.Book{initialBook})
+		require.NoError(t, err)
+		defer replica.Close()
+		_, err = replica.GetById(ctx, initialBook.Id)
+		assert.ErrorIs(t, err, ErrRecordNotFound)
+
+		// A different prefix has its own books.
+		other, err := NewRedisBookRepository(redis.NewClient(redis.Options{Addr: server.Addr()}), "other:", []models.Book{initialBook})
+		require.NoError(t, err)
+		defer other.Close()
+		books, err := other.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Book{initialBook}, books)
+	})
+}
+
+func TestRedisBookRepositoryTransaction(t *testing.T) {
+	ctx := context.Background()
+	repo, server := newRedisBookRepository(t, []models.Book{{Id: "1", Title: "Test Book", Status: models.ReadStatusToRead}})
+
+	t.Run("Commit", func(t *testing.T) {
+		err := repo.RunInTransaction(ctx, func(tx models.BookRepository) error {
+			if _, err := tx.Update(ctx, models.Book{Id: "1", Title: "Test Book", Status: models.ReadStatusRead}); err != nil {
+				return err
+			}
+			if _, err := tx.Add(ctx, models.Book{Id: "2", Title: "New Book"}); err != nil {
+				return err
+			}
+			// Changes are visible inside the transaction.
+			books, err := tx.List(ctx)
+			if err != nil {
+				return err
+			}
+			assert.Len(t, books, 2)
+			return nil
+		})
+		assert.NoError(t, err)
+		books, err := repo.List(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, books, 2)
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		errRollback := errors.New("rollback")
+		err := repo.RunInTransaction(ctx, func(tx models.BookRepository) error {
+			if _, err := tx.DeleteById(ctx, "1"); err != nil {
+				return err
+			}
+			if _, err := tx.GetById(ctx, "1"); !errors.Is(err, ErrRecordNotFound) {
+				return err
+			}
+			return errRollback
+		})
+		assert.ErrorIs(t, err, errRollback)
+		book, err := repo.GetById(ctx, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, models.ReadStatusRead, book.Status)
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		replica, err := NewRedisBookRepository(redis.NewClient(redis.Options{Addr: server.Addr()}), "reading-list:", nil)
+		require.NoError(t, err)
+		defer replica.Close()
+		err = repo.RunInTransaction(ctx, func(tx models.BookRepository) error {
+			book, err := tx.GetById(ctx, "1")
+			if err != nil {
+				return err
+			}
+			// Another replica changes the book the transaction read.
+			if _, err := replica.Update(ctx, models.Book{Id: "1", Title: "Changed"}); err != nil {
+				return err
+			}
+			book.Status = models.ReadStatusReading
+			_, err = tx.Update(ctx, book)
+			return err
+		})
+		assert.ErrorIs(t, err, ErrConflict)
+		book, err := repo.GetById(ctx, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, "Changed", book.Title)
+	})
+}
+
+func TestRedisBookRepositoryConcurrency(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newRedisBookRepository(t, nil)
+
+	// Concurrent adds of the same id are checked atomically.
+	var wg sync.WaitGroup
+	results := make(chan error, 20)
+	for i := 0; i < cap(results); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := repo.Add(ctx, models.Book{Id: fmt.Sprintf("book-%d", i%5), Title: "Concurrent Book"})
+			results <- err
+		}(i)
+	}
+	wg.Wait()
+	close(results)
+	added := 0
+	for err := range results {
+		if err == nil {
+			added++
+		} else {
+			assert.ErrorIs(t, err, ErrRecordAlreadyExists)
+		}
+	}
+	assert.Equal(t, 5, added)
+	books, err := repo.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, books, 5)
+}
+
+func TestRedisBookRepositoryContext(t *testing.T) {
+	repo, _ := newRedisBookRepository(t, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := repo.Add(ctx, models.Book{Title: "Canceled Book"})
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = repo.List(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
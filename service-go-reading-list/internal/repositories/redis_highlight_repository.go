@@ -0,0 +1,209 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/redis"
+)
+
+// redisHighlightRepository stores the highlights in Redis, so that the
+// replicas of the service sharing the books of a Redis share them too. The
+// highlights are the JSON values of the <prefix>highlights hash, by id.
+type redisHighlightRepository struct {
+	client *redis.Client
+	key    string
+}
+
+func newRedisHighlightRepository(client *redis.Client, prefix string) *redisHighlightRepository {
+	return &redisHighlightRepository{client: client, key: prefix + "highlights"}
+}
+
+func (r *redisHighlightRepository) Add(ctx context.Context, highlight models.Highlight) (models.Highlight, error) {
+	highlight.Id = uuid.NewString()
+	hset, err := r.encode(highlight)
+	if err != nil {
+		return models.Highlight{}, fmt.Errorf("redisHighlightRepository:Add: %w", err)
+	}
+	if _, err := r.client.Do(ctx, hset...); err != nil {
+		return models.Highlight{}, fmt.Errorf("redisHighlightRepository:Add: %w", err)
+	}
+	return highlight, nil
+}
+
+func (r *redisHighlightRepository) Update(ctx context.Context, highlight models.Highlight) (models.Highlight, error) {
+	err := r.update(ctx, func(conn *redis.Conn) ([][]string, error) {
+		if _, err := r.get(ctx, conn, highlight.Id); err != nil {
+			return nil, err
+		}
+		hset, err := r.encode(highlight)
+		if err != nil {
+			return nil, err
+		}
+		return [][]string{hset}, nil
+	})
+	if err != nil {
+		return models.Highlight{}, fmt.Errorf("redisHighlightRepository:Update: %w", err)
+	}
+	return highlight, nil
+}
+
+func (r *redisHighlightRepository) GetById(ctx context.Context, id string) (models.Highlight, error) {
+	conn, err := r.client.Conn(ctx)
+	if err != nil {
+		return models.Highlight{}, fmt.Errorf("redisHighlightRepository:GetById: %w", err)
+	}
+	defer conn.Close()
+	highlight, err := r.get(ctx, conn, id)
+	if err != nil {
+		return models.Highlight{}, fmt.Errorf("redisHighlightRepository:GetById: %w", err)
+	}
+	return highlight, nil
+}
+
+func (r *redisHighlightRepository) DeleteById(ctx context.Context, id string) (models.Highlight, error) {
+	var deleted models.Highlight
+	err := r.update(ctx, func(conn *redis.Conn) (commands [][]string, err error) {
+		if deleted, err = r.get(ctx, conn, id); err != nil {
+			return nil, err
+		}
+		return [][]string{{"HDEL", r.key, id}}, nil
+	})
+	if err != nil {
+		return models.Highlight{}, fmt.Errorf("redisHighlightRepository:DeleteById: %w", err)
+	}
+	return deleted, nil
+}
+
+func (r *redisHighlightRepository) List(ctx context.Context, filter models.HighlightFilter) ([]models.Highlight, error) {
+	conn, err := r.client.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("redisHighlightRepository:List: %w", err)
+	}
+	defer conn.Close()
+	all, err := r.list(ctx, conn)
+	if err != nil {
+		return nil, fmt.Errorf("redisHighlightRepository:List: %w", err)
+	}
+	highlights := make([]models.Highlight, 0)
+	for _, highlight := range all {
+		if filter.Matches(highlight) {
+			highlights = append(highlights, highlight)
+		}
+	}
+	sortHighlights(highlights)
+	return highlights, nil
+}
+
+func (r *redisHighlightRepository) DeleteByBook(ctx context.Context, bookId string) (int, error) {
+	deleted := 0
+	err := r.update(ctx, func(conn *redis.Conn) ([][]string, error) {
+		highlights, err := r.list(ctx, conn)
+		if err != nil {
+			return nil, err
+		}
+		hdel := []string{"HDEL", r.key}
+		for _, highlight := range highlights {
+			if highlight.BookId == bookId {
+				hdel = append(hdel, highlight.Id)
+			}
+		}
+		deleted = len(hdel) - 2
+		if deleted == 0 {
+			return nil, nil
+		}
+		return [][]string{hdel}, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("redisHighlightRepository:DeleteByBook: %w", err)
+	}
+	return deleted, nil
+}
+
+// purge removes all the highlights.
+func (r *redisHighlightRepository) purge(ctx context.Context) error {
+	if _, err := r.client.Do(ctx, "DEL", r.key); err != nil {
+		return fmt.Errorf("redisHighlightRepository:Purge: %w", err)
+	}
+	return nil
+}
+
+// update watches the highlights before fn reads them, and runs the commands
+// that fn returns in a MULTI. They are read again, calling fn again, when
+// another client changed them before the EXEC.
+func (r *redisHighlightRepository) update(ctx context.Context, fn func(conn *redis.Conn) ([][]string, error)) error {
+	conn, err := r.client.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	for attempt := 0; attempt < redisCommitAttempts; attempt++ {
+		if _, err := conn.Do(ctx, "WATCH", r.key); err != nil {
+			return err
+		}
+		commands, err := fn(conn)
+		if err != nil || len(commands) == 0 {
+			return err
+		}
+		if _, err := conn.Do(ctx, "MULTI"); err != nil {
+			return err
+		}
+		for _, command := range commands {
+			if _, err := conn.Do(ctx, command...); err != nil {
+				return err
+			}
+		}
+		replies, err := conn.Do(ctx, "EXEC")
+		if err != nil || replies != nil {
+			return err
+		}
+	}
+	return ErrConflict
+}
+
+func (r *redisHighlightRepository) get(ctx context.Context, conn *redis.Conn, id string) (models.Highlight, error) {
+	value, err := redis.String(conn.Do(ctx, "HGET", r.key, id))
+	if errors.Is(err, redis.ErrNil) {
+		return models.Highlight{}, ErrRecordNotFound
+	} else if err != nil {
+		return models.Highlight{}, err
+	}
+	var highlight models.Highlight
+	if err := json.Unmarshal([]byte(value), &highlight); err != nil {
+		return models.Highlight{}, fmt.Errorf("invalid highlight [%s]: %w", id, err)
+	}
+	return highlight, nil
+}
+
+func (r *redisHighlightRepository) list(ctx context.Context, conn *redis.Conn) ([]models.Highlight, error) {
+	values, err := redis.StringMap(conn.Do(ctx, "HGETALL", r.key))
+	if err != nil {
+		return nil, err
+	}
+	highlights := make([]models.Highlight, 0, len(values))
+	for id, value := range values {
+		var highlight models.Highlight
+		if err := json.Unmarshal([]byte(value), &highlight); err != nil {
+			return nil, fmt.Errorf("invalid highlight [%s]: %w", id, err)
+		}
+		highlights = append(highlights, highlight)
+	}
+	return highlights, nil
+}
+
+// encode returns the HSET command storing the highlight.
+func (r *redisHighlightRepository) encode(highlight models.Highlight) ([]string, error) {
+	contents, err := json.Marshal(highlight)
+	if err != nil {
+		return nil, err
+	}
+	return []string{"HSET", r.key, highlight.Id, string(contents)}, nil
+}
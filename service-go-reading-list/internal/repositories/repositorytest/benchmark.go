@@ -0,0 +1,258 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+)
+
+// NewBenchmarkRepository returns a new repository holding only the initial
+// data, which should be released with b.Cleanup.
+type NewBenchmarkRepository func(b *testing.B, initialData []models.Book) models.BookRepository
+
+// BenchmarkSizes are the numbers of books the repositories are benchmarked
+// with.
+var BenchmarkSizes = []int{100, 1000, 10000}
+
+// benchmarkAuthors is how many authors the books of the benchmarks share.
+const benchmarkAuthors = 50
+
+// BenchmarkBooks returns n books spread over the statuses and over
+// benchmarkAuthors authors, added a minute apart.
+func BenchmarkBooks(n int) []models.Book {
+	statuses := []models.ReadStatus{models.ReadStatusToRead, models.ReadStatusReading, models.ReadStatusRead}
+	first := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	books := make([]models.Book, n)
+	for i := range books {
+		createdAt := first.Add(time.Duration(i) * time.Minute)
+		books[i] = models.Book{
+			Id:        fmt.Sprintf("book-%06d", i),
+			Title:     fmt.Sprintf("Book %d", i),
+			Author:    fmt.Sprintf("Author %d", i%benchmarkAuthors),
+			Status:    statuses[i%len(statuses)],
+			Tags:      []string{"benchmark"},
+			CreatedAt: &createdAt,
+			UpdatedAt: &createdAt,
+		}
+	}
+	return books
+}
+
+// addedBook returns a book to add, which is added after the books of
+// BenchmarkBooks as the service sets the time the books are added.
+func addedBook(id string) models.Book {
+	now := time.Now().UTC()
+	return models.Book{Id: id, Title: "Added Book", Author: "Author", CreatedAt: &now, UpdatedAt: &now}
+}
+
+// BenchmarkBookRepository benchmarks the operations of the repositories
+// returned by newRepository, for each of BenchmarkSizes. The indexes and the
+// pages are benchmarked too when the repositories implement models.BookIndex
+// and models.BookPager. The Parallel benchmarks send the operations from
+// GOMAXPROCS goroutines, to measure how the repositories behave under
+// contention.
+func BenchmarkBookRepository(b *testing.B, newRepository NewBenchmarkRepository) {
+	benchmarks := []struct {
+		name      string
+		benchmark func(b *testing.B, repo models.BookRepository, books []models.Book)
+	}{
+		{"Add", benchmarkAdd},
+		{"GetById", benchmarkGetById},
+		{"Update", benchmarkUpdate},
+		{"List", benchmarkList},
+		{"ListByStatus", benchmarkListByStatus},
+		{"ListPage", benchmarkListPage},
+		{"DeleteRestore", benchmarkDeleteRestore},
+		{"ParallelReads", benchmarkParallelReads},
+		{"ParallelWrites", benchmarkParallelWrites},
+		{"ParallelMixed", benchmarkParallelMixed},
+	}
+	for _, size := range BenchmarkSizes {
+		books := BenchmarkBooks(size)
+		for _, bm := range benchmarks {
+			b.Run(fmt.Sprintf("Books=%d/%s", size, bm.name), func(b *testing.B) {
+				repo := newRepository(b, books)
+				b.ReportAllocs()
+				b.ResetTimer()
+				bm.benchmark(b, repo, books)
+			})
+		}
+	}
+}
+
+func benchmarkAdd(b *testing.B, repo models.BookRepository, _ []models.Book) {
+	ctx := context.Background()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.Add(ctx, addedBook(fmt.Sprintf("added-%d", i))); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkGetById(b *testing.B, repo models.BookRepository, books []models.Book) {
+	ctx := context.Background()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.GetById(ctx, books[i%len(books)].Id); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkUpdate(b *testing.B, repo models.BookRepository, books []models.Book) {
+	ctx := context.Background()
+	for i := 0; i < b.N; i++ {
+		book := books[i%len(books)]
+		book.Title = fmt.Sprintf("Updated Book %d", i)
+		if _, err := repo.Update(ctx, book); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkList(b *testing.B, repo models.BookRepository, books []models.Book) {
+	ctx := context.Background()
+	for i := 0; i < b.N; i++ {
+		listed, err := repo.List(ctx)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(listed) != len(books) {
+			b.Fatalf("listed %d books instead of %d", len(listed), len(books))
+		}
+	}
+}
+
+// benchmarkListByStatus lists the books with a status, with the index of the
+// repository or else by filtering all the books, as the controllers do.
+func benchmarkListByStatus(b *testing.B, repo models.BookRepository, _ []models.Book) {
+	ctx := context.Background()
+	index, indexed := repo.(models.BookIndex)
+	filter := models.BookFilter{Status: models.ReadStatusReading}
+	for i := 0; i < b.N; i++ {
+		if indexed {
+			if _, err := index.ListByStatus(ctx, filter.Status); err != nil {
+				b.Fatal(err)
+			}
+			continue
+		}
+		books, err := repo.List(ctx)
+		if err != nil {
+			b.Fatal(err)
+		}
+		selected := 0
+		for _, book := range books {
+			if filter.Matches(book) {
+				selected++
+			}
+		}
+		if selected == 0 {
+			b.Fatal("no book is selected")
+		}
+	}
+}
+
+// benchmarkListPage reads a page from the middle of the books, with the
+// pager of the repository or else by sorting all the books, as the
+// controllers do.
+func benchmarkListPage(b *testing.B, repo models.BookRepository, books []models.Book) {
+	ctx := context.Background()
+	pager, paged := repo.(models.BookPager)
+	offset, limit := len(books)/2, 20
+	for i := 0; i < b.N; i++ {
+		if paged {
+			if _, _, err := pager.ListPage(ctx, offset, limit); err != nil {
+				b.Fatal(err)
+			}
+			continue
+		}
+		listed, err := repo.List(ctx)
+		if err != nil {
+			b.Fatal(err)
+		}
+		sort.Slice(listed, func(i, j int) bool {
+			return listed[i].AddedBefore(listed[j])
+		})
+		_ = listed[offset:min(offset+limit, len(listed))]
+	}
+}
+
+func benchmarkDeleteRestore(b *testing.B, repo models.BookRepository, books []models.Book) {
+	ctx := context.Background()
+	for i := 0; i < b.N; i++ {
+		id := books[i%len(books)].Id
+		if _, err := repo.DeleteById(ctx, id); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := repo.RestoreById(ctx, id); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkParallelReads(b *testing.B, repo models.BookRepository, books []models.Book) {
+	ctx := context.Background()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			if _, err := repo.GetById(ctx, books[i%len(books)].Id); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+func benchmarkParallelWrites(b *testing.B, repo models.BookRepository, books []models.Book) {
+	ctx := context.Background()
+	var workers atomic.Int64
+	b.RunParallel(func(pb *testing.PB) {
+		// The goroutines start from different books, so that they only
+		// contend on the locks they share.
+		offset := int(workers.Add(1)) * len(books) / 8
+		for i := 0; pb.Next(); i++ {
+			book := books[(offset+i)%len(books)]
+			book.Title = fmt.Sprintf("Updated Book %d", i)
+			if _, err := repo.Update(ctx, book); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+// benchmarkParallelMixed sends the operations in the proportions of a read
+// mostly API: 80% of gets, 10% of updates, 5% of adds and 5% of lists.
+func benchmarkParallelMixed(b *testing.B, repo models.BookRepository, books []models.Book) {
+	ctx := context.Background()
+	var workers atomic.Int64
+	b.RunParallel(func(pb *testing.PB) {
+		// The ids of the added books are unique to the goroutine.
+		worker := workers.Add(1)
+		for i := 0; pb.Next(); i++ {
+			book := books[i%len(books)]
+			var err error
+			switch i % 20 {
+			case 0, 1:
+				book.Title = fmt.Sprintf("Updated Book %d", i)
+				_, err = repo.Update(ctx, book)
+			case 2:
+				_, err = repo.Add(ctx, addedBook(fmt.Sprintf("added-%d-%d", worker, i)))
+			case 3:
+				_, err = repo.List(ctx)
+			default:
+				_, err = repo.GetById(ctx, book.Id)
+			}
+			if err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,519 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package repositorytest provides a conformance suite that every
+// implementation of models.BookRepository is expected to pass, so that the
+// storages, and the test doubles of the controllers, behave the same. Run it
+// with -race to check that the implementations are safe for concurrent use.
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories"
+)
+
+// NewBookRepository returns a new repository holding only the initial data.
+// It is called once for every test of the suite, so that the tests do not
+// depend on each other, and should release the repository with t.Cleanup.
+type NewBookRepository func(t *testing.T, initialData []models.Book) models.BookRepository
+
+var (
+	dune       = models.Book{Id: "dune", Title: "Dune", Author: "Frank Herbert", Status: models.ReadStatusReading}
+	emma       = models.Book{Id: "emma", Title: "Emma", Author: "Jane Austen", Status: models.ReadStatusRead}
+	persuasion = models.Book{Id: "persuasion", Title: "Persuasion", Author: "Jane Austen", Status: models.ReadStatusToRead}
+)
+
+// initialData is the data every test starts with.
+func initialData() []models.Book {
+	return []models.Book{dune, emma, persuasion}
+}
+
+// TestBookRepository runs the conformance suite against the repositories
+// returned by newRepository. The transactions, the indexes and the pages are
+// tested too when the repositories implement models.BookTransactor,
+// models.BookIndex and models.BookPager.
+func TestBookRepository(t *testing.T, newRepository NewBookRepository) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, repo models.BookRepository)
+	}{
+		{"InitialData", testInitialData},
+		{"Add", testAdd},
+		{"Update", testUpdate},
+		{"UpdateById", testUpdateById},
+		{"DeleteById", testDeleteById},
+		{"RestoreById", testRestoreById},
+		{"PurgeById", testPurgeById},
+		{"PurgeDeletedBefore", testPurgeDeletedBefore},
+		{"Transaction", testTransaction},
+		{"Index", testIndex},
+		{"Page", testPage},
+		{"Concurrency", testConcurrency},
+		{"ContextCanceled", testContextCanceled},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.test(t, newRepository(t, initialData()))
+		})
+	}
+}
+
+// assertSentinel asserts that err is the sentinel error wrapped with the
+// operation that failed, as the controllers map the sentinels to statuses.
+func assertSentinel(t *testing.T, err, sentinel error) {
+	t.Helper()
+	if assert.ErrorIs(t, err, sentinel) {
+		assert.NotEqual(t, sentinel.Error(), err.Error(), "the error should say which operation failed")
+	}
+}
+
+func bookIds(books []models.Book) []string {
+	ids := make([]string, len(books))
+	for i, book := range books {
+		ids[i] = book.Id
+	}
+	return ids
+}
+
+func testInitialData(t *testing.T, repo models.BookRepository) {
+	ctx := context.Background()
+	books, err := repo.List(ctx)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, initialData(), books)
+	for _, want := range initialData() {
+		book, err := repo.GetById(ctx, want.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, want, book)
+	}
+	deleted, err := repo.ListDeleted(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, deleted)
+
+	_, err = repo.GetById(ctx, "missing")
+	assertSentinel(t, err, repositories.ErrRecordNotFound)
+}
+
+func testAdd(t *testing.T, repo models.BookRepository) {
+	ctx := context.Background()
+	book := models.Book{Id: "middlemarch", Title: "Middlemarch", Author: "George Eliot", Status: models.ReadStatusToRead, Tags: []string{"classic"}, Rating: 4}
+	added, err := repo.Add(ctx, book)
+	assert.NoError(t, err)
+	assert.Equal(t, book, added)
+	got, err := repo.GetById(ctx, book.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, book, got)
+
+	// An id is generated for the books without one.
+	generated, err := repo.Add(ctx, models.Book{Title: "Villette"})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, generated.Id)
+	got, err = repo.GetById(ctx, generated.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Villette", got.Title)
+
+	_, err = repo.Add(ctx, models.Book{Id: dune.Id, Title: "Dune Messiah"})
+	assertSentinel(t, err, repositories.ErrRecordAlreadyExists)
+	got, err = repo.GetById(ctx, dune.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, dune, got)
+
+	// The books in the trash keep their ids.
+	_, err = repo.DeleteById(ctx, emma.Id)
+	require.NoError(t, err)
+	_, err = repo.Add(ctx, emma)
+	assertSentinel(t, err, repositories.ErrRecordAlreadyExists)
+
+	books, err := repo.List(ctx)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{dune.Id, persuasion.Id, book.Id, generated.Id}, bookIds(books))
+}
+
+func testUpdate(t *testing.T, repo models.BookRepository) {
+	ctx := context.Background()
+	book := dune
+	book.Title = "Dune Messiah"
+	book.Status = models.ReadStatusRead
+	book.Tags = []string{"science fiction"}
+	updated, err := repo.Update(ctx, book)
+	assert.NoError(t, err)
+	assert.Equal(t, book, updated)
+	got, err := repo.GetById(ctx, dune.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, book, got)
+
+	_, err = repo.Update(ctx, models.Book{Id: "missing", Title: "Missing"})
+	assertSentinel(t, err, repositories.ErrRecordNotFound)
+	_, err = repo.GetById(ctx, "missing")
+	assertSentinel(t, err, repositories.ErrRecordNotFound)
+
+	// The books in the trash are not updated.
+	_, err = repo.DeleteById(ctx, emma.Id)
+	require.NoError(t, err)
+	_, err = repo.Update(ctx, emma)
+	assertSentinel(t, err, repositories.ErrRecordNotFound)
+}
+
+func testUpdateById(t *testing.T, repo models.BookRepository) {
+	updater, ok := repo.(models.BookUpdater)
+	if !ok {
+		t.Skip("the repository does not update the books by id")
+	}
+	ctx := context.Background()
+	updated, err := updater.UpdateById(ctx, dune.Id, func(current models.Book) (models.Book, error) {
+		assert.Equal(t, dune, current)
+		current.Id = "another"
+		current.Rating = 5
+		return current, nil
+	})
+	assert.NoError(t, err)
+	book := dune
+	book.Rating = 5
+	assert.Equal(t, book, updated)
+	got, err := repo.GetById(ctx, dune.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, book, got)
+
+	// Nothing is updated when fn fails.
+	errUpdate := errors.New("update failed")
+	_, err = updater.UpdateById(ctx, dune.Id, func(current models.Book) (models.Book, error) {
+		return models.Book{Id: dune.Id, Title: "Dune Messiah"}, errUpdate
+	})
+	assertSentinel(t, err, errUpdate)
+	got, err = repo.GetById(ctx, dune.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, book, got)
+
+	fail := func(models.Book) (models.Book, error) {
+		t.Error("fn is called for a book that is not found")
+		return models.Book{}, nil
+	}
+	_, err = updater.UpdateById(ctx, "missing", fail)
+	assertSentinel(t, err, repositories.ErrRecordNotFound)
+	_, err = repo.DeleteById(ctx, emma.Id)
+	require.NoError(t, err)
+	_, err = updater.UpdateById(ctx, emma.Id, fail)
+	assertSentinel(t, err, repositories.ErrRecordNotFound)
+}
+
+func testDeleteById(t *testing.T, repo models.BookRepository) {
+	ctx := context.Background()
+	deleted, err := repo.DeleteById(ctx, dune.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, dune.Id, deleted.Id)
+	assert.Equal(t, dune.Title, deleted.Title)
+	assert.True(t, deleted.IsDeleted())
+
+	_, err = repo.GetById(ctx, dune.Id)
+	assertSentinel(t, err, repositories.ErrRecordNotFound)
+	books, err := repo.List(ctx)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{emma.Id, persuasion.Id}, bookIds(books))
+	trashed, err := repo.ListDeleted(ctx)
+	assert.NoError(t, err)
+	require.Len(t, trashed, 1)
+	assert.Equal(t, dune.Id, trashed[0].Id)
+	assert.True(t, trashed[0].IsDeleted())
+
+	_, err = repo.DeleteById(ctx, dune.Id)
+	assertSentinel(t, err, repositories.ErrRecordNotFound)
+	_, err = repo.DeleteById(ctx, "missing")
+	assertSentinel(t, err, repositories.ErrRecordNotFound)
+}
+
+func testRestoreById(t *testing.T, repo models.BookRepository) {
+	ctx := context.Background()
+	_, err := repo.DeleteById(ctx, dune.Id)
+	require.NoError(t, err)
+	restored, err := repo.RestoreById(ctx, dune.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, dune, restored)
+	got, err := repo.GetById(ctx, dune.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, dune, got)
+	trashed, err := repo.ListDeleted(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, trashed)
+
+	// Only the books in the trash are restored.
+	_, err = repo.RestoreById(ctx, dune.Id)
+	assertSentinel(t, err, repositories.ErrRecordNotFound)
+	_, err = repo.RestoreById(ctx, "missing")
+	assertSentinel(t, err, repositories.ErrRecordNotFound)
+}
+
+func testPurgeById(t *testing.T, repo models.BookRepository) {
+	ctx := context.Background()
+	// Both the books in the trash and the others are purged.
+	_, err := repo.DeleteById(ctx, dune.Id)
+	require.NoError(t, err)
+	purged, err := repo.PurgeById(ctx, dune.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, dune.Id, purged.Id)
+	purged, err = repo.PurgeById(ctx, emma.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, emma, purged)
+
+	books, err := repo.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{persuasion.Id}, bookIds(books))
+	trashed, err := repo.ListDeleted(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, trashed)
+	_, err = repo.RestoreById(ctx, dune.Id)
+	assertSentinel(t, err, repositories.ErrRecordNotFound)
+	_, err = repo.PurgeById(ctx, dune.Id)
+	assertSentinel(t, err, repositories.ErrRecordNotFound)
+
+	// The ids of the purged books can be used again.
+	_, err = repo.Add(ctx, emma)
+	assert.NoError(t, err)
+}
+
+func testPurgeDeletedBefore(t *testing.T, repo models.BookRepository) {
+	ctx := context.Background()
+	for _, id := range []string{dune.Id, emma.Id} {
+		_, err := repo.DeleteById(ctx, id)
+		require.NoError(t, err)
+	}
+	purged, err := repo.PurgeDeletedBefore(ctx, time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, purged)
+	trashed, err := repo.ListDeleted(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, trashed, 2)
+
+	purged, err = repo.PurgeDeletedBefore(ctx, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, bookIds(trashed), bookIds(purged))
+	for _, book := range purged {
+		assert.True(t, book.IsDeleted())
+	}
+	trashed, err = repo.ListDeleted(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, trashed)
+	// The books that are not in the trash are kept.
+	books, err := repo.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Book{persuasion}, books)
+}
+
+func testTransaction(t *testing.T, repo models.BookRepository) {
+	transactor, ok := repo.(models.BookTransactor)
+	if !ok {
+		t.Skip("the repository does not support transactions")
+	}
+	ctx := context.Background()
+
+	err := transactor.RunInTransaction(ctx, func(tx models.BookRepository) error {
+		if _, err := tx.Update(ctx, models.Book{Id: dune.Id, Title: dune.Title, Status: models.ReadStatusRead}); err != nil {
+			return err
+		}
+		if _, err := tx.DeleteById(ctx, emma.Id); err != nil {
+			return err
+		}
+		// The changes are visible inside the transaction.
+		_, err := tx.GetById(ctx, emma.Id)
+		if !errors.Is(err, repositories.ErrRecordNotFound) {
+			return fmt.Errorf("the book deleted in the transaction was found: %v", err)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	book, err := repo.GetById(ctx, dune.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ReadStatusRead, book.Status)
+	_, err = repo.GetById(ctx, emma.Id)
+	assertSentinel(t, err, repositories.ErrRecordNotFound)
+
+	// Nothing is applied when the transaction fails.
+	errRollback := errors.New("rollback")
+	err = transactor.RunInTransaction(ctx, func(tx models.BookRepository) error {
+		if _, err := tx.Add(ctx, models.Book{Id: "villette", Title: "Villette"}); err != nil {
+			return err
+		}
+		if _, err := tx.PurgeById(ctx, persuasion.Id); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	assert.ErrorIs(t, err, errRollback)
+	_, err = repo.GetById(ctx, "villette")
+	assertSentinel(t, err, repositories.ErrRecordNotFound)
+	book, err = repo.GetById(ctx, persuasion.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, persuasion, book)
+}
+
+func testIndex(t *testing.T, repo models.BookRepository) {
+	index, ok := repo.(models.BookIndex)
+	if !ok {
+		t.Skip("the repository does not index the books")
+	}
+	ctx := context.Background()
+	byStatus := func(status models.ReadStatus) []string {
+		books, err := index.ListByStatus(ctx, status)
+		require.NoError(t, err)
+		return bookIds(books)
+	}
+	byAuthor := func(author string) []string {
+		books, err := index.ListByAuthor(ctx, author)
+		require.NoError(t, err)
+		return bookIds(books)
+	}
+
+	assert.ElementsMatch(t, []string{dune.Id}, byStatus(models.ReadStatusReading))
+	assert.ElementsMatch(t, []string{emma.Id, persuasion.Id}, byAuthor(" JANE austen"))
+
+	// The indexes follow the changes and leave out the books in the trash.
+	_, err := repo.Update(ctx, models.Book{Id: dune.Id, Title: dune.Title, Author: "Jane Austen", Status: models.ReadStatusRead})
+	require.NoError(t, err)
+	_, err = repo.DeleteById(ctx, emma.Id)
+	require.NoError(t, err)
+	assert.Empty(t, byStatus(models.ReadStatusReading))
+	assert.ElementsMatch(t, []string{dune.Id}, byStatus(models.ReadStatusRead))
+	assert.Empty(t, byAuthor(dune.Author))
+	assert.ElementsMatch(t, []string{dune.Id, persuasion.Id}, byAuthor("Jane Austen"))
+}
+
+func testPage(t *testing.T, repo models.BookRepository) {
+	pager, ok := repo.(models.BookPager)
+	if !ok {
+		t.Skip("the repository does not page the books")
+	}
+	ctx := context.Background()
+	page := func(offset, limit int) ([]string, int) {
+		books, total, err := pager.ListPage(ctx, offset, limit)
+		require.NoError(t, err)
+		return bookIds(books), total
+	}
+	assertPage := func(offset, limit int, want []string, wantTotal int) {
+		t.Helper()
+		ids, total := page(offset, limit)
+		assert.Equal(t, want, ids)
+		assert.Equal(t, wantTotal, total)
+	}
+
+	// The books added with a time come after the initial data, which is
+	// ordered by id.
+	first := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+	_, err := repo.Add(ctx, models.Book{Id: "villette", Title: "Villette", CreatedAt: &second})
+	require.NoError(t, err)
+	_, err = repo.Add(ctx, models.Book{Id: "middlemarch", Title: "Middlemarch", CreatedAt: &first})
+	require.NoError(t, err)
+	assertPage(0, 2, []string{dune.Id, emma.Id}, 5)
+	assertPage(2, 2, []string{persuasion.Id, "middlemarch"}, 5)
+	assertPage(4, 2, []string{"villette"}, 5)
+	assertPage(5, 2, []string{}, 5)
+	// The pages are cut from the books in the order of List.
+	books, err := repo.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{dune.Id, emma.Id, persuasion.Id, "middlemarch", "villette"}, bookIds(books))
+
+	// The pages follow the changes and leave out the books in the trash.
+	_, err = repo.DeleteById(ctx, emma.Id)
+	require.NoError(t, err)
+	_, err = repo.PurgeById(ctx, dune.Id)
+	require.NoError(t, err)
+	earlier := first.Add(-time.Hour)
+	_, err = repo.Update(ctx, models.Book{Id: "villette", Title: "Villette", CreatedAt: &earlier})
+	require.NoError(t, err)
+	assertPage(0, 10, []string{persuasion.Id, "villette", "middlemarch"}, 3)
+	_, err = repo.RestoreById(ctx, emma.Id)
+	require.NoError(t, err)
+	assertPage(0, 2, []string{emma.Id, persuasion.Id}, 4)
+}
+
+func testConcurrency(t *testing.T, repo models.BookRepository) {
+	ctx := context.Background()
+	const workers = 8
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	added := make(map[string]int)
+	deleted := 0
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Every worker adds its own book and races with the others to
+			// add the same book and to delete the same book.
+			_, err := repo.Add(ctx, models.Book{Id: fmt.Sprintf("book-%d", i), Title: "Concurrent Book"})
+			assert.NoError(t, err)
+			_, sharedErr := repo.Add(ctx, models.Book{Id: "shared", Title: "Shared Book"})
+			_, deleteErr := repo.DeleteById(ctx, dune.Id)
+			_, err = repo.Update(ctx, models.Book{Id: emma.Id, Title: fmt.Sprintf("Emma %d", i)})
+			assert.NoError(t, err)
+			_, err = repo.GetById(ctx, persuasion.Id)
+			assert.NoError(t, err)
+			_, err = repo.List(ctx)
+			assert.NoError(t, err)
+
+			lock.Lock()
+			defer lock.Unlock()
+			if sharedErr == nil {
+				added["shared"]++
+			} else {
+				assertSentinel(t, sharedErr, repositories.ErrRecordAlreadyExists)
+			}
+			if deleteErr == nil {
+				deleted++
+			} else {
+				assertSentinel(t, deleteErr, repositories.ErrRecordNotFound)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, added["shared"], "the shared book should be added once")
+	assert.Equal(t, 1, deleted, "the book should be deleted once")
+	books, err := repo.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, books, workers+3)
+}
+
+func testContextCanceled(t *testing.T, repo models.BookRepository) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.Add(ctx, models.Book{Id: "villette", Title: "Villette"})
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = repo.Update(ctx, models.Book{Id: dune.Id, Title: "Dune Messiah"})
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = repo.List(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = repo.GetById(ctx, dune.Id)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = repo.DeleteById(ctx, dune.Id)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = repo.ListDeleted(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = repo.RestoreById(ctx, dune.Id)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = repo.PurgeById(ctx, dune.Id)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = repo.PurgeDeletedBefore(ctx, time.Now())
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// The calls past the deadline of the context fail too.
+	expired, cancelExpired := context.WithTimeout(context.Background(), -time.Second)
+	defer cancelExpired()
+	_, err = repo.Add(expired, models.Book{Id: "villette", Title: "Villette"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	_, err = repo.GetById(expired, dune.Id)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Nothing is changed by the canceled calls.
+	books, err := repo.List(context.Background())
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, initialData(), books)
+}
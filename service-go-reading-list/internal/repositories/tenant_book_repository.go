@@ -0,0 +1,552 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/tenancy"
+)
+
+// tenantSeparator cannot be in the id of a book, which comes from a path
+// segment.
+const tenantSeparator = "/"
+
+type TenantBookRepositoryConfig struct {
+	// InitialData returns the books a tenant starts with. The tenant is
+	// given them again on its next use when it fails.
+	InitialData func(tenant string) ([]models.Book, error)
+	// Quota returns how many books, including the ones in the trash, a
+	// tenant can have. The books are not limited when it returns zero.
+	Quota func(tenant string) int
+	// Shared is set when other replicas change the underlying repository
+	// too. The numbers of books of the tenants are then counted in it on
+	// every add, rather than kept.
+	Shared bool
+}
+
+// TenantBookRepository keeps the books of each tenant apart, by the tenant
+// of the context. The books of the default tenant are those of the
+// underlying repository, so that enabling multi-tenancy keeps the books of a
+// single-tenant deployment, and those of the other tenants are stored apart
+// by it, as a models.BookTenantStore.
+type TenantBookRepository struct {
+	// books are the books of the default tenant, or those of the tenant of
+	// the transaction.
+	books  models.BookRepository
+	store  models.BookTenantStore
+	config TenantBookRepositoryConfig
+	// tenants are the books of the tenants that were used.
+	tenants *tenantRegistry
+	// inTransaction is set on the repository of a transaction, which runs
+	// with the books of its tenant locked.
+	inTransaction bool
+}
+
+type tenantRegistry struct {
+	lock    sync.Mutex
+	tenants map[string]*tenantBooks
+	// counts are the numbers of books, including the ones in the trash, of
+	// the tenants with a quota, once they are counted.
+	counts map[string]int
+	// adding are the locks that serialise the changes to the number of
+	// books of the tenants with a quota, so that a book is only added if it
+	// fits.
+	adding map[string]*sync.Mutex
+}
+
+// tenantBooks are the books of a tenant. Its lock is held while they are
+// opened or the tenant is deleted, so that the other tenants are not
+// blocked meanwhile.
+type tenantBooks struct {
+	lock sync.Mutex
+	// books are nil until the tenant is used, and again once it is deleted.
+	books models.BookRepository
+}
+
+// NewTenantBookRepository wraps the books repository, which stores the books
+// of each tenant apart. A tenant is given its initial data when its books
+// are first stored, which is again the case after it is deleted.
+func NewTenantBookRepository(books models.BookRepository, cfg TenantBookRepositoryConfig) (*TenantBookRepository, error) {
+	store, ok := books.(models.BookTenantStore)
+	if !ok {
+		return nil, fmt.Errorf("tenantBookRepository:New: the underlying repository does not store the books of the tenants apart")
+	}
+	// The default tenant got its initial data with the underlying repository.
+	tenants := map[string]*tenantBooks{tenancy.DefaultTenant: {books: books}}
+	return &TenantBookRepository{
+		books:   books,
+		store:   store,
+		config:  cfg,
+		tenants: &tenantRegistry{tenants: tenants, counts: map[string]int{}, adding: map[string]*sync.Mutex{}},
+	}, nil
+}
+
+func (r *TenantBookRepository) Add(ctx context.Context, book models.Book) (models.Book, error) {
+	tenant, books, err := r.tenant(ctx)
+	if err != nil {
+		return models.Book{}, err
+	}
+	unlock := r.lockCount(tenant)
+	defer unlock()
+	if quota := r.quota(tenant); quota > 0 {
+		count, err := r.count(ctx, tenant, books)
+		if err != nil {
+			return models.Book{}, fmt.Errorf("tenantBookRepository:Add: %w", err)
+		}
+		if count >= quota {
+			return models.Book{}, fmt.Errorf("tenantBookRepository:Add: %w", ErrQuotaExceeded)
+		}
+	}
+	if book.Id == "" {
+		book.Id = uuid.NewString()
+	} else if strings.Contains(book.Id, tenantSeparator) {
+		return models.Book{}, fmt.Errorf("tenantBookRepository:Add: %w", ErrInvalidId)
+	}
+	book, err = books.Add(ctx, book)
+	if err == nil {
+		r.changeCount(tenant, 1)
+	}
+	return book, err
+}
+
+func (r *TenantBookRepository) Update(ctx context.Context, updatedBook models.Book) (models.Book, error) {
+	_, books, err := r.tenant(ctx)
+	if err != nil {
+		return models.Book{}, err
+	}
+	return books.Update(ctx, updatedBook)
+}
+
+// UpdateById updates the book of the tenant of the context atomically when
+// its books are a models.BookUpdater or those of a transaction.
+func (r *TenantBookRepository) UpdateById(ctx context.Context, id string, fn func(current models.Book) (models.Book, error)) (models.Book, error) {
+	_, books, err := r.tenant(ctx)
+	if err != nil {
+		return models.Book{}, err
+	}
+	if updater, ok := books.(models.BookUpdater); ok {
+		return updater.UpdateById(ctx, id, fn)
+	}
+	book, err := updateById(ctx, books, id, fn)
+	if err != nil {
+		return models.Book{}, fmt.Errorf("tenantBookRepository:UpdateById: %w", err)
+	}
+	return book, nil
+}
+
+func (r *TenantBookRepository) List(ctx context.Context) ([]models.Book, error) {
+	_, books, err := r.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return books.List(ctx)
+}
+
+// ListPage returns a page of the books of the tenant of the context, in the
+// order they were added. The page is read from the books of the tenant when
+// they are a models.BookPager, and cut from all of them otherwise.
+func (r *TenantBookRepository) ListPage(ctx context.Context, offset, limit int) ([]models.Book, int, error) {
+	_, books, err := r.tenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	if pager, ok := books.(models.BookPager); ok {
+		return pager.ListPage(ctx, offset, limit)
+	}
+	list, err := books.List(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].AddedBefore(list[j])
+	})
+	start := min(max(offset, 0), len(list))
+	return list[start:min(start+max(limit, 0), len(list))], len(list), nil
+}
+
+func (r *TenantBookRepository) GetById(ctx context.Context, id string) (models.Book, error) {
+	_, books, err := r.tenant(ctx)
+	if err != nil {
+		return models.Book{}, err
+	}
+	return books.GetById(ctx, id)
+}
+
+func (r *TenantBookRepository) DeleteById(ctx context.Context, id string) (models.Book, error) {
+	_, books, err := r.tenant(ctx)
+	if err != nil {
+		return models.Book{}, err
+	}
+	return books.DeleteById(ctx, id)
+}
+
+func (r *TenantBookRepository) ListDeleted(ctx context.Context) ([]models.Book, error) {
+	_, books, err := r.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return books.ListDeleted(ctx)
+}
+
+func (r *TenantBookRepository) RestoreById(ctx context.Context, id string) (models.Book, error) {
+	_, books, err := r.tenant(ctx)
+	if err != nil {
+		return models.Book{}, err
+	}
+	return books.RestoreById(ctx, id)
+}
+
+func (r *TenantBookRepository) PurgeById(ctx context.Context, id string) (models.Book, error) {
+	tenant, books, err := r.tenant(ctx)
+	if err != nil {
+		return models.Book{}, err
+	}
+	unlock := r.lockCount(tenant)
+	defer unlock()
+	book, err := books.PurgeById(ctx, id)
+	if err == nil {
+		r.changeCount(tenant, -1)
+	}
+	return book, err
+}
+
+func (r *TenantBookRepository) PurgeDeletedBefore(ctx context.Context, before time.Time) ([]models.Book, error) {
+	tenant, books, err := r.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	unlock := r.lockCount(tenant)
+	defer unlock()
+	purged, err := books.PurgeDeletedBefore(ctx, before)
+	if err != nil {
+		// The books purged before the error are counted again.
+		r.forgetCount(tenant)
+		return purged, err
+	}
+	r.changeCount(tenant, -len(purged))
+	return purged, nil
+}
+
+// RunInTransaction runs fn with the books of the tenant of the context in a
+// transaction of their repository. When the tenant has a quota, its books
+// are not added or purged outside the transaction until it completes.
+func (r *TenantBookRepository) RunInTransaction(ctx context.Context, fn func(tx models.BookRepository) error) error {
+	tenant, books, err := r.tenant(ctx)
+	if err != nil {
+		return err
+	}
+	transactor, ok := books.(models.BookTransactor)
+	if !ok {
+		return fmt.Errorf("tenantBookRepository:RunInTransaction: the underlying repository does not support transactions")
+	}
+	unlock := r.lockCount(tenant)
+	defer unlock()
+	// The books the transaction adds or purges are counted again once it is
+	// committed or rolled back.
+	defer r.forgetCount(tenant)
+	return transactor.RunInTransaction(ctx, func(tx models.BookRepository) error {
+		return fn(&TenantBookRepository{books: tx, store: r.store, config: r.config, tenants: r.tenants, inTransaction: true})
+	})
+}
+
+func (r *TenantBookRepository) ListByStatus(ctx context.Context, status models.ReadStatus) ([]models.Book, error) {
+	return r.listIndexed(ctx, models.BookFilter{Status: status}, func(index models.BookIndex) ([]models.Book, error) {
+		return index.ListByStatus(ctx, status)
+	})
+}
+
+func (r *TenantBookRepository) ListByAuthor(ctx context.Context, author string) ([]models.Book, error) {
+	return r.listIndexed(ctx, models.BookFilter{Author: author}, func(index models.BookIndex) ([]models.Book, error) {
+		return index.ListByAuthor(ctx, author)
+	})
+}
+
+// listIndexed returns the books of the tenant of the context that match the
+// filter, using the index of its books if they have one.
+func (r *TenantBookRepository) listIndexed(ctx context.Context, filter models.BookFilter, list func(index models.BookIndex) ([]models.Book, error)) ([]models.Book, error) {
+	_, books, err := r.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if index, ok := books.(models.BookIndex); ok {
+		return list(index)
+	}
+	all, err := books.List(ctx)
+	var matching []models.Book
+	for _, book := range all {
+		if filter.Matches(book) {
+			matching = append(matching, book)
+		}
+	}
+	return matching, err
+}
+
+// Backup writes the copy of the books of all the tenants, if the underlying
+// repository can write one.
+func (r *TenantBookRepository) Backup(ctx context.Context, w io.Writer) (int64, error) {
+	backuper, ok := r.books.(models.BookBackuper)
+	if !ok {
+		return 0, fmt.Errorf("tenantBookRepository:Backup: %w", ErrNotSupported)
+	}
+	return backuper.Backup(ctx, w)
+}
+
+// ListTenants returns the tenants that have books, ordered by their ids.
+func (r *TenantBookRepository) ListTenants(ctx context.Context) ([]models.Tenant, error) {
+	ids, err := r.store.ListTenantIds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tenantBookRepository:ListTenants: %w", err)
+	}
+	var list []models.Tenant
+	for _, id := range append([]string{tenancy.DefaultTenant}, ids...) {
+		entry := r.tenants.entry(id)
+		entry.lock.Lock()
+		books, err := r.stored(ctx, id, entry, ids)
+		entry.lock.Unlock()
+		if err != nil {
+			return nil, fmt.Errorf("tenantBookRepository:ListTenants: %w", err)
+		}
+		if books == nil {
+			continue
+		}
+		tenant, err := countTenant(ctx, id, books)
+		if err != nil {
+			return nil, fmt.Errorf("tenantBookRepository:ListTenants: %w", err)
+		}
+		if tenant.Books+tenant.Trashed > 0 {
+			tenant.Quota = r.quota(id)
+			list = append(list, tenant)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Id < list[j].Id })
+	return list, nil
+}
+
+// DeleteTenant purges all the books of the tenant. The tenant is given its
+// initial data again when it is next used.
+func (r *TenantBookRepository) DeleteTenant(ctx context.Context, id string) (models.Tenant, error) {
+	unlock := r.lockCount(id)
+	defer unlock()
+	defer r.forgetCount(id)
+	ids, err := r.store.ListTenantIds(ctx)
+	if err != nil {
+		return models.Tenant{}, fmt.Errorf("tenantBookRepository:DeleteTenant: %w", err)
+	}
+	entry := r.tenants.entry(id)
+	entry.lock.Lock()
+	defer entry.lock.Unlock()
+	books, err := r.stored(ctx, id, entry, ids)
+	if err != nil {
+		return models.Tenant{}, fmt.Errorf("tenantBookRepository:DeleteTenant: %w", err)
+	}
+	if books == nil {
+		return models.Tenant{}, fmt.Errorf("tenantBookRepository:DeleteTenant: %w", ErrRecordNotFound)
+	}
+	tenant, err := countTenant(ctx, id, books)
+	if err != nil {
+		return models.Tenant{}, fmt.Errorf("tenantBookRepository:DeleteTenant: %w", err)
+	}
+	if tenant.Books+tenant.Trashed == 0 {
+		return models.Tenant{}, fmt.Errorf("tenantBookRepository:DeleteTenant: %w", ErrRecordNotFound)
+	}
+	tenant.Quota = r.quota(id)
+	if id == tenancy.DefaultTenant {
+		stored, err := listAll(ctx, books)
+		if err != nil {
+			return models.Tenant{}, fmt.Errorf("tenantBookRepository:DeleteTenant: %w", err)
+		}
+		for _, book := range stored {
+			if _, err := books.PurgeById(ctx, book.Id); err != nil && !errors.Is(err, ErrRecordNotFound) {
+				return models.Tenant{}, fmt.Errorf("tenantBookRepository:DeleteTenant: %w", err)
+			}
+		}
+	} else if err := r.store.DropTenant(ctx, id); err != nil {
+		return models.Tenant{}, fmt.Errorf("tenantBookRepository:DeleteTenant: %w", err)
+	}
+	entry.books = nil
+	return tenant, nil
+}
+
+// tenant returns the tenant of the context and its books, which are opened
+// with its initial data the first time the tenant is used. Only the tenant
+// is locked while they are opened.
+func (r *TenantBookRepository) tenant(ctx context.Context) (string, models.BookRepository, error) {
+	tenant := tenancy.FromContext(ctx)
+	if r.inTransaction {
+		return tenant, r.books, nil
+	}
+	entry := r.tenants.entry(tenant)
+	entry.lock.Lock()
+	defer entry.lock.Unlock()
+	if entry.books == nil {
+		books, err := r.open(ctx, tenant)
+		if err != nil {
+			return "", nil, err
+		}
+		entry.books = books
+	}
+	return tenant, entry.books, nil
+}
+
+// open opens the books of the tenant, which are given its initial data when
+// the store has none of the tenant.
+func (r *TenantBookRepository) open(ctx context.Context, tenant string) (models.BookRepository, error) {
+	var initialData []models.Book
+	if r.config.InitialData != nil {
+		var err error
+		if initialData, err = r.config.InitialData(tenant); err != nil {
+			return nil, fmt.Errorf("tenantBookRepository: failed to load the initial data of the tenant [%s]: %w", tenant, err)
+		}
+	}
+	if tenant != tenancy.DefaultTenant {
+		books, err := r.store.OpenTenant(ctx, tenant, initialData)
+		if err != nil {
+			return nil, fmt.Errorf("tenantBookRepository: failed to open the books of the tenant [%s]: %w", tenant, err)
+		}
+		return books, nil
+	}
+	// The default tenant was deleted, as it got its initial data with the
+	// underlying repository otherwise.
+	for _, book := range initialData {
+		if _, err := r.books.Add(ctx, book); err != nil && !errors.Is(err, ErrRecordAlreadyExists) {
+			return nil, fmt.Errorf("tenantBookRepository: failed to add the initial data of the tenant [%s]: %w", tenant, err)
+		}
+	}
+	return r.books, nil
+}
+
+// stored returns the books of the tenant without giving it its initial
+// data, or nil when the store, whose tenants are ids, has none of the
+// tenant. It is called with the tenant locked.
+func (r *TenantBookRepository) stored(ctx context.Context, tenant string, entry *tenantBooks, ids []string) (models.BookRepository, error) {
+	if entry.books != nil {
+		return entry.books, nil
+	}
+	if tenant == tenancy.DefaultTenant {
+		return r.books, nil
+	}
+	if !slices.Contains(ids, tenant) {
+		return nil, nil
+	}
+	books, err := r.store.OpenTenant(ctx, tenant, nil)
+	if err != nil {
+		return nil, err
+	}
+	entry.books = books
+	return books, nil
+}
+
+// entry returns the books of the tenant, which is registered the first time.
+func (t *tenantRegistry) entry(tenant string) *tenantBooks {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	entry, ok := t.tenants[tenant]
+	if !ok {
+		entry = &tenantBooks{}
+		t.tenants[tenant] = entry
+	}
+	return entry
+}
+
+func (r *TenantBookRepository) quota(tenant string) int {
+	if r.config.Quota == nil {
+		return 0
+	}
+	return r.config.Quota(tenant)
+}
+
+// lockCount locks the number of books of the tenant when it has a quota,
+// and returns the function that unlocks it. The repository of a transaction
+// runs with it locked already.
+func (r *TenantBookRepository) lockCount(tenant string) func() {
+	if r.inTransaction || r.quota(tenant) <= 0 {
+		return func() {}
+	}
+	r.tenants.lock.Lock()
+	lock, ok := r.tenants.adding[tenant]
+	if !ok {
+		lock = &sync.Mutex{}
+		r.tenants.adding[tenant] = lock
+	}
+	r.tenants.lock.Unlock()
+	lock.Lock()
+	return lock.Unlock
+}
+
+// count returns the number of books of the tenant, including the ones in the
+// trash. It is counted in its books the first time and kept from then on,
+// unless the repository is shared or that of a transaction.
+func (r *TenantBookRepository) count(ctx context.Context, tenant string, books models.BookRepository) (int, error) {
+	keep := !r.inTransaction && !r.config.Shared
+	if keep {
+		r.tenants.lock.Lock()
+		count, ok := r.tenants.counts[tenant]
+		r.tenants.lock.Unlock()
+		if ok {
+			return count, nil
+		}
+	}
+	stored, err := listAll(ctx, books)
+	if err != nil {
+		return 0, err
+	}
+	if keep {
+		r.tenants.lock.Lock()
+		r.tenants.counts[tenant] = len(stored)
+		r.tenants.lock.Unlock()
+	}
+	return len(stored), nil
+}
+
+// changeCount adds delta to the number of books of the tenant, if it is kept.
+func (r *TenantBookRepository) changeCount(tenant string, delta int) {
+	r.tenants.lock.Lock()
+	defer r.tenants.lock.Unlock()
+	if count, ok := r.tenants.counts[tenant]; ok && !r.inTransaction {
+		r.tenants.counts[tenant] = count + delta
+	}
+}
+
+// forgetCount drops the number of books of the tenant, which is counted
+// again when it is next needed.
+func (r *TenantBookRepository) forgetCount(tenant string) {
+	r.tenants.lock.Lock()
+	defer r.tenants.lock.Unlock()
+	delete(r.tenants.counts, tenant)
+}
+
+// countTenant returns the tenant with the numbers of its books.
+func countTenant(ctx context.Context, id string, books models.BookRepository) (models.Tenant, error) {
+	active, err := books.List(ctx)
+	if err != nil {
+		return models.Tenant{}, err
+	}
+	deleted, err := books.ListDeleted(ctx)
+	if err != nil {
+		return models.Tenant{}, err
+	}
+	return models.Tenant{Id: id, Books: len(active), Trashed: len(deleted)}, nil
+}
+
+// listAll returns the books of the repository, including the ones in the trash.
+func listAll(ctx context.Context, repo models.BookRepository) ([]models.Book, error) {
+	books, err := repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := repo.ListDeleted(ctx)
+	return append(books, deleted...), err
+}
@@ -0,0 +1,263 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/tenancy"
+)
+
+func TestTenantBookRepository(t *testing.T) {
+	books := NewBookRepository([]models.Book{{Id: "1", Title: "Emma"}})
+	repo, err := NewTenantBookRepository(books, TenantBookRepositoryConfig{
+		InitialData: func(tenant string) ([]models.Book, error) {
+			if tenant == "broken" {
+				return nil, errors.New("unreadable")
+			}
+			return []models.Book{{Id: "hobbit", Title: "The Hobbit"}}, nil
+		},
+		Quota: func(tenant string) int {
+			if tenant == "globex" {
+				return 2
+			}
+			return 0
+		},
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+	acme := tenancy.WithTenant(ctx, "acme")
+	globex := tenancy.WithTenant(ctx, "globex")
+
+	t.Run("DefaultTenant", func(t *testing.T) {
+		// The default tenant keeps the books of the underlying repository.
+		list, err := repo.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Book{{Id: "1", Title: "Emma"}}, list)
+	})
+
+	t.Run("InitialData", func(t *testing.T) {
+		list, err := repo.List(acme)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Book{{Id: "hobbit", Title: "The Hobbit"}}, list)
+		// The books of the tenant are stored apart from those of the default
+		// tenant.
+		_, err = books.GetById(ctx, "hobbit")
+		assert.True(t, errors.Is(err, ErrRecordNotFound))
+		ids, err := books.(models.BookTenantStore).ListTenantIds(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"acme"}, ids)
+
+		// The tenant whose initial data cannot be loaded is not served.
+		_, err = repo.List(tenancy.WithTenant(ctx, "broken"))
+		assert.ErrorContains(t, err, "unreadable")
+	})
+
+	t.Run("Isolation", func(t *testing.T) {
+		added, err := repo.Add(acme, models.Book{Id: "1", Title: "Dune"})
+		assert.NoError(t, err)
+		assert.Equal(t, "1", added.Id)
+
+		book, err := repo.GetById(ctx, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, "Emma", book.Title)
+		book, err = repo.GetById(acme, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, "Dune", book.Title)
+
+		_, err = repo.DeleteById(globex, "1")
+		assert.True(t, errors.Is(err, ErrRecordNotFound))
+		_, err = repo.Update(globex, models.Book{Id: "1", Title: "Persuasion"})
+		assert.True(t, errors.Is(err, ErrRecordNotFound))
+	})
+
+	t.Run("TenantIds", func(t *testing.T) {
+		_, err := repo.GetById(globex, "acme/1")
+		assert.True(t, errors.Is(err, ErrRecordNotFound))
+		_, err = repo.GetById(ctx, "acme/1")
+		assert.True(t, errors.Is(err, ErrRecordNotFound))
+		_, err = repo.Add(globex, models.Book{Id: "acme/2", Title: "Persuasion"})
+		assert.True(t, errors.Is(err, ErrInvalidId))
+	})
+
+	t.Run("Quota", func(t *testing.T) {
+		added, err := repo.Add(globex, models.Book{Title: "Persuasion"})
+		assert.NoError(t, err)
+		// The books in the trash count against the quota.
+		_, err = repo.DeleteById(globex, added.Id)
+		assert.NoError(t, err)
+		_, err = repo.Add(globex, models.Book{Title: "Mansfield Park"})
+		assert.True(t, errors.Is(err, ErrQuotaExceeded))
+		_, err = repo.PurgeById(globex, added.Id)
+		assert.NoError(t, err)
+		_, err = repo.Add(globex, models.Book{Title: "Mansfield Park"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Transaction", func(t *testing.T) {
+		err := repo.RunInTransaction(acme, func(tx models.BookRepository) error {
+			if _, err := tx.Add(acme, models.Book{Id: "2", Title: "Sense and Sensibility"}); err != nil {
+				return err
+			}
+			_, err := tx.Add(acme, models.Book{Id: "1", Title: "Dune"})
+			return err
+		})
+		assert.True(t, errors.Is(err, ErrRecordAlreadyExists))
+		_, err = repo.GetById(acme, "2")
+		assert.True(t, errors.Is(err, ErrRecordNotFound))
+	})
+
+	t.Run("ListPage", func(t *testing.T) {
+		// The pages of every tenant are read from its own books.
+		books, total, err := repo.ListPage(acme, 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Equal(t, []string{"1", "hobbit"}, []string{books[0].Id, books[1].Id})
+		books, total, err = repo.ListPage(ctx, 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Equal(t, []models.Book{{Id: "1", Title: "Emma"}}, books)
+		books, total, err = repo.ListPage(globex, 2, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Empty(t, books)
+	})
+
+	t.Run("ListTenants", func(t *testing.T) {
+		tenants, err := repo.ListTenants(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Tenant{
+			{Id: "acme", Books: 2},
+			{Id: "default", Books: 1},
+			{Id: "globex", Books: 2, Quota: 2},
+		}, tenants)
+	})
+
+	t.Run("DeleteTenant", func(t *testing.T) {
+		tenant, err := repo.DeleteTenant(ctx, "acme")
+		assert.NoError(t, err)
+		assert.Equal(t, models.Tenant{Id: "acme", Books: 2}, tenant)
+		_, err = repo.DeleteTenant(ctx, "acme")
+		assert.True(t, errors.Is(err, ErrRecordNotFound))
+
+		// The tenant starts over with its initial data.
+		list, err := repo.List(acme)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Book{{Id: "hobbit", Title: "The Hobbit"}}, list)
+	})
+}
+
+func TestTenantBookRepositoryQuota(t *testing.T) {
+	for _, shared := range []bool{false, true} {
+		t.Run(fmt.Sprintf("Shared=%v", shared), func(t *testing.T) {
+			repo, err := NewTenantBookRepository(NewBookRepository(nil), TenantBookRepositoryConfig{
+				Quota:  func(tenant string) int { return 5 },
+				Shared: shared,
+			})
+			require.NoError(t, err)
+			acme := tenancy.WithTenant(context.Background(), "acme")
+
+			// The concurrent adds do not exceed the quota.
+			var wg sync.WaitGroup
+			var lock sync.Mutex
+			added := 0
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if _, err := repo.Add(acme, models.Book{Title: "Emma"}); err == nil {
+						lock.Lock()
+						added++
+						lock.Unlock()
+					} else {
+						assert.True(t, errors.Is(err, ErrQuotaExceeded), err)
+					}
+				}()
+			}
+			wg.Wait()
+			assert.Equal(t, 5, added)
+
+			// The books purged in and out of transactions are counted.
+			list, err := repo.List(acme)
+			require.NoError(t, err)
+			err = repo.RunInTransaction(acme, func(tx models.BookRepository) error {
+				if _, err := tx.PurgeById(acme, list[0].Id); err != nil {
+					return err
+				}
+				_, err := tx.Add(acme, models.Book{Title: "Persuasion"})
+				return err
+			})
+			assert.NoError(t, err)
+			_, err = repo.Add(acme, models.Book{Title: "Mansfield Park"})
+			assert.True(t, errors.Is(err, ErrQuotaExceeded))
+			_, err = repo.PurgeById(acme, list[1].Id)
+			assert.NoError(t, err)
+			_, err = repo.Add(acme, models.Book{Title: "Mansfield Park"})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTenantBookRepositoryStore(t *testing.T) {
+	stores := map[string]func(dir string) (models.BookRepository, error){
+		"WriteAheadLog": func(dir string) (models.BookRepository, error) {
+			return NewPersistentBookRepository(dir, nil)
+		},
+		"Bolt": func(dir string) (models.BookRepository, error) {
+			return NewBoltBookRepository(dir, nil)
+		},
+	}
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			ctx := context.Background()
+			acme := tenancy.WithTenant(ctx, "acme")
+			cfg := TenantBookRepositoryConfig{
+				InitialData: func(tenant string) ([]models.Book, error) {
+					return []models.Book{{Id: "hobbit", Title: "The Hobbit"}}, nil
+				},
+			}
+			books, err := open(dir)
+			require.NoError(t, err)
+			repo, err := NewTenantBookRepository(books, cfg)
+			require.NoError(t, err)
+			_, err = repo.Add(acme, models.Book{Id: "dune", Title: "Dune"})
+			require.NoError(t, err)
+			require.NoError(t, books.(io.Closer).Close())
+
+			// The books of the tenant are kept, and it is not given its
+			// initial data again.
+			books, err = open(dir)
+			require.NoError(t, err)
+			defer books.(io.Closer).Close()
+			repo, err = NewTenantBookRepository(books, cfg)
+			require.NoError(t, err)
+			tenants, err := repo.ListTenants(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, []models.Tenant{{Id: "acme", Books: 2}}, tenants)
+			_, err = repo.PurgeById(acme, "hobbit")
+			assert.NoError(t, err)
+
+			// The deleted tenant is dropped from the store.
+			tenant, err := repo.DeleteTenant(ctx, "acme")
+			assert.NoError(t, err)
+			assert.Equal(t, models.Tenant{Id: "acme", Books: 1}, tenant)
+			ids, err := books.(models.BookTenantStore).ListTenantIds(ctx)
+			assert.NoError(t, err)
+			assert.Empty(t, ids)
+			list, err := repo.List(acme)
+			assert.NoError(t, err)
+			assert.Equal(t, []models.Book{{Id: "hobbit", Title: "The Hobbit"}}, list)
+		})
+	}
+}
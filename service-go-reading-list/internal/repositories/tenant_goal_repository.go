@@ -0,0 +1,61 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories
+
+import (
+	"context"
+	"sync"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/tenancy"
+)
+
+// TenantGoalRepository keeps the goals of each tenant apart, by the tenant of the context.
+type TenantGoalRepository struct {
+	goals map[string]models.GoalRepository
+	lock  sync.Mutex
+}
+
+func NewTenantGoalRepository() *TenantGoalRepository {
+	return &TenantGoalRepository{
+		goals: make(map[string]models.GoalRepository),
+		lock:  sync.Mutex{},
+	}
+}
+
+func (r *TenantGoalRepository) Put(ctx context.Context, goal models.Goal) (models.Goal, error) {
+	return r.tenantGoals(ctx).Put(ctx, goal)
+}
+
+func (r *TenantGoalRepository) List(ctx context.Context) ([]models.Goal, error) {
+	return r.tenantGoals(ctx).List(ctx)
+}
+
+func (r *TenantGoalRepository) GetByYear(ctx context.Context, year int) (models.Goal, error) {
+	return r.tenantGoals(ctx).GetByYear(ctx, year)
+}
+
+func (r *TenantGoalRepository) DeleteByYear(ctx context.Context, year int) (models.Goal, error) {
+	return r.tenantGoals(ctx).DeleteByYear(ctx, year)
+}
+
+// PurgeTenant removes all the goals of the tenant.
+func (r *TenantGoalRepository) PurgeTenant(ctx context.Context, id string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.goals, id)
+	return nil
+}
+
+func (r *TenantGoalRepository) tenantGoals(ctx context.Context) models.GoalRepository {
+	tenant := tenancy.FromContext(ctx)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	goals, ok := r.goals[tenant]
+	if !ok {
+		goals = NewGoalRepository()
+		r.goals[tenant] = goals
+	}
+	return goals
+}
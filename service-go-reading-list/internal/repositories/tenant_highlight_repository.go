@@ -0,0 +1,228 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/redis"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/tenancy"
+)
+
+// TenantHighlightRepository keeps the highlights of each tenant apart, by the tenant of the context.
+type TenantHighlightRepository struct {
+	highlights map[string]models.HighlightRepository
+	lock       sync.Mutex
+	// dir, when set, holds a snapshot and a write-ahead log of the
+	// highlights of each tenant.
+	dir string
+	// client, when set, is the Redis the highlights are stored in under
+	// prefix.
+	client *redis.Client
+	prefix string
+}
+
+func NewTenantHighlightRepository() *TenantHighlightRepository {
+	return &TenantHighlightRepository{
+		highlights: make(map[string]models.HighlightRepository),
+		lock:       sync.Mutex{},
+	}
+}
+
+// NewPersistentTenantHighlightRepository returns the highlights of the
+// tenants stored in dir, which is created when it does not exist. The
+// highlights of a tenant are read from its file when it is first used.
+func NewPersistentTenantHighlightRepository(dir string) (*TenantHighlightRepository, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create the highlight directory [%s]: %w", dir, err)
+	}
+	repo := NewTenantHighlightRepository()
+	repo.dir = dir
+	return repo, nil
+}
+
+// NewRedisTenantHighlightRepository returns the highlights of the tenants
+// stored in Redis under the key prefix, as the books of RedisBookRepository
+// are. The client is closed by Close.
+func NewRedisTenantHighlightRepository(client *redis.Client, prefix string) *TenantHighlightRepository {
+	repo := NewTenantHighlightRepository()
+	repo.client = client
+	repo.prefix = prefix
+	return repo
+}
+
+func (r *TenantHighlightRepository) Add(ctx context.Context, highlight models.Highlight) (models.Highlight, error) {
+	highlights, err := r.tenantHighlights(ctx)
+	if err != nil {
+		return models.Highlight{}, err
+	}
+	return highlights.Add(ctx, highlight)
+}
+
+func (r *TenantHighlightRepository) Update(ctx context.Context, highlight models.Highlight) (models.Highlight, error) {
+	highlights, err := r.tenantHighlights(ctx)
+	if err != nil {
+		return models.Highlight{}, err
+	}
+	return highlights.Update(ctx, highlight)
+}
+
+func (r *TenantHighlightRepository) GetById(ctx context.Context, id string) (models.Highlight, error) {
+	highlights, err := r.tenantHighlights(ctx)
+	if err != nil {
+		return models.Highlight{}, err
+	}
+	return highlights.GetById(ctx, id)
+}
+
+func (r *TenantHighlightRepository) DeleteById(ctx context.Context, id string) (models.Highlight, error) {
+	highlights, err := r.tenantHighlights(ctx)
+	if err != nil {
+		return models.Highlight{}, err
+	}
+	return highlights.DeleteById(ctx, id)
+}
+
+func (r *TenantHighlightRepository) List(ctx context.Context, filter models.HighlightFilter) ([]models.Highlight, error) {
+	highlights, err := r.tenantHighlights(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return highlights.List(ctx, filter)
+}
+
+func (r *TenantHighlightRepository) DeleteByBook(ctx context.Context, bookId string) (int, error) {
+	highlights, err := r.tenantHighlights(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return highlights.DeleteByBook(ctx, bookId)
+}
+
+// PurgeTenant removes all the highlights of the tenant.
+func (r *TenantHighlightRepository) PurgeTenant(ctx context.Context, id string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.client != nil {
+		if err := newRedisHighlightRepository(r.client, r.redisPrefix(id)).purge(ctx); err != nil {
+			return fmt.Errorf("tenantHighlightRepository:PurgeTenant: %w", err)
+		}
+	}
+	if r.dir != "" {
+		// The log of the highlights that were opened is released first, as
+		// it is locked.
+		if highlights, ok := r.highlights[id].(*highlightRepository); ok {
+			if err := highlights.release(); err != nil {
+				return fmt.Errorf("tenantHighlightRepository:PurgeTenant: %w", err)
+			}
+		}
+		for _, path := range []string{r.path(id), r.walPath(id)} {
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				delete(r.highlights, id)
+				return fmt.Errorf("tenantHighlightRepository:PurgeTenant: %w", err)
+			}
+		}
+	}
+	delete(r.highlights, id)
+	return nil
+}
+
+// RunCompaction compacts the write-ahead logs of the highlights of the
+// tenants every interval until the context is cancelled.
+func (r *TenantHighlightRepository) RunCompaction(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for tenant, highlights := range r.persisted() {
+				if highlights.pendingRecords() == 0 {
+					continue
+				}
+				if err := highlights.compact(); err != nil {
+					logrus.WithFields(logrus.Fields{"tenant": tenant}).Errorf("failed to compact the highlights: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// Close compacts the write-ahead logs of the highlights of the tenants and
+// releases them, or closes the Redis client.
+func (r *TenantHighlightRepository) Close() error {
+	var errs []error
+	for _, highlights := range r.persisted() {
+		errs = append(errs, highlights.close())
+	}
+	if r.client != nil {
+		errs = append(errs, r.client.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// persisted returns the highlights of the tenants that were opened from dir.
+func (r *TenantHighlightRepository) persisted() map[string]*highlightRepository {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	persisted := make(map[string]*highlightRepository)
+	for tenant, highlights := range r.highlights {
+		if highlights, ok := highlights.(*highlightRepository); ok && highlights.wal != nil {
+			persisted[tenant] = highlights
+		}
+	}
+	return persisted
+}
+
+func (r *TenantHighlightRepository) tenantHighlights(ctx context.Context) (models.HighlightRepository, error) {
+	tenant := tenancy.FromContext(ctx)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	highlights, ok := r.highlights[tenant]
+	if ok {
+		return highlights, nil
+	}
+	if r.client != nil {
+		highlights = newRedisHighlightRepository(r.client, r.redisPrefix(tenant))
+	} else if r.dir == "" {
+		highlights = NewHighlightRepository()
+	} else {
+		var err error
+		if highlights, err = newPersistentHighlightRepository(r.path(tenant), r.walPath(tenant)); err != nil {
+			return nil, fmt.Errorf("tenantHighlightRepository: failed to read the highlights of the tenant [%s]: %w", tenant, err)
+		}
+	}
+	r.highlights[tenant] = highlights
+	return highlights, nil
+}
+
+// path returns the snapshot of the highlights of the tenant.
+func (r *TenantHighlightRepository) path(tenant string) string {
+	return filepath.Join(r.dir, url.PathEscape(tenant)+".json")
+}
+
+// walPath returns the write-ahead log of the highlights of the tenant.
+func (r *TenantHighlightRepository) walPath(tenant string) string {
+	return filepath.Join(r.dir, url.PathEscape(tenant)+".wal")
+}
+
+// redisPrefix returns the key prefix of the highlights of the tenant, that
+// of its books in RedisBookRepository.
+func (r *TenantHighlightRepository) redisPrefix(tenant string) string {
+	if tenant == tenancy.DefaultTenant {
+		return r.prefix
+	}
+	return r.prefix + "tenants:" + tenant + ":"
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package repositories
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// walHeaderSize is the size of the header written before every record:
+// the payload length followed by the CRC-32 checksum of the payload.
+const walHeaderSize = 8
+
+// walMaxRecordSize is the maximum payload length of a record. A length over
+// it can only be read from a damaged header.
+const walMaxRecordSize = 64 << 20
+
+// writeAheadLog is an append-only file of records of entries E, such as the
+// journal entries of the books. Every record is fsync'd before append
+// returns, so a mutation that was acknowledged to a client survives a crash.
+type writeAheadLog[E any] struct {
+	file *os.File
+	// records is the number of records appended since the log was last truncated.
+	records int
+	// err is the error of a failed append whose bytes could not be removed.
+	// The log is not written to anymore, as a record appended after them
+	// could not be read back.
+	err error
+}
+
+// openWriteAheadLog opens the log at path, creating it if needed, and returns
+// the records it already contains. A truncated or torn record at the end of
+// the file is the result of a crash during append; it is discarded and the
+// file is truncated to the last complete record. A damaged record anywhere
+// else, or one whose length is over walMaxRecordSize, is reported as
+// ErrCorruptedLog. The file is locked until the log is closed, so that
+// another process opening it fails with ErrLocked.
+func openWriteAheadLog[E any](path string) (*writeAheadLog[E], [][]E, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := lockFile(file); err != nil {
+		_ = file.Close()
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	records, validSize, err := readWriteAheadLog[E](file)
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, err
+	}
+	if validSize < info.Size() {
+		logrus.WithFields(logrus.Fields{"path": path, "discardedBytes": info.Size() - validSize}).
+			Warn("discarding a truncated record at the end of the write-ahead log")
+		if err := file.Truncate(validSize); err != nil {
+			_ = file.Close()
+			return nil, nil, err
+		}
+		if err := file.Sync(); err != nil {
+			_ = file.Close()
+			return nil, nil, err
+		}
+	}
+	if _, err := file.Seek(validSize, io.SeekStart); err != nil {
+		_ = file.Close()
+		return nil, nil, err
+	}
+	return &writeAheadLog[E]{file: file, records: len(records)}, records, nil
+}
+
+// readWriteAheadLog reads all the complete records in the file and returns
+// them together with the offset just after the last complete record.
+func readWriteAheadLog[E any](file *os.File) ([][]E, int64, error) {
+	contents, err := io.ReadAll(file)
+	if err != nil {
+		return nil, 0, err
+	}
+	var records [][]E
+	var offset int64
+	for int64(len(contents)) > offset {
+		remaining := contents[offset:]
+		if len(remaining) < walHeaderSize {
+			break
+		}
+		size := int64(binary.LittleEndian.Uint32(remaining[0:4]))
+		checksum := binary.LittleEndian.Uint32(remaining[4:8])
+		if size > walMaxRecordSize {
+			return nil, 0, fmt.Errorf("invalid record length %d at offset %d: %w", size, offset, ErrCorruptedLog)
+		}
+		end := walHeaderSize + size
+		if int64(len(remaining)) < end {
+			// A torn write of the last record, which runs to the end of the file.
+			break
+		}
+		payload := remaining[walHeaderSize:end]
+		if crc32.ChecksumIEEE(payload) != checksum {
+			if int64(len(remaining)) == end {
+				// A torn write of the last record.
+				break
+			}
+			return nil, 0, fmt.Errorf("checksum mismatch at offset %d: %w", offset, ErrCorruptedLog)
+		}
+		var entries []E
+		if err := json.Unmarshal(payload, &entries); err != nil {
+			return nil, 0, fmt.Errorf("invalid record at offset %d: %w", offset, ErrCorruptedLog)
+		}
+		records = append(records, entries)
+		offset += end
+	}
+	return records, offset, nil
+}
+
+// append writes the record of the entries at the end of the log. When the
+// record cannot be written in full, such as when the disk is full, the log
+// is truncated back to its previous end, so that the torn record is not
+// followed by the next ones.
+func (l *writeAheadLog[E]) append(entries ...E) error {
+	if l.err != nil {
+		return l.err
+	}
+	payload, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	if len(payload) > walMaxRecordSize {
+		return fmt.Errorf("the record of %d bytes is over the maximum of %d bytes", len(payload), walMaxRecordSize)
+	}
+	end, err := l.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	var record bytes.Buffer
+	header := make([]byte, walHeaderSize)
+	binary.LittleEndian.PutUint32(header[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(header[4:8], crc32.ChecksumIEEE(payload))
+	record.Write(header)
+	record.Write(payload)
+	if _, err := l.file.Write(record.Bytes()); err != nil {
+		return l.rollback(end, err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return l.rollback(end, err)
+	}
+	l.records++
+	return nil
+}
+
+// rollback removes the bytes written after end by a failed append.
+func (l *writeAheadLog[E]) rollback(end int64, err error) error {
+	if truncateErr := l.truncate(end); truncateErr != nil {
+		l.err = fmt.Errorf("the write-ahead log cannot be written to after a failed append: %w", errors.Join(err, truncateErr))
+		return l.err
+	}
+	return err
+}
+
+func (l *writeAheadLog[E]) truncate(size int64) error {
+	if err := l.file.Truncate(size); err != nil {
+		return err
+	}
+	if _, err := l.file.Seek(size, io.SeekStart); err != nil {
+		return err
+	}
+	return l.file.Sync()
+}
+
+// reset discards all the records, once they have been captured in a snapshot.
+// It also recovers the log from a failed append whose bytes could not be
+// removed.
+func (l *writeAheadLog[E]) reset() error {
+	if err := l.truncate(0); err != nil {
+		return err
+	}
+	l.records = 0
+	l.err = nil
+	return nil
+}
+
+func (l *writeAheadLog[E]) close() error {
+	return l.file.Close()
+}
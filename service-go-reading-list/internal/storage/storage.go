@@ -0,0 +1,155 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package storage opens the repositories on the storage selected by the
+// configuration, for the service and for the commands that operate on the
+// storage directly.
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/models"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/redis"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/repositories"
+)
+
+// AuditLogFileName is the file of the audit log in the data directory.
+const AuditLogFileName = "audit.jsonl"
+
+// CoversDirName is the directory of the cover images in the data directory.
+const CoversDirName = "covers"
+
+// HighlightsDirName is the directory of the highlights in the data directory.
+const HighlightsDirName = "highlights"
+
+// OpenBookRepository opens the books stored in Redis when cfg.RedisURL is
+// set, in cfg.DataDir as selected by cfg.DataStore when it is set, or in
+// memory otherwise. The initial data is added to a storage that has no
+// books. The repositories that hold resources implement io.Closer.
+func OpenBookRepository(cfg *config.Config, initialData []models.Book) (models.BookRepository, error) {
+	if cfg.RedisURL != "" {
+		return openRedisBookRepository(cfg, initialData)
+	}
+	if cfg.DataDir == "" {
+		return repositories.NewBookRepository(initialData), nil
+	}
+	if cfg.DataStore == config.DataStoreBolt {
+		return openBoltBookRepository(cfg, initialData)
+	}
+	repo, err := repositories.NewPersistentBookRepository(cfg.DataDir, initialData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore the books from [%s]: %w", cfg.DataDir, err)
+	}
+	return repo, nil
+}
+
+func openBoltBookRepository(cfg *config.Config, initialData []models.Book) (models.BookRepository, error) {
+	if cfg.DataRestorePath != "" {
+		restored, err := repositories.RestoreBoltBackup(cfg.DataDir, cfg.DataRestorePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore the books from [%s]: %w", cfg.DataRestorePath, err)
+		}
+		if restored {
+			logrus.Infof("restored the books from [%s]", cfg.DataRestorePath)
+		} else {
+			logrus.Warnf("ignored the backup [%s] as [%s] already has books", cfg.DataRestorePath, cfg.DataDir)
+		}
+	}
+	repo, err := repositories.NewBoltBookRepository(cfg.DataDir, initialData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the books in [%s]: %w", cfg.DataDir, err)
+	}
+	return repo, nil
+}
+
+func openRedisBookRepository(cfg *config.Config, initialData []models.Book) (models.BookRepository, error) {
+	opts, err := redis.ParseURL(cfg.RedisURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the Redis URL: %w", err)
+	}
+	repo, err := repositories.NewRedisBookRepository(redis.NewClient(opts), cfg.RedisKeyPrefix, initialData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis at [%s]: %w", opts.Addr, err)
+	}
+	return repo, nil
+}
+
+// NewTenantBookRepository namespaces the books by tenant. The books of a
+// single-tenant deployment are those of the default tenant.
+func NewTenantBookRepository(cfg *config.Config, books models.BookRepository) (*repositories.TenantBookRepository, error) {
+	repo, err := repositories.NewTenantBookRepository(books, repositories.TenantBookRepositoryConfig{
+		InitialData: func(tenant string) ([]models.Book, error) {
+			initialData, err := config.LoadTenantInitialData(tenant)
+			return initialData.Books, err
+		},
+		Quota: func(tenant string) int {
+			if quota, ok := cfg.Tenancy.BookQuotas[tenant]; ok {
+				return quota
+			}
+			return cfg.Tenancy.BookQuota
+		},
+		Shared: cfg.RedisURL != "",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the tenants: %w", err)
+	}
+	return repo, nil
+}
+
+// OpenAuditRepository opens the audit log in cfg.DataDir when it is set, or
+// in memory otherwise.
+func OpenAuditRepository(cfg *config.Config) (models.AuditRepository, error) {
+	if cfg.DataDir == "" {
+		return repositories.NewAuditRepository(), nil
+	}
+	path := filepath.Join(cfg.DataDir, AuditLogFileName)
+	repo, err := repositories.NewPersistentAuditRepository(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the audit log [%s]: %w", path, err)
+	}
+	return repo, nil
+}
+
+// OpenCoverRepository opens the cover images of the books in cfg.DataDir
+// when it is set, or in memory otherwise. The covers are not stored in Redis,
+// so the replicas sharing the books of a Redis share their covers only when
+// they share cfg.DataDir.
+func OpenCoverRepository(cfg *config.Config) (models.BlobRepository, error) {
+	if cfg.DataDir == "" {
+		return repositories.NewBlobRepository(), nil
+	}
+	dir := filepath.Join(cfg.DataDir, CoversDirName)
+	repo, err := repositories.NewFileBlobRepository(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the covers in [%s]: %w", dir, err)
+	}
+	return repo, nil
+}
+
+// OpenHighlightRepository opens the highlights of the tenants stored in
+// Redis when cfg.RedisURL is set, so that the replicas sharing the books
+// share them too, in cfg.DataDir when it is set, or in memory otherwise.
+// The repository is closed by its Close.
+func OpenHighlightRepository(cfg *config.Config) (*repositories.TenantHighlightRepository, error) {
+	if cfg.RedisURL != "" {
+		opts, err := redis.ParseURL(cfg.RedisURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the Redis URL: %w", err)
+		}
+		return repositories.NewRedisTenantHighlightRepository(redis.NewClient(opts), cfg.RedisKeyPrefix), nil
+	}
+	if cfg.DataDir == "" {
+		return repositories.NewTenantHighlightRepository(), nil
+	}
+	dir := filepath.Join(cfg.DataDir, HighlightsDirName)
+	repo, err := repositories.NewPersistentTenantHighlightRepository(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the highlights in [%s]: %w", dir, err)
+	}
+	return repo, nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2025 The OpenChoreo Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tenancy carries the tenant of a request, such as an OpenChoreo
+// organization or project, whose data is isolated from the other tenants.
+package tenancy
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// DefaultTenant is the tenant of the requests when multi-tenancy is not
+// enabled. Its books are the ones of a single-tenant deployment, so that
+// enabling multi-tenancy keeps them.
+const DefaultTenant = "default"
+
+// idPattern restricts the tenant ids to DNS labels, like the names of the
+// OpenChoreo organizations and projects.
+var idPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// NormalizeId returns the tenant id in lower case and whether it is valid.
+func NormalizeId(id string) (string, bool) {
+	id = strings.ToLower(strings.TrimSpace(id))
+	return id, idPattern.MatchString(id)
+}
+
+type tenantCtxKey struct{}
+
+// WithTenant returns a copy of ctx carrying the tenant.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantCtxKey{}, tenant)
+}
+
+// FromContext returns the tenant stored by WithTenant, or the DefaultTenant
+// when there is none, e.g. for the background jobs of a single tenant.
+func FromContext(ctx context.Context) string {
+	if tenant, ok := ctx.Value(tenantCtxKey{}).(string); ok && tenant != "" {
+		return tenant
+	}
+	return DefaultTenant
+}
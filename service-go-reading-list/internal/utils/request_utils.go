@@ -5,19 +5,69 @@ package utils
 
 import (
 	"context"
+	"errors"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
+
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/auth"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/tenancy"
 )
 
 const correlationIdHeaderName = "x-correlation-id"
 const correlationIdCtxKey = "correlation-id"
+const principalLocalsKey = "principal"
+const tenantLocalsKey = "tenant"
 
+// GetRequestContext returns the context of the request, which carries its
+// correlation id, caller and tenant, and is done when its deadline, set by
+// the timeout middleware, is exceeded.
 func GetRequestContext(rCtx *fiber.Ctx) context.Context {
-	correlationId := rCtx.Get(correlationIdHeaderName)
-	ctx := context.Background()
+	// The header is copied, as it points into the request buffer, which is
+	// reused once the request is served, while the context may outlive it,
+	// e.g. in the audit entries.
+	correlationId := strings.Clone(rCtx.Get(correlationIdHeaderName))
+	ctx := rCtx.UserContext()
+	if principal, ok := GetPrincipal(rCtx); ok {
+		ctx = auth.WithPrincipal(ctx, principal)
+	}
+	if tenant, ok := GetTenant(rCtx); ok {
+		ctx = tenancy.WithTenant(ctx, tenant)
+	}
 	return context.WithValue(ctx, correlationIdCtxKey, correlationId)
 }
 
+// GetCorrelationId returns the correlation id of the request the context was
+// created for by GetRequestContext, if any.
+func GetCorrelationId(ctx context.Context) string {
+	correlationId, _ := ctx.Value(correlationIdCtxKey).(string)
+	return correlationId
+}
+
+// SetPrincipal stores the authenticated caller of the request, so that it is
+// carried by the context returned from GetRequestContext.
+func SetPrincipal(rCtx *fiber.Ctx, principal *auth.Principal) {
+	rCtx.Locals(principalLocalsKey, principal)
+}
+
+// GetPrincipal returns the authenticated caller of the request, if any.
+func GetPrincipal(rCtx *fiber.Ctx) (*auth.Principal, bool) {
+	principal, ok := rCtx.Locals(principalLocalsKey).(*auth.Principal)
+	return principal, ok
+}
+
+// SetTenant stores the tenant of the request, so that it is carried by the
+// context returned from GetRequestContext.
+func SetTenant(rCtx *fiber.Ctx, tenant string) {
+	rCtx.Locals(tenantLocalsKey, tenant)
+}
+
+// GetTenant returns the tenant of the request, if it was resolved.
+func GetTenant(rCtx *fiber.Ctx) (string, bool) {
+	tenant, ok := rCtx.Locals(tenantLocalsKey).(string)
+	return tenant, ok
+}
+
 func FiberErrorHandler(c *fiber.Ctx, err error) error {
 	// Default 500 status code
 	code := fiber.StatusInternalServerError
@@ -27,6 +77,10 @@ func FiberErrorHandler(c *fiber.Ctx, err error) error {
 		// Override status code if fiber.Error type
 		code = e.Code
 		msg = e.Error()
+	} else if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		e := MakeHttpContextError(err)
+		code = e.Code
+		msg = e.Error()
 	}
 	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
 
@@ -34,6 +88,17 @@ func FiberErrorHandler(c *fiber.Ctx, err error) error {
 	return c.Status(code).JSON(ErrorResponse{Message: msg})
 }
 
+// MakeHttpContextError returns the error answered when an operation fails
+// because the context of its request is done: 504 when the deadline of the
+// request was exceeded and 503 when the request was canceled, e.g. because
+// the service is shutting down.
+func MakeHttpContextError(err error) *fiber.Error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fiber.NewError(fiber.StatusGatewayTimeout, "the request timed out")
+	}
+	return fiber.NewError(fiber.StatusServiceUnavailable, "the request was canceled")
+}
+
 type ErrorResponse struct {
 	Message string `json:"message" example:"error message"`
 }
@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -17,18 +18,40 @@ import (
 
 	"github.com/wso2/choreo-sample-apps/go/rest-api/api/routes"
 	"github.com/wso2/choreo-sample-apps/go/rest-api/docs" // docs are generated by Swag CLI.
+	v2docs "github.com/wso2/choreo-sample-apps/go/rest-api/docs/v2"
+	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/cli"
 	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/config"
 	"github.com/wso2/choreo-sample-apps/go/rest-api/internal/utils"
 )
 
 // This is an example of a REST API service that manages a list of reading items.
 //
-//	@title			Choreo Reading List
-//	@version		1.0
-//	@description	This is a sample service that manages a list of reading items.
-//	@host			localhost:8080
-//	@BasePath		/api/v1/reading-list
+//	@title									Choreo Reading List
+//	@version								1.0
+//	@description							This is a sample service that manages a list of reading items.
+//	@host									localhost:8080
+//	@BasePath								/api/v1/reading-list
+//
+//	@securityDefinitions.oauth2.implicit	default
+//	@authorizationUrl						https://test.com
+//	@scope.read:books						Grants read access
+//	@scope.write:books						Grants write access
+//	@scope.read:audit						Grants read access to the audit log
+//	@scope.admin							Grants access to the administration of the tenants
 func main() {
+	app := &cli.App{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr, Serve: serve}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := app.Run(ctx, os.Args[1:])
+	stop()
+	os.Exit(code)
+}
+
+// shutdownGracePeriod is how long the requests that are running when SIGTERM
+// is received are given to complete.
+const shutdownGracePeriod = 10 * time.Second
+
+// serve runs the service until ctx is done, once SIGTERM is received.
+func serve(ctx context.Context) {
 	app := fiber.New(fiber.Config{
 		AppName:               "choreo-reading-list",
 		ReadTimeout:           time.Second * 2,
@@ -37,6 +60,7 @@ func main() {
 		DisableStartupMessage: true,
 		ErrorHandler:          utils.FiberErrorHandler,
 	})
+	app.Get("/swagger/v2/*", swagger.New(swagger.Config{InstanceName: "v2"}))
 	app.Get("/swagger/*", swagger.HandlerDefault) // default
 
 	cfg, err := config.LoadConfig()
@@ -45,6 +69,7 @@ func main() {
 	}
 
 	docs.SwaggerInfo.Host = fmt.Sprintf("%s:%d", cfg.Hostname, cfg.Port)
+	v2docs.SwaggerInfov2.Host = docs.SwaggerInfo.Host
 
 	routes.Initialize(app)
 
@@ -56,13 +81,23 @@ func main() {
 		}
 	}()
 
-	sigtermC := make(chan os.Signal, 1)
-	signal.Notify(sigtermC, os.Interrupt, syscall.SIGTERM)
-
-	<-sigtermC // block until SIGTERM is received
+	<-ctx.Done()
 	logrus.Info("SIGTERM received: gracefully shutting down...")
 
-	if err := app.Shutdown(); err != nil {
-		logrus.Errorf("server shutdown error: %v", err)
+	// The requests still running after the grace period are canceled, so
+	// that they are answered with 503 rather than cut off.
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- app.Shutdown() }()
+	var shutdownErr error
+	select {
+	case shutdownErr = <-shutdown:
+	case <-time.After(shutdownGracePeriod):
+		logrus.Warn("canceling the requests that are still running")
+		routes.CancelRequests()
+		shutdownErr = <-shutdown
+	}
+	if shutdownErr != nil {
+		logrus.Errorf("server shutdown error: %v", shutdownErr)
 	}
+	routes.Shutdown()
 }